@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net"
 	_ "net/http/pprof"
 	"os"
+	"slices"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/sirini/goapi/internal/configs"
@@ -37,11 +40,26 @@ func main() {
 	if len(os.Args) > 1 && os.Args[1] == "update" {
 		configs.Update(db, configs.Env.Prefix)
 	}
+	if len(os.Args) > 1 && os.Args[1] == "board" {
+		runBoardCommand(db, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "images" {
+		runImageCommand(db, os.Args[2:])
+		return
+	}
 
 	repo := repositories.NewRepository(db)
 	service := services.NewService(repo)
 	handler := handlers.NewHandler(service, db)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go service.Trending.RunRankingJob(ctx)
+	go service.Analytics.RunRollupJob(ctx)
+	go service.ActivityPub.RunDeliveryJob(ctx)
+	go service.Sync.RunConsumerJob(ctx)
+
 	sizeLimit := configs.GetFileSizeLimit()
 	app := fiber.New(fiber.Config{
 		BodyLimit: sizeLimit,
@@ -97,3 +115,98 @@ func openInstallDatabase() (*sql.DB, error) {
 	}
 	return models.Open(&configs.Env, true)
 }
+
+// 게시판 보관 파일 명령 실행 (board export <id> [file.zip], board import <file.zip> [new id] [group id])
+func runBoardCommand(db *sql.DB, args []string) {
+	repo := repositories.NewRepository(db)
+	service := services.NewService(repo)
+	switch {
+	case len(args) >= 2 && args[0] == "export":
+		output := fmt.Sprintf("%s.zip", args[1])
+		if len(args) > 2 {
+			output = args[2]
+		}
+		exportBoardArchive(service, args[1], output)
+	case len(args) >= 2 && args[0] == "import":
+		param := models.BoardArchiveImportParam{ActorUid: repo.Import.FindUserUidByEmail(configs.Env.AdminID)}
+		if param.ActorUid < 1 {
+			log.Fatalf("Cannot find the admin account %q", configs.Env.AdminID)
+		}
+		if len(args) > 2 {
+			param.BoardId = args[2]
+		}
+		if len(args) > 3 {
+			param.GroupId = args[3]
+		}
+		importBoardArchive(service, args[1], param)
+	default:
+		log.Fatalln("Usage: goapi board export <id> [file.zip] | goapi board import <file.zip> [new id] [group id]")
+	}
+}
+
+// 게시판을 보관 파일로 내보내기
+func exportBoardArchive(service *services.Service, boardId string, output string) {
+	boardUid := service.Board.GetBoardUid(boardId)
+	if boardUid < 1 {
+		log.Fatalf("Cannot find board %q", boardId)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", output, err)
+	}
+	err = service.Archive.ExportBoard(boardUid, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		log.Fatalf("Failed to export board %q: %v", boardId, err)
+	}
+	log.Printf("✅ Board %q exported to %s\n", boardId, output)
+}
+
+// 보관 파일로 새 게시판 만들고 결과 보고서 출력하기
+func importBoardArchive(service *services.Service, input string, param models.BoardArchiveImportParam) {
+	file, err := os.Open(input)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", input, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", input, err)
+	}
+	report, err := service.Archive.ImportBoard(file, info.Size(), param)
+	encoded, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(encoded))
+	if err != nil {
+		log.Fatalf("Failed to import board archive: %v", err)
+	}
+	log.Printf("✅ Board %q imported from %s\n", report.BoardId, input)
+}
+
+// 기존 이미지 반응형 변형 채우기 명령 실행 (images backfill [thumbnails|images|profile])
+func runImageCommand(db *sql.DB, args []string) {
+	categories := []models.UploadCategory{models.UPLOAD_THUMB, models.UPLOAD_IMAGE, models.UPLOAD_PROFILE}
+	if len(args) < 1 || args[0] != "backfill" {
+		log.Fatalln("Usage: goapi images backfill [thumbnails|images|profile]")
+	}
+	if len(args) > 1 {
+		category := models.UploadCategory(args[1])
+		if !slices.Contains(categories, category) {
+			log.Fatalf("Unknown image category %q", args[1])
+		}
+		categories = []models.UploadCategory{category}
+	}
+
+	service := services.NewService(repositories.NewRepository(db))
+	for _, category := range categories {
+		report, err := service.Image.Backfill(category)
+		encoded, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(encoded))
+		if err != nil {
+			log.Fatalf("Failed to backfill %s variants: %v", category, err)
+		}
+		log.Printf("✅ %d of %d %s images now have responsive variants\n", report.Created, report.Scanned, category)
+	}
+}
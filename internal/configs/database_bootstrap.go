@@ -18,7 +18,13 @@ var baseTableNames = []string{
 	"skin_setting", "board_category", "point_history", "post", "hashtag",
 	"post_hashtag", "post_like", "comment", "comment_like", "file",
 	"file_thumbnail", "image", "notification", "exif", "image_description",
-	"trade", "mail_campaign", "mail_delivery", "push_device",
+	"trade", "mail_campaign", "mail_delivery", "push_device", "content_filter",
+	"content_filter_log", "spam_log", "post_approval", "role", "role_member",
+	"board_acl", "post_share", "post_share_log", "comment_history",
+	"link_preview", "post_view", "post_read_event", "post_stat_daily",
+	"post_stat_referrer", "import_job", "import_map", "activitypub_key",
+	"activitypub_actor", "activitypub_follower", "activitypub_delivery", "activitypub_activity",
+	"sync_change", "sync_source", "sync_log", "image_variant",
 }
 
 // 지정한 이름의 DB가 없으면 utf8mb4 기본값으로 생성한다.
@@ -3,8 +3,10 @@ package configs
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -53,6 +55,17 @@ type Config struct {
 	FirebaseProjectID       string
 	FirebaseCredentialsFile string
 	ImageDescription        ImageDescriptionEnv
+	Trending                TrendingEnv
+	Spam                    SpamEnv
+	ReportHideThreshold     string
+	LinkPreview             LinkPreviewEnv
+	ViewWindowHours         string
+	Analytics               AnalyticsEnv
+	Feed                    FeedEnv
+	ActivityPub             ActivityPubEnv
+	SyncConsumer            SyncConsumerEnv
+	ImageFormat             ImageFormatEnv
+	ImageWidths             ImageWidthsEnv
 }
 
 type ImageDescriptionEnv struct {
@@ -69,6 +82,145 @@ type ImageDescriptionConfig struct {
 	MaxConcurrent int
 }
 
+type TrendingEnv struct {
+	HitWeight      string
+	LikeWeight     string
+	CommentWeight  string
+	Gravity        string
+	WindowDays     string
+	Limit          string
+	RefreshMinutes string
+	MaxLevel       string
+}
+
+type TrendingConfig struct {
+	HitWeight      float64
+	LikeWeight     float64
+	CommentWeight  float64
+	Gravity        float64
+	WindowDays     int
+	Limit          int
+	RefreshMinutes int
+	MaxLevel       int
+}
+
+type SpamEnv struct {
+	HoldScore       string
+	RejectScore     string
+	NewAccountHours string
+	TrustedLevel    string
+	VelocityMinutes string
+	VelocityLimit   string
+	StrikeLimit     string
+	StrikeDays      string
+}
+
+type SpamConfig struct {
+	HoldScore       int
+	RejectScore     int
+	NewAccountHours int
+	TrustedLevel    int
+	VelocityMinutes int
+	VelocityLimit   int
+	StrikeLimit     int
+	StrikeDays      int
+}
+
+type LinkPreviewEnv struct {
+	Enabled        string
+	TimeoutSeconds string
+	MaxLinks       string
+	CacheHours     string
+}
+
+type LinkPreviewConfig struct {
+	Enabled        bool
+	TimeoutSeconds int
+	MaxLinks       int
+	CacheHours     int
+}
+
+type AnalyticsEnv struct {
+	RetentionDays string
+	RollupMinutes string
+}
+
+type AnalyticsConfig struct {
+	RetentionDays int
+	RollupMinutes int
+}
+
+type FeedEnv struct {
+	Language string
+	Items    string
+	Timezone string
+}
+
+type FeedConfig struct {
+	Language string
+	Items    int
+	Location *time.Location
+}
+
+type ActivityPubEnv struct {
+	Enabled       string
+	RemoteReplies string
+	MaxAttempts   string
+}
+
+type ActivityPubConfig struct {
+	Enabled       bool
+	RemoteReplies bool
+	MaxAttempts   int
+}
+
+type SyncConsumerEnv struct {
+	RemoteURL       string
+	RemoteKey       string
+	RemoteBoard     string
+	TargetBoard     string
+	IntervalMinutes string
+}
+
+type SyncConsumerConfig struct {
+	Enabled         bool
+	RemoteURL       string
+	RemoteKey       string
+	RemoteBoard     string
+	TargetBoard     string
+	IntervalMinutes int
+}
+
+type ImageFormatEnv struct {
+	Insert      string
+	Thumbnail   string
+	Profile     string
+	Fallback    string
+	AvifQuality string
+	AvifEffort  string
+}
+
+type ImageFormatConfig struct {
+	Insert      string
+	Thumbnail   string
+	Profile     string
+	Fallback    string
+	AvifQuality int
+	AvifEffort  int
+}
+
+type ImageWidthsEnv struct {
+	Insert    string
+	Thumbnail string
+	Profile   string
+}
+
+type ImageWidthsConfig struct {
+	Insert    []uint
+	Thumbnail []uint
+	Profile   []uint
+}
+
 const EnvironmentFileVariable = "NUBO_ENV_FILE"
 
 // EnvironmentFilePath는 명시적인 런타임 설정 경로를 반환하며,
@@ -99,6 +251,57 @@ func GetImageDescriptionConfig() ImageDescriptionConfig {
 	}
 }
 
+// GetTrendingConfig는 인기글 점수 계산에 쓰는 가중치와 감쇠 설정을 반환한다.
+// 점수 = (조회수*hit + 좋아요*like + 댓글*comment) / (경과 시간 + 2)^gravity
+func GetTrendingConfig() TrendingConfig {
+	return TrendingConfig{
+		HitWeight:      parseBoundedFloat(Env.Trending.HitWeight, 1, 0, 1000),
+		LikeWeight:     parseBoundedFloat(Env.Trending.LikeWeight, 5, 0, 1000),
+		CommentWeight:  parseBoundedFloat(Env.Trending.CommentWeight, 3, 0, 1000),
+		Gravity:        parseBoundedFloat(Env.Trending.Gravity, 1.8, 0, 10),
+		WindowDays:     parseBoundedInt(Env.Trending.WindowDays, 7, 1, 365),
+		Limit:          parseBoundedInt(Env.Trending.Limit, 20, 1, 100),
+		RefreshMinutes: parseBoundedInt(Env.Trending.RefreshMinutes, 10, 1, 1440),
+		MaxLevel:       parseBoundedInt(Env.Trending.MaxLevel, 0, 0, 10),
+	}
+}
+
+// GetSpamConfig는 스팸 점수의 보류/거부 기준과 반복 위반 시 권한 회수 기준을 반환한다.
+// 거부 점수가 보류 점수보다 낮게 설정되면 보류 점수에 맞춘다.
+func GetSpamConfig() SpamConfig {
+	config := SpamConfig{
+		HoldScore:       parseBoundedInt(Env.Spam.HoldScore, 5, 1, 100),
+		RejectScore:     parseBoundedInt(Env.Spam.RejectScore, 8, 1, 100),
+		NewAccountHours: parseBoundedInt(Env.Spam.NewAccountHours, 72, 0, 8760),
+		TrustedLevel:    parseBoundedInt(Env.Spam.TrustedLevel, 3, 0, 10),
+		VelocityMinutes: parseBoundedInt(Env.Spam.VelocityMinutes, 10, 1, 1440),
+		VelocityLimit:   parseBoundedInt(Env.Spam.VelocityLimit, 5, 1, 1000),
+		StrikeLimit:     parseBoundedInt(Env.Spam.StrikeLimit, 3, 0, 100),
+		StrikeDays:      parseBoundedInt(Env.Spam.StrikeDays, 7, 1, 365),
+	}
+	config.RejectScore = max(config.RejectScore, config.HoldScore)
+	return config
+}
+
+// GetLinkPreviewConfig는 본문/쪽지 링크 미리보기의 요청 제한 시간, 글 하나당 링크 수, 캐시 유지 시간을 반환한다.
+func GetLinkPreviewConfig() LinkPreviewConfig {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(Env.LinkPreview.Enabled))
+	return LinkPreviewConfig{
+		Enabled:        err == nil && enabled,
+		TimeoutSeconds: parseBoundedInt(Env.LinkPreview.TimeoutSeconds, 5, 1, 30),
+		MaxLinks:       parseBoundedInt(Env.LinkPreview.MaxLinks, 3, 1, 10),
+		CacheHours:     parseBoundedInt(Env.LinkPreview.CacheHours, 168, 1, 8760),
+	}
+}
+
+func parseBoundedFloat(value string, fallback, minimum, maximum float64) float64 {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || parsed < minimum || parsed > maximum {
+		return fallback
+	}
+	return parsed
+}
+
 func parseBoundedInt(value string, fallback, minimum, maximum int) int {
 	parsed, err := strconv.Atoi(strings.TrimSpace(value))
 	if err != nil || parsed < minimum || parsed > maximum {
@@ -107,6 +310,120 @@ func parseBoundedInt(value string, fallback, minimum, maximum int) int {
 	return parsed
 }
 
+// 서로 다른 사용자에게 이만큼 신고받은 글/댓글은 관리자 검토 전까지 숨긴다. 0이면 자동 숨김을 하지 않는다.
+func GetReportHideThreshold() int {
+	return parseBoundedInt(Env.ReportHideThreshold, 5, 0, 1000)
+}
+
+// 같은 사용자(비회원은 IP와 브라우저 정보)의 조회를 한 번으로 세는 시간 범위를 반환한다.
+func GetViewWindowHours() int {
+	return parseBoundedInt(Env.ViewWindowHours, 24, 1, 720)
+}
+
+// 게시글 통계 원본 이벤트 보관 기간과 집계 주기를 반환한다.
+func GetAnalyticsConfig() AnalyticsConfig {
+	return AnalyticsConfig{
+		RetentionDays: parseBoundedInt(Env.Analytics.RetentionDays, 30, 2, 365),
+		RollupMinutes: parseBoundedInt(Env.Analytics.RollupMinutes, 10, 1, 1440),
+	}
+}
+
+// 피드(RSS, Atom, JSON Feed)의 언어 코드, 한 번에 싣는 글 수, 날짜를 표시할 시간대를 반환한다.
+// 시간대를 찾을 수 없으면 UTC를 사용한다.
+func GetFeedConfig() FeedConfig {
+	language := strings.TrimSpace(Env.Feed.Language)
+	if language == "" {
+		language = "ko-KR"
+	}
+	location, err := time.LoadLocation(strings.TrimSpace(Env.Feed.Timezone))
+	if err != nil {
+		location = time.UTC
+	}
+	return FeedConfig{
+		Language: language,
+		Items:    parseBoundedInt(Env.Feed.Items, 50, 1, 200),
+		Location: location,
+	}
+}
+
+// 블로그 게시판 ActivityPub 연합 사용 여부, 원격 답글의 댓글 표시 여부, 발송 최대 재시도 횟수를 반환한다.
+func GetActivityPubConfig() ActivityPubConfig {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(Env.ActivityPub.Enabled))
+	replies, replyErr := strconv.ParseBool(strings.TrimSpace(Env.ActivityPub.RemoteReplies))
+	return ActivityPubConfig{
+		Enabled:       err == nil && enabled,
+		RemoteReplies: replyErr == nil && replies,
+		MaxAttempts:   parseBoundedInt(Env.ActivityPub.MaxAttempts, 8, 1, 20),
+	}
+}
+
+// 다른 NUBO의 동기화 피드를 가져올 원격 주소, 키, 원격/대상 게시판, 가져오기 주기를 반환한다.
+// 원격 주소, 키, 대상 게시판 중 하나라도 비어 있으면 사용하지 않는다.
+func GetSyncConsumerConfig() SyncConsumerConfig {
+	config := SyncConsumerConfig{
+		RemoteURL:       strings.TrimRight(strings.TrimSpace(Env.SyncConsumer.RemoteURL), "/"),
+		RemoteKey:       strings.TrimSpace(Env.SyncConsumer.RemoteKey),
+		RemoteBoard:     strings.TrimSpace(Env.SyncConsumer.RemoteBoard),
+		TargetBoard:     strings.TrimSpace(Env.SyncConsumer.TargetBoard),
+		IntervalMinutes: parseBoundedInt(Env.SyncConsumer.IntervalMinutes, 10, 1, 1440),
+	}
+	config.Enabled = config.RemoteURL != "" && config.RemoteKey != "" && config.TargetBoard != ""
+	return config
+}
+
+// 본문 삽입, 썸네일, 프로필 이미지의 저장 형식(webp, jpeg, avif)과 AVIF 대체 형식, AVIF 품질/압축 노력을 반환한다.
+// 대체 형식은 AVIF로 저장할 때만 함께 만들며, none이면 만들지 않는다.
+func GetImageFormatConfig() ImageFormatConfig {
+	fallback := parseImageFormat(Env.ImageFormat.Fallback, "webp")
+	if strings.EqualFold(strings.TrimSpace(Env.ImageFormat.Fallback), "none") || fallback == "avif" {
+		fallback = ""
+	}
+	return ImageFormatConfig{
+		Insert:      parseImageFormat(Env.ImageFormat.Insert, "webp"),
+		Thumbnail:   parseImageFormat(Env.ImageFormat.Thumbnail, "webp"),
+		Profile:     parseImageFormat(Env.ImageFormat.Profile, "webp"),
+		Fallback:    fallback,
+		AvifQuality: parseBoundedInt(Env.ImageFormat.AvifQuality, 60, 1, 100),
+		AvifEffort:  parseBoundedInt(Env.ImageFormat.AvifEffort, 4, 0, 9),
+	}
+}
+
+func parseImageFormat(value string, fallback string) string {
+	switch format := strings.ToLower(strings.TrimSpace(value)); format {
+	case "webp", "avif", "jpeg":
+		return format
+	case "jpg":
+		return "jpeg"
+	default:
+		return fallback
+	}
+}
+
+// 본문 삽입, 썸네일, 프로필 이미지마다 추가로 만들 반응형(srcset) 너비 목록을 반환한다.
+// 쉼표로 구분한 16~4096 사이의 너비만 받고, 작은 순서로 중복 없이 최대 8개까지 쓴다.
+func GetImageWidthsConfig() ImageWidthsConfig {
+	return ImageWidthsConfig{
+		Insert:    parseImageWidths(Env.ImageWidths.Insert),
+		Thumbnail: parseImageWidths(Env.ImageWidths.Thumbnail),
+		Profile:   parseImageWidths(Env.ImageWidths.Profile),
+	}
+}
+
+func parseImageWidths(value string) []uint {
+	widths := make([]uint, 0)
+	for _, item := range strings.Split(value, ",") {
+		width := parseBoundedInt(item, 0, 16, 4096)
+		if width > 0 && !slices.Contains(widths, uint(width)) {
+			widths = append(widths, uint(width))
+		}
+	}
+	slices.Sort(widths)
+	if len(widths) > 8 {
+		widths = widths[:8]
+	}
+	return widths
+}
+
 func GetSignupMode() string {
 	mode := strings.ToLower(strings.TrimSpace(Env.SignupMode))
 	switch mode {
@@ -191,6 +508,68 @@ func LoadConfig() error {
 			MaxPerPost:  getEnv("OPENAI_IMAGE_DESCRIPTION_MAX_PER_POST", "3"),
 			Concurrency: getEnv("OPENAI_IMAGE_DESCRIPTION_CONCURRENCY", "1"),
 		},
+		Trending: TrendingEnv{
+			HitWeight:      getEnv("GOAPI_TRENDING_HIT_WEIGHT", "1"),
+			LikeWeight:     getEnv("GOAPI_TRENDING_LIKE_WEIGHT", "5"),
+			CommentWeight:  getEnv("GOAPI_TRENDING_COMMENT_WEIGHT", "3"),
+			Gravity:        getEnv("GOAPI_TRENDING_GRAVITY", "1.8"),
+			WindowDays:     getEnv("GOAPI_TRENDING_WINDOW_DAYS", "7"),
+			Limit:          getEnv("GOAPI_TRENDING_LIMIT", "20"),
+			RefreshMinutes: getEnv("GOAPI_TRENDING_REFRESH_MINUTES", "10"),
+			MaxLevel:       getEnv("GOAPI_TRENDING_MAX_LEVEL", "0"),
+		},
+		ReportHideThreshold: getEnv("GOAPI_REPORT_HIDE_THRESHOLD", "5"),
+		Spam: SpamEnv{
+			HoldScore:       getEnv("GOAPI_SPAM_HOLD_SCORE", "5"),
+			RejectScore:     getEnv("GOAPI_SPAM_REJECT_SCORE", "8"),
+			NewAccountHours: getEnv("GOAPI_SPAM_NEW_ACCOUNT_HOURS", "72"),
+			TrustedLevel:    getEnv("GOAPI_SPAM_TRUSTED_LEVEL", "3"),
+			VelocityMinutes: getEnv("GOAPI_SPAM_VELOCITY_MINUTES", "10"),
+			VelocityLimit:   getEnv("GOAPI_SPAM_VELOCITY_LIMIT", "5"),
+			StrikeLimit:     getEnv("GOAPI_SPAM_STRIKE_LIMIT", "3"),
+			StrikeDays:      getEnv("GOAPI_SPAM_STRIKE_DAYS", "7"),
+		},
+		ViewWindowHours: getEnv("GOAPI_VIEW_WINDOW_HOURS", "24"),
+		LinkPreview: LinkPreviewEnv{
+			Enabled:        getEnv("GOAPI_LINK_PREVIEW_ENABLED", "true"),
+			TimeoutSeconds: getEnv("GOAPI_LINK_PREVIEW_TIMEOUT", "5"),
+			MaxLinks:       getEnv("GOAPI_LINK_PREVIEW_MAX_LINKS", "3"),
+			CacheHours:     getEnv("GOAPI_LINK_PREVIEW_CACHE_HOURS", "168"),
+		},
+		Analytics: AnalyticsEnv{
+			RetentionDays: getEnv("GOAPI_ANALYTICS_RETENTION_DAYS", "30"),
+			RollupMinutes: getEnv("GOAPI_ANALYTICS_ROLLUP_MINUTES", "10"),
+		},
+		Feed: FeedEnv{
+			Language: getEnv("GOAPI_FEED_LANGUAGE", "ko-KR"),
+			Items:    getEnv("GOAPI_FEED_ITEMS", "50"),
+			Timezone: getEnv("GOAPI_FEED_TIMEZONE", "Asia/Seoul"),
+		},
+		ActivityPub: ActivityPubEnv{
+			Enabled:       getEnv("GOAPI_ACTIVITYPUB_ENABLED", "false"),
+			RemoteReplies: getEnv("GOAPI_ACTIVITYPUB_REMOTE_REPLIES", "false"),
+			MaxAttempts:   getEnv("GOAPI_ACTIVITYPUB_MAX_ATTEMPTS", "8"),
+		},
+		SyncConsumer: SyncConsumerEnv{
+			RemoteURL:       getEnv("GOAPI_SYNC_REMOTE_URL", ""),
+			RemoteKey:       getEnv("GOAPI_SYNC_REMOTE_KEY", ""),
+			RemoteBoard:     getEnv("GOAPI_SYNC_REMOTE_BOARD", ""),
+			TargetBoard:     getEnv("GOAPI_SYNC_TARGET_BOARD", ""),
+			IntervalMinutes: getEnv("GOAPI_SYNC_INTERVAL_MINUTES", "10"),
+		},
+		ImageFormat: ImageFormatEnv{
+			Insert:      getEnv("GOAPI_IMAGE_FORMAT_INSERT", "webp"),
+			Thumbnail:   getEnv("GOAPI_IMAGE_FORMAT_THUMBNAIL", "webp"),
+			Profile:     getEnv("GOAPI_IMAGE_FORMAT_PROFILE", "webp"),
+			Fallback:    getEnv("GOAPI_IMAGE_FORMAT_FALLBACK", "webp"),
+			AvifQuality: getEnv("GOAPI_AVIF_QUALITY", "60"),
+			AvifEffort:  getEnv("GOAPI_AVIF_EFFORT", "4"),
+		},
+		ImageWidths: ImageWidthsEnv{
+			Insert:    getEnv("GOAPI_IMAGE_WIDTHS_INSERT", "320,480,960"),
+			Thumbnail: getEnv("GOAPI_IMAGE_WIDTHS_THUMBNAIL", "256,1024,1600"),
+			Profile:   getEnv("GOAPI_IMAGE_WIDTHS_PROFILE", "64,128"),
+		},
 	}
 	return nil
 }
@@ -3,6 +3,7 @@ package configs
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -264,6 +265,49 @@ func TestGetImageDescriptionConfigAcceptsBoundedOverrides(t *testing.T) {
 	}
 }
 
+func TestGetImageFormatConfigNormalizesFormatsAndFallback(t *testing.T) {
+	original := Env
+	defer func() { Env = original }()
+
+	Env.ImageFormat = ImageFormatEnv{Insert: "AVIF", Thumbnail: "jpg", Profile: "png", Fallback: "avif", AvifQuality: "101", AvifEffort: "9"}
+	config := GetImageFormatConfig()
+	if config.Insert != "avif" || config.Thumbnail != "jpeg" || config.Profile != "webp" {
+		t.Fatalf("unexpected formats %+v", config)
+	}
+	if config.Fallback != "" {
+		t.Fatalf("avif cannot be its own fallback, got %q", config.Fallback)
+	}
+	if config.AvifQuality != 60 || config.AvifEffort != 9 {
+		t.Fatalf("quality = %d, effort = %d", config.AvifQuality, config.AvifEffort)
+	}
+
+	Env.ImageFormat.Fallback = ""
+	if config := GetImageFormatConfig(); config.Fallback != "webp" {
+		t.Fatalf("fallback = %q, want webp", config.Fallback)
+	}
+	Env.ImageFormat.Fallback = "None"
+	if config := GetImageFormatConfig(); config.Fallback != "" {
+		t.Fatalf("fallback = %q, want none", config.Fallback)
+	}
+}
+
+func TestGetImageWidthsConfigSortsAndBoundsWidths(t *testing.T) {
+	original := Env
+	defer func() { Env = original }()
+
+	Env.ImageWidths = ImageWidthsEnv{Insert: "960, 320,abc,320,8,480", Thumbnail: "", Profile: "1,2,3,4,5,6,7,8,9,10,16,32,64,128,256,512,1024,2048,4096"}
+	config := GetImageWidthsConfig()
+	if !slices.Equal(config.Insert, []uint{320, 480, 960}) {
+		t.Fatalf("insert widths = %v", config.Insert)
+	}
+	if len(config.Thumbnail) != 0 {
+		t.Fatalf("empty ladder should stay empty, got %v", config.Thumbnail)
+	}
+	if !slices.Equal(config.Profile, []uint{16, 32, 64, 128, 256, 512, 1024, 2048}) {
+		t.Fatalf("profile widths = %v", config.Profile)
+	}
+}
+
 func TestNotificationSenderForeignKeyReferencesUser(t *testing.T) {
 	ddl := notificationSenderForeignKeyDDL("nubo_")
 	if !strings.Contains(ddl, "REFERENCES nubo_user(uid)") {
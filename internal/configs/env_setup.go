@@ -139,6 +139,69 @@ func InstallSchema(db *sql.DB, prefix string) error {
 	if err := ensureTradeSchema(db, prefix); err != nil {
 		return err
 	}
+	if err := ensureHashtagSchema(db, prefix); err != nil {
+		return err
+	}
+	if err := createContentFilterTable(db, prefix); err != nil {
+		return err
+	}
+	if err := createContentFilterLogTable(db, prefix); err != nil {
+		return err
+	}
+	if err := createSpamLogTable(db, prefix); err != nil {
+		return err
+	}
+	if err := ensureReportSchema(db, prefix); err != nil {
+		return err
+	}
+	if err := ensureApprovalSchema(db, prefix); err != nil {
+		return err
+	}
+	if err := ensureRoleSchema(db, prefix); err != nil {
+		return err
+	}
+	if err := createPostShareTable(db, prefix); err != nil {
+		return err
+	}
+	if err := createPostShareLogTable(db, prefix); err != nil {
+		return err
+	}
+	if err := ensureCommentThreadSchema(db, prefix); err != nil {
+		return err
+	}
+	if err := createCommentHistoryTable(db, prefix); err != nil {
+		return err
+	}
+	if err := createLinkPreviewTable(db, prefix); err != nil {
+		return err
+	}
+	if err := createPostViewTable(db, prefix); err != nil {
+		return err
+	}
+	if err := createPostAnalyticsTables(db, prefix); err != nil {
+		return err
+	}
+	if err := ensureReadEventSchema(db, prefix); err != nil {
+		return err
+	}
+	if err := createImportTables(db, prefix); err != nil {
+		return err
+	}
+	if err := createActivityPubTables(db, prefix); err != nil {
+		return err
+	}
+	if err := createSyncChangeTable(db, prefix); err != nil {
+		return err
+	}
+	if err := createSyncConsumerTables(db, prefix); err != nil {
+		return err
+	}
+	if err := ensureImageFallbackSchema(db, prefix); err != nil {
+		return err
+	}
+	if err := createImageVariantTable(db, prefix); err != nil {
+		return err
+	}
 	var count uint
 	err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
 		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'skin_key'`, prefix+"board").Scan(&count)
@@ -151,6 +214,189 @@ func InstallSchema(db *sql.DB, prefix string) error {
 	return err
 }
 
+// 회원 역할, 역할 구성원, 게시판 역할별 권한 테이블 추가
+func ensureRoleSchema(db *sql.DB, prefix string) error {
+	if err := createRoleTable(db, prefix); err != nil {
+		return err
+	}
+	if err := createRoleMemberTable(db, prefix); err != nil {
+		return err
+	}
+	return createBoardAclTable(db, prefix)
+}
+
+// 게시판별 승인 필요 여부 컬럼과 승인 대기열 테이블 추가
+func ensureApprovalSchema(db *sql.DB, prefix string) error {
+	if err := createPostApprovalTable(db, prefix); err != nil {
+		return err
+	}
+	var count uint
+	err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'require_approval'`, prefix+"board").Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		_, err = db.Exec(fmt.Sprintf("ALTER TABLE %sboard ADD COLUMN require_approval TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER point_download", prefix))
+	}
+	return err
+}
+
+// AVIF 썸네일, 본문 삽입 이미지, 프로필 이미지의 대체 형식(WebP/JPEG) 경로 컬럼 추가
+func ensureImageFallbackSchema(db *sql.DB, prefix string) error {
+	for _, column := range []struct {
+		table string
+		name  string
+		ddl   string
+	}{
+		{"file_thumbnail", "fallback_path", "VARCHAR(300) NOT NULL DEFAULT '' AFTER full_path"},
+		{"file_thumbnail", "fallback_full_path", "VARCHAR(300) NOT NULL DEFAULT '' AFTER fallback_path"},
+		{"image", "fallback_path", "VARCHAR(300) NOT NULL DEFAULT '' AFTER path"},
+		{"user", "profile_fallback", "VARCHAR(300) NOT NULL DEFAULT '' AFTER profile"},
+	} {
+		var count uint
+		err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?`, prefix+column.table, column.name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s%s ADD COLUMN %s %s", prefix, column.table, column.name, column.ddl)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 조회수가 오른 열람인지 구분하는 컬럼 추가 (기존 이벤트는 모두 조회수가 오른 것으로 봄)
+func ensureReadEventSchema(db *sql.DB, prefix string) error {
+	var count uint
+	err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'counted'`, prefix+"post_read_event").Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		_, err = db.Exec(fmt.Sprintf("ALTER TABLE %spost_read_event ADD COLUMN counted TINYINT UNSIGNED NOT NULL DEFAULT 1 AFTER referrer_host", prefix))
+	}
+	return err
+}
+
+// 다단계 댓글을 위한 부모, 깊이, 경로 컬럼과 게시판별 댓글 설정 컬럼 추가 (기존 댓글은 경로를 채워 넣음)
+func ensureCommentThreadSchema(db *sql.DB, prefix string) error {
+	for _, column := range []struct {
+		table string
+		name  string
+		ddl   string
+	}{
+		{prefix + "comment", "parent_uid", "INT UNSIGNED NOT NULL DEFAULT 0 AFTER reply_uid"},
+		{prefix + "comment", "depth", "TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER parent_uid"},
+		{prefix + "comment", "path", "VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '' AFTER depth"},
+		{prefix + "board", "comment_depth", "TINYINT UNSIGNED NOT NULL DEFAULT 1 AFTER require_approval"},
+		{prefix + "board", "public_comment_history", "TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER comment_depth"},
+		{prefix + "board", "feed_summary", "TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER public_comment_history"},
+		{prefix + "board", "sync_scope", "TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER feed_summary"},
+	} {
+		var count uint
+		err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?`, column.table, column.name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", column.table, column.name, column.ddl)); err != nil {
+				return err
+			}
+		}
+	}
+
+	table := prefix + "comment"
+	var indexCount uint
+	err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = 'idx_comment_path'`, table).Scan(&indexCount)
+	if err != nil {
+		return err
+	}
+	if indexCount == 0 {
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD KEY idx_comment_path (post_uid, path), ADD KEY (parent_uid)", table)); err != nil {
+			return err
+		}
+	}
+
+	// 최상위 댓글(reply_uid가 자기 자신)부터 경로를 채우고, 답글은 부모 경로를 이어 붙임
+	if _, err := db.Exec(fmt.Sprintf(`UPDATE %s SET parent_uid = 0, depth = 0, path = CONCAT(LPAD(uid, 10, '0'), '/')
+		WHERE path = '' AND (reply_uid = uid OR reply_uid = 0)`, table)); err != nil {
+		return err
+	}
+	for range 10 {
+		result, err := db.Exec(fmt.Sprintf(`UPDATE %s c JOIN %s p ON p.uid = c.reply_uid
+			SET c.parent_uid = p.uid, c.depth = p.depth + 1, c.path = CONCAT(p.path, LPAD(c.uid, 10, '0'), '/')
+			WHERE c.path = '' AND p.path != ''`, table, table))
+		if err != nil {
+			return err
+		}
+		if changed, _ := result.RowsAffected(); changed == 0 {
+			break
+		}
+	}
+	_, err = db.Exec(fmt.Sprintf(`UPDATE %s SET parent_uid = 0, depth = 0, path = CONCAT(LPAD(uid, 10, '0'), '/')
+		WHERE path = ''`, table))
+	return err
+}
+
+// 게시글/댓글 신고를 위한 대상, 사유, 자동 숨김(과 숨기기 전 상태) 컬럼 추가
+func ensureReportSchema(db *sql.DB, prefix string) error {
+	table := prefix + "report"
+	for _, column := range []struct {
+		name string
+		ddl  string
+	}{
+		{"target_type", "TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER from_uid"},
+		{"target_uid", "INT UNSIGNED NOT NULL DEFAULT 0 AFTER target_type"},
+		{"reason", "TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER target_uid"},
+		{"hidden", "TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER solved"},
+		{"hidden_status", "TINYINT NOT NULL DEFAULT 0 AFTER hidden"},
+	} {
+		var count uint
+		err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
+			WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?`, table, column.name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column.name, column.ddl)); err != nil {
+				return err
+			}
+		}
+	}
+
+	var indexCount uint
+	err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = 'idx_report_target'`, table).Scan(&indexCount)
+	if err != nil {
+		return err
+	}
+	if indexCount == 0 {
+		_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD KEY idx_report_target (target_type, target_uid)", table))
+	}
+	return err
+}
+
+// 관리자가 금지한 태그를 추천 목록에서 제외하기 위한 banned 컬럼 추가
+func ensureHashtagSchema(db *sql.DB, prefix string) error {
+	var count uint
+	err := db.QueryRow(`SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = 'banned'`, prefix+"hashtag").Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		_, err = db.Exec(fmt.Sprintf("ALTER TABLE %shashtag ADD COLUMN banned TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER timestamp", prefix))
+	}
+	return err
+}
+
 func ensureTradeSchema(db *sql.DB, prefix string) error {
 	if err := createTradeTable(db, prefix); err != nil {
 		return err
@@ -541,6 +787,24 @@ func createTables(db *sql.DB, dbInfo DBInfo) {
 	createTradeTable(db, dbInfo.Prefix)
 	_ = createMailCampaignTable(db, dbInfo.Prefix)
 	_ = createMailDeliveryTable(db, dbInfo.Prefix)
+	_ = createContentFilterTable(db, dbInfo.Prefix)
+	_ = createContentFilterLogTable(db, dbInfo.Prefix)
+	_ = createSpamLogTable(db, dbInfo.Prefix)
+	_ = createPostApprovalTable(db, dbInfo.Prefix)
+	_ = createRoleTable(db, dbInfo.Prefix)
+	_ = createRoleMemberTable(db, dbInfo.Prefix)
+	_ = createBoardAclTable(db, dbInfo.Prefix)
+	_ = createPostShareTable(db, dbInfo.Prefix)
+	_ = createPostShareLogTable(db, dbInfo.Prefix)
+	_ = createCommentHistoryTable(db, dbInfo.Prefix)
+	_ = createLinkPreviewTable(db, dbInfo.Prefix)
+	_ = createPostViewTable(db, dbInfo.Prefix)
+	_ = createPostAnalyticsTables(db, dbInfo.Prefix)
+	_ = createImportTables(db, dbInfo.Prefix)
+	_ = createActivityPubTables(db, dbInfo.Prefix)
+	_ = createSyncChangeTable(db, dbInfo.Prefix)
+	_ = createSyncConsumerTables(db, dbInfo.Prefix)
+	_ = createImageVariantTable(db, dbInfo.Prefix)
 }
 
 // 사용자별 FCM 등록 토큰을 저장한다. 토큰은 계정 전환 시 한 사용자에게만 귀속된다.
@@ -578,6 +842,7 @@ func createUserTable(db *sql.DB, prefix string) {
   name VARCHAR(30) NOT NULL DEFAULT '',
   password CHAR(64) NOT NULL DEFAULT '',
   profile VARCHAR(300) NOT NULL DEFAULT '',
+  profile_fallback VARCHAR(300) NOT NULL DEFAULT '',
   level TINYINT UNSIGNED NOT NULL DEFAULT 0,
   point INT UNSIGNED NOT NULL DEFAULT 0,
   signature VARCHAR(300) NOT NULL DEFAULT '',
@@ -675,12 +940,18 @@ func createReportTable(db *sql.DB, prefix string) {
   uid INT UNSIGNED NOT NULL auto_increment,
   to_uid INT UNSIGNED NOT NULL DEFAULT 0,
   from_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  target_type TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  target_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  reason TINYINT UNSIGNED NOT NULL DEFAULT 0,
   request VARCHAR(1000) NOT NULL DEFAULT '',
   response VARCHAR(1000) NOT NULL DEFAULT '',
   timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
   solved TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  hidden TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  hidden_status TINYINT NOT NULL DEFAULT 0,
   PRIMARY KEY (uid),
-  KEY (solved)
+  KEY (solved),
+  KEY idx_report_target (target_type, target_uid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
 	db.Exec(query)
 }
@@ -737,6 +1008,11 @@ func createBoardTable(db *sql.DB, prefix string) {
   point_write INT NOT NULL DEFAULT 0,
   point_comment INT NOT NULL DEFAULT 0,
   point_download INT NOT NULL DEFAULT 0,
+  require_approval TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  comment_depth TINYINT UNSIGNED NOT NULL DEFAULT 1,
+  public_comment_history TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  feed_summary TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  sync_scope TINYINT UNSIGNED NOT NULL DEFAULT 0,
   PRIMARY KEY (uid),
   CONSTRAINT fk_bg FOREIGN KEY (group_uid) REFERENCES %sgroup(uid),
   CONSTRAINT fk_ba FOREIGN KEY (admin_uid) REFERENCES %suser(uid)
@@ -820,6 +1096,7 @@ func createHashtagTable(db *sql.DB, prefix string) {
   name VARCHAR(30) NOT NULL DEFAULT '',
   used INT UNSIGNED NOT NULL DEFAULT 0,
   timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  banned TINYINT UNSIGNED NOT NULL DEFAULT 0,
   PRIMARY KEY (uid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
 	db.Exec(query)
@@ -864,6 +1141,9 @@ func createCommentTable(db *sql.DB, prefix string) {
 	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %scomment (
   uid INT UNSIGNED NOT NULL auto_increment,
   reply_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  parent_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  depth TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  path VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
   board_uid INT UNSIGNED NOT NULL DEFAULT 0,
   post_uid INT UNSIGNED NOT NULL DEFAULT 0,
   user_uid INT UNSIGNED NOT NULL DEFAULT 0,
@@ -873,6 +1153,8 @@ func createCommentTable(db *sql.DB, prefix string) {
   status TINYINT NOT NULL DEFAULT 0,
   PRIMARY KEY (uid),
   KEY (reply_uid),
+  KEY (parent_uid),
+  KEY idx_comment_path (post_uid, path),
   KEY (board_uid),
   KEY (post_uid),
   KEY (user_uid),
@@ -928,6 +1210,8 @@ func createFileThumbnailTable(db *sql.DB, prefix string) {
   post_uid INT UNSIGNED NOT NULL DEFAULT 0,
   path VARCHAR(300) NOT NULL DEFAULT '',
   full_path VARCHAR(300) NOT NULL DEFAULT '',
+  fallback_path VARCHAR(300) NOT NULL DEFAULT '',
+  fallback_full_path VARCHAR(300) NOT NULL DEFAULT '',
   PRIMARY KEY (uid),
   KEY (file_uid),
   KEY (post_uid),
@@ -944,6 +1228,7 @@ func createImageTable(db *sql.DB, prefix string) {
   board_uid INT UNSIGNED NOT NULL DEFAULT 0,
   user_uid INT UNSIGNED NOT NULL DEFAULT 0,
   path VARCHAR(300) NOT NULL DEFAULT '',
+  fallback_path VARCHAR(300) NOT NULL DEFAULT '',
   timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
   PRIMARY KEY (uid),
   KEY (user_uid),
@@ -1190,3 +1475,440 @@ func insertDefaultGalleryCategory(db *sql.DB, prefix string) {
 	query = fmt.Sprintf("INSERT INTO %sboard_category (board_uid, name) VALUES (?, ?)", prefix)
 	db.Exec(query, 2, "portrait")
 }
+
+// content_filter 테이블 생성 (관리자가 등록한 금지어/링크 필터 목록)
+func createContentFilterTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %scontent_filter (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  pattern VARCHAR(200) NOT NULL DEFAULT '',
+  match_type TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  action TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  enabled TINYINT UNSIGNED NOT NULL DEFAULT 1,
+  created BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// content_filter_log 테이블 생성 (필터 적중 기록, 규칙 조정용)
+func createContentFilterLogTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %scontent_filter_log (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  filter_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  target TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  action TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  user_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  excerpt VARCHAR(300) NOT NULL DEFAULT '',
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  KEY (filter_uid),
+  KEY (timestamp)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 스팸 점수 기록 테이블 생성 (작성 속도, 중복 내용, 반복 위반 판단에 사용)
+func createSpamLogTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %sspam_log (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  user_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  target TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+  score SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  verdict TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  KEY (user_uid, timestamp),
+  KEY (hash, timestamp),
+  KEY (timestamp)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 승인 대기 게시글 테이블 생성 (승인 후 적용할 원래 상태 보관)
+func createPostApprovalTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %spost_approval (
+  post_uid INT UNSIGNED NOT NULL,
+  board_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  user_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  status TINYINT NOT NULL DEFAULT 0,
+  submitted BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (post_uid),
+  KEY (board_uid, submitted),
+  KEY (user_uid),
+  CONSTRAINT fk_pap FOREIGN KEY (post_uid) REFERENCES %spost(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 회원 역할(스태프 등) 테이블 생성
+func createRoleTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %srole (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  name VARCHAR(30) NOT NULL DEFAULT '',
+  info VARCHAR(100) NOT NULL DEFAULT '',
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  UNIQUE KEY (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 역할에 속한 회원 테이블 생성
+func createRoleMemberTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %srole_member (
+  role_uid INT UNSIGNED NOT NULL,
+  user_uid INT UNSIGNED NOT NULL,
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (role_uid, user_uid),
+  KEY (user_uid),
+  CONSTRAINT fk_rmr FOREIGN KEY (role_uid) REFERENCES %srole(uid) ON DELETE CASCADE,
+  CONSTRAINT fk_rmu FOREIGN KEY (user_uid) REFERENCES %suser(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix, prefix, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 게시판 활동별 역할 허용/거부 규칙 테이블 생성
+func createBoardAclTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %sboard_acl (
+  board_uid INT UNSIGNED NOT NULL,
+  role_uid INT UNSIGNED NOT NULL,
+  action TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  allow TINYINT UNSIGNED NOT NULL DEFAULT 1,
+  PRIMARY KEY (board_uid, action, role_uid),
+  KEY (role_uid),
+  CONSTRAINT fk_bab FOREIGN KEY (board_uid) REFERENCES %sboard(uid) ON DELETE CASCADE,
+  CONSTRAINT fk_bar FOREIGN KEY (role_uid) REFERENCES %srole(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix, prefix, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 비밀글 공유 링크 테이블 생성 (토큰은 해시로만 보관)
+func createPostShareTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %spost_share (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  board_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  post_uid INT UNSIGNED NOT NULL,
+  created_by INT UNSIGNED NOT NULL DEFAULT 0,
+  token_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  created BIGINT NOT NULL DEFAULT 0,
+  expires BIGINT NOT NULL DEFAULT 0,
+  max_views INT UNSIGNED NOT NULL DEFAULT 0,
+  views INT UNSIGNED NOT NULL DEFAULT 0,
+  allow_comment TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  allow_download TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  revoked TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  UNIQUE KEY (token_hash),
+  KEY (post_uid),
+  CONSTRAINT fk_psp FOREIGN KEY (post_uid) REFERENCES %spost(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 공유 링크 열람 기록 테이블 생성
+func createPostShareLogTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %spost_share_log (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  share_uid INT UNSIGNED NOT NULL,
+  user_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  ip_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+  agent VARCHAR(200) NOT NULL DEFAULT '',
+  timestamp BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  KEY (share_uid, timestamp),
+  CONSTRAINT fk_psl FOREIGN KEY (share_uid) REFERENCES %spost_share(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 댓글 수정 이력 테이블 생성 (수정 전 내용을 보관)
+func createCommentHistoryTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %scomment_history (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  comment_uid INT UNSIGNED NOT NULL,
+  board_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  user_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  content VARCHAR(10000) NOT NULL DEFAULT '',
+  written BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  KEY (comment_uid, uid),
+  CONSTRAINT fk_chc FOREIGN KEY (comment_uid) REFERENCES %scomment(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 링크 미리보기 캐시 테이블 생성 (주소는 해시로 조회)
+func createLinkPreviewTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %slink_preview (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  url_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  url VARCHAR(2048) NOT NULL DEFAULT '',
+  title VARCHAR(1200) NOT NULL DEFAULT '',
+  description VARCHAR(3000) NOT NULL DEFAULT '',
+  site_name VARCHAR(600) NOT NULL DEFAULT '',
+  image VARCHAR(300) NOT NULL DEFAULT '',
+  type VARCHAR(30) NOT NULL DEFAULT '',
+  failed TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  fetched BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  UNIQUE KEY (url_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 중복 조회를 거르기 위한 게시글별 최근 조회자 테이블 생성 (회원 번호 또는 IP·브라우저 정보를 해시로 보관)
+func createPostViewTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %spost_view (
+  post_uid INT UNSIGNED NOT NULL,
+  viewer_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  viewed BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (post_uid, viewer_hash),
+  KEY (viewed)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
+	_, err := db.Exec(query)
+	return err
+}
+
+// 게시글 열람 원본 이벤트와 날짜별/유입 경로별 집계 테이블 생성 (원본은 보관 기간이 지나면 삭제되고 집계만 남음)
+func createPostAnalyticsTables(db *sql.DB, prefix string) error {
+	queries := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %spost_read_event (
+  uid BIGINT UNSIGNED NOT NULL auto_increment,
+  post_uid INT UNSIGNED NOT NULL,
+  viewer_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  source TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  referrer_host VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+  counted TINYINT UNSIGNED NOT NULL DEFAULT 1,
+  token_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  duration INT UNSIGNED NOT NULL DEFAULT 0,
+  day INT UNSIGNED NOT NULL,
+  timestamp BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  KEY (day, post_uid),
+  KEY (token_hash),
+  KEY (timestamp)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %spost_stat_daily (
+  post_uid INT UNSIGNED NOT NULL,
+  day INT UNSIGNED NOT NULL,
+  views INT UNSIGNED NOT NULL DEFAULT 0,
+  readers INT UNSIGNED NOT NULL DEFAULT 0,
+  read_count INT UNSIGNED NOT NULL DEFAULT 0,
+  read_seconds BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (post_uid, day)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %spost_stat_referrer (
+  post_uid INT UNSIGNED NOT NULL,
+  day INT UNSIGNED NOT NULL,
+  source TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  host VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+  views INT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (post_uid, day, source, host)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+	}
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 외부 블로그 가져오기 작업 기록과 원본 항목 ↔ 가져온 레코드 연결 테이블 생성 (다시 실행해도 중복 생성하지 않도록)
+func createImportTables(db *sql.DB, prefix string) error {
+	queries := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %simport_job (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  board_uid INT UNSIGNED NOT NULL,
+  user_uid INT UNSIGNED NOT NULL,
+  source VARCHAR(500) NOT NULL DEFAULT '',
+  status VARCHAR(20) NOT NULL DEFAULT 'running',
+  report MEDIUMTEXT NOT NULL,
+  last_error VARCHAR(1000) NOT NULL DEFAULT '',
+  created BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  updated BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  KEY (board_uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %simport_map (
+  board_uid INT UNSIGNED NOT NULL,
+  source_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  kind VARCHAR(20) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  key_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  target_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  target_path VARCHAR(300) NOT NULL DEFAULT '',
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (board_uid, source_hash, kind, key_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+	}
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ActivityPub 연합용 테이블 생성 (게시판 서명 키, 원격 액터, 팔로워, 발송 대기열, 받은 활동)
+func createActivityPubTables(db *sql.DB, prefix string) error {
+	queries := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %sactivitypub_key (
+  board_uid INT UNSIGNED NOT NULL,
+  public_key TEXT NOT NULL,
+  private_key TEXT NOT NULL,
+  created BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (board_uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %sactivitypub_actor (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  actor_id VARCHAR(500) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  key_id VARCHAR(500) NOT NULL DEFAULT '',
+  public_key TEXT NOT NULL,
+  inbox VARCHAR(500) NOT NULL DEFAULT '',
+  shared_inbox VARCHAR(500) NOT NULL DEFAULT '',
+  name VARCHAR(100) NOT NULL DEFAULT '',
+  url VARCHAR(500) NOT NULL DEFAULT '',
+  fetched BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  UNIQUE KEY (actor_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %sactivitypub_follower (
+  board_uid INT UNSIGNED NOT NULL,
+  actor_uid INT UNSIGNED NOT NULL,
+  follow_id VARCHAR(500) NOT NULL DEFAULT '',
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (board_uid, actor_uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %sactivitypub_delivery (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  board_uid INT UNSIGNED NOT NULL,
+  inbox VARCHAR(500) NOT NULL,
+  activity MEDIUMTEXT NOT NULL,
+  status TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  attempts INT UNSIGNED NOT NULL DEFAULT 0,
+  next_attempt BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  last_error VARCHAR(500) NOT NULL DEFAULT '',
+  created BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  KEY (status, next_attempt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %sactivitypub_activity (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  board_uid INT UNSIGNED NOT NULL,
+  post_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  comment_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  actor_uid INT UNSIGNED NOT NULL,
+  type VARCHAR(20) NOT NULL,
+  activity_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  activity_id VARCHAR(500) NOT NULL DEFAULT '',
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (uid),
+  UNIQUE KEY (activity_hash),
+  KEY (post_uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+	}
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 동기화 변경 피드용 게시글 변경 기록 테이블 생성 (처음 만들 때 기존 게시글들을 작성 기록으로 채움)
+func createSyncChangeTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %ssync_change (
+  seq BIGINT UNSIGNED NOT NULL auto_increment,
+  board_uid INT UNSIGNED NOT NULL,
+  post_uid INT UNSIGNED NOT NULL,
+  action TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  timestamp BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  PRIMARY KEY (seq),
+  KEY (board_uid, seq),
+  KEY (post_uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
+	if _, err := db.Exec(query); err != nil {
+		return err
+	}
+	var count uint
+	if err := db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %ssync_change", prefix)).Scan(&count); err != nil || count > 0 {
+		return err
+	}
+	_, err := db.Exec(fmt.Sprintf(`INSERT INTO %ssync_change (board_uid, post_uid, action, timestamp)
+		SELECT board_uid, uid, 0, GREATEST(submitted, modified) FROM %spost WHERE status != ? ORDER BY uid ASC`,
+		prefix, prefix), -1) // -1: 삭제된 글 (models.CONTENT_REMOVED)
+	return err
+}
+
+// 다른 NUBO의 글을 가져오는 동기화 원본(읽은 위치, 최근 결과)과 실행 기록 테이블 생성
+func createSyncConsumerTables(db *sql.DB, prefix string) error {
+	queries := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %ssync_source (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  source_hash CHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+  remote VARCHAR(300) NOT NULL DEFAULT '',
+  board_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  cursor_seq BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  created INT UNSIGNED NOT NULL DEFAULT 0,
+  modified INT UNSIGNED NOT NULL DEFAULT 0,
+  removed INT UNSIGNED NOT NULL DEFAULT 0,
+  last_run BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  last_success BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  last_error VARCHAR(500) NOT NULL DEFAULT '',
+  PRIMARY KEY (uid),
+  UNIQUE KEY (source_hash, board_uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %ssync_log (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  source_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  started BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  finished BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  from_seq BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  to_seq BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  created INT UNSIGNED NOT NULL DEFAULT 0,
+  modified INT UNSIGNED NOT NULL DEFAULT 0,
+  removed INT UNSIGNED NOT NULL DEFAULT 0,
+  images INT UNSIGNED NOT NULL DEFAULT 0,
+  status VARCHAR(20) NOT NULL DEFAULT '',
+  error VARCHAR(500) NOT NULL DEFAULT '',
+  PRIMARY KEY (uid),
+  KEY (source_uid, uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix),
+	}
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 반응형 이미지(srcset) 변형 테이블 생성 (source는 첨부 썸네일, 본문 삽입 이미지, 프로필 이미지의 공개 경로)
+func createImageVariantTable(db *sql.DB, prefix string) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %simage_variant (
+  uid INT UNSIGNED NOT NULL auto_increment,
+  file_uid INT UNSIGNED NOT NULL DEFAULT 0,
+  source VARCHAR(300) NOT NULL DEFAULT '',
+  path VARCHAR(300) NOT NULL DEFAULT '',
+  width SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+  type VARCHAR(20) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '',
+  PRIMARY KEY (uid),
+  KEY (file_uid),
+  KEY idx_image_variant_source (source(191))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`, prefix)
+	_, err := db.Exec(query)
+	return err
+}
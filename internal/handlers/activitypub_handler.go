@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/sirini/goapi/internal/services"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type ActivityPubHandler interface {
+	ActorHandler(c fiber.Ctx) error
+	FollowersHandler(c fiber.Ctx) error
+	InboxHandler(c fiber.Ctx) error
+	NoteHandler(c fiber.Ctx) error
+	OutboxHandler(c fiber.Ctx) error
+	WebFingerHandler(c fiber.Ctx) error
+}
+
+type NuboActivityPubHandler struct {
+	service *services.Service
+}
+
+// services.Service 주입 받기
+func NewNuboActivityPubHandler(service *services.Service) *NuboActivityPubHandler {
+	return &NuboActivityPubHandler{service: service}
+}
+
+// ActivityPub 오류를 상태 코드로 바꿔 응답하기
+func sendActivityPubError(c fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrActivityPubNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	case errors.Is(err, services.ErrActivityPubUnauthorized):
+		return c.SendStatus(fiber.StatusUnauthorized)
+	case errors.Is(err, services.ErrActivityPubInvalid):
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+	return c.SendStatus(fiber.StatusInternalServerError)
+}
+
+// 게시판 액터 문서 핸들러
+func (h *NuboActivityPubHandler) ActorHandler(c fiber.Ctx) error {
+	actor, err := h.service.ActivityPub.GetActor(c.Params("id"))
+	if err != nil {
+		return sendActivityPubError(c, err)
+	}
+	return c.JSON(actor, models.AP_CONTENT_TYPE)
+}
+
+// 게시판 팔로워 컬렉션 핸들러
+func (h *NuboActivityPubHandler) FollowersHandler(c fiber.Ctx) error {
+	followers, err := h.service.ActivityPub.GetFollowers(c.Params("id"))
+	if err != nil {
+		return sendActivityPubError(c, err)
+	}
+	return c.JSON(followers, models.AP_CONTENT_TYPE)
+}
+
+// 게시판 받은편지함 핸들러 (서명 확인 후 202 응답)
+func (h *NuboActivityPubHandler) InboxHandler(c fiber.Ctx) error {
+	headers := make(map[string]string)
+	for name, values := range c.GetReqHeaders() {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+	headers["host"] = string(c.Request().Header.Host())
+
+	err := h.service.ActivityPub.HandleInbox(c.Params("id"), models.ApInboxRequest{
+		Method:  c.Method(),
+		Path:    c.OriginalURL(),
+		Headers: headers,
+		Body:    append([]byte(nil), c.Body()...),
+	})
+	if err != nil {
+		return sendActivityPubError(c, err)
+	}
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// 게시글 Note 핸들러
+func (h *NuboActivityPubHandler) NoteHandler(c fiber.Ctx) error {
+	postUid, err := strconv.ParseUint(c.Params("uid"), 10, 32)
+	if err != nil {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	note, err := h.service.ActivityPub.GetNote(c.Params("id"), uint(postUid))
+	if err != nil {
+		return sendActivityPubError(c, err)
+	}
+	return c.JSON(note, models.AP_CONTENT_TYPE)
+}
+
+// 게시판 outbox 핸들러
+func (h *NuboActivityPubHandler) OutboxHandler(c fiber.Ctx) error {
+	outbox, err := h.service.ActivityPub.GetOutbox(c.Params("id"))
+	if err != nil {
+		return sendActivityPubError(c, err)
+	}
+	return c.JSON(outbox, models.AP_CONTENT_TYPE)
+}
+
+// WebFinger 핸들러 (acct:게시판아이디@도메인)
+func (h *NuboActivityPubHandler) WebFingerHandler(c fiber.Ctx) error {
+	finger, err := h.service.ActivityPub.GetWebFinger(c.Query("resource"))
+	if err != nil {
+		return sendActivityPubError(c, err)
+	}
+	return c.JSON(finger, models.AP_JRD_TYPE)
+}
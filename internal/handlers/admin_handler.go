@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"io"
 	"net/url"
 	"strconv"
 	"strings"
@@ -13,7 +14,9 @@ import (
 )
 
 type AdminHandler interface {
+	BoardExportHandler(c fiber.Ctx) error
 	BoardGeneralLoadHandler(c fiber.Ctx) error
+	BoardImportHandler(c fiber.Ctx) error
 	ChangeGroupAdminHandler(c fiber.Ctx) error
 	ChangeGroupIdHandler(c fiber.Ctx) error
 	CreateBoardHandler(c fiber.Ctx) error
@@ -22,6 +25,8 @@ type AdminHandler interface {
 	DashboardUploadUsageHandler(c fiber.Ctx) error
 	DashboardItemLoadHandler(c fiber.Ctx) error
 	DashboardStatisticLoadHandler(c fiber.Ctx) error
+	DashboardSyncRunHandler(c fiber.Ctx) error
+	DashboardSyncStatusHandler(c fiber.Ctx) error
 	GetAdminCandidatesHandler(c fiber.Ctx) error
 	GroupGeneralLoadHandler(c fiber.Ctx) error
 	GroupListLoadHandler(c fiber.Ctx) error
@@ -54,6 +59,27 @@ type AdminHandler interface {
 	SkinSettingsLoadHandler(c fiber.Ctx) error
 	SkinSettingModifyHandler(c fiber.Ctx) error
 	ReportResolveHandler(c fiber.Ctx) error
+	HashtagListHandler(c fiber.Ctx) error
+	HashtagRenameHandler(c fiber.Ctx) error
+	HashtagMergeHandler(c fiber.Ctx) error
+	HashtagBanHandler(c fiber.Ctx) error
+	HashtagRecountHandler(c fiber.Ctx) error
+	HashtagCleanupHandler(c fiber.Ctx) error
+	ImportJobListHandler(c fiber.Ctx) error
+	ImportJobLoadHandler(c fiber.Ctx) error
+	WordPressImportHandler(c fiber.Ctx) error
+	ContentFilterListHandler(c fiber.Ctx) error
+	ContentFilterSaveHandler(c fiber.Ctx) error
+	ContentFilterRemoveHandler(c fiber.Ctx) error
+	ContentFilterLogListHandler(c fiber.Ctx) error
+	ContentReportListHandler(c fiber.Ctx) error
+	ContentReportResolveHandler(c fiber.Ctx) error
+	RoleListHandler(c fiber.Ctx) error
+	RoleSaveHandler(c fiber.Ctx) error
+	RoleRemoveHandler(c fiber.Ctx) error
+	RoleMemberListHandler(c fiber.Ctx) error
+	RoleMemberAddHandler(c fiber.Ctx) error
+	RoleMemberRemoveHandler(c fiber.Ctx) error
 }
 
 func (h *NuboAdminHandler) SignupInviteListHandler(c fiber.Ctx) error {
@@ -105,6 +131,123 @@ func (h *NuboAdminHandler) MailDeliveryListHandler(c fiber.Ctx) error {
 	return utils.Ok(c, result)
 }
 
+// 게시판 보관 파일(zip) 내려받기 핸들러
+func (h *NuboAdminHandler) BoardExportHandler(c fiber.Ctx) error {
+	boardId := c.Query("id")
+	boardUid := h.service.Board.GetBoardUid(boardId)
+	if boardUid < 1 {
+		return utils.Err(c, "Invalid board id, cannot find a board", models.CODE_INVALID_PARAMETER)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(h.service.Archive.ExportBoard(boardUid, writer))
+	}()
+	c.Attachment(fmt.Sprintf("%s.zip", boardId))
+	return c.SendStream(reader)
+}
+
+// 게시판 보관 파일로 새 게시판 만들기 핸들러 (id, group이 비어 있으면 보관 파일의 값 사용)
+func (h *NuboAdminHandler) BoardImportHandler(c fiber.Ctx) error {
+	header, err := c.FormFile("archive")
+	if err != nil {
+		return utils.Err(c, "board archive file is required", models.CODE_INVALID_PARAMETER)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	defer file.Close()
+
+	result, err := h.service.Archive.ImportBoard(file, header.Size, models.BoardArchiveImportParam{
+		ActorUid: uint(utils.ExtractUserUid(c.Get(models.AUTH_KEY))),
+		BoardId:  strings.TrimSpace(c.FormValue("id")),
+		GroupId:  strings.TrimSpace(c.FormValue("group")),
+	})
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 다른 NUBO에서 글을 가져오는 동기화 설정, 원본별 상태, 최근 실행 기록 핸들러
+func (h *NuboAdminHandler) DashboardSyncStatusHandler(c fiber.Ctx) error {
+	limit, _ := strconv.ParseUint(c.Query("limit", "20"), 10, 32)
+	result, err := h.service.Sync.GetConsumerStatus(uint(limit))
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 다음 주기를 기다리지 않고 바로 원격 변경 내역 가져오기 핸들러
+func (h *NuboAdminHandler) DashboardSyncRunHandler(c fiber.Ctx) error {
+	result, err := h.service.Sync.PullRemote()
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 최근 가져오기 작업 목록 핸들러
+func (h *NuboAdminHandler) ImportJobListHandler(c fiber.Ctx) error {
+	limit, _ := strconv.ParseUint(c.Query("limit", "20"), 10, 32)
+	result, err := h.service.Import.GetImportJobs(uint(limit))
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 가져오기 작업 진행 상태와 보고서 핸들러
+func (h *NuboAdminHandler) ImportJobLoadHandler(c fiber.Ctx) error {
+	uid, err := strconv.ParseUint(c.Params("uid"), 10, 32)
+	if err != nil || uid < 1 {
+		return utils.Err(c, "invalid import job id", models.CODE_INVALID_PARAMETER)
+	}
+	result, err := h.service.Import.GetImportJob(uint(uid))
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 워드프레스 내보내기(WXR) 파일을 블로그 게시판으로 가져오는 핸들러 (dryRun=true면 보고서만 반환)
+func (h *NuboAdminHandler) WordPressImportHandler(c fiber.Ctx) error {
+	boardUid := h.service.Board.GetBoardUid(c.FormValue("id"))
+	if boardUid < 1 {
+		return utils.Err(c, "Invalid board id, cannot find a board", models.CODE_INVALID_PARAMETER)
+	}
+	dryRun, err := strconv.ParseBool(c.FormValue("dryRun", "false"))
+	if err != nil {
+		return utils.Err(c, "invalid dryRun value", models.CODE_INVALID_PARAMETER)
+	}
+	header, err := c.FormFile("wxr")
+	if err != nil {
+		return utils.Err(c, "WXR file is required", models.CODE_INVALID_PARAMETER)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	defer file.Close()
+	site, err := utils.ParseWXR(file)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+
+	result, err := h.service.Import.ImportWordPress(models.WordPressImportParam{
+		BoardUid: boardUid,
+		UserUid:  uint(utils.ExtractUserUid(c.Get(models.AUTH_KEY))),
+		DryRun:   dryRun,
+		Site:     site,
+	})
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
 func (h *NuboAdminHandler) MailCampaignListHandler(c fiber.Ctx) error {
 	limit, _ := strconv.ParseUint(c.Query("limit", "20"), 10, 32)
 	result, err := h.service.Admin.GetMailCampaigns(uint(limit))
@@ -219,6 +362,42 @@ func (h *NuboAdminHandler) ReportResolveHandler(c fiber.Ctx) error {
 	return utils.Ok(c, nil)
 }
 
+// 대상별로 묶인 게시글/댓글 신고 목록 핸들러
+func (h *NuboAdminHandler) ContentReportListHandler(c fiber.Ctx) error {
+	param := models.AdminContentReportParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if param.Page < 1 {
+		param.Page = 1
+	}
+	if param.Limit < 1 || param.Limit > 100 {
+		param.Limit = 15
+	}
+
+	result, err := h.service.Admin.GetContentReports(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 게시글/댓글 신고 처리 핸들러
+func (h *NuboAdminHandler) ContentReportResolveHandler(c fiber.Ctx) error {
+	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
+	param := models.AdminContentReportResolveParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if param.TargetUid < 1 {
+		return utils.Err(c, "Invalid target uid, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Admin.ResolveContentReport(uint(actionUserUid), param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
 type NuboAdminHandler struct {
 	service *services.Service
 }
@@ -250,9 +429,24 @@ func (h *NuboAdminHandler) BoardGeneralLoadHandler(c fiber.Ctx) error {
 		pairs = append(pairs, pair)
 	}
 
+	roles, err := h.service.Admin.GetRoles()
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	rolePairs := make([]models.Pair, 0, len(roles))
+	for _, role := range roles {
+		rolePairs = append(rolePairs, models.Pair{Uid: role.Uid, Name: role.Name})
+	}
+	acl, err := h.service.Admin.GetBoardAcl(boardUid)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+
 	return utils.Ok(c, models.AdminBoardResult{
 		Config: config,
 		Groups: pairs,
+		Roles:  rolePairs,
+		Acl:    acl,
 	})
 }
 
@@ -625,3 +819,208 @@ func (h *NuboAdminHandler) UserListLoadHandler(c fiber.Ctx) error {
 	result := h.service.Admin.GetUserList(param)
 	return utils.Ok(c, result)
 }
+
+// 해시태그 관리 목록 가져오기 핸들러
+func (h *NuboAdminHandler) HashtagListHandler(c fiber.Ctx) error {
+	param := models.AdminHashtagSearchParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if param.Page < 1 {
+		param.Page = 1
+	}
+	if param.Limit < 1 || param.Limit > 100 {
+		param.Limit = 30
+	}
+
+	result, err := h.service.Admin.GetHashtagList(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 해시태그 이름 변경 핸들러
+func (h *NuboAdminHandler) HashtagRenameHandler(c fiber.Ctx) error {
+	param := models.AdminHashtagRenameParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Admin.RenameHashtag(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 해시태그 병합 핸들러
+func (h *NuboAdminHandler) HashtagMergeHandler(c fiber.Ctx) error {
+	param := models.AdminHashtagMergeParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Admin.MergeHashtags(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 해시태그 금지 설정 핸들러
+func (h *NuboAdminHandler) HashtagBanHandler(c fiber.Ctx) error {
+	param := models.AdminHashtagBanParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Admin.BanHashtag(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 해시태그 사용 횟수 재계산 핸들러
+func (h *NuboAdminHandler) HashtagRecountHandler(c fiber.Ctx) error {
+	recounted, err := h.service.Admin.RecountHashtags()
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, models.AdminHashtagCleanupResult{Recounted: recounted})
+}
+
+// 게시글 없는 해시태그 정리 핸들러
+func (h *NuboAdminHandler) HashtagCleanupHandler(c fiber.Ctx) error {
+	result, err := h.service.Admin.CleanupHashtags()
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 금지어 필터 목록 가져오기 핸들러
+func (h *NuboAdminHandler) ContentFilterListHandler(c fiber.Ctx) error {
+	items, err := h.service.Admin.GetContentFilters()
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, items)
+}
+
+// 금지어 필터 추가/수정 핸들러
+func (h *NuboAdminHandler) ContentFilterSaveHandler(c fiber.Ctx) error {
+	param := models.ContentFilterSaveParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	filterUid, err := h.service.Admin.SaveContentFilter(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, filterUid)
+}
+
+// 금지어 필터 삭제 핸들러
+func (h *NuboAdminHandler) ContentFilterRemoveHandler(c fiber.Ctx) error {
+	filterUid, err := strconv.ParseUint(c.Query("filterUid"), 10, 32)
+	if err != nil || filterUid < 1 {
+		return utils.Err(c, "Invalid filter uid, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Admin.RemoveContentFilter(uint(filterUid)); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 금지어 필터 적중 기록 가져오기 핸들러
+func (h *NuboAdminHandler) ContentFilterLogListHandler(c fiber.Ctx) error {
+	param := models.ContentFilterLogParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if param.Page < 1 {
+		param.Page = 1
+	}
+	if param.Limit < 1 || param.Limit > 100 {
+		param.Limit = 30
+	}
+
+	result, err := h.service.Admin.GetContentFilterLogs(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 회원 역할 목록 가져오기 핸들러
+func (h *NuboAdminHandler) RoleListHandler(c fiber.Ctx) error {
+	items, err := h.service.Admin.GetRoles()
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, items)
+}
+
+// 회원 역할 추가/수정 핸들러
+func (h *NuboAdminHandler) RoleSaveHandler(c fiber.Ctx) error {
+	param := models.AdminRoleSaveParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	roleUid, err := h.service.Admin.SaveRole(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, roleUid)
+}
+
+// 회원 역할 삭제 핸들러
+func (h *NuboAdminHandler) RoleRemoveHandler(c fiber.Ctx) error {
+	roleUid, err := strconv.ParseUint(c.Query("roleUid"), 10, 32)
+	if err != nil || roleUid < 1 {
+		return utils.Err(c, "Invalid role uid, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Admin.RemoveRole(uint(roleUid)); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 역할 구성원 목록 가져오기 핸들러
+func (h *NuboAdminHandler) RoleMemberListHandler(c fiber.Ctx) error {
+	roleUid, err := strconv.ParseUint(c.Query("roleUid"), 10, 32)
+	if err != nil || roleUid < 1 {
+		return utils.Err(c, "Invalid role uid, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+	items, err := h.service.Admin.GetRoleMembers(uint(roleUid))
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, items)
+}
+
+// 역할에 회원 추가 핸들러
+func (h *NuboAdminHandler) RoleMemberAddHandler(c fiber.Ctx) error {
+	param := models.AdminRoleMemberParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if param.RoleUid < 1 || param.UserUid < 1 {
+		return utils.Err(c, "Invalid role or user uid", models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Admin.AddRoleMember(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 역할에서 회원 빼기 핸들러
+func (h *NuboAdminHandler) RoleMemberRemoveHandler(c fiber.Ctx) error {
+	param := models.AdminRoleMemberParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if param.RoleUid < 1 || param.UserUid < 1 {
+		return utils.Err(c, "Invalid role or user uid", models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Admin.RemoveRoleMember(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
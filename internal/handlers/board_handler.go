@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,7 +18,10 @@ import (
 )
 
 type BoardHandler interface {
+	ApprovalListHandler(c fiber.Ctx) error
+	ApprovePostHandler(c fiber.Ctx) error
 	BoardListHandler(c fiber.Ctx) error
+	BoardMetaHandler(c fiber.Ctx) error
 	BoardRecentTagListHandler(c fiber.Ctx) error
 	BoardViewHandler(c fiber.Ctx) error
 	DownloadHandler(c fiber.Ctx) error
@@ -23,8 +29,20 @@ type BoardHandler interface {
 	LikePostHandler(c fiber.Ctx) error
 	ListForMoveHandler(c fiber.Ctx) error
 	MovePostHandler(c fiber.Ctx) error
+	OEmbedHandler(c fiber.Ctx) error
+	PostStatsHandler(c fiber.Ctx) error
+	ReadBeaconHandler(c fiber.Ctx) error
+	RejectPostHandler(c fiber.Ctx) error
 	RemovePostHandler(c fiber.Ctx) error
+	ReportContentHandler(c fiber.Ctx) error
+	ShareCreateHandler(c fiber.Ctx) error
+	ShareListHandler(c fiber.Ctx) error
+	ShareLogListHandler(c fiber.Ctx) error
+	ShareRevokeHandler(c fiber.Ctx) error
+	SharedDownloadHandler(c fiber.Ctx) error
+	SharedViewHandler(c fiber.Ctx) error
 	TransferHandler(c fiber.Ctx) error
+	TrendingPostsHandler(c fiber.Ctx) error
 }
 
 // 다운로드 시 검증용으로 쓸 임시 토큰 구조체
@@ -121,6 +139,8 @@ func (h *NuboBoardHandler) BoardViewHandler(c fiber.Ctx) error {
 		return utils.Err(c, "Invalid parameters", models.CODE_INVALID_PARAMETER)
 	}
 	param.UserUid = uint(actionUserUid)
+	param.IP = c.IP()
+	param.Agent = c.Get(fiber.HeaderUserAgent)
 
 	boardUid := h.service.Board.GetBoardUid(param.Id)
 	if boardUid < 1 {
@@ -142,6 +162,98 @@ func (h *NuboBoardHandler) BoardViewHandler(c fiber.Ctx) error {
 	return utils.Ok(c, result)
 }
 
+// 게시글의 OpenGraph 메타데이터(제목, 설명, 대표 주소, 대표 이미지, 작성자, 작성/수정 시간, 태그) 핸들러
+func (h *NuboBoardHandler) BoardMetaHandler(c fiber.Ctx) error {
+	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
+	if actionUserUid < 0 {
+		actionUserUid = 0
+	}
+	postUid, err := strconv.ParseUint(c.Query("postUid"), 10, 32)
+	if err != nil || postUid < 1 {
+		return utils.Err(c, "Invalid post uid, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+	boardUid := h.service.Board.GetBoardUid(c.Query("id"))
+	if boardUid < 1 {
+		return utils.Err(c, "Invalid board id, cannot find a board", models.CODE_INVALID_PARAMETER)
+	}
+
+	result, err := h.service.Board.GetPostMeta(models.BoardViewParam{
+		BoardUid: boardUid,
+		PostUid:  uint(postUid),
+		UserUid:  uint(actionUserUid),
+	})
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 게시글 주소를 붙여 넣은 다른 앱에 보낼 oEmbed 응답 핸들러 (format은 json 또는 xml, 비회원 기준으로 공개된 글만)
+func (h *NuboBoardHandler) OEmbedHandler(c fiber.Ctx) error {
+	param := models.OEmbedParam{}
+	if err := c.Bind().Query(&param); err != nil || strings.TrimSpace(param.URL) == "" {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+	format := models.OEmbedFormat(strings.ToLower(param.Format))
+	if format == "" {
+		format = models.OEMBED_JSON
+	}
+	if format != models.OEMBED_JSON && format != models.OEMBED_XML {
+		return c.SendStatus(fiber.StatusNotImplemented)
+	}
+
+	result, err := h.service.Board.GetOEmbed(param)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPostMetaNotFound):
+			return c.SendStatus(fiber.StatusNotFound)
+		case errors.Is(err, services.ErrPostMetaPrivate):
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+	c.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", models.OEMBED_CACHE_AGE))
+	if format == models.OEMBED_JSON {
+		return c.JSON(result)
+	}
+	body, err := xml.Marshal(result)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+	c.Set(fiber.HeaderContentType, "text/xml; charset=utf-8")
+	return c.Send(append([]byte(`<?xml version="1.0" encoding="utf-8" standalone="yes"?>`), body...))
+}
+
+// 게시글 작성자나 게시판 관리자가 보는 열람 통계 핸들러
+func (h *NuboBoardHandler) PostStatsHandler(c fiber.Ctx) error {
+	param := models.PostStatParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, "Invalid parameters", models.CODE_INVALID_PARAMETER)
+	}
+	param.BoardUid = h.service.Board.GetBoardUid(c.Query("id"))
+	if param.BoardUid < 1 {
+		return utils.Err(c, "Invalid board id, cannot find a board", models.CODE_INVALID_PARAMETER)
+	}
+	param.UserUid = uint(utils.ExtractUserUid(c.Get(models.AUTH_KEY)))
+	result, err := h.service.Analytics.GetPostStats(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 게시글을 떠날 때 보내는 열람 시간 비콘 핸들러 (sendBeacon은 text/plain으로 보내므로 본문을 JSON으로 직접 해석)
+func (h *NuboBoardHandler) ReadBeaconHandler(c fiber.Ctx) error {
+	param := models.PostReadBeaconParam{}
+	if err := c.Bind().JSON(&param); err != nil {
+		return utils.Err(c, "Invalid parameters", models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Analytics.RecordReadTime(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
 // 첨부파일 다운로드 핸들러
 func (h *NuboBoardHandler) DownloadHandler(c fiber.Ctx) error {
 	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
@@ -170,6 +282,103 @@ func (h *NuboBoardHandler) DownloadHandler(c fiber.Ctx) error {
 	return utils.Ok(c, result)
 }
 
+// 비밀글 공유 링크 만들기 핸들러
+func (h *NuboBoardHandler) ShareCreateHandler(c fiber.Ctx) error {
+	param := models.PostShareCreateParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, "invalid parameters", models.CODE_INVALID_PARAMETER)
+	}
+	param.UserUid = uint(utils.ExtractUserUid(c.Get(models.AUTH_KEY)))
+	result, err := h.service.Board.CreateShare(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 게시글 공유 링크 목록 핸들러
+func (h *NuboBoardHandler) ShareListHandler(c fiber.Ctx) error {
+	param := models.PostShareManageParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, "invalid parameters", models.CODE_INVALID_PARAMETER)
+	}
+	param.UserUid = uint(utils.ExtractUserUid(c.Get(models.AUTH_KEY)))
+	items, err := h.service.Board.GetShares(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, items)
+}
+
+// 공유 링크 열람 기록 핸들러
+func (h *NuboBoardHandler) ShareLogListHandler(c fiber.Ctx) error {
+	param := models.PostShareManageParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, "invalid parameters", models.CODE_INVALID_PARAMETER)
+	}
+	param.UserUid = uint(utils.ExtractUserUid(c.Get(models.AUTH_KEY)))
+	items, err := h.service.Board.GetShareLogs(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, items)
+}
+
+// 공유 링크 폐기 핸들러
+func (h *NuboBoardHandler) ShareRevokeHandler(c fiber.Ctx) error {
+	param := models.PostShareManageParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, "invalid parameters", models.CODE_INVALID_PARAMETER)
+	}
+	param.UserUid = uint(utils.ExtractUserUid(c.Get(models.AUTH_KEY)))
+	if err := h.service.Board.RevokeShare(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 공유 링크로 첨부파일 다운로드 핸들러
+func (h *NuboBoardHandler) SharedDownloadHandler(c fiber.Ctx) error {
+	fileUid, err := strconv.ParseUint(c.Query("fileUid"), 10, 32)
+	if err != nil {
+		return utils.Err(c, "Invalid file uid, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+	result, err := h.service.Board.DownloadSharedFile(c.Query("token"), uint(fileUid))
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+
+	token := uuid.New().String()
+	h.storeDownloadToken(token, DownloadToken{
+		Name:   result.Name,
+		Path:   result.Path,
+		Expiry: time.Now().Add(1 * time.Minute),
+	})
+	result.Path = fmt.Sprintf("/board/transfer?token=%s", token)
+	return utils.Ok(c, result)
+}
+
+// 공유 링크로 비밀글 보기 핸들러
+func (h *NuboBoardHandler) SharedViewHandler(c fiber.Ctx) error {
+	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
+	if actionUserUid < 0 {
+		actionUserUid = 0
+	}
+	param := models.PostShareViewParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, "invalid parameters", models.CODE_INVALID_PARAMETER)
+	}
+	param.UserUid = uint(actionUserUid)
+	param.IP = c.IP()
+	param.Agent = c.Get(fiber.HeaderUserAgent)
+
+	result, err := h.service.Board.ViewSharedPost(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
 // 특정 사용자의 최근 활동(글, 댓글)들 가져오기
 func (h *NuboBoardHandler) LatestUserContentHandler(c fiber.Ctx) error {
 	uid, err := strconv.ParseUint(c.FormValue("targetUserUid"), 10, 32)
@@ -257,6 +466,83 @@ func (h *NuboBoardHandler) RemovePostHandler(c fiber.Ctx) error {
 	return utils.Ok(c, nil)
 }
 
+// 게시글, 댓글 신고하기 핸들러
+func (h *NuboBoardHandler) ReportContentHandler(c fiber.Ctx) error {
+	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
+	param := models.ContentReportParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return utils.Err(c, "invalid parameters", models.CODE_INVALID_PARAMETER)
+	}
+	if param.TargetUid < 1 {
+		return utils.Err(c, "Invalid target uid, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+
+	param.UserUid = uint(actionUserUid)
+	if err := h.service.Board.ReportContent(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 승인 대기 게시글 목록 가져오기 핸들러
+func (h *NuboBoardHandler) ApprovalListHandler(c fiber.Ctx) error {
+	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
+	boardUid := h.service.Board.GetBoardUid(c.Query("id"))
+	if boardUid < 1 {
+		return utils.Err(c, "Invalid board id", models.CODE_INVALID_PARAMETER)
+	}
+	page, _ := strconv.ParseUint(c.Query("page", "1"), 10, 32)
+	limit, _ := strconv.ParseUint(c.Query("limit", "20"), 10, 32)
+
+	result, err := h.service.Board.GetPendingPosts(models.BoardApprovalParam{
+		BoardUid: boardUid,
+		UserUid:  uint(actionUserUid),
+		Page:     uint(page),
+		Limit:    uint(limit),
+	})
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
+// 승인 대기 게시글 승인하기 핸들러
+func (h *NuboBoardHandler) ApprovePostHandler(c fiber.Ctx) error {
+	param, err := bindApprovalDecision(c)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Board.ApprovePost(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// 승인 대기 게시글 반려하기 핸들러
+func (h *NuboBoardHandler) RejectPostHandler(c fiber.Ctx) error {
+	param, err := bindApprovalDecision(c)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+	if err := h.service.Board.RejectPost(param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, nil)
+}
+
+// (내부용) 승인/반려 요청 파라미터 검사하기
+func bindApprovalDecision(c fiber.Ctx) (models.BoardApprovalDecisionParam, error) {
+	param := models.BoardApprovalDecisionParam{}
+	if err := c.Bind().Body(&param); err != nil {
+		return param, fmt.Errorf("invalid parameters")
+	}
+	if param.BoardUid < 1 || param.PostUid < 1 {
+		return param, fmt.Errorf("invalid board or post uid")
+	}
+	param.UserUid = uint(utils.ExtractUserUid(c.Get(models.AUTH_KEY)))
+	return param, nil
+}
+
 // (내부용) 다운로드용 토큰 정리하기
 func (h *NuboBoardHandler) cleanupOldTokens() {
 	h.downloadTokenMu.Lock()
@@ -307,3 +593,29 @@ func (h *NuboBoardHandler) TransferHandler(c fiber.Ctx) error {
 	filePath := fmt.Sprintf(".%s", data.Path)
 	return c.Download(filePath, data.Name)
 }
+
+// 지정된 게시판의 인기글들 가져오기 핸들러
+func (h *NuboBoardHandler) TrendingPostsHandler(c fiber.Ctx) error {
+	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
+	if actionUserUid < 0 {
+		actionUserUid = 0
+	}
+	limit, err := strconv.ParseUint(c.Query("limit"), 10, 32)
+	if err != nil || limit < 1 || limit > 100 {
+		return utils.Err(c, "Invalid limit, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+
+	boardUid := h.service.Board.GetBoardUid(c.Query("id"))
+	if boardUid < 1 {
+		return utils.Err(c, "Invalid board id, unable to find board", models.CODE_INVALID_PARAMETER)
+	}
+
+	items, err := h.service.Trending.GetTrendingPosts(boardUid, uint(limit), uint(actionUserUid))
+	if err != nil {
+		return utils.Err(c, "Failed to get trending posts from specific board", models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, models.TrendingPostResult{
+		Items:  items,
+		Config: h.service.Board.GetBoardConfig(boardUid),
+	})
+}
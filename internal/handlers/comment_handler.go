@@ -10,6 +10,7 @@ import (
 )
 
 type CommentHandler interface {
+	CommentHistoryHandler(c fiber.Ctx) error
 	CommentListHandler(c fiber.Ctx) error
 	LikeCommentHandler(c fiber.Ctx) error
 	ModifyCommentHandler(c fiber.Ctx) error
@@ -27,6 +28,25 @@ func NewNuboCommentHandler(service *services.Service) *NuboCommentHandler {
 	return &NuboCommentHandler{service: service}
 }
 
+// 댓글 수정 이력 가져오기 핸들러
+func (h *NuboCommentHandler) CommentHistoryHandler(c fiber.Ctx) error {
+	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
+	if actionUserUid < 0 {
+		actionUserUid = 0
+	}
+	param := models.CommentHistoryParam{}
+	if err := c.Bind().Query(&param); err != nil {
+		return utils.Err(c, err.Error(), models.CODE_INVALID_PARAMETER)
+	}
+
+	param.UserUid = uint(actionUserUid)
+	result, err := h.service.Comment.History(param)
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, result)
+}
+
 // 댓글 목록 가져오기 핸들러
 func (h *NuboCommentHandler) CommentListHandler(c fiber.Ctx) error {
 	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
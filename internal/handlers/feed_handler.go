@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/sirini/goapi/internal/services"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/templates"
+)
+
+type FeedHandler interface {
+	BoardFeedHandler(c fiber.Ctx) error
+	SiteFeedHandler(c fiber.Ctx) error
+	TagFeedHandler(c fiber.Ctx) error
+	UserFeedHandler(c fiber.Ctx) error
+}
+
+type NuboFeedHandler struct {
+	service *services.Service
+}
+
+// services.Service 주입 받기
+func NewNuboFeedHandler(service *services.Service) *NuboFeedHandler {
+	return &NuboFeedHandler{service: service}
+}
+
+// 피드를 만들어 형식에 맞는 Content-Type으로 보내기 (ETag, Last-Modified가 같으면 304 응답)
+func sendFeed(c fiber.Ctx, service *services.Service, param models.FeedParam) error {
+	feed, err := service.Feed.GetFeed(param)
+	if err != nil {
+		if errors.Is(err, services.ErrFeedNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+	body, err := templates.RenderFeed(feed, param.Format)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	c.Set("ETag", fmt.Sprintf(`"%x"`, sha256.Sum256(body)))
+	c.Set("Last-Modified", time.UnixMilli(feed.Updated).UTC().Format(http.TimeFormat))
+	c.Set("Cache-Control", "public, max-age=600")
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set("Content-Type", templates.FeedContentType[param.Format])
+	return c.Send(body)
+}
+
+// 게시판 피드 핸들러
+func (h *NuboFeedHandler) BoardFeedHandler(c fiber.Ctx) error {
+	return sendFeed(c, h.service, models.FeedParam{
+		Scope:   models.FEED_BOARD,
+		Format:  models.FeedFormat(c.Params("format")),
+		BoardId: c.Params("id"),
+	})
+}
+
+// 사이트 전체 피드 핸들러
+func (h *NuboFeedHandler) SiteFeedHandler(c fiber.Ctx) error {
+	return sendFeed(c, h.service, models.FeedParam{
+		Scope:  models.FEED_SITE,
+		Format: models.FeedFormat(c.Params("format")),
+	})
+}
+
+// 해시태그 피드 핸들러
+func (h *NuboFeedHandler) TagFeedHandler(c fiber.Ctx) error {
+	return sendFeed(c, h.service, models.FeedParam{
+		Scope:   models.FEED_TAG,
+		Format:  models.FeedFormat(c.Params("format")),
+		Hashtag: c.Params("name"),
+	})
+}
+
+// 작성자 피드 핸들러
+func (h *NuboFeedHandler) UserFeedHandler(c fiber.Ctx) error {
+	userUid, err := strconv.ParseUint(c.Params("uid"), 10, 32)
+	if err != nil {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	return sendFeed(c, h.service, models.FeedParam{
+		Scope:   models.FEED_USER,
+		Format:  models.FeedFormat(c.Params("format")),
+		UserUid: uint(userUid),
+	})
+}
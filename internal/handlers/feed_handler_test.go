@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/sirini/goapi/internal/services"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type feedServiceStub struct{}
+
+func (feedServiceStub) GetFeed(param models.FeedParam) (models.Feed, error) {
+	switch param.BoardId {
+	case "missing":
+		return models.Feed{}, fmt.Errorf("board not found: %w", services.ErrFeedNotFound)
+	case "broken":
+		return models.Feed{}, fmt.Errorf("database is down")
+	}
+	return models.Feed{Title: "Diary", Updated: 1_700_000_000_000,
+		Items: []models.FeedItem{{Title: "A & B", Content: "<p>1 < 2</p>", Published: 1_700_000_000_000}}}, nil
+}
+
+func TestFeedHandlerSupportsConditionalRequests(t *testing.T) {
+	handler := NewNuboBlogHandler(&services.Service{Feed: feedServiceStub{}})
+	app := fiber.New()
+	app.Get("/rss/:id", handler.BlogRssLoadHandler)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/rss/diary", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != fiber.StatusOK || etag == "" || resp.Header.Get("Last-Modified") != "Tue, 14 Nov 2023 22:13:20 GMT" ||
+		resp.Header.Get("Content-Type") != "application/rss+xml; charset=UTF-8" {
+		t.Fatalf("unexpected response %d %v", resp.StatusCode, resp.Header)
+	}
+
+	for name, header := range map[string][2]string{
+		"etag":          {"If-None-Match", etag},
+		"modified time": {"If-Modified-Since", "Tue, 14 Nov 2023 22:13:20 GMT"},
+	} {
+		req := httptest.NewRequest("GET", "/rss/diary", nil)
+		req.Header.Set(header[0], header[1])
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusNotModified {
+			t.Fatalf("%s: unchanged feed should return 304, got %d", name, resp.StatusCode)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/rss/diary", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	if resp, err = app.Test(req); err != nil || resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("changed feed should be sent again, got %v %v", resp.StatusCode, err)
+	}
+	if resp, err = app.Test(httptest.NewRequest("GET", "/rss/missing", nil)); err != nil || resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("unknown board should return 404, got %v %v", resp.StatusCode, err)
+	}
+	if resp, err = app.Test(httptest.NewRequest("GET", "/rss/broken", nil)); err != nil || resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("server errors should return 500, got %v %v", resp.StatusCode, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 // 모든 핸들러들을 관리
 type Handler struct {
 	CanAuthenticate func(uint) bool
+	ActivityPub     ActivityPubHandler
 	Admin           AdminHandler
 	Auth            AuthHandler
 	Board           BoardHandler
@@ -16,6 +17,7 @@ type Handler struct {
 	Chat            ChatHandler
 	Comment         CommentHandler
 	Editor          EditorHandler
+	Feed            FeedHandler
 	Home            HomeHandler
 	Noti            NotiHandler
 	OAuth2          OAuth2Handler
@@ -30,6 +32,7 @@ type Handler struct {
 func NewHandler(s *services.Service, db *sql.DB) *Handler {
 	return &Handler{
 		CanAuthenticate: s.Auth.CanAuthenticate,
+		ActivityPub:     NewNuboActivityPubHandler(s),
 		Admin:           NewNuboAdminHandler(s),
 		Auth:            NewNuboAuthHandler(s),
 		Board:           NewNuboBoardHandler(s),
@@ -37,6 +40,7 @@ func NewHandler(s *services.Service, db *sql.DB) *Handler {
 		Chat:            NewNuboChatHandler(s),
 		Comment:         NewNuboCommentHandler(s),
 		Editor:          NewNuboEditorHandler(s),
+		Feed:            NewNuboFeedHandler(s),
 		Home:            NewNuboHomeHandler(s),
 		Noti:            NewNuboNotiHandler(s),
 		OAuth2:          NewNuboOAuth2Handler(s),
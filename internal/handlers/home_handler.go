@@ -8,6 +8,7 @@ import (
 	"github.com/sirini/goapi/internal/configs"
 	"github.com/sirini/goapi/internal/services"
 	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/templates"
 	"github.com/sirini/goapi/pkg/utils"
 )
 
@@ -17,6 +18,9 @@ type HomeHandler interface {
 	LoadSidebarLinkHandler(c fiber.Ctx) error
 	LoadAllPostsHandler(c fiber.Ctx) error
 	LoadPostsByIdHandler(c fiber.Ctx) error
+	LoadTrendingPostsHandler(c fiber.Ctx) error
+	SitemapBoardHandler(c fiber.Ctx) error
+	SitemapIndexHandler(c fiber.Ctx) error
 }
 
 type NuboHomeHandler struct {
@@ -139,3 +143,51 @@ func (h *NuboHomeHandler) LoadPostsByIdHandler(c fiber.Ctx) error {
 		Config: config,
 	})
 }
+
+// 홈화면에서 사이트 전체 인기글들 가져오기 핸들러
+func (h *NuboHomeHandler) LoadTrendingPostsHandler(c fiber.Ctx) error {
+	actionUserUid := utils.ExtractUserUid(c.Get(models.AUTH_KEY))
+	if actionUserUid < 0 {
+		actionUserUid = 0
+	}
+	limit, err := strconv.ParseUint(c.Query("limit"), 10, 32)
+	if err != nil || limit < 1 || limit > 100 {
+		return utils.Err(c, "Invalid limit, not a valid number", models.CODE_INVALID_PARAMETER)
+	}
+
+	items, err := h.service.Trending.GetTrendingPosts(0, uint(limit), uint(actionUserUid))
+	if err != nil {
+		return utils.Err(c, "Failed to get trending posts", models.CODE_FAILED_OPERATION)
+	}
+	return utils.Ok(c, items)
+}
+
+// 사이트맵 인덱스(sitemap.xml) 핸들러
+func (h *NuboHomeHandler) SitemapIndexHandler(c fiber.Ctx) error {
+	body, err := templates.RenderSitemapIndex(h.service.Home.GetSitemapIndex())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString(`<error>Unable to build the sitemap index.</error>`)
+	}
+	c.Set("Content-Type", "application/xml; charset=UTF-8")
+	c.Set("Cache-Control", "public, max-age=3600")
+	return c.SendString(body)
+}
+
+// 게시판별 사이트맵 핸들러 (페이지마다 최대 5만 개의 게시글 주소)
+func (h *NuboHomeHandler) SitemapBoardHandler(c fiber.Ctx) error {
+	c.Set("Content-Type", "application/xml; charset=UTF-8")
+	page, err := strconv.ParseUint(c.Params("page"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString(`<error>Invalid sitemap page.</error>`)
+	}
+	items, err := h.service.Home.GetSitemapPage(c.Params("id"), uint(page))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString(`<error>Sitemap not found.</error>`)
+	}
+	body, err := templates.RenderSitemap(items)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString(`<error>Unable to build the sitemap.</error>`)
+	}
+	c.Set("Cache-Control", "public, max-age=3600")
+	return c.SendString(body)
+}
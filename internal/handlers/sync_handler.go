@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"crypto/subtle"
+	"encoding/json"
 	"strconv"
 
 	"github.com/gofiber/fiber/v3"
@@ -25,7 +26,33 @@ func configuredSyncKey() string {
 	return configs.Env.JWTSecretKey
 }
 
+// 동기화 결과를 공통 응답 형식으로 보내면서 본문의 HMAC 서명을 헤더에 붙이기
+func sendSignedSync(c fiber.Ctx, result any) error {
+	body, err := json.Marshal(models.ResponseCommon{
+		Success: true,
+		Result:  result,
+		Error:   "",
+		Code:    models.CODE_SUCCESS,
+	})
+	if err != nil {
+		return utils.Err(c, "Failed to encode sync result", models.CODE_FAILED_OPERATION)
+	}
+	c.Set(models.SYNC_SIGNATURE_HEADER, utils.SignSyncBody(body, configuredSyncKey()))
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(body)
+}
+
+// 요청 헤더나 파라미터의 동기화 키 확인하기
+func syncKeyFromRequest(c fiber.Ctx) bool {
+	key := c.Get("X-Sync-Key")
+	if key == "" {
+		key = c.FormValue("key")
+	}
+	return syncKeyMatches(key, configuredSyncKey())
+}
+
 type SyncHandler interface {
+	SyncChangesHandler(c fiber.Ctx) error
 	SyncPostHandler(c fiber.Ctx) error
 }
 
@@ -38,21 +65,51 @@ func NewNuboSyncHandler(service *services.Service) *NuboSyncHandler {
 	return &NuboSyncHandler{service: service}
 }
 
+// since 이후의 게시글 변경 내역(작성, 수정, 삭제) 출력
+func (h *NuboSyncHandler) SyncChangesHandler(c fiber.Ctx) error {
+	since := uint64(0)
+	if raw := c.FormValue("since"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return utils.Err(c, "Invalid since, not a valid number", models.CODE_INVALID_PARAMETER)
+		}
+		since = parsed
+	}
+	limit := uint64(100)
+	if raw := c.FormValue("limit"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || parsed < 1 || parsed > models.SYNC_CHANGES_MAX {
+			return utils.Err(c, "Invalid limit, not a valid number", models.CODE_INVALID_PARAMETER)
+		}
+		limit = parsed
+	}
+
+	if !syncKeyFromRequest(c) {
+		return utils.Err(c, "Invalid key, unauthorized access", models.CODE_INVALID_PARAMETER)
+	}
+
+	result, err := h.service.Sync.GetChanges(models.SyncChangeParam{
+		Since:   since,
+		Limit:   uint(limit),
+		BoardId: c.FormValue("board"),
+	})
+	if err != nil {
+		return utils.Err(c, err.Error(), models.CODE_FAILED_OPERATION)
+	}
+	return sendSignedSync(c, result)
+}
+
 // (허용된) 다른 곳으로 이 곳의 게시글들을 동기화 할 수 있도록 데이터 출력
 func (h *NuboSyncHandler) SyncPostHandler(c fiber.Ctx) error {
-	key := c.Get("X-Sync-Key")
-	if key == "" {
-		key = c.FormValue("key")
-	}
 	bunch, err := strconv.ParseUint(c.FormValue("limit"), 10, 32)
 	if err != nil || bunch < 1 || bunch > 100 {
 		return utils.Err(c, "Invalid limit, not a valid number", models.CODE_INVALID_PARAMETER)
 	}
 
-	if !syncKeyMatches(key, configuredSyncKey()) {
+	if !syncKeyFromRequest(c) {
 		return utils.Err(c, "Invalid key, unauthorized access", models.CODE_INVALID_PARAMETER)
 	}
 
 	result := h.service.Sync.GetLatestPosts(uint(bunch))
-	return utils.Ok(c, result)
+	return sendSignedSync(c, result)
 }
@@ -1,9 +1,15 @@
 package handlers
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/gofiber/fiber/v3"
 	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
 )
 
 func TestSyncKeyMatches(t *testing.T) {
@@ -42,3 +48,26 @@ func TestConfiguredSyncKeyPrefersDedicatedSecretWithJWTFallback(t *testing.T) {
 		t.Fatalf("configured sync key = %q, want dedicated secret", got)
 	}
 }
+
+func TestSendSignedSyncSignsBody(t *testing.T) {
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.SyncSecretKey = "sync-secret"
+
+	app := fiber.New()
+	app.Get("/sync", func(c fiber.Ctx) error {
+		return sendSignedSync(c, []string{"a"})
+	})
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/sync", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	signature := resp.Header.Get(models.SYNC_SIGNATURE_HEADER)
+	if !utils.VerifySyncBody(body, "sync-secret", signature) {
+		t.Fatalf("signature %q should match body %s", signature, body)
+	}
+	if utils.VerifySyncBody(append(body, ' '), "sync-secret", signature) || utils.VerifySyncBody(body, "other", signature) {
+		t.Fatal("signature should not match a changed body or key")
+	}
+}
@@ -58,6 +58,8 @@ func (h *NuboTradeHandler) TradeViewHandler(c fiber.Ctx) error {
 		return utils.Err(c, "invalid parameters", models.CODE_INVALID_PARAMETER)
 	}
 	param.UserUid = uint(max(actionUserUid, 0))
+	param.IP = c.IP()
+	param.Agent = c.Get(fiber.HeaderUserAgent)
 	param.BoardUid = h.service.Board.GetBoardUid(param.Id)
 	if param.BoardUid < 1 {
 		return utils.Err(c, "invalid board id", models.CODE_INVALID_PARAMETER)
@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type ActivityPubRepository interface {
+	AddFollower(boardUid uint, actorUid uint, followId string) error
+	CountFollowers(boardUid uint) uint
+	FindRemoteActor(actorId string) (models.ApRemoteActor, error)
+	GetDueDeliveries(now int64, limit uint) ([]models.ApDelivery, error)
+	GetFollowerInboxes(boardUid uint) ([]string, error)
+	GetKey(boardUid uint) (models.ApKey, error)
+	HasActivity(activityId string) bool
+	InsertActivity(activity models.ApReceivedActivity) (bool, error)
+	InsertDeliveries(boardUid uint, inboxes []string, activity string) error
+	RemoveActivity(actorUid uint, activityId string) error
+	RemoveFollower(boardUid uint, actorUid uint) error
+	SaveKey(boardUid uint, key models.ApKey) error
+	SaveRemoteActor(actor models.ApRemoteActor) (uint, error)
+	UpdateDelivery(uid uint, status models.ApDeliveryStatus, attempts uint, nextAttempt int64, lastError string) error
+}
+
+type NuboActivityPubRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboActivityPubRepository(db *sql.DB) *NuboActivityPubRepository {
+	return &NuboActivityPubRepository{db: db}
+}
+
+// 길이 제한 없이 찾을 수 있도록 활동 주소를 해시로 바꾸기
+func activityHash(activityId string) string {
+	sum := sha256.Sum256([]byte(activityId))
+	return hex.EncodeToString(sum[:])
+}
+
+// 게시판 팔로워 추가하기 (이미 있으면 Follow 활동 주소만 갱신)
+func (r *NuboActivityPubRepository) AddFollower(boardUid uint, actorUid uint, followId string) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s (board_uid, actor_uid, follow_id, timestamp) VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE follow_id = VALUES(follow_id)`, configs.Env.Prefix, models.TABLE_AP_FOLLOWER)
+	_, err := r.db.Exec(query, boardUid, actorUid, followId, time.Now().UnixMilli())
+	return err
+}
+
+// 게시판 팔로워 수 가져오기
+func (r *NuboActivityPubRepository) CountFollowers(boardUid uint) uint {
+	var count uint
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s WHERE board_uid = ?", configs.Env.Prefix, models.TABLE_AP_FOLLOWER)
+	r.db.QueryRow(query, boardUid).Scan(&count)
+	return count
+}
+
+// 보관해 둔 원격 액터 정보 가져오기
+func (r *NuboActivityPubRepository) FindRemoteActor(actorId string) (models.ApRemoteActor, error) {
+	actor := models.ApRemoteActor{}
+	query := fmt.Sprintf(`SELECT uid, actor_id, key_id, public_key, inbox, shared_inbox, name, url, fetched
+		FROM %s%s WHERE actor_id = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_AP_ACTOR)
+	err := r.db.QueryRow(query, actorId).Scan(&actor.Uid, &actor.ActorId, &actor.KeyId, &actor.PublicKey,
+		&actor.Inbox, &actor.SharedInbox, &actor.Name, &actor.Url, &actor.Fetched)
+	return actor, err
+}
+
+// 보낼 차례가 된 발송 대기열 항목 가져오기
+func (r *NuboActivityPubRepository) GetDueDeliveries(now int64, limit uint) ([]models.ApDelivery, error) {
+	items := make([]models.ApDelivery, 0)
+	query := fmt.Sprintf(`SELECT uid, board_uid, inbox, activity, attempts FROM %s%s
+		WHERE status = ? AND next_attempt <= ? ORDER BY next_attempt ASC, uid ASC LIMIT ?`,
+		configs.Env.Prefix, models.TABLE_AP_DELIVERY)
+	rows, err := r.db.Query(query, models.AP_DELIVERY_PENDING, now, limit)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ApDelivery{}
+		if err := rows.Scan(&item.Uid, &item.BoardUid, &item.Inbox, &item.Activity, &item.Attempts); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 게시판 팔로워들의 받은편지함 주소를 중복 없이 가져오기 (공유 받은편지함 우선)
+func (r *NuboActivityPubRepository) GetFollowerInboxes(boardUid uint) ([]string, error) {
+	items := make([]string, 0)
+	query := fmt.Sprintf(`SELECT DISTINCT IF(a.shared_inbox != '', a.shared_inbox, a.inbox)
+		FROM %s%s f JOIN %s%s a ON a.uid = f.actor_uid WHERE f.board_uid = ?`,
+		configs.Env.Prefix, models.TABLE_AP_FOLLOWER, configs.Env.Prefix, models.TABLE_AP_ACTOR)
+	rows, err := r.db.Query(query, boardUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var inbox string
+		if err := rows.Scan(&inbox); err != nil {
+			return items, err
+		}
+		if len(inbox) > 0 {
+			items = append(items, inbox)
+		}
+	}
+	return items, rows.Err()
+}
+
+// 게시판 액터의 서명 키 가져오기
+func (r *NuboActivityPubRepository) GetKey(boardUid uint) (models.ApKey, error) {
+	key := models.ApKey{}
+	query := fmt.Sprintf("SELECT public_key, private_key FROM %s%s WHERE board_uid = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_AP_KEY)
+	err := r.db.QueryRow(query, boardUid).Scan(&key.PublicKey, &key.PrivateKey)
+	return key, err
+}
+
+// 이미 처리한 활동인지 확인하기
+func (r *NuboActivityPubRepository) HasActivity(activityId string) bool {
+	var uid uint
+	query := fmt.Sprintf("SELECT uid FROM %s%s WHERE activity_hash = ? LIMIT 1", configs.Env.Prefix, models.TABLE_AP_ACTIVITY)
+	r.db.QueryRow(query, activityHash(activityId)).Scan(&uid)
+	return uid > 0
+}
+
+// 받은 활동 기록하기 (같은 활동이 다시 오면 false 반환)
+func (r *NuboActivityPubRepository) InsertActivity(activity models.ApReceivedActivity) (bool, error) {
+	query := fmt.Sprintf(`INSERT IGNORE INTO %s%s
+		(board_uid, post_uid, comment_uid, actor_uid, type, activity_hash, activity_id, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_AP_ACTIVITY)
+	result, err := r.db.Exec(query, activity.BoardUid, activity.PostUid, activity.CommentUid, activity.ActorUid,
+		activity.Type, activityHash(activity.ActivityId), activity.ActivityId, time.Now().UnixMilli())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// 받은편지함마다 보낼 활동을 발송 대기열에 넣기
+func (r *NuboActivityPubRepository) InsertDeliveries(boardUid uint, inboxes []string, activity string) error {
+	if len(inboxes) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	args := make([]any, 0, len(inboxes)*5)
+	for _, inbox := range inboxes {
+		args = append(args, boardUid, inbox, activity, now, now)
+	}
+	query := fmt.Sprintf("INSERT INTO %s%s (board_uid, inbox, activity, next_attempt, created) VALUES %s",
+		configs.Env.Prefix, models.TABLE_AP_DELIVERY, strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?),", len(inboxes)), ","))
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// 취소(Undo)된 활동 기록 지우기 (활동을 보낸 액터의 기록만 지움)
+func (r *NuboActivityPubRepository) RemoveActivity(actorUid uint, activityId string) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE actor_uid = ? AND activity_hash = ?", configs.Env.Prefix, models.TABLE_AP_ACTIVITY)
+	_, err := r.db.Exec(query, actorUid, activityHash(activityId))
+	return err
+}
+
+// 게시판 팔로워 삭제하기
+func (r *NuboActivityPubRepository) RemoveFollower(boardUid uint, actorUid uint) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE board_uid = ? AND actor_uid = ?", configs.Env.Prefix, models.TABLE_AP_FOLLOWER)
+	_, err := r.db.Exec(query, boardUid, actorUid)
+	return err
+}
+
+// 게시판 액터의 서명 키 저장하기 (동시에 만들어졌다면 먼저 저장된 키를 유지)
+func (r *NuboActivityPubRepository) SaveKey(boardUid uint, key models.ApKey) error {
+	query := fmt.Sprintf("INSERT IGNORE INTO %s%s (board_uid, public_key, private_key, created) VALUES (?, ?, ?, ?)",
+		configs.Env.Prefix, models.TABLE_AP_KEY)
+	_, err := r.db.Exec(query, boardUid, key.PublicKey, key.PrivateKey, time.Now().UnixMilli())
+	return err
+}
+
+// 원격 액터 정보 저장하고 고유번호 반환하기 (이미 있으면 갱신)
+func (r *NuboActivityPubRepository) SaveRemoteActor(actor models.ApRemoteActor) (uint, error) {
+	query := fmt.Sprintf(`INSERT INTO %s%s (actor_id, key_id, public_key, inbox, shared_inbox, name, url, fetched)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE uid = LAST_INSERT_ID(uid), key_id = VALUES(key_id), public_key = VALUES(public_key),
+		inbox = VALUES(inbox), shared_inbox = VALUES(shared_inbox), name = VALUES(name), url = VALUES(url),
+		fetched = VALUES(fetched)`, configs.Env.Prefix, models.TABLE_AP_ACTOR)
+	result, err := r.db.Exec(query, actor.ActorId, actor.KeyId, actor.PublicKey, actor.Inbox, actor.SharedInbox,
+		actor.Name, actor.Url, actor.Fetched)
+	if err != nil {
+		return models.FAILED, err
+	}
+	uid, err := result.LastInsertId()
+	return uint(uid), err
+}
+
+// 발송 결과 기록하기
+func (r *NuboActivityPubRepository) UpdateDelivery(uid uint, status models.ApDeliveryStatus, attempts uint, nextAttempt int64, lastError string) error {
+	query := fmt.Sprintf("UPDATE %s%s SET status = ?, attempts = ?, next_attempt = ?, last_error = ? WHERE uid = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_AP_DELIVERY)
+	_, err := r.db.Exec(query, status, attempts, nextAttempt, lastError, uid)
+	return err
+}
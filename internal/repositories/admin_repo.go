@@ -85,6 +85,8 @@ func boardRemovalStatements(prefix string) []string {
 		fmt.Sprintf("DELETE FROM %simage_description WHERE post_uid IN (SELECT uid FROM %spost WHERE board_uid = ?)", prefix, prefix),
 		fmt.Sprintf("DELETE FROM %sexif WHERE post_uid IN (SELECT uid FROM %spost WHERE board_uid = ?)", prefix, prefix),
 		fmt.Sprintf("DELETE FROM %sfile_thumbnail WHERE post_uid IN (SELECT uid FROM %spost WHERE board_uid = ?)", prefix, prefix),
+		fmt.Sprintf("DELETE FROM %simage_variant WHERE file_uid IN (SELECT uid FROM %sfile WHERE board_uid = ?)", prefix, prefix),
+		fmt.Sprintf("DELETE FROM %simage_variant WHERE source IN (SELECT path FROM %simage WHERE board_uid = ?)", prefix, prefix),
 		fmt.Sprintf("DELETE FROM %sfile WHERE board_uid = ?", prefix),
 		fmt.Sprintf("DELETE FROM %simage WHERE board_uid = ?", prefix),
 		fmt.Sprintf("DELETE FROM %scomment_like WHERE board_uid = ?", prefix),
@@ -120,8 +122,8 @@ func (r *NuboAdminRepository) CreateBoard(param models.AdminBoardCreateParam) ui
 	query := fmt.Sprintf(`INSERT INTO %s%s 
 												(id, group_uid, admin_uid, type, skin_key, name, info,
 													row_count, width, use_category, level_list, level_view, level_write,
-													level_comment, level_download, point_view, point_write, point_comment, point_download) 
-													VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+													level_comment, level_download, point_view, point_write, point_comment, point_download, require_approval, comment_depth, public_comment_history, feed_summary, sync_scope) 
+													VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		configs.Env.Prefix, models.TABLE_BOARD)
 	result, err := r.db.Exec(
 		query,
@@ -144,6 +146,11 @@ func (r *NuboAdminRepository) CreateBoard(param models.AdminBoardCreateParam) ui
 		param.PointWrite,
 		param.PointComment,
 		param.PointDownload,
+		param.RequireApproval,
+		param.CommentDepth,
+		param.PublicCommentHistory,
+		param.FeedSummary,
+		param.SyncScope,
 	)
 	if err != nil {
 		return models.FAILED
@@ -590,8 +597,8 @@ func (r *NuboAdminRepository) GetReportList(param models.AdminReportSearchParam)
 		isSolved = 1
 	}
 
-	whereClauses := []string{"solved = ?"}
-	whereArgs := []any{isSolved}
+	whereClauses := []string{"solved = ?", "r.target_type = ?"}
+	whereArgs := []any{isSolved, models.REPORT_TARGET_USER}
 
 	if len(param.Keyword) > 0 {
 		switch param.Option {
@@ -1054,7 +1061,12 @@ func (r *NuboAdminRepository) ModifyBoard(param models.AdminBoardModifyParam) er
 			point_view = ?,
 			point_write = ?,
 			point_comment = ?,
-			point_download = ?
+			point_download = ?,
+			require_approval = ?,
+			comment_depth = ?,
+			public_comment_history = ?,
+			feed_summary = ?,
+			sync_scope = ?
 		WHERE uid = ? LIMIT 1
 		`, configs.Env.Prefix, models.TABLE_BOARD)
 	_, err := r.db.Exec(query,
@@ -1076,6 +1088,11 @@ func (r *NuboAdminRepository) ModifyBoard(param models.AdminBoardModifyParam) er
 		param.PointWrite,
 		param.PointComment,
 		param.PointDownload,
+		param.RequireApproval,
+		param.CommentDepth,
+		param.PublicCommentHistory,
+		param.FeedSummary,
+		param.SyncScope,
 		param.BoardUid,
 	)
 	return err
@@ -1221,6 +1238,9 @@ func (r *NuboAdminRepository) RemoveFileRecords(boardUid uint) error {
 		if err := r.RemoveRecordByFileUid(models.TABLE_FILE_THUMB, fileUid); err != nil {
 			return err
 		}
+		if err := r.RemoveRecordByFileUid(models.TABLE_IMAGE_VARIANT, fileUid); err != nil {
+			return err
+		}
 		if err := r.RemoveRecordByFileUid(models.TABLE_EXIF, fileUid); err != nil {
 			return err
 		}
@@ -1239,7 +1259,12 @@ func (r *NuboAdminRepository) RemoveFileRecords(boardUid uint) error {
 
 // 게시판 삭제 시 이미지 삽입 경로들도 삭제하기 (주의: 실제 파일들 삭제 처리 이후 실행 필요)
 func (r *NuboAdminRepository) RemoveImageRecords(boardUid uint) error {
-	query := fmt.Sprintf("DELETE FROM %s%s WHERE board_uid = ?", configs.Env.Prefix, models.TABLE_IMAGE)
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE source IN (SELECT path FROM %s%s WHERE board_uid = ?)",
+		configs.Env.Prefix, models.TABLE_IMAGE_VARIANT, configs.Env.Prefix, models.TABLE_IMAGE)
+	if _, err := r.db.Exec(query, boardUid); err != nil {
+		return err
+	}
+	query = fmt.Sprintf("DELETE FROM %s%s WHERE board_uid = ?", configs.Env.Prefix, models.TABLE_IMAGE)
 	_, err := r.db.Exec(query, boardUid)
 	return err
 }
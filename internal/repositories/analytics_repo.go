@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type AnalyticsRepository interface {
+	GetPostDailyStats(postUid uint, fromDay uint) ([]models.PostStatDaily, error)
+	GetPostReferrerStats(postUid uint, fromDay uint, limit uint) ([]models.PostStatReferrer, error)
+	GetPostSourceStats(postUid uint, fromDay uint) ([]models.PostStatSource, error)
+	GetPostStatTotal(postUid uint, fromDay uint) (uint, uint, uint)
+	InsertReadEvent(event models.PostReadEvent) error
+	RemoveReadEventsBefore(before int64) error
+	RollupPostStats(fromDay uint) error
+	UpdateReadDuration(tokenHash string, seconds uint, since int64) (bool, error)
+}
+
+type NuboAnalyticsRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboAnalyticsRepository(db *sql.DB) *NuboAnalyticsRepository {
+	return &NuboAnalyticsRepository{db: db}
+}
+
+// 기간 안의 날짜별 조회수, 순 방문자 수, 평균 열람 시간 가져오기
+func (r *NuboAnalyticsRepository) GetPostDailyStats(postUid uint, fromDay uint) ([]models.PostStatDaily, error) {
+	items := make([]models.PostStatDaily, 0)
+	query := fmt.Sprintf(`SELECT day, views, readers, IF(read_count > 0, read_seconds DIV read_count, 0)
+		FROM %s%s WHERE post_uid = ? AND day >= ? ORDER BY day ASC`, configs.Env.Prefix, models.TABLE_POST_STAT)
+	rows, err := r.db.Query(query, postUid, fromDay)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.PostStatDaily{}
+		if err := rows.Scan(&item.Day, &item.Views, &item.Readers, &item.AvgReadSeconds); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 기간 안에 많이 유입된 참조 도메인 순으로 가져오기
+func (r *NuboAnalyticsRepository) GetPostReferrerStats(postUid uint, fromDay uint, limit uint) ([]models.PostStatReferrer, error) {
+	items := make([]models.PostStatReferrer, 0)
+	query := fmt.Sprintf(`SELECT host, SUM(views) AS total FROM %s%s
+		WHERE post_uid = ? AND day >= ? AND host != '' GROUP BY host ORDER BY total DESC, host ASC LIMIT ?`,
+		configs.Env.Prefix, models.TABLE_POST_STAT_REF)
+	rows, err := r.db.Query(query, postUid, fromDay, limit)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.PostStatReferrer{}
+		if err := rows.Scan(&item.Host, &item.Views); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 기간 안의 유입 경로별 조회수 가져오기
+func (r *NuboAnalyticsRepository) GetPostSourceStats(postUid uint, fromDay uint) ([]models.PostStatSource, error) {
+	items := make([]models.PostStatSource, 0)
+	query := fmt.Sprintf(`SELECT source, SUM(views) AS total FROM %s%s
+		WHERE post_uid = ? AND day >= ? GROUP BY source ORDER BY total DESC`,
+		configs.Env.Prefix, models.TABLE_POST_STAT_REF)
+	rows, err := r.db.Query(query, postUid, fromDay)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var source models.TrafficSource
+		item := models.PostStatSource{}
+		if err := rows.Scan(&source, &item.Views); err != nil {
+			return items, err
+		}
+		item.Source = source.String()
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 기간 전체의 조회수, 순 열람자 수, 평균 열람 시간(초) 가져오기 (순 열람자는 보관 중인 원본 이벤트로 계산)
+func (r *NuboAnalyticsRepository) GetPostStatTotal(postUid uint, fromDay uint) (uint, uint, uint) {
+	var views, readers, avgSeconds uint
+	query := fmt.Sprintf(`SELECT COALESCE(SUM(views), 0), COALESCE(SUM(read_seconds) DIV NULLIF(SUM(read_count), 0), 0)
+		FROM %s%s WHERE post_uid = ? AND day >= ?`, configs.Env.Prefix, models.TABLE_POST_STAT)
+	r.db.QueryRow(query, postUid, fromDay).Scan(&views, &avgSeconds)
+
+	query = fmt.Sprintf("SELECT COUNT(DISTINCT viewer_hash) FROM %s%s WHERE post_uid = ? AND day >= ?",
+		configs.Env.Prefix, models.TABLE_POST_READ)
+	r.db.QueryRow(query, postUid, fromDay).Scan(&readers)
+	return views, readers, avgSeconds
+}
+
+// 게시글 열람 원본 이벤트 기록하기
+func (r *NuboAnalyticsRepository) InsertReadEvent(event models.PostReadEvent) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s
+		(post_uid, viewer_hash, source, referrer_host, counted, token_hash, day, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_POST_READ)
+	_, err := r.db.Exec(query, event.PostUid, event.ViewerHash, event.Source,
+		event.ReferrerHost, event.Counted, event.TokenHash, event.Day, event.Timestamp)
+	return err
+}
+
+// 보관 기간이 지난 원본 이벤트 지우기 (집계 테이블은 그대로 유지)
+func (r *NuboAnalyticsRepository) RemoveReadEventsBefore(before int64) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE timestamp < ?", configs.Env.Prefix, models.TABLE_POST_READ)
+	_, err := r.db.Exec(query, before)
+	return err
+}
+
+// fromDay 이후 원본 이벤트로 날짜별 통계와 유입 경로별 통계 다시 계산하기 (조회수는 조회수가 오른 열람만 셈)
+func (r *NuboAnalyticsRepository) RollupPostStats(fromDay uint) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`INSERT INTO %s%s (post_uid, day, views, readers, read_count, read_seconds)
+		SELECT post_uid, day, SUM(counted), COUNT(DISTINCT viewer_hash), SUM(duration > 0), SUM(duration)
+		FROM %s%s WHERE day >= ? GROUP BY post_uid, day
+		ON DUPLICATE KEY UPDATE views = VALUES(views), readers = VALUES(readers),
+		read_count = VALUES(read_count), read_seconds = VALUES(read_seconds)`,
+		configs.Env.Prefix, models.TABLE_POST_STAT, configs.Env.Prefix, models.TABLE_POST_READ)
+	if _, err := tx.Exec(query, fromDay); err != nil {
+		return err
+	}
+	query = fmt.Sprintf(`INSERT INTO %s%s (post_uid, day, source, host, views)
+		SELECT post_uid, day, source, referrer_host, SUM(counted)
+		FROM %s%s WHERE day >= ? GROUP BY post_uid, day, source, referrer_host
+		ON DUPLICATE KEY UPDATE views = VALUES(views)`,
+		configs.Env.Prefix, models.TABLE_POST_STAT_REF, configs.Env.Prefix, models.TABLE_POST_READ)
+	if _, err := tx.Exec(query, fromDay); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// 열람 토큰에 아직 기록되지 않은 열람 시간 남기기 (기록했으면 true)
+func (r *NuboAnalyticsRepository) UpdateReadDuration(tokenHash string, seconds uint, since int64) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s%s SET duration = ?
+		WHERE token_hash = ? AND duration = 0 AND timestamp >= ? LIMIT 1`, configs.Env.Prefix, models.TABLE_POST_READ)
+	result, err := r.db.Exec(query, seconds, tokenHash, since)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
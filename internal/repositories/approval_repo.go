@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type ApprovalRepository interface {
+	ApprovePost(postUid uint) error
+	GetPendingPosts(param models.BoardApprovalParam) (models.BoardApprovalResult, error)
+	InsertApproval(postUid uint, param models.EditorWriteParam) error
+	RemoveApproval(postUid uint) error
+	UpdateApprovalStatus(postUid uint, status models.Status) error
+}
+
+type NuboApprovalRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboApprovalRepository(db *sql.DB) *NuboApprovalRepository {
+	return &NuboApprovalRepository{db: db}
+}
+
+// 승인 대기 중인 글이면 대기 상태, 신고로 숨겨진 글이면 비밀 상태, 아니면 공지/비밀 여부에 맞는 상태 반환하기
+func writeStatus(param models.EditorWriteParam) models.Status {
+	if param.IsPending {
+		return models.CONTENT_PENDING
+	}
+	if param.IsHidden {
+		return models.CONTENT_SECRET
+	}
+	return utils.GetContentStatus(param.IsNotice, param.IsSecret)
+}
+
+// 새 글을 승인 대기열에 넣기 (승인 후 적용할 원래 상태 보관)
+func insertPostApprovalTx(tx *sql.Tx, postUid uint, param models.EditorWriteParam) error {
+	if !param.IsPending {
+		return nil
+	}
+	query := fmt.Sprintf(`INSERT INTO %s%s (post_uid, board_uid, user_uid, status, submitted)
+		VALUES (?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_POST_APPROVAL)
+	_, err := tx.Exec(query, postUid, param.BoardUid, param.UserUid,
+		utils.GetContentStatus(param.IsNotice, param.IsSecret), time.Now().UnixMilli())
+	return err
+}
+
+// 승인 대기 중인 글을 원래 상태로 공개하고 대기열에서 빼기
+func (r *NuboApprovalRepository) ApprovePost(postUid uint) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	prefix := configs.Env.Prefix
+	var status models.Status
+	query := fmt.Sprintf("SELECT status FROM %s%s WHERE post_uid = ? LIMIT 1 FOR UPDATE", prefix, models.TABLE_POST_APPROVAL)
+	if err := tx.QueryRow(query, postUid).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("post is not waiting for approval")
+		}
+		return err
+	}
+	query = fmt.Sprintf("UPDATE %s%s SET status = ? WHERE uid = ? AND status = ? LIMIT 1", prefix, models.TABLE_POST)
+	if _, err := tx.Exec(query, status, postUid, models.CONTENT_PENDING); err != nil {
+		return err
+	}
+	if err := insertSyncChange(tx, postUid, models.SYNC_MODIFIED); err != nil {
+		return err
+	}
+	query = fmt.Sprintf("DELETE FROM %s%s WHERE post_uid = ? LIMIT 1", prefix, models.TABLE_POST_APPROVAL)
+	if _, err := tx.Exec(query, postUid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// 승인 대기 게시글 목록 가져오기 (UserUid가 0이 아니면 해당 사용자의 글만)
+func (r *NuboApprovalRepository) GetPendingPosts(param models.BoardApprovalParam) (models.BoardApprovalResult, error) {
+	result := models.BoardApprovalResult{Item: make([]models.BoardApprovalItem, 0)}
+	prefix := configs.Env.Prefix
+	where := "a.board_uid = ?"
+	args := []any{param.BoardUid}
+	if param.UserUid > 0 {
+		where += " AND a.user_uid = ?"
+		args = append(args, param.UserUid)
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s a WHERE %s", prefix, models.TABLE_POST_APPROVAL, where)
+	if err := r.db.QueryRow(query, args...).Scan(&result.Total); err != nil {
+		return result, err
+	}
+
+	query = fmt.Sprintf(`SELECT a.post_uid, a.board_uid, p.title, a.user_uid, COALESCE(u.name, ''), COALESCE(u.profile, ''),
+		a.submitted, a.status
+		FROM %s%s a JOIN %s%s p ON p.uid = a.post_uid LEFT JOIN %s%s u ON u.uid = a.user_uid
+		WHERE %s ORDER BY a.submitted ASC LIMIT ? OFFSET ?`,
+		prefix, models.TABLE_POST_APPROVAL, prefix, models.TABLE_POST, prefix, models.TABLE_USER, where)
+	rows, err := r.db.Query(query, append(args, param.Limit, (param.Page-1)*param.Limit)...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.BoardApprovalItem{}
+		var status models.Status
+		if err := rows.Scan(&item.PostUid, &item.BoardUid, &item.Title, &item.Writer.UserUid, &item.Writer.Name,
+			&item.Writer.Profile, &item.Submitted, &status); err != nil {
+			return result, err
+		}
+		item.IsSecret = status == models.CONTENT_SECRET
+		result.Item = append(result.Item, item)
+	}
+	return result, rows.Err()
+}
+
+// 이미 작성된 글을 승인 대기열에 넣기 (수정하면서 검토 대상으로 보류된 경우)
+func (r *NuboApprovalRepository) InsertApproval(postUid uint, param models.EditorWriteParam) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertPostApprovalTx(tx, postUid, param); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// 승인 대기열에서 빼기
+func (r *NuboApprovalRepository) RemoveApproval(postUid uint) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE post_uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_POST_APPROVAL)
+	_, err := r.db.Exec(query, postUid)
+	return err
+}
+
+// 승인 대기 중인 글이 수정되었을 때 승인 후 적용할 상태 바꾸기
+func (r *NuboApprovalRepository) UpdateApprovalStatus(postUid uint, status models.Status) error {
+	query := fmt.Sprintf("UPDATE %s%s SET status = ? WHERE post_uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_POST_APPROVAL)
+	_, err := r.db.Exec(query, status, postUid)
+	return err
+}
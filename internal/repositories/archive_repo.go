@@ -0,0 +1,300 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type ArchiveRepository interface {
+	GetArchiveBoard(boardUid uint) (models.AdminBoardCreateParam, string, error)
+	GetArchiveCategories(boardUid uint) ([]models.Pair, error)
+	GetArchiveComments(boardUid uint) ([]models.ArchiveComment, error)
+	GetArchiveFiles(boardUid uint) (map[uint][]models.ArchiveFile, error)
+	GetArchiveImages(boardUid uint) ([]models.ArchiveImage, error)
+	GetArchiveLikes(table models.Table, boardUid uint) (map[uint][]models.ArchiveLike, error)
+	GetArchivePosts(boardUid uint) ([]models.ArchivePost, error)
+	GetArchiveTags(boardUid uint) (map[uint][]string, error)
+	GetArchiveUsers(userUids []uint) ([]models.ArchiveUser, error)
+	InsertArchiveImage(boardUid uint, userUid uint, image models.ArchiveImage) error
+	InsertArchiveLikes(table models.Table, boardUid uint, targetUid uint, likes []models.ArchiveLike) error
+}
+
+type NuboArchiveRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboArchiveRepository(db *sql.DB) *NuboArchiveRepository {
+	return &NuboArchiveRepository{db: db}
+}
+
+// 좋아요 테이블의 대상 컬럼 이름 반환
+func archiveLikeColumn(table models.Table) (string, error) {
+	switch table {
+	case models.TABLE_POST_LIKE:
+		return "post_uid", nil
+	case models.TABLE_COMMENT_LIKE:
+		return "comment_uid", nil
+	}
+	return "", fmt.Errorf("unsupported like table %q", table)
+}
+
+// 게시판 설정과 소속 그룹 ID 가져오기
+func (r *NuboArchiveRepository) GetArchiveBoard(boardUid uint) (models.AdminBoardCreateParam, string, error) {
+	item := models.AdminBoardCreateParam{}
+	var groupId string
+	query := fmt.Sprintf(`SELECT b.id, b.group_uid, COALESCE(g.id, ''), b.admin_uid, b.type, b.skin_key, b.name, b.info,
+		b.row_count, b.width, b.use_category, b.level_list, b.level_view, b.level_write, b.level_comment,
+		b.level_download, b.point_view, b.point_write, b.point_comment, b.point_download,
+		b.require_approval, b.comment_depth, b.public_comment_history, b.feed_summary, b.sync_scope
+		FROM %s%s b LEFT JOIN %s%s g ON g.uid = b.group_uid WHERE b.uid = ? LIMIT 1`,
+		configs.Env.Prefix, models.TABLE_BOARD, configs.Env.Prefix, models.TABLE_GROUP)
+	err := r.db.QueryRow(query, boardUid).Scan(&item.Id, &item.GroupUid, &groupId, &item.AdminUid, &item.Type,
+		&item.SkinKey, &item.Name, &item.Info, &item.RowCount, &item.Width, &item.UseCategory,
+		&item.LevelList, &item.LevelView, &item.LevelWrite, &item.LevelComment, &item.LevelDownload,
+		&item.PointView, &item.PointWrite, &item.PointComment, &item.PointDownload,
+		&item.RequireApproval, &item.CommentDepth, &item.PublicCommentHistory, &item.FeedSummary, &item.SyncScope)
+	return item, groupId, err
+}
+
+// 게시판의 카테고리 목록 가져오기
+func (r *NuboArchiveRepository) GetArchiveCategories(boardUid uint) ([]models.Pair, error) {
+	items := make([]models.Pair, 0)
+	query := fmt.Sprintf("SELECT uid, name FROM %s%s WHERE board_uid = ? ORDER BY uid ASC",
+		configs.Env.Prefix, models.TABLE_BOARD_CAT)
+	rows, err := r.db.Query(query, boardUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.Pair{}
+		if err := rows.Scan(&item.Uid, &item.Name); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 게시판의 댓글 전체를 작성 순서대로 가져오기 (스레드 복원을 위해 삭제된 댓글도 포함)
+func (r *NuboArchiveRepository) GetArchiveComments(boardUid uint) ([]models.ArchiveComment, error) {
+	items := make([]models.ArchiveComment, 0)
+	query := fmt.Sprintf(`SELECT uid, parent_uid, post_uid, user_uid, content, submitted, modified, status
+		FROM %s%s WHERE board_uid = ? ORDER BY uid ASC`, configs.Env.Prefix, models.TABLE_COMMENT)
+	rows, err := r.db.Query(query, boardUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ArchiveComment{}
+		if err := rows.Scan(&item.Uid, &item.ParentUid, &item.PostUid, &item.UserUid, &item.Content,
+			&item.Submitted, &item.Modified, &item.Status); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 게시판의 첨부파일을 썸네일, EXIF, 이미지 설명과 함께 게시글별로 가져오기
+func (r *NuboArchiveRepository) GetArchiveFiles(boardUid uint) (map[uint][]models.ArchiveFile, error) {
+	items := make(map[uint][]models.ArchiveFile)
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf(`SELECT f.uid, f.post_uid, f.name, f.path,
+		COALESCE(t.path, ''), COALESCE(t.full_path, ''), e.uid IS NOT NULL,
+		COALESCE(e.make, ''), COALESCE(e.model, ''), COALESCE(e.aperture, 0), COALESCE(e.iso, 0),
+		COALESCE(e.focal_length, 0), COALESCE(e.exposure, 0), COALESCE(e.width, 0), COALESCE(e.height, 0),
+		COALESCE(e.date, 0), COALESCE(d.description, '')
+		FROM %s%s f
+		LEFT JOIN %s%s t ON t.file_uid = f.uid
+		LEFT JOIN %s%s e ON e.file_uid = f.uid
+		LEFT JOIN %s%s d ON d.file_uid = f.uid
+		WHERE f.board_uid = ? ORDER BY f.uid ASC`,
+		prefix, models.TABLE_FILE, prefix, models.TABLE_FILE_THUMB, prefix, models.TABLE_EXIF, prefix, models.TABLE_IMAGE_DESC)
+	rows, err := r.db.Query(query, boardUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	seen := make(map[uint]bool)
+	for rows.Next() {
+		item := models.ArchiveFile{}
+		var postUid uint
+		var thumb models.BoardThumbnail
+		var exif models.BoardExif
+		var hasExif bool
+		if err := rows.Scan(&item.Uid, &postUid, &item.Name, &item.Path, &thumb.Small, &thumb.Large, &hasExif,
+			&exif.Make, &exif.Model, &exif.Aperture, &exif.ISO, &exif.FocalLength, &exif.Exposure,
+			&exif.Width, &exif.Height, &exif.Date, &item.Description); err != nil {
+			return items, err
+		}
+		if seen[item.Uid] {
+			continue
+		}
+		seen[item.Uid] = true
+		if thumb.Small != "" || thumb.Large != "" {
+			item.Thumbnail = &thumb
+		}
+		if hasExif {
+			item.Exif = &exif
+		}
+		items[postUid] = append(items[postUid], item)
+	}
+	return items, rows.Err()
+}
+
+// 게시판에 삽입된 이미지 목록 가져오기
+func (r *NuboArchiveRepository) GetArchiveImages(boardUid uint) ([]models.ArchiveImage, error) {
+	items := make([]models.ArchiveImage, 0)
+	query := fmt.Sprintf("SELECT user_uid, path, timestamp FROM %s%s WHERE board_uid = ? ORDER BY uid ASC",
+		configs.Env.Prefix, models.TABLE_IMAGE)
+	rows, err := r.db.Query(query, boardUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ArchiveImage{}
+		if err := rows.Scan(&item.UserUid, &item.Path, &item.Timestamp); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 게시판의 좋아요 기록을 대상(게시글/댓글)별로 가져오기
+func (r *NuboArchiveRepository) GetArchiveLikes(table models.Table, boardUid uint) (map[uint][]models.ArchiveLike, error) {
+	items := make(map[uint][]models.ArchiveLike)
+	column, err := archiveLikeColumn(table)
+	if err != nil {
+		return items, err
+	}
+	query := fmt.Sprintf("SELECT %s, user_uid, liked, timestamp FROM %s%s WHERE board_uid = ?",
+		column, configs.Env.Prefix, table)
+	rows, err := r.db.Query(query, boardUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ArchiveLike{}
+		var targetUid uint
+		if err := rows.Scan(&targetUid, &item.UserUid, &item.Liked, &item.Timestamp); err != nil {
+			return items, err
+		}
+		items[targetUid] = append(items[targetUid], item)
+	}
+	return items, rows.Err()
+}
+
+// 게시판의 게시글 목록 가져오기 (삭제된 글은 제외)
+func (r *NuboArchiveRepository) GetArchivePosts(boardUid uint) ([]models.ArchivePost, error) {
+	items := make([]models.ArchivePost, 0)
+	query := fmt.Sprintf(`SELECT uid, user_uid, category_uid, title, COALESCE(content, ''), submitted, modified, hit, status
+		FROM %s%s WHERE board_uid = ? AND status != ? ORDER BY uid ASC`, configs.Env.Prefix, models.TABLE_POST)
+	rows, err := r.db.Query(query, boardUid, models.CONTENT_REMOVED)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ArchivePost{}
+		if err := rows.Scan(&item.Uid, &item.UserUid, &item.CategoryUid, &item.Title, &item.Content,
+			&item.Submitted, &item.Modified, &item.Hit, &item.Status); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 게시판의 태그 이름들을 게시글별로 가져오기
+func (r *NuboArchiveRepository) GetArchiveTags(boardUid uint) (map[uint][]string, error) {
+	items := make(map[uint][]string)
+	query := fmt.Sprintf(`SELECT ph.post_uid, h.name FROM %s%s ph JOIN %s%s h ON h.uid = ph.hashtag_uid
+		WHERE ph.board_uid = ?`, configs.Env.Prefix, models.TABLE_POST_HASHTAG, configs.Env.Prefix, models.TABLE_HASHTAG)
+	rows, err := r.db.Query(query, boardUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postUid uint
+		var name string
+		if err := rows.Scan(&postUid, &name); err != nil {
+			return items, err
+		}
+		items[postUid] = append(items[postUid], name)
+	}
+	return items, rows.Err()
+}
+
+// 보관 파일에 기록할 회원 아이디와 이름 가져오기
+func (r *NuboArchiveRepository) GetArchiveUsers(userUids []uint) ([]models.ArchiveUser, error) {
+	items := make([]models.ArchiveUser, 0)
+	if len(userUids) == 0 {
+		return items, nil
+	}
+	args := make([]any, 0, len(userUids))
+	for _, uid := range userUids {
+		args = append(args, uid)
+	}
+	query := fmt.Sprintf("SELECT uid, id, name FROM %s%s WHERE uid IN (%s)", configs.Env.Prefix, models.TABLE_USER,
+		strings.TrimSuffix(strings.Repeat("?,", len(userUids)), ","))
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ArchiveUser{}
+		if err := rows.Scan(&item.Uid, &item.Id, &item.Name); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 삽입 이미지 기록을 원래 시간 그대로 저장하기
+func (r *NuboArchiveRepository) InsertArchiveImage(boardUid uint, userUid uint, image models.ArchiveImage) error {
+	query := fmt.Sprintf("INSERT INTO %s%s (board_uid, user_uid, path, timestamp) VALUES (?, ?, ?, ?)",
+		configs.Env.Prefix, models.TABLE_IMAGE)
+	_, err := r.db.Exec(query, boardUid, userUid, image.Path, image.Timestamp)
+	return err
+}
+
+// 좋아요 기록들을 한 번에 저장하기
+func (r *NuboArchiveRepository) InsertArchiveLikes(table models.Table, boardUid uint, targetUid uint, likes []models.ArchiveLike) error {
+	if len(likes) == 0 {
+		return nil
+	}
+	column, err := archiveLikeColumn(table)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s%s (board_uid, %s, user_uid, liked, timestamp) VALUES ",
+		configs.Env.Prefix, table, column)
+	values := make([]any, 0, len(likes)*5)
+	for _, like := range likes {
+		query += "(?, ?, ?, ?, ?),"
+		values = append(values, boardUid, targetUid, like.UserUid, like.Liked, like.Timestamp)
+	}
+	_, err = r.db.Exec(strings.TrimSuffix(query, ","), values...)
+	return err
+}
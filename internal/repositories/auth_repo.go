@@ -46,7 +46,7 @@ func NewNuboAuthRepository(db *sql.DB) *NuboAuthRepository {
 	return &NuboAuthRepository{db: db}
 }
 
-// 게시판, 그룹 혹은 최고 관리자인지 확인
+// 게시판, 그룹 혹은 최고 관리자이거나 관리 권한이 허용된 역할인지 확인
 func (r *NuboAuthRepository) CheckPermissionByUid(userUid uint, boardUid uint) bool {
 	if userUid == 1 {
 		return true
@@ -55,7 +55,7 @@ func (r *NuboAuthRepository) CheckPermissionByUid(userUid uint, boardUid uint) b
 	if userUid == adminUid.Group || userUid == adminUid.Board {
 		return true
 	}
-	return false
+	return userUid > 0 && evaluateBoardAcl(r.db, boardUid, userUid, models.BOARD_ACTION_MANAGE) == models.BOARD_ACL_ALLOW
 }
 
 // 사용자가 지정된 액션에 대한 권한이 있는지 확인
@@ -137,12 +137,12 @@ func (r *NuboAuthRepository) ClearRefreshToken(userUid uint) {
 // 회원번호에 해당하는 사용자의 공개 정보 반환
 func (r *NuboAuthRepository) FindUserInfoByUid(userUid uint) (models.UserInfoResult, error) {
 	info := models.UserInfoResult{}
-	query := fmt.Sprintf(`SELECT name, profile, level, signature, signup, signin, blocked 
+	query := fmt.Sprintf(`SELECT name, profile, profile_fallback, level, signature, signup, signin, blocked 
 												FROM %s%s WHERE uid = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_USER)
 
 	var blocked uint
 	err := r.db.QueryRow(query, userUid).Scan(
-		&info.Name, &info.Profile, &info.Level, &info.Signature, &info.Signup, &info.Signin, &blocked)
+		&info.Name, &info.Profile, &info.ProfileFallback, &info.Level, &info.Signature, &info.Signup, &info.Signin, &blocked)
 	if err != nil {
 		return info, err
 	}
@@ -156,11 +156,11 @@ func (r *NuboAuthRepository) FindUserInfoByUid(userUid uint) (models.UserInfoRes
 // 아이디와 (sha256으로 해시된)비밀번호로 내정보 가져오기
 func (r *NuboAuthRepository) FindMyInfoByIDPW(id string, pw string) models.MyInfoResult {
 	info := models.MyInfoResult{}
-	query := fmt.Sprintf(`SELECT uid, name, profile, level, point, signature, signup 
+	query := fmt.Sprintf(`SELECT uid, name, profile, profile_fallback, level, point, signature, signup 
 												FROM %s%s WHERE blocked = 0 AND id = ? AND password = ? LIMIT 1`,
 		configs.Env.Prefix, models.TABLE_USER)
 
-	err := r.db.QueryRow(query, id, pw).Scan(&info.Uid, &info.Name, &info.Profile, &info.Level, &info.Point, &info.Signature, &info.Signup)
+	err := r.db.QueryRow(query, id, pw).Scan(&info.Uid, &info.Name, &info.Profile, &info.ProfileFallback, &info.Level, &info.Point, &info.Signature, &info.Signup)
 	if err == sql.ErrNoRows {
 		return info
 	}
@@ -175,10 +175,10 @@ func (r *NuboAuthRepository) FindMyInfoByIDPW(id string, pw string) models.MyInf
 // 사용자 고유 번호로 내정보 가져오기
 func (r *NuboAuthRepository) FindMyInfoByUid(userUid uint) models.MyInfoResult {
 	info := models.MyInfoResult{}
-	query := fmt.Sprintf(`SELECT uid, id, name, profile, level, point, signature, signup, signin, blocked 
+	query := fmt.Sprintf(`SELECT uid, id, name, profile, profile_fallback, level, point, signature, signup, signin, blocked 
 												FROM %s%s WHERE uid = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_USER)
 
-	err := r.db.QueryRow(query, userUid).Scan(&info.Uid, &info.Id, &info.Name, &info.Profile, &info.Level, &info.Point, &info.Signature, &info.Signup, &info.Signin, &info.Blocked)
+	err := r.db.QueryRow(query, userUid).Scan(&info.Uid, &info.Id, &info.Name, &info.Profile, &info.ProfileFallback, &info.Level, &info.Point, &info.Signature, &info.Signup, &info.Signin, &info.Blocked)
 	if err == sql.ErrNoRows {
 		return info
 	}
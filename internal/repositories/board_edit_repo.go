@@ -7,7 +7,6 @@ import (
 
 	"github.com/sirini/goapi/internal/configs"
 	"github.com/sirini/goapi/pkg/models"
-	"github.com/sirini/goapi/pkg/utils"
 )
 
 type BoardEditRepository interface {
@@ -24,11 +23,11 @@ type BoardEditRepository interface {
 	InsertFile(param models.EditorSaveFileParam) (uint, error)
 	InsertFileThumbnail(param models.EditorSaveThumbnailParam) error
 	InsertImageDescription(fileUid uint, postUid uint, description string) error
-	InsertImagePaths(boardUid uint, userUid uint, paths []string) error
+	InsertImagePaths(boardUid uint, userUid uint, images []models.SavedImage) error
 	InsertPost(param models.EditorWriteParam, point models.UpdatePointParam) (uint, error)
 	InsertPostHashtag(boardUid uint, postUid uint, hashtagUid uint) error
 	InsertTag(boardUid uint, postUid uint, tag string) (uint, error)
-	RemoveInsertedImage(imageUid uint, actionUserUid uint) (string, error)
+	RemoveInsertedImage(imageUid uint, actionUserUid uint) ([]string, error)
 	UpdatePost(param models.EditorModifyParam) error
 	UpdateTag(hashtagUid uint) error
 }
@@ -141,7 +140,7 @@ func (r *NuboBoardEditRepository) GetSuggestionTitles(input string, bunch uint)
 // 태그 추천하기 목록 가져오기
 func (r *NuboBoardEditRepository) GetSuggestionTags(input string, bunch uint) ([]models.EditorTagItem, error) {
 	items := make([]models.EditorTagItem, 0)
-	query := fmt.Sprintf("SELECT uid, name, used FROM %s%s WHERE name LIKE ? LIMIT ?",
+	query := fmt.Sprintf("SELECT uid, name, used FROM %s%s WHERE name LIKE ? AND banned = 0 LIMIT ?",
 		configs.Env.Prefix, models.TABLE_HASHTAG)
 
 	rows, err := r.db.Query(query, "%"+input+"%", bunch)
@@ -197,12 +196,14 @@ func (r *NuboBoardEditRepository) InsertFile(param models.EditorSaveFileParam) (
 	return uint(insertId), nil
 }
 
-// 썸네일 경로 저장하기
+// 썸네일 경로와 반응형 변형 저장하기 (AVIF 썸네일이면 대체 형식 경로도 함께)
 func (r *NuboBoardEditRepository) InsertFileThumbnail(param models.EditorSaveThumbnailParam) error {
-	query := fmt.Sprintf("INSERT INTO %s%s (file_uid, post_uid, path, full_path) VALUES (?, ?, ?, ?)",
-		configs.Env.Prefix, models.TABLE_FILE_THUMB)
-	_, err := r.db.Exec(query, param.FileUid, param.PostUid, param.Small, param.Large)
-	return err
+	query := fmt.Sprintf(`INSERT INTO %s%s (file_uid, post_uid, path, full_path, fallback_path, fallback_full_path)
+		VALUES (?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_FILE_THUMB)
+	if _, err := r.db.Exec(query, param.FileUid, param.PostUid, param.Small, param.Large, param.SmallFallback, param.LargeFallback); err != nil {
+		return err
+	}
+	return insertImageVariants(r.db, param.FileUid, param.Small, param.Variants)
 }
 
 // 이미지 설명글 저장하기 (OpenAI API 사용 시에만 가능)
@@ -213,17 +214,17 @@ func (r *NuboBoardEditRepository) InsertImageDescription(fileUid uint, postUid u
 	return err
 }
 
-// 게시글에 삽입한 이미지 정보들을 한 번에 저장하기
-func (r *NuboBoardEditRepository) InsertImagePaths(boardUid uint, userUid uint, paths []string) error {
-	query := fmt.Sprintf("INSERT INTO %s%s (board_uid, user_uid, path, timestamp) VALUES ",
+// 게시글에 삽입한 이미지 정보들(AVIF이면 대체 형식 경로도 함께)을 한 번에 저장하기
+func (r *NuboBoardEditRepository) InsertImagePaths(boardUid uint, userUid uint, images []models.SavedImage) error {
+	query := fmt.Sprintf("INSERT INTO %s%s (board_uid, user_uid, path, fallback_path, timestamp) VALUES ",
 		configs.Env.Prefix, models.TABLE_IMAGE)
 
 	values := make([]any, 0)
 	now := time.Now().UnixMilli()
 
-	for _, path := range paths {
-		query += "(?, ?, ?, ?),"
-		values = append(values, boardUid, userUid, path, now)
+	for _, image := range images {
+		query += "(?, ?, ?, ?, ?),"
+		values = append(values, boardUid, userUid, image.Path, image.Fallback, now)
 	}
 
 	query = query[:len(query)-1]
@@ -246,7 +247,7 @@ func (r *NuboBoardEditRepository) InsertPost(param models.EditorWriteParam, poin
 												(board_uid, user_uid, category_uid, title, content, submitted, modified, hit, status) 
 												VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_POST)
 
-	status := utils.GetContentStatus(param.IsNotice, param.IsSecret)
+	status := writeStatus(param)
 	result, err := tx.Exec(
 		query,
 		param.BoardUid,
@@ -267,6 +268,12 @@ func (r *NuboBoardEditRepository) InsertPost(param models.EditorWriteParam, poin
 	if err != nil {
 		return models.FAILED, err
 	}
+	if err := insertPostApprovalTx(tx, uint(insertId), param); err != nil {
+		return models.FAILED, err
+	}
+	if err := insertSyncChange(tx, uint(insertId), models.SYNC_CREATED); err != nil {
+		return models.FAILED, err
+	}
 	if err := tx.Commit(); err != nil {
 		return models.FAILED, err
 	}
@@ -298,25 +305,52 @@ func (r *NuboBoardEditRepository) InsertTag(boardUid uint, postUid uint, tag str
 	return uint(hashtagUid), nil
 }
 
-// 게시글에 삽입한 이미지 삭제하기
-func (r *NuboBoardEditRepository) RemoveInsertedImage(imageUid uint, actionUserUid uint) (string, error) {
-	query := fmt.Sprintf("SELECT user_uid, path FROM %s%s WHERE uid = ? LIMIT 1",
+// 게시글에 삽입한 이미지 삭제하고 지워야 할 파일 경로들(원본, 대체 이미지, 변형 이미지) 반환하기
+func (r *NuboBoardEditRepository) RemoveInsertedImage(imageUid uint, actionUserUid uint) ([]string, error) {
+	query := fmt.Sprintf("SELECT user_uid, path, fallback_path FROM %s%s WHERE uid = ? LIMIT 1",
 		configs.Env.Prefix, models.TABLE_IMAGE)
 
 	var userUid uint
-	var path string
-	err := r.db.QueryRow(query, imageUid).Scan(&userUid, &path)
+	var path, fallback string
+	err := r.db.QueryRow(query, imageUid).Scan(&userUid, &path, &fallback)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if actionUserUid != userUid {
-		return "", fmt.Errorf("unauthorized access, only writer can remove an image")
+		return nil, fmt.Errorf("unauthorized access, only writer can remove an image")
+	}
+
+	removes := []string{path}
+	if len(fallback) > 0 {
+		removes = append(removes, fallback)
+	}
+	query = fmt.Sprintf("SELECT path FROM %s%s WHERE source = ?", configs.Env.Prefix, models.TABLE_IMAGE_VARIANT)
+	rows, err := r.db.Query(query, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var variant string
+		if err := rows.Scan(&variant); err != nil {
+			return nil, err
+		}
+		removes = append(removes, variant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	query = fmt.Sprintf("DELETE FROM %s%s WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_IMAGE)
-	_, err = r.db.Exec(query, imageUid)
-	return path, err
+	if _, err = r.db.Exec(query, imageUid); err != nil {
+		return nil, err
+	}
+	query = fmt.Sprintf("DELETE FROM %s%s WHERE source = ?", configs.Env.Prefix, models.TABLE_IMAGE_VARIANT)
+	if _, err = r.db.Exec(query, path); err != nil {
+		return nil, err
+	}
+	return removes, nil
 }
 
 // 기존 게시글 수정하기
@@ -324,7 +358,7 @@ func (r *NuboBoardEditRepository) UpdatePost(param models.EditorModifyParam) err
 	query := fmt.Sprintf(`UPDATE %s%s SET category_uid = ?, title = ?, content = ?, modified = ?, status = ? 
 												WHERE uid = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_POST)
 
-	status := utils.GetContentStatus(param.IsNotice, param.IsSecret)
+	status := writeStatus(param.EditorWriteParam)
 	_, err := r.db.Exec(
 		query,
 		param.CategoryUid,
@@ -334,7 +368,10 @@ func (r *NuboBoardEditRepository) UpdatePost(param models.EditorModifyParam) err
 		status,
 		param.PostUid,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return insertSyncChange(r.db, param.PostUid, models.SYNC_MODIFIED)
 }
 
 // 기존 태그 사용 횟수 올리고 태그와 게시글 번호 연결하기
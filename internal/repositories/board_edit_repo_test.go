@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirini/goapi/internal/configs"
+)
+
+func TestRemoveInsertedImageReturnsVariantPathsBeforeDeletingRows(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Prefix = "nubo_"
+	t.Cleanup(func() { configs.Env = previous })
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewNuboBoardEditRepository(db, nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_uid, path, fallback_path FROM nubo_image WHERE uid = ?")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"user_uid", "path", "fallback_path"}).
+			AddRow(7, "/upload/images/a.avif", "/upload/images/a.webp"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT path FROM nubo_image_variant WHERE source = ?")).
+		WithArgs("/upload/images/a.avif").
+		WillReturnRows(sqlmock.NewRows([]string{"path"}).AddRow("/upload/images/a-480.avif").AddRow("/upload/images/a-480.webp"))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM nubo_image WHERE uid = ?")).
+		WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM nubo_image_variant WHERE source = ?")).
+		WithArgs("/upload/images/a.avif").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	removes, err := repo.RemoveInsertedImage(3, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/upload/images/a.avif", "/upload/images/a.webp", "/upload/images/a-480.avif", "/upload/images/a-480.webp"}
+	if len(removes) != len(want) {
+		t.Fatalf("removes = %v, want %v", removes, want)
+	}
+	for i := range want {
+		if removes[i] != want[i] {
+			t.Fatalf("removes = %v, want %v", removes, want)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
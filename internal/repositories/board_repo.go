@@ -80,16 +80,19 @@ func (r *NuboBoardRepository) GetBoardConfig(boardUid uint) models.BoardConfig {
 	config := models.BoardConfig{}
 	query := fmt.Sprintf(`SELECT id, group_uid, admin_uid, type, skin_key, name, info, row_count, width, use_category,
 												level_list, level_view, level_write, level_comment, level_download,
-												point_view, point_write, point_comment, point_download 
+												point_view, point_write, point_comment, point_download, require_approval, comment_depth, public_comment_history, feed_summary, sync_scope 
 												FROM %s%s WHERE uid = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_BOARD)
 
-	var useCategory uint8
+	var useCategory, requireApproval, publicCommentHistory, feedSummary uint8
 	r.db.QueryRow(query, boardUid).Scan(&config.Id, &config.GroupUid, &config.Admin.Board, &config.Type, &config.SkinKey, &config.Name, &config.Info,
 		&config.RowCount, &config.Width, &useCategory, &config.Level.List, &config.Level.View,
 		&config.Level.Write, &config.Level.Comment, &config.Level.Download, &config.Point.View,
-		&config.Point.Write, &config.Point.Comment, &config.Point.Download)
+		&config.Point.Write, &config.Point.Comment, &config.Point.Download, &requireApproval, &config.CommentDepth, &publicCommentHistory, &feedSummary, &config.SyncScope)
 	config.Uid = boardUid
 	config.UseCategory = useCategory > 0
+	config.RequireApproval = requireApproval > 0
+	config.PublicCommentHistory = publicCommentHistory > 0
+	config.FeedSummary = feedSummary > 0
 	config.Category = r.GetBoardCategories(boardUid)
 	config.Admin.Group = r.GetGroupAdminUid(boardUid)
 	return config
@@ -20,7 +20,7 @@ type BoardViewRepository interface {
 	GetBasicBoardConfig(boardUid uint) models.BoardBasicConfig
 	GetDownloadInfo(fileUid uint) models.BoardViewDownloadResult
 	GetExif(fileUid uint) models.BoardExif
-	GetNeededLevelPoint(boardUid uint, action models.BoardAction) (int, int)
+	GetNeededLevelPoint(boardUid uint, userUid uint, action models.BoardAction) (int, int)
 	GetPrevPostUid(boardUid uint, postUid uint) uint
 	GetNextPostUid(boardUid uint, postUid uint) uint
 	GetPostItem(postUid uint, actionUserUid uint) (models.BoardListItem, error)
@@ -47,7 +47,8 @@ type BoardViewRepository interface {
 	RemovePostTags(postUid uint)
 	RemoveThumbnails(fileUid uint) []string
 	UpdateLikePost(param models.BoardViewLikeParam)
-	UpdatePostHit(postUid uint)
+	CountPostView(postUid uint, viewerHash string, since int64) (bool, error)
+	RemovePostViewsBefore(before int64) error
 	MovePost(targetBoardUid uint, postUid uint) error
 }
 
@@ -231,10 +232,7 @@ func (r *NuboBoardViewRepository) GetAttachedImages(postUid uint) ([]models.Boar
 				Uid:  fileUid,
 				Path: filePath,
 			},
-			Thumbnail: models.BoardThumbnail{
-				Small: thumb.Small,
-				Large: thumb.Large,
-			},
+			Thumbnail:   thumb,
 			Exif:        exif,
 			Description: desc,
 		}
@@ -283,34 +281,40 @@ func (r *NuboBoardViewRepository) GetImageDescription(fileUid uint) string {
 	return description
 }
 
-// Action에 필요한 포인트 양 확인하기
-func (r *NuboBoardViewRepository) GetNeededLevelPoint(boardUid uint, action models.BoardAction) (int, int) {
+// Action에 필요한 레벨과 포인트 양 확인하기 (역할 규칙으로 허용되면 레벨 면제, 거부되면 도달 불가 레벨 반환)
+func (r *NuboBoardViewRepository) GetNeededLevelPoint(boardUid uint, userUid uint, action models.BoardAction) (int, int) {
 	var level, point int
 	act := action.String()
 	query := fmt.Sprintf("SELECT level_%s, point_%s FROM %s%s WHERE uid = ? LIMIT 1",
 		act, act, configs.Env.Prefix, models.TABLE_BOARD)
 
 	r.db.QueryRow(query, boardUid).Scan(&level, &point)
+	switch evaluateBoardAcl(r.db, boardUid, userUid, action) {
+	case models.BOARD_ACL_ALLOW:
+		level = 0
+	case models.BOARD_ACL_DENY:
+		level = models.BOARD_LEVEL_DENIED
+	}
 	return level, point
 }
 
 // 현재 게시글의 이전 게시글 번호 가져오기
 func (r *NuboBoardViewRepository) GetPrevPostUid(boardUid uint, postUid uint) uint {
 	var prevUid uint
-	query := fmt.Sprintf(`SELECT uid FROM %s%s WHERE board_uid = ? AND status != ? AND uid < ? 
+	query := fmt.Sprintf(`SELECT uid FROM %s%s WHERE board_uid = ? AND status NOT IN (?, ?) AND uid < ? 
 												ORDER BY uid DESC LIMIT 1`, configs.Env.Prefix, models.TABLE_POST)
 
-	r.db.QueryRow(query, boardUid, models.CONTENT_REMOVED, postUid).Scan(&prevUid)
+	r.db.QueryRow(query, boardUid, models.CONTENT_REMOVED, models.CONTENT_PENDING, postUid).Scan(&prevUid)
 	return prevUid
 }
 
 // 현재 게시글의 다음 게시글 번호 가져오기
 func (r *NuboBoardViewRepository) GetNextPostUid(boardUid uint, postUid uint) uint {
 	var nextUid uint
-	query := fmt.Sprintf(`SELECT uid FROM %s%s WHERE board_uid = ? AND status != ? AND uid > ?
+	query := fmt.Sprintf(`SELECT uid FROM %s%s WHERE board_uid = ? AND status NOT IN (?, ?) AND uid > ?
 											 ORDER BY uid ASC LIMIT 1`, configs.Env.Prefix, models.TABLE_POST)
 
-	r.db.QueryRow(query, boardUid, models.CONTENT_REMOVED, postUid).Scan(&nextUid)
+	r.db.QueryRow(query, boardUid, models.CONTENT_REMOVED, models.CONTENT_PENDING, postUid).Scan(&nextUid)
 	return nextUid
 }
 
@@ -401,12 +405,14 @@ func (r *NuboBoardViewRepository) GetTagName(hashtagUid uint) string {
 	return name
 }
 
-// 썸네일 이미지 가져오기
+// 썸네일 이미지와 반응형 변형 가져오기
 func (r *NuboBoardViewRepository) GetThumbnailImage(fileUid uint) models.BoardThumbnail {
 	thumb := models.BoardThumbnail{}
-	query := fmt.Sprintf("SELECT path, full_path FROM %s%s WHERE file_uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_FILE_THUMB)
+	query := fmt.Sprintf("SELECT path, full_path, fallback_path, fallback_full_path FROM %s%s WHERE file_uid = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_FILE_THUMB)
 
-	r.db.QueryRow(query, fileUid).Scan(&thumb.Small, &thumb.Large)
+	r.db.QueryRow(query, fileUid).Scan(&thumb.Small, &thumb.Large, &thumb.SmallFallback, &thumb.LargeFallback)
+	thumb.Variants = getFileImageVariants(r.db, fileUid)
 	return thumb
 }
 
@@ -443,10 +449,10 @@ func (r *NuboBoardViewRepository) GetWriterLatestComment(writerUid uint, limit u
 // 게시글 작성자의 최근 포스트들 가져오기
 func (r *NuboBoardViewRepository) GetWriterLatestPost(writerUid uint, limit uint) ([]models.BoardWriterLatestPost, error) {
 	query := fmt.Sprintf(`SELECT uid, board_uid, title, submitted FROM %s%s 
-												WHERE user_uid = ? AND status != ? 
+												WHERE user_uid = ? AND status NOT IN (?, ?) 
 												ORDER BY uid DESC LIMIT ?`, configs.Env.Prefix, models.TABLE_POST)
 
-	rows, err := r.db.Query(query, writerUid, models.CONTENT_REMOVED, limit)
+	rows, err := r.db.Query(query, writerUid, models.CONTENT_REMOVED, models.CONTENT_PENDING, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -559,8 +565,10 @@ func (r *NuboBoardViewRepository) RemoveImageDescription(fileUid uint) {
 // 게시글 삭제 상태로 변경하기
 func (r *NuboBoardViewRepository) RemovePost(postUid uint) error {
 	query := fmt.Sprintf("UPDATE %s%s SET status = ? WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_POST)
-	_, err := r.db.Exec(query, models.CONTENT_REMOVED, postUid)
-	return err
+	if _, err := r.db.Exec(query, models.CONTENT_REMOVED, postUid); err != nil {
+		return err
+	}
+	return insertSyncChange(r.db, postUid, models.SYNC_REMOVED)
 }
 
 // 게시글에 등록된 태그 제거하기
@@ -608,6 +616,8 @@ func (r *NuboBoardViewRepository) RemoveThumbnails(fileUid uint) []string {
 
 	query = fmt.Sprintf("DELETE FROM %s%s WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_FILE_THUMB)
 	r.db.Exec(query, uid)
+	query = fmt.Sprintf("DELETE FROM %s%s WHERE file_uid = ?", configs.Env.Prefix, models.TABLE_IMAGE_VARIANT)
+	r.db.Exec(query, fileUid)
 	return removes
 }
 
@@ -619,10 +629,37 @@ func (r *NuboBoardViewRepository) UpdateLikePost(param models.BoardViewLikeParam
 	r.db.Exec(query, param.Liked, time.Now().UnixMilli(), param.PostUid, param.UserUid)
 }
 
-// 조회수 업데이트 하기
-func (r *NuboBoardViewRepository) UpdatePostHit(postUid uint) {
-	query := fmt.Sprintf("UPDATE %s%s SET hit = hit + 1 WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_POST)
-	r.db.Exec(query, postUid)
+// 조회자가 since 이후 이 글을 본 적이 없을 때만 조회 기록을 남기고 조회수 올리기 (올렸으면 true)
+func (r *NuboBoardViewRepository) CountPostView(postUid uint, viewerHash string, since int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// 새로 추가되면 1, 기간이 지난 기록을 갱신하면 2, 기간 안이라 그대로면 0행이 바뀜
+	query := fmt.Sprintf(`INSERT INTO %s%s (post_uid, viewer_hash, viewed) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE viewed = IF(viewed < ?, VALUES(viewed), viewed)`,
+		configs.Env.Prefix, models.TABLE_POST_VIEW)
+	result, err := tx.Exec(query, postUid, viewerHash, time.Now().UnixMilli(), since)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+	query = fmt.Sprintf("UPDATE %s%s SET hit = hit + 1 WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_POST)
+	if _, err := tx.Exec(query, postUid); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// 중복 확인 기간이 지난 조회 기록 지우기
+func (r *NuboBoardViewRepository) RemovePostViewsBefore(before int64) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE viewed < ?", configs.Env.Prefix, models.TABLE_POST_VIEW)
+	_, err := r.db.Exec(query, before)
+	return err
 }
 
 // 게시글과 종속 레코드의 소속 게시판을 하나의 트랜잭션에서 변경한다.
@@ -654,10 +691,16 @@ func (r *NuboBoardViewRepository) MovePost(targetBoardUid uint, postUid uint) er
 	if _, err := tx.Exec(query, targetBoardUid, postUid); err != nil {
 		return err
 	}
+	if err := insertSyncChange(tx, postUid, models.SYNC_REMOVED); err != nil {
+		return err
+	}
 	query = fmt.Sprintf("UPDATE %s%s SET board_uid = ?, category_uid = ?, modified = ? WHERE uid = ? LIMIT 1",
 		configs.Env.Prefix, models.TABLE_POST)
 	if _, err := tx.Exec(query, targetBoardUid, targetCategoryUid, time.Now().UnixMilli(), postUid); err != nil {
 		return err
 	}
+	if err := insertSyncChange(tx, postUid, models.SYNC_CREATED); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
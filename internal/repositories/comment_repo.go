@@ -3,6 +3,7 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sirini/goapi/internal/configs"
@@ -11,17 +12,19 @@ import (
 
 type CommentRepository interface {
 	FindPostUserUidByUid(commentUid uint) (uint, uint)
-	GetComments(param models.CommentListParam) ([]models.CommentItem, error)
+	GetComments(param models.CommentListParam, expand uint) ([]models.CommentItem, error)
+	GetCommentHistory(commentUid uint) ([]models.CommentHistoryItem, error)
+	GetCommentThread(commentUid uint) (models.CommentThread, error)
 	GetPostStatus(postUid uint) models.Status
 	GetPostWriterUid(postUid uint) uint
-	HasReplyComment(commentUid uint) bool
+	GetThreadCount(postUid uint, parentUid uint) uint
 	IsLikedComment(commentUid uint, userUid uint) bool
 	IsCommentInBoard(commentUid uint, boardUid uint) bool
 	IsCommentInPost(commentUid uint, postUid uint, boardUid uint) bool
-	InsertComment(param models.CommentWriteParam, replyUid uint, point models.UpdatePointParam) (uint, error)
+	InsertComment(param models.CommentWriteParam, parentUid uint, point models.UpdatePointParam) (uint, error)
 	InsertLikeComment(param models.CommentLikeParam)
 	RemoveComment(commentUid uint) error
-	UpdateComment(commentUid uint, content string)
+	UpdateComment(commentUid uint, editorUid uint, content string) error
 	UpdateLikeComment(param models.CommentLikeParam)
 }
 
@@ -85,26 +88,26 @@ func (r *NuboCommentRepository) GetPostWriterUid(postUid uint) uint {
 	return userUid
 }
 
-// 이 댓글에 답글이 하나라도 있는지 확인하기
-func (r *NuboCommentRepository) HasReplyComment(commentUid uint) bool {
-	var replyUid uint
-	query := fmt.Sprintf("SELECT reply_uid FROM %s%s WHERE uid = ? AND status != ? LIMIT 1",
+// 댓글의 스레드 위치 정보 가져오기
+func (r *NuboCommentRepository) GetCommentThread(commentUid uint) (models.CommentThread, error) {
+	thread := models.CommentThread{Uid: commentUid}
+	var status int8
+	query := fmt.Sprintf("SELECT post_uid, parent_uid, reply_uid, depth, path, status FROM %s%s WHERE uid = ? LIMIT 1",
 		configs.Env.Prefix, models.TABLE_COMMENT)
 
-	r.db.QueryRow(query, commentUid, models.CONTENT_REMOVED).Scan(&replyUid)
-	if replyUid != commentUid {
-		return false
-	}
+	err := r.db.QueryRow(query, commentUid).Scan(&thread.PostUid, &thread.ParentUid, &thread.RootUid, &thread.Depth, &thread.Path, &status)
+	thread.Status = models.Status(status)
+	return thread, err
+}
 
-	var exists bool
-	query = fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s%s WHERE reply_uid = ? AND uid != ? AND status != ?)",
-		configs.Env.Prefix, models.TABLE_COMMENT)
+// 같은 부모 아래 보여줄 수 있는 댓글(스레드) 개수 가져오기
+func (r *NuboCommentRepository) GetThreadCount(postUid uint, parentUid uint) uint {
+	var count uint
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s AS c WHERE c.post_uid = ? AND c.parent_uid = ? AND %s",
+		configs.Env.Prefix, models.TABLE_COMMENT, visibleCommentCondition("c"))
 
-	err := r.db.QueryRow(query, commentUid, commentUid, models.CONTENT_REMOVED).Scan(&exists)
-	if err != nil {
-		return false
-	}
-	return exists
+	r.db.QueryRow(query, postUid, parentUid).Scan(&count)
+	return count
 }
 
 // 이미 이 댓글에 좋아요를 클릭한 적이 있는지 확인하기
@@ -117,8 +120,8 @@ func (r *NuboCommentRepository) IsLikedComment(commentUid uint, userUid uint) bo
 	return uid > 0
 }
 
-// 새로운 댓글 작성하기
-func (r *NuboCommentRepository) InsertComment(param models.CommentWriteParam, replyUid uint, point models.UpdatePointParam) (uint, error) {
+// 새로운 댓글 작성하기 (parentUid가 있으면 해당 댓글의 답글로 경로를 이어 붙임)
+func (r *NuboCommentRepository) InsertComment(param models.CommentWriteParam, parentUid uint, point models.UpdatePointParam) (uint, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return models.FAILED, err
@@ -128,13 +131,26 @@ func (r *NuboCommentRepository) InsertComment(param models.CommentWriteParam, re
 		return models.FAILED, err
 	}
 
+	var rootUid, depth uint
+	var parentPath string
+	if parentUid > 0 {
+		query := fmt.Sprintf("SELECT reply_uid, depth, path FROM %s%s WHERE uid = ? LIMIT 1 FOR UPDATE",
+			configs.Env.Prefix, models.TABLE_COMMENT)
+		if err := tx.QueryRow(query, parentUid).Scan(&rootUid, &depth, &parentPath); err != nil {
+			return models.FAILED, err
+		}
+		depth++
+	}
+
 	query := fmt.Sprintf(`INSERT INTO %s%s 
-												(reply_uid, board_uid, post_uid, user_uid, content, submitted, modified, status) 
-												VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_COMMENT)
+												(reply_uid, parent_uid, depth, board_uid, post_uid, user_uid, content, submitted, modified, status) 
+												VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_COMMENT)
 
 	result, err := tx.Exec(
 		query,
-		replyUid,
+		rootUid,
+		parentUid,
+		depth,
 		param.BoardUid,
 		param.PostUid,
 		param.UserUid,
@@ -150,12 +166,13 @@ func (r *NuboCommentRepository) InsertComment(param models.CommentWriteParam, re
 	if err != nil {
 		return models.FAILED, err
 	}
-	if replyUid == 0 {
-		query = fmt.Sprintf("UPDATE %s%s SET reply_uid = ? WHERE uid = ? LIMIT 1",
-			configs.Env.Prefix, models.TABLE_COMMENT)
-		if _, err := tx.Exec(query, insertId, insertId); err != nil {
-			return models.FAILED, err
-		}
+	if rootUid == 0 {
+		rootUid = uint(insertId)
+	}
+	query = fmt.Sprintf("UPDATE %s%s SET reply_uid = ?, path = ? WHERE uid = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_COMMENT)
+	if _, err := tx.Exec(query, rootUid, commentPath(parentPath, uint(insertId)), insertId); err != nil {
+		return models.FAILED, err
 	}
 	if err := tx.Commit(); err != nil {
 		return models.FAILED, err
@@ -179,12 +196,70 @@ func (r *NuboCommentRepository) RemoveComment(commentUid uint) error {
 	return err
 }
 
-// 기존 댓글 수정하기
-func (r *NuboCommentRepository) UpdateComment(commentUid uint, content string) {
-	query := fmt.Sprintf("UPDATE %s%s SET content = ?, modified = ? WHERE uid = ? LIMIT 1",
+// 기존 댓글 수정하기 (수정 전 내용은 이력으로 남김)
+func (r *NuboCommentRepository) UpdateComment(commentUid uint, editorUid uint, content string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var boardUid uint
+	var oldContent string
+	var submitted, modified uint64
+	query := fmt.Sprintf("SELECT board_uid, content, submitted, modified FROM %s%s WHERE uid = ? LIMIT 1 FOR UPDATE",
+		configs.Env.Prefix, models.TABLE_COMMENT)
+	if err := tx.QueryRow(query, commentUid).Scan(&boardUid, &oldContent, &submitted, &modified); err != nil {
+		return err
+	}
+	if oldContent == content {
+		return nil
+	}
+	written := submitted
+	if modified > 0 {
+		written = modified
+	}
+
+	now := time.Now().UnixMilli()
+	query = fmt.Sprintf(`INSERT INTO %s%s (comment_uid, board_uid, user_uid, content, written, timestamp)
+												VALUES (?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_COMMENT_HIST)
+	if _, err := tx.Exec(query, commentUid, boardUid, editorUid, oldContent, written, now); err != nil {
+		return err
+	}
+	query = fmt.Sprintf("UPDATE %s%s SET content = ?, modified = ? WHERE uid = ? LIMIT 1",
 		configs.Env.Prefix, models.TABLE_COMMENT)
+	if _, err := tx.Exec(query, content, now, commentUid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// 댓글 수정 이력 가져오기 (최근 수정부터)
+func (r *NuboCommentRepository) GetCommentHistory(commentUid uint) ([]models.CommentHistoryItem, error) {
+	items := make([]models.CommentHistoryItem, 0)
+	query := fmt.Sprintf(`SELECT h.uid, h.user_uid, IFNULL(u.name, ''), IFNULL(u.profile, ''), h.content, h.written, h.timestamp
+		FROM %s%s AS h
+		LEFT JOIN %s%s AS u ON h.user_uid = u.uid
+		WHERE h.comment_uid = ?
+		ORDER BY h.uid DESC`,
+		configs.Env.Prefix, models.TABLE_COMMENT_HIST,
+		configs.Env.Prefix, models.TABLE_USER)
+
+	rows, err := r.db.Query(query, commentUid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	r.db.Exec(query, content, time.Now().UnixMilli(), commentUid)
+	for rows.Next() {
+		item := models.CommentHistoryItem{}
+		if err := rows.Scan(&item.Uid, &item.Editor.UserUid, &item.Editor.Name, &item.Editor.Profile,
+			&item.Content, &item.Written, &item.Timestamp); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
 }
 
 // 이 댓글에 대한 좋아요 변경하기
@@ -195,62 +270,137 @@ func (r *NuboCommentRepository) UpdateLikeComment(param models.CommentLikeParam)
 	r.db.Exec(query, param.Liked, time.Now().UnixMilli(), param.CommentUid, param.UserUid)
 }
 
-// 댓글 목록 가져오기
-func (r *NuboCommentRepository) GetComments(param models.CommentListParam) ([]models.CommentItem, error) {
+// 부모 경로 뒤에 댓글 고유 번호를 고정 길이로 붙여 정렬 가능한 경로 만들기
+func commentPath(parentPath string, commentUid uint) string {
+	return fmt.Sprintf("%s%010d/", parentPath, commentUid)
+}
+
+// 목록에 보여줄 댓글 조건 (삭제된 댓글은 살아있는 답글이 있을 때만 자리 표시용으로 남김)
+func visibleCommentCondition(alias string) string {
+	return fmt.Sprintf(`(%[1]s.status IN (%[2]d, %[3]d) OR (%[1]s.status = %[4]d AND EXISTS(
+		SELECT 1 FROM %[5]s%[6]s AS d WHERE d.post_uid = %[1]s.post_uid AND d.path LIKE CONCAT(%[1]s.path, '%%')
+		AND d.uid != %[1]s.uid AND d.status IN (%[2]d, %[3]d))))`,
+		alias, models.CONTENT_NORMAL, models.CONTENT_SECRET, models.CONTENT_REMOVED,
+		configs.Env.Prefix, models.TABLE_COMMENT)
+}
+
+// 댓글 목록 가져오기 (parentUid 아래 댓글들을 정렬해 자르고, expand 단계까지의 답글을 경로 순으로 덧붙임)
+func (r *NuboCommentRepository) GetComments(param models.CommentListParam, expand uint) ([]models.CommentItem, error) {
 	items := make([]models.CommentItem, 0)
 	offset := (param.Page - 1) * param.Limit
 	prefix := configs.Env.Prefix
+	visible := visibleCommentCondition("c")
+
+	var order string
+	switch param.Sort {
+	case models.COMMENT_SORT_NEWEST:
+		order = "c.uid DESC"
+	case models.COMMENT_SORT_LIKES:
+		order = "like_count DESC, c.uid ASC"
+	default:
+		order = "c.uid ASC"
+	}
 
-	query := fmt.Sprintf(`SELECT 
-			c.uid, c.reply_uid, c.user_uid, c.content, c.submitted, c.modified, c.status,
-			u.name, u.profile,
-			(SELECT COUNT(*) FROM %s%s WHERE comment_uid = c.uid AND liked = 1),
-			EXISTS(SELECT 1 FROM %s%s WHERE comment_uid = c.uid AND user_uid = ? AND liked = 1)
-		FROM %s%s AS c
-		JOIN (
-			SELECT uid FROM %s%s 
-			WHERE post_uid = ? AND status IN (?, ?) 
-			ORDER BY reply_uid ASC, uid ASC 
-			LIMIT ? OFFSET ?
-		) AS p ON c.uid = p.uid
-		LEFT JOIN %s%s AS u ON c.user_uid = u.uid
-		ORDER BY c.reply_uid ASC, c.uid ASC`,
+	columns := fmt.Sprintf(`c.uid, c.reply_uid, c.parent_uid, c.depth, c.path, c.post_uid, c.user_uid, c.content, c.submitted, c.modified, c.status,
+			IFNULL(u.name, ''), IFNULL(u.profile, ''),
+			(SELECT COUNT(*) FROM %s%s WHERE comment_uid = c.uid AND liked = 1) AS like_count,
+			EXISTS(SELECT 1 FROM %s%s WHERE comment_uid = c.uid AND user_uid = ? AND liked = 1),
+			(SELECT COUNT(*) FROM %s%s AS r WHERE r.parent_uid = c.uid AND %s),
+			(SELECT COUNT(*) FROM %s%s WHERE comment_uid = c.uid)`,
 		prefix, models.TABLE_COMMENT_LIKE,
 		prefix, models.TABLE_COMMENT_LIKE,
-		prefix, models.TABLE_COMMENT,
+		prefix, models.TABLE_COMMENT, visibleCommentCondition("r"),
+		prefix, models.TABLE_COMMENT_HIST)
+
+	query := fmt.Sprintf(`SELECT %s
+		FROM %s%s AS c
+		LEFT JOIN %s%s AS u ON c.user_uid = u.uid
+		WHERE c.post_uid = ? AND c.parent_uid = ? AND %s
+		ORDER BY %s
+		LIMIT ? OFFSET ?`,
+		columns,
 		prefix, models.TABLE_COMMENT,
 		prefix, models.TABLE_USER,
+		visible, order,
 	)
+	anchors, err := r.scanComments(query, param.UserUid, param.PostUid, param.ParentUid, param.Limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if len(anchors) == 0 || expand == 0 {
+		for _, anchor := range anchors {
+			items = append(items, anchor.item)
+		}
+		return items, nil
+	}
 
-	rows, err := r.db.Query(query,
-		param.UserUid,
-		param.PostUid,
-		models.CONTENT_NORMAL,
-		models.CONTENT_SECRET,
-		param.Limit,
-		offset,
+	// 같은 부모의 자식들이므로 깊이가 모두 같음, 한 번의 쿼리로 펼칠 답글들을 가져옴
+	likes := make([]string, 0, len(anchors))
+	args := []any{param.UserUid, param.PostUid, anchors[0].item.Depth, anchors[0].item.Depth + expand}
+	for _, anchor := range anchors {
+		likes = append(likes, "c.path LIKE ?")
+		args = append(args, anchor.path+"%")
+	}
+	query = fmt.Sprintf(`SELECT %s
+		FROM %s%s AS c
+		LEFT JOIN %s%s AS u ON c.user_uid = u.uid
+		WHERE c.post_uid = ? AND c.depth > ? AND c.depth <= ? AND (%s) AND %s
+		ORDER BY c.path ASC`,
+		columns,
+		prefix, models.TABLE_COMMENT,
+		prefix, models.TABLE_USER,
+		strings.Join(likes, " OR "), visible,
 	)
+	replies, err := r.scanComments(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, anchor := range anchors {
+		items = append(items, anchor.item)
+		for _, reply := range replies {
+			if strings.HasPrefix(reply.path, anchor.path) {
+				items = append(items, reply.item)
+			}
+		}
+	}
+	return items, nil
+}
+
+// 경로와 함께 읽어온 댓글 항목
+type commentRow struct {
+	item models.CommentItem
+	path string
+}
+
+// 댓글 목록 쿼리 결과를 항목들로 변환하기
+func (r *NuboCommentRepository) scanComments(query string, args ...any) ([]commentRow, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	result := make([]commentRow, 0)
 	for rows.Next() {
-		item := models.CommentItem{}
+		row := commentRow{}
+		item := &row.item
 		err := rows.Scan(
-			&item.Uid, &item.ReplyUid, &item.Writer.UserUid, &item.Content, &item.Submitted, &item.Modified, &item.Status,
+			&item.Uid, &item.ReplyUid, &item.ParentUid, &item.Depth, &row.path, &item.PostUid, &item.Writer.UserUid,
+			&item.Content, &item.Submitted, &item.Modified, &item.Status,
 			&item.Writer.Name, &item.Writer.Profile,
 			&item.Like,
 			&item.Liked,
+			&item.ReplyCount,
+			&item.EditCount,
 		)
 		if err == nil {
-			item.PostUid = param.PostUid
-			items = append(items, item)
+			item.Edited = item.Modified > 0
+			result = append(result, row)
 		}
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-
-	return items, nil
+	return result, nil
 }
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirini/goapi/internal/configs"
+)
+
+func TestUpdateCommentKeepsPreviousVersion(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Prefix = "nubo_"
+	t.Cleanup(func() { configs.Env = previous })
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewNuboCommentRepository(db, nil)
+
+	selectQuery := regexp.QuoteMeta("SELECT board_uid, content, submitted, modified FROM nubo_comment WHERE uid = ?")
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectQuery).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"board_uid", "content", "submitted", "modified"}).AddRow(3, "first", 100, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO nubo_comment_history")).
+		WithArgs(7, 3, 9, "first", 100, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE nubo_comment SET content = ?, modified = ?")).
+		WithArgs("second", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.UpdateComment(7, 9, "second"); err != nil {
+		t.Fatalf("update comment: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectQuery).WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"board_uid", "content", "submitted", "modified"}).AddRow(3, "second", 100, 200))
+	mock.ExpectRollback()
+
+	if err := repo.UpdateComment(7, 9, "second"); err != nil {
+		t.Fatalf("unchanged comment should not fail: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
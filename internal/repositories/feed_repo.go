@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type FeedRepository interface {
+	GetFeedFiles(postUids []uint) (map[uint][]models.FeedFile, error)
+	GetFeedPosts(param models.FeedPostParam) ([]models.FeedPost, error)
+	GetFeedTags(postUids []uint) (map[uint][]string, error)
+	GetFeedUserName(userUid uint) (string, error)
+}
+
+type NuboFeedRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboFeedRepository(db *sql.DB) *NuboFeedRepository {
+	return &NuboFeedRepository{db: db}
+}
+
+// 게시글 번호 목록을 IN 절 자리표시자와 인자로 바꾸기
+func feedPostArgs(postUids []uint) (string, []any) {
+	args := make([]any, 0, len(postUids))
+	for _, uid := range postUids {
+		args = append(args, uid)
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", len(postUids)), ","), args
+}
+
+// 게시글들의 첨부파일 목록을 게시글별로 가져오기
+func (r *NuboFeedRepository) GetFeedFiles(postUids []uint) (map[uint][]models.FeedFile, error) {
+	items := make(map[uint][]models.FeedFile)
+	if len(postUids) == 0 {
+		return items, nil
+	}
+	holders, args := feedPostArgs(postUids)
+	query := fmt.Sprintf("SELECT post_uid, name, path FROM %s%s WHERE post_uid IN (%s) ORDER BY uid ASC",
+		configs.Env.Prefix, models.TABLE_FILE, holders)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.FeedFile{}
+		var postUid uint
+		if err := rows.Scan(&postUid, &item.Name, &item.Path); err != nil {
+			return items, err
+		}
+		items[postUid] = append(items[postUid], item)
+	}
+	return items, rows.Err()
+}
+
+// 목록과 본문을 누구나 볼 수 있는 게시판의 공개 게시글을 최신순으로 가져오기
+func (r *NuboFeedRepository) GetFeedPosts(param models.FeedPostParam) ([]models.FeedPost, error) {
+	items := make([]models.FeedPost, 0)
+	prefix := configs.Env.Prefix
+	where := ""
+	args := []any{models.CONTENT_NORMAL, models.CONTENT_NOTICE, models.BOARD_ACTION_LIST, models.BOARD_ACTION_VIEW}
+	if param.BoardUid > 0 {
+		where += " AND p.board_uid = ?"
+		args = append(args, param.BoardUid)
+	}
+	if param.PostUid > 0 {
+		where += " AND p.uid = ?"
+		args = append(args, param.PostUid)
+	}
+	if param.UserUid > 0 {
+		where += " AND p.user_uid = ?"
+		args = append(args, param.UserUid)
+	}
+	if len(param.Hashtag) > 0 {
+		where += fmt.Sprintf(` AND p.uid IN (SELECT ph.post_uid FROM %s%s ph JOIN %s%s h ON h.uid = ph.hashtag_uid
+			WHERE h.name = ? AND h.banned = 0)`, prefix, models.TABLE_POST_HASHTAG, prefix, models.TABLE_HASHTAG)
+		args = append(args, param.Hashtag)
+	}
+	args = append(args, param.Limit)
+
+	query := fmt.Sprintf(`SELECT p.uid, b.id, b.type, b.feed_summary, COALESCE(u.name, ''),
+		p.title, COALESCE(p.content, ''), p.submitted, p.modified
+		FROM %s%s p
+		JOIN %s%s b ON b.uid = p.board_uid
+		LEFT JOIN %s%s u ON u.uid = p.user_uid
+		WHERE p.status IN (?, ?) AND b.level_list = 0 AND b.level_view = 0 AND b.point_view >= 0
+		AND NOT EXISTS (SELECT 1 FROM %s%s a WHERE a.board_uid = b.uid AND a.allow = 1 AND a.action IN (?, ?))%s
+		ORDER BY p.uid DESC LIMIT ?`,
+		prefix, models.TABLE_POST, prefix, models.TABLE_BOARD, prefix, models.TABLE_USER,
+		prefix, models.TABLE_BOARD_ACL, where)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.FeedPost{}
+		var summary uint8
+		if err := rows.Scan(&item.Uid, &item.BoardId, &item.BoardType, &summary, &item.UserName,
+			&item.Title, &item.Content, &item.Submitted, &item.Modified); err != nil {
+			return items, err
+		}
+		item.FeedSummary = summary > 0
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 게시글들의 태그 이름을 게시글별로 가져오기
+func (r *NuboFeedRepository) GetFeedTags(postUids []uint) (map[uint][]string, error) {
+	items := make(map[uint][]string)
+	if len(postUids) == 0 {
+		return items, nil
+	}
+	holders, args := feedPostArgs(postUids)
+	query := fmt.Sprintf(`SELECT ph.post_uid, h.name FROM %s%s ph JOIN %s%s h ON h.uid = ph.hashtag_uid
+		WHERE ph.post_uid IN (%s) AND h.banned = 0 ORDER BY h.name ASC`,
+		configs.Env.Prefix, models.TABLE_POST_HASHTAG, configs.Env.Prefix, models.TABLE_HASHTAG, holders)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postUid uint
+		var name string
+		if err := rows.Scan(&postUid, &name); err != nil {
+			return items, err
+		}
+		items[postUid] = append(items[postUid], name)
+	}
+	return items, rows.Err()
+}
+
+// 작성자 피드 제목에 쓸 회원 이름 가져오기 (차단된 회원은 제외)
+func (r *NuboFeedRepository) GetFeedUserName(userUid uint) (string, error) {
+	var name string
+	query := fmt.Sprintf("SELECT name FROM %s%s WHERE uid = ? AND blocked = 0 LIMIT 1",
+		configs.Env.Prefix, models.TABLE_USER)
+	err := r.db.QueryRow(query, userUid).Scan(&name)
+	return name, err
+}
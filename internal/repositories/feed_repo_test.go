@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+func TestFeedPostsSkipPaidViewBoards(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Prefix = "nubo_"
+	t.Cleanup(func() { configs.Env = previous })
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewNuboFeedRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE p.status IN (?, ?) AND b.level_list = 0 AND b.level_view = 0 AND b.point_view >= 0")).
+		WithArgs(models.CONTENT_NORMAL, models.CONTENT_NOTICE, models.BOARD_ACTION_LIST, models.BOARD_ACTION_VIEW, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"uid", "id", "type", "feed_summary", "name", "title", "content", "submitted", "modified"}))
+
+	if _, err := repo.GetFeedPosts(models.FeedPostParam{Limit: 20}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
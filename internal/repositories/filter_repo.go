@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type ContentFilterRepository interface {
+	GetFilterLogs(param models.ContentFilterLogParam) (models.ContentFilterLogResult, error)
+	GetFilterRules(enabledOnly bool) ([]models.ContentFilterRule, error)
+	InsertFilterLog(item models.ContentFilterLog) error
+	RemoveFilterRule(filterUid uint) error
+	SaveFilterRule(param models.ContentFilterSaveParam) (uint, error)
+}
+
+type NuboContentFilterRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboContentFilterRepository(db *sql.DB) *NuboContentFilterRepository {
+	return &NuboContentFilterRepository{db: db}
+}
+
+// 금지어 필터 적중 기록 가져오기 (filterUid가 0이면 전체)
+func (r *NuboContentFilterRepository) GetFilterLogs(param models.ContentFilterLogParam) (models.ContentFilterLogResult, error) {
+	result := models.ContentFilterLogResult{Item: make([]models.ContentFilterLog, 0)}
+	prefix := configs.Env.Prefix
+	where := ""
+	args := make([]any, 0, 3)
+	if param.FilterUid > 0 {
+		where = "WHERE l.filter_uid = ?"
+		args = append(args, param.FilterUid)
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s l %s", prefix, models.TABLE_FILTER_LOG, where)
+	if err := r.db.QueryRow(query, args...).Scan(&result.Total); err != nil {
+		return result, err
+	}
+
+	query = fmt.Sprintf(`SELECT l.uid, l.filter_uid, COALESCE(f.pattern, ''), l.target, l.action, l.user_uid, l.excerpt, l.timestamp
+		FROM %s%s l LEFT JOIN %s%s f ON f.uid = l.filter_uid %s
+		ORDER BY l.uid DESC LIMIT ? OFFSET ?`,
+		prefix, models.TABLE_FILTER_LOG, prefix, models.TABLE_FILTER, where)
+	args = append(args, param.Limit, (param.Page-1)*param.Limit)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ContentFilterLog{}
+		if err := rows.Scan(&item.Uid, &item.FilterUid, &item.Pattern, &item.Target, &item.Action,
+			&item.UserUid, &item.Excerpt, &item.Timestamp); err != nil {
+			return result, err
+		}
+		result.Item = append(result.Item, item)
+	}
+	return result, rows.Err()
+}
+
+// 금지어 필터 목록 가져오기
+func (r *NuboContentFilterRepository) GetFilterRules(enabledOnly bool) ([]models.ContentFilterRule, error) {
+	items := make([]models.ContentFilterRule, 0)
+	where := ""
+	if enabledOnly {
+		where = "WHERE enabled = 1"
+	}
+	query := fmt.Sprintf("SELECT uid, pattern, match_type, action, enabled, created FROM %s%s %s ORDER BY uid ASC",
+		configs.Env.Prefix, models.TABLE_FILTER, where)
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ContentFilterRule{}
+		if err := rows.Scan(&item.Uid, &item.Pattern, &item.Match, &item.Action, &item.Enabled, &item.Created); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 금지어 필터 적중 기록 남기기
+func (r *NuboContentFilterRepository) InsertFilterLog(item models.ContentFilterLog) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s (filter_uid, target, action, user_uid, excerpt, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_FILTER_LOG)
+	_, err := r.db.Exec(query, item.FilterUid, item.Target, item.Action, item.UserUid, item.Excerpt, time.Now().UnixMilli())
+	return err
+}
+
+// 금지어 필터 삭제하기 (적중 기록은 규칙 조정 이력으로 남겨둠)
+func (r *NuboContentFilterRepository) RemoveFilterRule(filterUid uint) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_FILTER)
+	_, err := r.db.Exec(query, filterUid)
+	return err
+}
+
+// 금지어 필터 추가 또는 수정하기
+func (r *NuboContentFilterRepository) SaveFilterRule(param models.ContentFilterSaveParam) (uint, error) {
+	if param.Uid > 0 {
+		query := fmt.Sprintf("UPDATE %s%s SET pattern = ?, match_type = ?, action = ?, enabled = ? WHERE uid = ? LIMIT 1",
+			configs.Env.Prefix, models.TABLE_FILTER)
+		_, err := r.db.Exec(query, param.Pattern, param.Match, param.Action, param.Enabled, param.Uid)
+		return param.Uid, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s%s (pattern, match_type, action, enabled, created) VALUES (?, ?, ?, ?, ?)",
+		configs.Env.Prefix, models.TABLE_FILTER)
+	result, err := r.db.Exec(query, param.Pattern, param.Match, param.Action, param.Enabled, time.Now().UnixMilli())
+	if err != nil {
+		return models.FAILED, err
+	}
+	insertId, err := result.LastInsertId()
+	if err != nil {
+		return models.FAILED, err
+	}
+	return uint(insertId), nil
+}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type HashtagRepository interface {
+	GetHashtagList(param models.AdminHashtagSearchParam) (models.AdminHashtagListResult, error)
+	MergeHashtags(targetUid uint, sourceUids []uint) error
+	PurgeOrphans() (int64, error)
+	RecountUsage() (int64, error)
+	RenameHashtag(hashtagUid uint, name string) error
+	UpdateBanned(hashtagUid uint, banned bool) error
+}
+
+type NuboHashtagRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboHashtagRepository(db *sql.DB) *NuboHashtagRepository {
+	return &NuboHashtagRepository{db: db}
+}
+
+// 해시태그 목록을 실제 연결된 게시글 수와 함께 가져오기
+func (r *NuboHashtagRepository) GetHashtagList(param models.AdminHashtagSearchParam) (models.AdminHashtagListResult, error) {
+	result := models.AdminHashtagListResult{Item: make([]models.AdminHashtagItem, 0)}
+	prefix := configs.Env.Prefix
+	where := ""
+	args := make([]any, 0, 3)
+	if keyword := strings.TrimSpace(param.Keyword); len(keyword) > 0 {
+		where = "WHERE h.name LIKE ?"
+		args = append(args, "%"+keyword+"%")
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s h %s", prefix, models.TABLE_HASHTAG, where)
+	if err := r.db.QueryRow(query, args...).Scan(&result.Total); err != nil {
+		return result, err
+	}
+
+	query = fmt.Sprintf(`SELECT h.uid, h.name, h.used, h.banned, h.timestamp,
+		(SELECT COUNT(*) FROM %s%s ph WHERE ph.hashtag_uid = h.uid) AS posts
+		FROM %s%s h %s ORDER BY h.used DESC, h.uid DESC LIMIT ? OFFSET ?`,
+		prefix, models.TABLE_POST_HASHTAG, prefix, models.TABLE_HASHTAG, where)
+	args = append(args, param.Limit, (param.Page-1)*param.Limit)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.AdminHashtagItem{}
+		if err := rows.Scan(&item.Uid, &item.Name, &item.Used, &item.Banned, &item.Timestamp, &item.Posts); err != nil {
+			return result, err
+		}
+		result.Item = append(result.Item, item)
+	}
+	return result, rows.Err()
+}
+
+// 중복 태그들을 대상 태그로 합치고 원래 태그들은 삭제하기
+func (r *NuboHashtagRepository) MergeHashtags(targetUid uint, sourceUids []uint) error {
+	prefix := configs.Env.Prefix
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, sourceUid := range sourceUids {
+		if err := insertHashtagSyncChanges(tx, sourceUid); err != nil {
+			return err
+		}
+		// 이미 대상 태그가 달린 게시글은 중복 연결이 생기지 않도록 먼저 정리
+		query := fmt.Sprintf(`DELETE s FROM %s%s s JOIN %s%s t ON t.post_uid = s.post_uid AND t.hashtag_uid = ?
+			WHERE s.hashtag_uid = ?`, prefix, models.TABLE_POST_HASHTAG, prefix, models.TABLE_POST_HASHTAG)
+		if _, err := tx.Exec(query, targetUid, sourceUid); err != nil {
+			return err
+		}
+		query = fmt.Sprintf("UPDATE %s%s SET hashtag_uid = ? WHERE hashtag_uid = ?", prefix, models.TABLE_POST_HASHTAG)
+		if _, err := tx.Exec(query, targetUid, sourceUid); err != nil {
+			return err
+		}
+		query = fmt.Sprintf("DELETE FROM %s%s WHERE uid = ? LIMIT 1", prefix, models.TABLE_HASHTAG)
+		if _, err := tx.Exec(query, sourceUid); err != nil {
+			return err
+		}
+	}
+
+	query := fmt.Sprintf(`UPDATE %s%s SET used = (SELECT COUNT(*) FROM %s%s WHERE hashtag_uid = ?) WHERE uid = ? LIMIT 1`,
+		prefix, models.TABLE_HASHTAG, prefix, models.TABLE_POST_HASHTAG)
+	if _, err := tx.Exec(query, targetUid, targetUid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// 삭제된 게시글과의 연결을 끊고, 게시글이 하나도 없는 태그들 삭제하기 (금지 태그는 유지)
+func (r *NuboHashtagRepository) PurgeOrphans() (int64, error) {
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf(`DELETE ph FROM %s%s ph JOIN %s%s p ON p.uid = ph.post_uid WHERE p.status = ?`,
+		prefix, models.TABLE_POST_HASHTAG, prefix, models.TABLE_POST)
+	if _, err := r.db.Exec(query, models.CONTENT_REMOVED); err != nil {
+		return 0, err
+	}
+
+	query = fmt.Sprintf(`DELETE h FROM %s%s h LEFT JOIN %s%s ph ON ph.hashtag_uid = h.uid
+		WHERE ph.hashtag_uid IS NULL AND h.banned = 0`,
+		prefix, models.TABLE_HASHTAG, prefix, models.TABLE_POST_HASHTAG)
+	result, err := r.db.Exec(query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// 삭제되지 않은 게시글 기준으로 태그 사용 횟수 다시 계산하기
+func (r *NuboHashtagRepository) RecountUsage() (int64, error) {
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf(`UPDATE %s%s h LEFT JOIN (
+			SELECT ph.hashtag_uid, COUNT(*) AS used_count FROM %s%s ph
+			JOIN %s%s p ON p.uid = ph.post_uid WHERE p.status != ?
+			GROUP BY ph.hashtag_uid
+		) counted ON counted.hashtag_uid = h.uid
+		SET h.used = COALESCE(counted.used_count, 0)`,
+		prefix, models.TABLE_HASHTAG, prefix, models.TABLE_POST_HASHTAG, prefix, models.TABLE_POST)
+	result, err := r.db.Exec(query, models.CONTENT_REMOVED)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// 태그 이름 변경하기
+func (r *NuboHashtagRepository) RenameHashtag(hashtagUid uint, name string) error {
+	query := fmt.Sprintf("UPDATE %s%s SET name = ? WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_HASHTAG)
+	return r.updateHashtag(hashtagUid, query, name, hashtagUid)
+}
+
+// 태그 금지 여부 변경하기
+func (r *NuboHashtagRepository) UpdateBanned(hashtagUid uint, banned bool) error {
+	query := fmt.Sprintf("UPDATE %s%s SET banned = ? WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_HASHTAG)
+	return r.updateHashtag(hashtagUid, query, banned, hashtagUid)
+}
+
+// 해시태그를 바꾸고 그 태그가 달린 게시글들의 변경 기록을 같은 트랜잭션으로 남기기
+func (r *NuboHashtagRepository) updateHashtag(hashtagUid uint, query string, args ...any) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		return err
+	}
+	if err := insertHashtagSyncChanges(tx, hashtagUid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeHashtagsMovesLinksBeforeDeletingSources(t *testing.T) {
+	state := &pointDriver{rowsAffected: 1}
+	repo := NewNuboHashtagRepository(openPointTestDB(t, state))
+	if err := repo.MergeHashtags(3, []uint{7}); err != nil {
+		t.Fatal(err)
+	}
+	if !state.committed || state.rolledBack {
+		t.Fatalf("unexpected transaction state: committed=%v rolledBack=%v", state.committed, state.rolledBack)
+	}
+	if len(state.execs) != 5 {
+		t.Fatalf("merge executed %d statements, want 5", len(state.execs))
+	}
+	wants := []string{"INSERT INTO", "DELETE s FROM", "UPDATE ", "DELETE FROM", "SET used = (SELECT COUNT(*)"}
+	for i, want := range wants {
+		if !strings.Contains(state.execs[i].query, want) {
+			t.Fatalf("statement %d = %q, want it to contain %q", i, state.execs[i].query, want)
+		}
+	}
+	if !strings.Contains(state.execs[0].query, "sync_change") || state.execs[0].args[2].Value != int64(7) {
+		t.Fatalf("posts tagged with the source should be logged for sync: %q %#v", state.execs[0].query, state.execs[0].args)
+	}
+	if state.execs[3].args[0].Value != int64(7) {
+		t.Fatalf("source hashtag was not the one deleted: %#v", state.execs[3].args)
+	}
+}
+
+func TestRenameHashtagLogsSyncChangesInSameTransaction(t *testing.T) {
+	state := &pointDriver{rowsAffected: 1}
+	repo := NewNuboHashtagRepository(openPointTestDB(t, state))
+	if err := repo.RenameHashtag(3, "travel"); err != nil {
+		t.Fatal(err)
+	}
+	if !state.committed || len(state.execs) != 2 || !strings.Contains(state.execs[1].query, "sync_change") {
+		t.Fatalf("rename should log sync changes before committing, got %d statements committed=%v", len(state.execs), state.committed)
+	}
+}
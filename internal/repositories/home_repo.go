@@ -20,6 +20,9 @@ type HomeRepository interface {
 	GetBoardLinks(stmt *sql.Stmt, groupUid uint) ([]models.HomeSidebarBoardResult, error)
 	GetGroupBoardLinks() ([]models.HomeSidebarGroupResult, error)
 	GetLatestPosts(param models.HomePostParam) ([]models.HomePostItem, error)
+	GetSitemapBoardStat(boardUid uint) models.SitemapBoardStat
+	GetSitemapPosts(boardUid uint, offset uint, limit uint) ([]models.SitemapPost, error)
+	HasRestrictedAcl(boardUid uint) bool
 	InsertVisitorLog(userUid uint)
 }
 
@@ -266,6 +269,48 @@ func (r *NuboHomeRepository) GetLatestPosts(param models.HomePostParam) ([]model
 	return r.AppendItem(rows)
 }
 
+// 사이트맵에 넣을 공개 게시글 수와 마지막 수정 시간 가져오기 (비밀글, 삭제글, 승인 대기글 제외)
+func (r *NuboHomeRepository) GetSitemapBoardStat(boardUid uint) models.SitemapBoardStat {
+	stat := models.SitemapBoardStat{}
+	query := fmt.Sprintf(`SELECT COUNT(*), COALESCE(MAX(GREATEST(modified, submitted)), 0)
+		FROM %s%s WHERE board_uid = ? AND status IN (?, ?)`, configs.Env.Prefix, models.TABLE_POST)
+	r.db.QueryRow(query, boardUid, models.CONTENT_NORMAL, models.CONTENT_NOTICE).Scan(&stat.Count, &stat.Modified)
+	return stat
+}
+
+// 사이트맵 한 페이지 분량의 공개 게시글 가져오기
+func (r *NuboHomeRepository) GetSitemapPosts(boardUid uint, offset uint, limit uint) ([]models.SitemapPost, error) {
+	items := make([]models.SitemapPost, 0)
+	query := fmt.Sprintf(`SELECT uid, GREATEST(modified, submitted) FROM %s%s
+		WHERE board_uid = ? AND status IN (?, ?) ORDER BY uid ASC LIMIT ? OFFSET ?`,
+		configs.Env.Prefix, models.TABLE_POST)
+	rows, err := r.db.Query(query, boardUid, models.CONTENT_NORMAL, models.CONTENT_NOTICE, limit, offset)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.SitemapPost{}
+		if err := rows.Scan(&item.Uid, &item.Modified); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 목록이나 글 보기를 특정 역할에게만 허용하는 게시판인지 확인
+func (r *NuboHomeRepository) HasRestrictedAcl(boardUid uint) bool {
+	var restricted bool
+	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s%s WHERE board_uid = ? AND allow = 1 AND action IN (?, ?))`,
+		configs.Env.Prefix, models.TABLE_BOARD_ACL)
+	if err := r.db.QueryRow(query, boardUid, models.BOARD_ACTION_LIST, models.BOARD_ACTION_VIEW).Scan(&restricted); err != nil {
+		return true
+	}
+	return restricted
+}
+
 // 방문자 기록하기
 func (r *NuboHomeRepository) InsertVisitorLog(userUid uint) {
 	query := fmt.Sprintf("INSERT INTO %s%s (user_uid, timestamp) VALUES (?, ?)",
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type ImageVariantRepository interface {
+	GetImageVariants(sources []string) (map[string][]models.ImageVariant, error)
+	GetVariantTargets(category models.UploadCategory, widths []uint, afterUid uint, limit uint) ([]models.ImageVariantTarget, error)
+	InsertImageVariants(fileUid uint, source string, variants []models.ImageVariant) error
+	RemoveImageVariants(source string) error
+}
+
+type NuboImageVariantRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboImageVariantRepository(db *sql.DB) *NuboImageVariantRepository {
+	return &NuboImageVariantRepository{db: db}
+}
+
+// 이미지 경로별 반응형 변형 목록 가져오기 (변형이 없는 경로는 결과에 없음)
+func (r *NuboImageVariantRepository) GetImageVariants(sources []string) (map[string][]models.ImageVariant, error) {
+	result := make(map[string][]models.ImageVariant)
+	if len(sources) < 1 {
+		return result, nil
+	}
+	args := make([]any, 0, len(sources))
+	for _, source := range sources {
+		args = append(args, source)
+	}
+	query := fmt.Sprintf("SELECT source, path, width, type FROM %s%s WHERE source IN (?%s) ORDER BY width ASC, uid ASC",
+		configs.Env.Prefix, models.TABLE_IMAGE_VARIANT, strings.Repeat(", ?", len(sources)-1))
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var source string
+		variant := models.ImageVariant{}
+		if err := rows.Scan(&source, &variant.Path, &variant.Width, &variant.Type); err != nil {
+			return nil, err
+		}
+		result[source] = append(result[source], variant)
+	}
+	return result, rows.Err()
+}
+
+// 기록된 변형에 주어진 너비 중 하나라도 빠진 기존 이미지를 uid 순서로 가져오기 (첨부 썸네일, 본문 삽입 이미지, 프로필 이미지)
+func (r *NuboImageVariantRepository) GetVariantTargets(category models.UploadCategory, widths []uint, afterUid uint, limit uint) ([]models.ImageVariantTarget, error) {
+	items := make([]models.ImageVariantTarget, 0)
+	if len(widths) < 1 {
+		return items, nil
+	}
+	prefix := configs.Env.Prefix
+	missing := fmt.Sprintf("(SELECT COUNT(DISTINCT v.width) FROM %s%s v WHERE %%s AND v.width IN (?%s)) < ?",
+		prefix, models.TABLE_IMAGE_VARIANT, strings.Repeat(", ?", len(widths)-1))
+	var query string
+	switch category {
+	case models.UPLOAD_THUMB:
+		query = fmt.Sprintf(`SELECT t.uid, t.file_uid, t.path, t.full_path, f.path FROM %s%s t
+			JOIN %s%s f ON f.uid = t.file_uid
+			WHERE t.uid > ? AND %s
+			ORDER BY t.uid ASC LIMIT ?`,
+			prefix, models.TABLE_FILE_THUMB, prefix, models.TABLE_FILE, fmt.Sprintf(missing, "v.file_uid = t.file_uid"))
+	case models.UPLOAD_IMAGE:
+		query = fmt.Sprintf(`SELECT i.uid, 0, i.path, '', i.path FROM %s%s i
+			WHERE i.uid > ? AND %s
+			ORDER BY i.uid ASC LIMIT ?`,
+			prefix, models.TABLE_IMAGE, fmt.Sprintf(missing, "v.source = i.path"))
+	case models.UPLOAD_PROFILE:
+		query = fmt.Sprintf(`SELECT u.uid, 0, u.profile, '', u.profile FROM %s%s u
+			WHERE u.uid > ? AND u.profile LIKE '/upload/%s/%%'
+			AND %s
+			ORDER BY u.uid ASC LIMIT ?`,
+			prefix, models.TABLE_USER, models.UPLOAD_PROFILE, fmt.Sprintf(missing, "v.source = u.profile"))
+	default:
+		return nil, fmt.Errorf("unsupported image category: %s", category)
+	}
+
+	args := []any{afterUid}
+	for _, width := range widths {
+		args = append(args, width)
+	}
+	args = append(args, len(widths), limit)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.ImageVariantTarget{}
+		if err := rows.Scan(&item.Uid, &item.FileUid, &item.Source, &item.Large, &item.Original); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 이미지 경로의 반응형 변형 목록 저장하기 (첨부 썸네일이 아니면 파일 번호는 0)
+func (r *NuboImageVariantRepository) InsertImageVariants(fileUid uint, source string, variants []models.ImageVariant) error {
+	return insertImageVariants(r.db, fileUid, source, variants)
+}
+
+// 이미지 경로의 반응형 변형 기록 지우기 (파일은 업로드 파일 삭제 시 함께 지워짐)
+func (r *NuboImageVariantRepository) RemoveImageVariants(source string) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE source = ?", configs.Env.Prefix, models.TABLE_IMAGE_VARIANT)
+	_, err := r.db.Exec(query, source)
+	return err
+}
+
+// 반응형 변형 목록을 한 번에 저장하기
+func insertImageVariants(db *sql.DB, fileUid uint, source string, variants []models.ImageVariant) error {
+	if len(variants) < 1 {
+		return nil
+	}
+	query := fmt.Sprintf("INSERT INTO %s%s (file_uid, source, path, width, type) VALUES ",
+		configs.Env.Prefix, models.TABLE_IMAGE_VARIANT)
+	values := make([]any, 0, len(variants)*5)
+	for _, variant := range variants {
+		query += "(?, ?, ?, ?, ?),"
+		values = append(values, fileUid, source, variant.Path, variant.Width, variant.Type)
+	}
+	_, err := db.Exec(query[:len(query)-1], values...)
+	return err
+}
+
+// 첨부 파일 썸네일의 반응형 변형 목록 가져오기
+func getFileImageVariants(db *sql.DB, fileUid uint) []models.ImageVariant {
+	variants := make([]models.ImageVariant, 0)
+	query := fmt.Sprintf("SELECT path, width, type FROM %s%s WHERE file_uid = ? ORDER BY width ASC, uid ASC",
+		configs.Env.Prefix, models.TABLE_IMAGE_VARIANT)
+	rows, err := db.Query(query, fileUid)
+	if err != nil {
+		return variants
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		variant := models.ImageVariant{}
+		if err := rows.Scan(&variant.Path, &variant.Width, &variant.Type); err != nil {
+			return variants
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
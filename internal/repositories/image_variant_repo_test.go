@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+func TestVariantTargetsAreSelectedByMissingWidth(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Prefix = "nubo_"
+	t.Cleanup(func() { configs.Env = previous })
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewNuboImageVariantRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM nubo_image i WHERE i.uid > ? AND (SELECT COUNT(DISTINCT v.width) FROM nubo_image_variant v WHERE v.source = i.path AND v.width IN (?, ?)) < ?")).
+		WithArgs(5, 1024, 480, 2, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"uid", "file_uid", "path", "full_path", "original"}).
+			AddRow(6, 0, "/upload/images/a.webp", "", "/upload/images/a.webp"))
+
+	targets, err := repo.GetVariantTargets(models.UPLOAD_IMAGE, []uint{1024, 480}, 5, 100)
+	if err != nil || len(targets) != 1 || targets[0].Uid != 6 || targets[0].Original != "/upload/images/a.webp" {
+		t.Fatalf("unexpected targets %+v %v", targets, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,299 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type ImportRepository interface {
+	CreateImportJob(boardUid uint, userUid uint, source string) (uint, error)
+	FindCategoryUid(boardUid uint, name string) uint
+	FindImportMap(boardUid uint, sourceHash string, kind string, keyHash string) (uint, string, bool)
+	FindUserUidByEmail(email string) uint
+	GetPostContent(postUid uint) (string, error)
+	FinishImportJob(uid uint, status string, report models.ImportReport, lastError string) error
+	GetImportJob(uid uint) (models.ImportJob, error)
+	GetImportJobs(limit uint) ([]models.ImportJob, error)
+	InsertCategory(boardUid uint, name string) (uint, error)
+	InsertImportedComment(param models.ImportCommentParam) (uint, error)
+	InsertImportedPost(param models.ImportPostParam) (uint, error)
+	SaveImportMap(boardUid uint, sourceHash string, kind string, keyHash string, targetUid uint, targetPath string) error
+	UpdateImportedPost(postUid uint, param models.ImportPostParam) error
+	UpdatePostContent(postUid uint, content string) error
+}
+
+type NuboImportRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboImportRepository(db *sql.DB) *NuboImportRepository {
+	return &NuboImportRepository{db: db}
+}
+
+// 진행 중 상태의 가져오기 작업 기록 만들기
+func (r *NuboImportRepository) CreateImportJob(boardUid uint, userUid uint, source string) (uint, error) {
+	now := time.Now().UnixMilli()
+	query := fmt.Sprintf(`INSERT INTO %s%s (board_uid, user_uid, source, status, report, created, updated)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_IMPORT_JOB)
+	result, err := r.db.Exec(query, boardUid, userUid, source, models.IMPORT_RUNNING, "{}", now, now)
+	if err != nil {
+		return models.FAILED, err
+	}
+	uid, err := result.LastInsertId()
+	return uint(uid), err
+}
+
+// 게시판에서 같은 이름의 카테고리 고유번호 찾기
+func (r *NuboImportRepository) FindCategoryUid(boardUid uint, name string) uint {
+	var uid uint
+	query := fmt.Sprintf("SELECT uid FROM %s%s WHERE board_uid = ? AND name = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_BOARD_CAT)
+	if err := r.db.QueryRow(query, boardUid, name).Scan(&uid); err != nil {
+		return models.FAILED
+	}
+	return uid
+}
+
+// 이미 가져온 원본 항목이면 연결된 레코드 번호와 경로 반환
+func (r *NuboImportRepository) FindImportMap(boardUid uint, sourceHash string, kind string, keyHash string) (uint, string, bool) {
+	var targetUid uint
+	var targetPath string
+	query := fmt.Sprintf(`SELECT target_uid, target_path FROM %s%s
+		WHERE board_uid = ? AND source_hash = ? AND kind = ? AND key_hash = ? LIMIT 1`,
+		configs.Env.Prefix, models.TABLE_IMPORT_MAP)
+	if err := r.db.QueryRow(query, boardUid, sourceHash, kind, keyHash).Scan(&targetUid, &targetPath); err != nil {
+		return models.FAILED, "", false
+	}
+	return targetUid, targetPath, true
+}
+
+// 이메일(아이디)에 해당하는 회원 고유번호 반환
+func (r *NuboImportRepository) FindUserUidByEmail(email string) uint {
+	var uid uint
+	query := fmt.Sprintf("SELECT uid FROM %s%s WHERE id = ? LIMIT 1", configs.Env.Prefix, models.TABLE_USER)
+	if err := r.db.QueryRow(query, email).Scan(&uid); err != nil {
+		return models.FAILED
+	}
+	return uid
+}
+
+// 게시글 본문 가져오기 (이전 실행에서 가져온 글의 링크를 고칠 때 사용)
+func (r *NuboImportRepository) GetPostContent(postUid uint) (string, error) {
+	var content string
+	query := fmt.Sprintf("SELECT content FROM %s%s WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_POST)
+	err := r.db.QueryRow(query, postUid).Scan(&content)
+	return content, err
+}
+
+// 가져오기 작업을 끝난 상태로 바꾸고 보고서 저장하기
+func (r *NuboImportRepository) FinishImportJob(uid uint, status string, report models.ImportReport, lastError string) error {
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s%s SET status = ?, report = ?, last_error = ?, updated = ? WHERE uid = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_IMPORT_JOB)
+	_, err = r.db.Exec(query, status, string(encoded), lastError, time.Now().UnixMilli(), uid)
+	return err
+}
+
+// 가져오기 작업 하나 가져오기
+func (r *NuboImportRepository) GetImportJob(uid uint) (models.ImportJob, error) {
+	query := fmt.Sprintf(`SELECT uid, board_uid, user_uid, source, status, report, last_error, created, updated
+		FROM %s%s WHERE uid = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_IMPORT_JOB)
+	return scanImportJob(r.db.QueryRow(query, uid))
+}
+
+// 최근 가져오기 작업 목록 가져오기
+func (r *NuboImportRepository) GetImportJobs(limit uint) ([]models.ImportJob, error) {
+	items := make([]models.ImportJob, 0)
+	query := fmt.Sprintf(`SELECT uid, board_uid, user_uid, source, status, report, last_error, created, updated
+		FROM %s%s ORDER BY uid DESC LIMIT ?`, configs.Env.Prefix, models.TABLE_IMPORT_JOB)
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanImportJob(rows)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 가져오기 작업 한 행을 읽고 보고서 JSON 풀기
+func scanImportJob(row interface{ Scan(...any) error }) (models.ImportJob, error) {
+	item := models.ImportJob{}
+	var report string
+	if err := row.Scan(&item.Uid, &item.BoardUid, &item.UserUid, &item.Source, &item.Status,
+		&report, &item.LastError, &item.Created, &item.Updated); err != nil {
+		return item, err
+	}
+	_ = json.Unmarshal([]byte(report), &item.Report)
+	return item, nil
+}
+
+// 게시판에 새 카테고리 추가하기
+func (r *NuboImportRepository) InsertCategory(boardUid uint, name string) (uint, error) {
+	query := fmt.Sprintf("INSERT INTO %s%s (board_uid, name) VALUES (?, ?)", configs.Env.Prefix, models.TABLE_BOARD_CAT)
+	result, err := r.db.Exec(query, boardUid, name)
+	if err != nil {
+		return models.FAILED, err
+	}
+	uid, err := result.LastInsertId()
+	return uint(uid), err
+}
+
+// 원래 작성 시간을 유지한 채 댓글 저장하기 (답글이면 부모 댓글 스레드 아래에 연결하되 게시판 최대 깊이를 넘지 않게 함)
+func (r *NuboImportRepository) InsertImportedComment(param models.ImportCommentParam) (uint, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.FAILED, err
+	}
+	defer tx.Rollback()
+
+	var rootUid, depth uint
+	var parentPath string
+	parentUid := param.ParentUid
+	if parentUid > 0 {
+		query := fmt.Sprintf("SELECT reply_uid, depth, path FROM %s%s WHERE uid = ? LIMIT 1 FOR UPDATE",
+			configs.Env.Prefix, models.TABLE_COMMENT)
+		if err := tx.QueryRow(query, parentUid).Scan(&rootUid, &depth, &parentPath); err != nil {
+			return models.FAILED, err
+		}
+		var maxDepth uint
+		query = fmt.Sprintf("SELECT comment_depth FROM %s%s WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_BOARD)
+		if err := tx.QueryRow(query, param.BoardUid).Scan(&maxDepth); err != nil {
+			return models.FAILED, err
+		}
+		if depth+1 > maxDepth {
+			parentUid, parentPath, depth = clampCommentParent(parentPath, maxDepth)
+			if parentUid == 0 {
+				return models.FAILED, fmt.Errorf("invalid parent comment path")
+			}
+		}
+		depth++
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s%s
+		(reply_uid, parent_uid, depth, board_uid, post_uid, user_uid, content, submitted, modified, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_COMMENT)
+	result, err := tx.Exec(query, rootUid, parentUid, depth, param.BoardUid, param.PostUid,
+		param.UserUid, param.Content, param.Submitted, param.Modified, param.Status)
+	if err != nil {
+		return models.FAILED, err
+	}
+	insertId, err := result.LastInsertId()
+	if err != nil {
+		return models.FAILED, err
+	}
+	if rootUid == 0 {
+		rootUid = uint(insertId)
+	}
+	query = fmt.Sprintf("UPDATE %s%s SET reply_uid = ?, path = ? WHERE uid = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_COMMENT)
+	if _, err := tx.Exec(query, rootUid, commentPath(parentPath, uint(insertId)), insertId); err != nil {
+		return models.FAILED, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.FAILED, err
+	}
+	return uint(insertId), nil
+}
+
+// 게시판 최대 깊이에 맞춰 답글을 붙일 가장 깊은 조상의 고유번호, 경로, 깊이 반환 (경로가 잘못되었으면 0)
+func clampCommentParent(parentPath string, maxDepth uint) (uint, string, uint) {
+	if maxDepth < 1 {
+		maxDepth = models.COMMENT_DEPTH_DEFAULT
+	}
+	maxDepth = min(maxDepth, models.COMMENT_DEPTH_MAX)
+	segments := strings.Split(strings.TrimSuffix(parentPath, "/"), "/")
+	if len(segments) < int(maxDepth) {
+		return models.FAILED, "", 0
+	}
+	uid, err := strconv.ParseUint(segments[maxDepth-1], 10, 32)
+	if err != nil {
+		return models.FAILED, "", 0
+	}
+	return uint(uid), strings.Join(segments[:maxDepth], "/") + "/", maxDepth - 1
+}
+
+// 원래 작성/수정 시간과 조회수를 유지한 채 게시글 저장하기 (포인트는 변경하지 않고, 원본 키가 있으면 연결 정보도 함께 저장)
+func (r *NuboImportRepository) InsertImportedPost(param models.ImportPostParam) (uint, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.FAILED, err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`INSERT INTO %s%s
+		(board_uid, user_uid, category_uid, title, content, submitted, modified, hit, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_POST)
+	result, err := tx.Exec(query, param.BoardUid, param.UserUid, param.CategoryUid, param.Title,
+		param.Content, param.Submitted, param.Modified, param.Hit, param.Status)
+	if err != nil {
+		return models.FAILED, err
+	}
+	uid, err := result.LastInsertId()
+	if err != nil {
+		return models.FAILED, err
+	}
+	if err := insertSyncChange(tx, uint(uid), models.SYNC_CREATED); err != nil {
+		return models.FAILED, err
+	}
+	if param.SourceHash != "" && param.SourceKey != "" {
+		if err := saveImportMap(tx, param.BoardUid, param.SourceHash, models.IMPORT_KIND_POST, param.SourceKey, uint(uid), ""); err != nil {
+			return models.FAILED, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return models.FAILED, err
+	}
+	return uint(uid), nil
+}
+
+// 원본 항목과 가져온 레코드 연결 저장하기
+func (r *NuboImportRepository) SaveImportMap(boardUid uint, sourceHash string, kind string, keyHash string, targetUid uint, targetPath string) error {
+	return saveImportMap(r.db, boardUid, sourceHash, kind, keyHash, targetUid, targetPath)
+}
+
+// 연결 정보 저장 쿼리 실행 (트랜잭션 안에서도 사용)
+func saveImportMap(db syncChangeExecer, boardUid uint, sourceHash string, kind string, keyHash string, targetUid uint, targetPath string) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s (board_uid, source_hash, kind, key_hash, target_uid, target_path, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE target_uid = VALUES(target_uid), target_path = VALUES(target_path)`,
+		configs.Env.Prefix, models.TABLE_IMPORT_MAP)
+	_, err := db.Exec(query, boardUid, sourceHash, kind, keyHash, targetUid, targetPath, time.Now().UnixMilli())
+	return err
+}
+
+// 가져온 게시글의 제목, 본문, 수정 시간 바꾸기 (원본에서 고친 내용을 반영할 때 사용)
+func (r *NuboImportRepository) UpdateImportedPost(postUid uint, param models.ImportPostParam) error {
+	query := fmt.Sprintf("UPDATE %s%s SET title = ?, content = ?, modified = ? WHERE uid = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_POST)
+	if _, err := r.db.Exec(query, param.Title, param.Content, param.Modified, postUid); err != nil {
+		return err
+	}
+	return insertSyncChange(r.db, postUid, models.SYNC_MODIFIED)
+}
+
+// 게시글 본문만 바꾸기 (가져온 글 사이의 링크를 새 주소로 고칠 때 사용)
+func (r *NuboImportRepository) UpdatePostContent(postUid uint, content string) error {
+	query := fmt.Sprintf("UPDATE %s%s SET content = ? WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_POST)
+	if _, err := r.db.Exec(query, content, postUid); err != nil {
+		return err
+	}
+	return insertSyncChange(r.db, postUid, models.SYNC_MODIFIED)
+}
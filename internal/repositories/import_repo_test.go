@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+func TestImportedReplyIsClampedToBoardCommentDepth(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Prefix = "nubo_"
+	t.Cleanup(func() { configs.Env = previous })
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewNuboImportRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT reply_uid, depth, path FROM nubo_comment WHERE uid = ?")).WithArgs(30).
+		WillReturnRows(sqlmock.NewRows([]string{"reply_uid", "depth", "path"}).
+			AddRow(10, 2, "0000000010/0000000020/0000000030/"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT comment_depth FROM nubo_board WHERE uid = ?")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"comment_depth"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO nubo_comment")).
+		WithArgs(10, 20, 2, 1, 5, 2, "reply", 100, 0, models.CONTENT_NORMAL).
+		WillReturnResult(sqlmock.NewResult(40, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE nubo_comment SET reply_uid = ?, path = ?")).
+		WithArgs(10, "0000000010/0000000020/0000000040/", 40).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	uid, err := repo.InsertImportedComment(models.ImportCommentParam{
+		BoardUid: 1, PostUid: 5, ParentUid: 30, UserUid: 2, Content: "reply", Submitted: 100, Status: models.CONTENT_NORMAL,
+	})
+	if err != nil || uid != 40 {
+		t.Fatalf("insert imported reply: uid %d err %v", uid, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
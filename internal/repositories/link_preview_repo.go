@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type LinkPreviewRepository interface {
+	GetLinkPreview(urlHash string) (models.LinkPreviewRecord, error)
+	SaveLinkPreview(record models.LinkPreviewRecord) error
+}
+
+type NuboLinkPreviewRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboLinkPreviewRepository(db *sql.DB) *NuboLinkPreviewRepository {
+	return &NuboLinkPreviewRepository{db: db}
+}
+
+// 주소 해시로 캐시된 링크 미리보기 가져오기
+func (r *NuboLinkPreviewRepository) GetLinkPreview(urlHash string) (models.LinkPreviewRecord, error) {
+	record := models.LinkPreviewRecord{UrlHash: urlHash}
+	query := fmt.Sprintf(`SELECT url, title, description, site_name, image, type, failed, fetched
+		FROM %s%s WHERE url_hash = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_LINK_PREVIEW)
+
+	err := r.db.QueryRow(query, urlHash).Scan(&record.Url, &record.Title, &record.Description, &record.SiteName,
+		&record.Image, &record.Type, &record.Failed, &record.Fetched)
+	return record, err
+}
+
+// 링크 미리보기 캐시 추가 또는 갱신하기
+func (r *NuboLinkPreviewRepository) SaveLinkPreview(record models.LinkPreviewRecord) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s
+		(url_hash, url, title, description, site_name, image, type, failed, fetched)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE title = VALUES(title), description = VALUES(description),
+			site_name = VALUES(site_name), image = VALUES(image), type = VALUES(type),
+			failed = VALUES(failed), fetched = VALUES(fetched)`,
+		configs.Env.Prefix, models.TABLE_LINK_PREVIEW)
+
+	_, err := r.db.Exec(query, record.UrlHash, record.Url, record.Title, record.Description, record.SiteName,
+		record.Image, record.Type, record.Failed, record.Fetched)
+	return err
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type RelatedRepository interface {
+	FindByCategory(postUid uint, limit uint) ([]models.BoardRelatedCandidate, error)
+	FindByImageDescription(postUid uint, limit uint) ([]models.BoardRelatedCandidate, error)
+	FindBySharedTags(postUid uint, limit uint) ([]models.BoardRelatedCandidate, error)
+	GetImageDescription(postUid uint) string
+}
+
+type NuboRelatedRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboRelatedRepository(db *sql.DB) *NuboRelatedRepository {
+	return &NuboRelatedRepository{db: db}
+}
+
+// 같은 게시판, 같은 카테고리의 최근 게시글들 가져오기
+func (r *NuboRelatedRepository) FindByCategory(postUid uint, limit uint) ([]models.BoardRelatedCandidate, error) {
+	query := fmt.Sprintf(`SELECT p.uid, p.board_uid, p.user_uid, p.title, p.submitted
+												FROM %s%s p JOIN %s%s s ON s.uid = ?
+												WHERE s.category_uid > 0 AND p.category_uid = s.category_uid AND p.board_uid = s.board_uid
+												AND p.uid != s.uid AND p.status = ? ORDER BY p.uid DESC LIMIT ?`,
+		configs.Env.Prefix, models.TABLE_POST, configs.Env.Prefix, models.TABLE_POST)
+
+	rows, err := r.db.Query(query, postUid, models.CONTENT_NORMAL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.BoardRelatedCandidate, 0)
+	for rows.Next() {
+		item := models.BoardRelatedCandidate{SameCategory: true}
+		if err := rows.Scan(&item.PostUid, &item.BoardUid, &item.UserUid, &item.Title, &item.Submitted); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 이미지 설명이 있는 최근 게시글들과 설명 텍스트 가져오기
+func (r *NuboRelatedRepository) FindByImageDescription(postUid uint, limit uint) ([]models.BoardRelatedCandidate, error) {
+	query := fmt.Sprintf(`SELECT p.uid, p.board_uid, p.user_uid, p.title, p.submitted, GROUP_CONCAT(d.description SEPARATOR ' ')
+												FROM %s%s d JOIN %s%s p ON p.uid = d.post_uid
+												WHERE d.post_uid != ? AND p.status = ?
+												GROUP BY p.uid ORDER BY p.uid DESC LIMIT ?`,
+		configs.Env.Prefix, models.TABLE_IMAGE_DESC, configs.Env.Prefix, models.TABLE_POST)
+
+	rows, err := r.db.Query(query, postUid, models.CONTENT_NORMAL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.BoardRelatedCandidate, 0)
+	for rows.Next() {
+		item := models.BoardRelatedCandidate{}
+		if err := rows.Scan(&item.PostUid, &item.BoardUid, &item.UserUid, &item.Title, &item.Submitted, &item.Description); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 해시태그를 공유하는 게시글들을 겹치는 태그 수가 많은 순서로 가져오기
+func (r *NuboRelatedRepository) FindBySharedTags(postUid uint, limit uint) ([]models.BoardRelatedCandidate, error) {
+	query := fmt.Sprintf(`SELECT p.uid, p.board_uid, p.user_uid, p.title, p.submitted, COUNT(*) AS shared
+												FROM %s%s a JOIN %s%s b ON a.hashtag_uid = b.hashtag_uid
+												JOIN %s%s p ON p.uid = b.post_uid
+												WHERE a.post_uid = ? AND b.post_uid != ? AND p.status = ?
+												GROUP BY p.uid ORDER BY shared DESC, p.uid DESC LIMIT ?`,
+		configs.Env.Prefix, models.TABLE_POST_HASHTAG, configs.Env.Prefix, models.TABLE_POST_HASHTAG,
+		configs.Env.Prefix, models.TABLE_POST)
+
+	rows, err := r.db.Query(query, postUid, postUid, models.CONTENT_NORMAL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.BoardRelatedCandidate, 0)
+	for rows.Next() {
+		item := models.BoardRelatedCandidate{}
+		if err := rows.Scan(&item.PostUid, &item.BoardUid, &item.UserUid, &item.Title, &item.Submitted, &item.SharedTags); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 게시글에 첨부된 이미지들의 설명 텍스트를 하나로 합쳐서 가져오기
+func (r *NuboRelatedRepository) GetImageDescription(postUid uint) string {
+	var description sql.NullString
+	query := fmt.Sprintf("SELECT GROUP_CONCAT(description SEPARATOR ' ') FROM %s%s WHERE post_uid = ?",
+		configs.Env.Prefix, models.TABLE_IMAGE_DESC)
+	r.db.QueryRow(query, postUid).Scan(&description)
+	return description.String
+}
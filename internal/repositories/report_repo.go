@@ -0,0 +1,308 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+const reportRequestPreview = 10
+
+type ReportRepository interface {
+	CountContentReporters(target models.ReportTarget, targetUid uint) (uint, error)
+	GetContentReports(param models.AdminContentReportParam) (models.AdminContentReportResult, error)
+	GetReportTarget(target models.ReportTarget, targetUid uint) (models.ReportTargetInfo, error)
+	GetHiddenStatus(target models.ReportTarget, targetUid uint) (models.Status, bool)
+	HideReportedContent(target models.ReportTarget, targetUid uint, status models.Status) error
+	HoldContent(target models.ReportTarget, targetUid uint, writerUid uint, reason models.ReportReason) error
+	InsertContentReport(param models.ContentReportParam, writerUid uint) error
+	IsContentReported(target models.ReportTarget, targetUid uint, userUid uint) bool
+	ResolveContentReports(target models.ReportTarget, targetUid uint, response string) error
+	UpdateContentStatus(target models.ReportTarget, targetUid uint, status models.Status) error
+}
+
+type NuboReportRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboReportRepository(db *sql.DB) *NuboReportRepository {
+	return &NuboReportRepository{db: db}
+}
+
+// 신고 대상 종류에 맞는 테이블 이름 반환하기
+func reportTargetTable(target models.ReportTarget) (models.Table, error) {
+	switch target {
+	case models.REPORT_TARGET_POST:
+		return models.TABLE_POST, nil
+	case models.REPORT_TARGET_COMMENT:
+		return models.TABLE_COMMENT, nil
+	}
+	return "", fmt.Errorf("invalid report target")
+}
+
+// 아직 처리되지 않은 신고를 남긴 서로 다른 사용자 수 세기
+func (r *NuboReportRepository) CountContentReporters(target models.ReportTarget, targetUid uint) (uint, error) {
+	var count uint
+	query := fmt.Sprintf(`SELECT COUNT(DISTINCT from_uid) FROM %s%s
+		WHERE target_type = ? AND target_uid = ? AND solved = 0`, configs.Env.Prefix, models.TABLE_REPORT)
+	err := r.db.QueryRow(query, target, targetUid).Scan(&count)
+	return count, err
+}
+
+// 게시글/댓글 신고를 대상별로 묶어서 가져오기
+func (r *NuboReportRepository) GetContentReports(param models.AdminContentReportParam) (models.AdminContentReportResult, error) {
+	result := models.AdminContentReportResult{Item: make([]models.AdminContentReportItem, 0)}
+	prefix := configs.Env.Prefix
+	where := "target_type IN (?, ?) AND solved = ?"
+	args := []any{models.REPORT_TARGET_POST, models.REPORT_TARGET_COMMENT, param.IsSolved}
+	if param.Target != models.REPORT_TARGET_USER {
+		where = "target_type = ? AND solved = ?"
+		args = []any{param.Target, param.IsSolved}
+	}
+
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM (SELECT 1 FROM %s%s WHERE %s GROUP BY target_type, target_uid) AS t`,
+		prefix, models.TABLE_REPORT, where)
+	if err := r.db.QueryRow(query, args...).Scan(&result.Total); err != nil {
+		return result, err
+	}
+
+	query = fmt.Sprintf(`SELECT target_type, target_uid, COUNT(*), MAX(hidden), MAX(timestamp)
+		FROM %s%s WHERE %s GROUP BY target_type, target_uid
+		ORDER BY COUNT(*) DESC, MAX(timestamp) DESC LIMIT ? OFFSET ?`, prefix, models.TABLE_REPORT, where)
+	rows, err := r.db.Query(query, append(args, param.Limit, (param.Page-1)*param.Limit)...)
+	if err != nil {
+		return result, err
+	}
+	for rows.Next() {
+		item := models.AdminContentReportItem{}
+		if err := rows.Scan(&item.Target, &item.TargetUid, &item.Count, &item.Hidden, &item.LastReported); err != nil {
+			rows.Close()
+			return result, err
+		}
+		result.Item = append(result.Item, item)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return result, err
+	}
+	rows.Close()
+
+	for i := range result.Item {
+		if err := r.fillContentReport(&result.Item[i], param.IsSolved); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// 신고 묶음에 대상 내용, 작성자, 사유별 건수, 최근 신고 내용 채우기
+func (r *NuboReportRepository) fillContentReport(item *models.AdminContentReportItem, isSolved bool) error {
+	prefix := configs.Env.Prefix
+	var query string
+	switch item.Target {
+	case models.REPORT_TARGET_POST:
+		query = fmt.Sprintf(`SELECT p.board_uid, p.uid, p.user_uid, p.title, p.status, COALESCE(u.name, ''), COALESCE(u.profile, '')
+			FROM %s%s p LEFT JOIN %s%s u ON u.uid = p.user_uid WHERE p.uid = ? LIMIT 1`,
+			prefix, models.TABLE_POST, prefix, models.TABLE_USER)
+	default:
+		query = fmt.Sprintf(`SELECT c.board_uid, c.post_uid, c.user_uid, SUBSTRING(c.content, 1, 200), c.status, COALESCE(u.name, ''), COALESCE(u.profile, '')
+			FROM %s%s c LEFT JOIN %s%s u ON u.uid = c.user_uid WHERE c.uid = ? LIMIT 1`,
+			prefix, models.TABLE_COMMENT, prefix, models.TABLE_USER)
+	}
+	err := r.db.QueryRow(query, item.TargetUid).Scan(&item.BoardUid, &item.PostUid, &item.Writer.UserUid,
+		&item.Excerpt, &item.Status, &item.Writer.Name, &item.Writer.Profile)
+	if err == sql.ErrNoRows {
+		item.Status = models.CONTENT_REMOVED
+	} else if err != nil {
+		return err
+	}
+
+	item.Reasons = make([]models.AdminContentReportReason, 0)
+	query = fmt.Sprintf(`SELECT reason, COUNT(*) FROM %s%s
+		WHERE target_type = ? AND target_uid = ? AND solved = ? GROUP BY reason ORDER BY COUNT(*) DESC`,
+		prefix, models.TABLE_REPORT)
+	rows, err := r.db.Query(query, item.Target, item.TargetUid, isSolved)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		reason := models.AdminContentReportReason{}
+		if err := rows.Scan(&reason.Reason, &reason.Count); err != nil {
+			rows.Close()
+			return err
+		}
+		item.Reasons = append(item.Reasons, reason)
+	}
+	rows.Close()
+
+	item.Requests = make([]models.AdminContentReportRequest, 0)
+	query = fmt.Sprintf(`SELECT r.from_uid, COALESCE(u.name, ''), COALESCE(u.profile, ''), r.reason, r.request, r.timestamp
+		FROM %s%s r LEFT JOIN %s%s u ON u.uid = r.from_uid
+		WHERE r.target_type = ? AND r.target_uid = ? AND r.solved = ? ORDER BY r.uid DESC LIMIT ?`,
+		prefix, models.TABLE_REPORT, prefix, models.TABLE_USER)
+	rows, err = r.db.Query(query, item.Target, item.TargetUid, isSolved, reportRequestPreview)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		request := models.AdminContentReportRequest{}
+		if err := rows.Scan(&request.From.UserUid, &request.From.Name, &request.From.Profile,
+			&request.Reason, &request.Request, &request.Date); err != nil {
+			return err
+		}
+		item.Requests = append(item.Requests, request)
+	}
+	return rows.Err()
+}
+
+// 신고된 게시글/댓글의 위치, 작성자, 상태 가져오기
+func (r *NuboReportRepository) GetReportTarget(target models.ReportTarget, targetUid uint) (models.ReportTargetInfo, error) {
+	info := models.ReportTargetInfo{}
+	prefix := configs.Env.Prefix
+	var query string
+	switch target {
+	case models.REPORT_TARGET_POST:
+		query = fmt.Sprintf("SELECT board_uid, uid, user_uid, status FROM %s%s WHERE uid = ? LIMIT 1", prefix, models.TABLE_POST)
+	case models.REPORT_TARGET_COMMENT:
+		query = fmt.Sprintf("SELECT board_uid, post_uid, user_uid, status FROM %s%s WHERE uid = ? LIMIT 1", prefix, models.TABLE_COMMENT)
+	default:
+		return info, fmt.Errorf("invalid report target")
+	}
+	err := r.db.QueryRow(query, targetUid).Scan(&info.BoardUid, &info.PostUid, &info.WriterUid, &info.Status)
+	return info, err
+}
+
+// 신고가 누적된 게시글/댓글을 비밀 상태로 숨기고 자동 숨김 여부와 숨기기 전 상태(일반/공지) 기록하기
+func (r *NuboReportRepository) HideReportedContent(target models.ReportTarget, targetUid uint, status models.Status) error {
+	table, err := reportTargetTable(target)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf("UPDATE %s%s SET status = ? WHERE uid = ? AND status = ? LIMIT 1", prefix, table)
+	result, err := tx.Exec(query, models.CONTENT_SECRET, targetUid, status)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != 1 {
+		return nil // 그 사이 상태가 바뀌었거나 이미 숨겨진(또는 삭제된) 상태
+	}
+	query = fmt.Sprintf("UPDATE %s%s SET hidden = 1, hidden_status = ? WHERE target_type = ? AND target_uid = ? AND solved = 0",
+		prefix, models.TABLE_REPORT)
+	if _, err := tx.Exec(query, status, target, targetUid); err != nil {
+		return err
+	}
+	if target == models.REPORT_TARGET_POST {
+		if err := insertSyncChange(tx, targetUid, models.SYNC_MODIFIED); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// 필터 등이 보류한 게시글/댓글을 검토 전까지 숨기고 신고자 없는(from_uid 0) 숨김 신고로 관리자 목록에 올리기
+func (r *NuboReportRepository) HoldContent(target models.ReportTarget, targetUid uint, writerUid uint, reason models.ReportReason) error {
+	table, err := reportTargetTable(target)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf("UPDATE %s%s SET status = ? WHERE uid = ? AND status != ? LIMIT 1", prefix, table)
+	if _, err := tx.Exec(query, models.CONTENT_SECRET, targetUid, models.CONTENT_REMOVED); err != nil {
+		return err
+	}
+	query = fmt.Sprintf(`INSERT INTO %s%s (to_uid, from_uid, target_type, target_uid, reason, request, response, timestamp, solved, hidden)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, prefix, models.TABLE_REPORT)
+	if _, err := tx.Exec(query, writerUid, 0, target, targetUid, reason, "", "", time.Now().UnixMilli(), 0, 1); err != nil {
+		return err
+	}
+	if target == models.REPORT_TARGET_POST {
+		if err := insertSyncChange(tx, targetUid, models.SYNC_MODIFIED); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// 게시글/댓글 신고 추가하기 (to_uid에는 작성자를 기록)
+func (r *NuboReportRepository) InsertContentReport(param models.ContentReportParam, writerUid uint) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s (to_uid, from_uid, target_type, target_uid, reason, request, response, timestamp, solved)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_REPORT)
+	_, err := r.db.Exec(query, writerUid, param.UserUid, param.Target, param.TargetUid, param.Reason,
+		param.Content, "", time.Now().UnixMilli(), 0)
+	return err
+}
+
+// 처리되지 않은 신고 때문에 자동으로 숨겨진 상태인지 확인하고 숨기기 전 상태 반환하기
+func (r *NuboReportRepository) GetHiddenStatus(target models.ReportTarget, targetUid uint) (models.Status, bool) {
+	var status models.Status
+	query := fmt.Sprintf(`SELECT hidden_status FROM %s%s
+		WHERE target_type = ? AND target_uid = ? AND solved = 0 AND hidden = 1 LIMIT 1`, configs.Env.Prefix, models.TABLE_REPORT)
+	if err := r.db.QueryRow(query, target, targetUid).Scan(&status); err != nil {
+		return models.CONTENT_NORMAL, false
+	}
+	return status, true
+}
+
+// 사용자가 이미 이 게시글/댓글을 신고했는지 확인
+func (r *NuboReportRepository) IsContentReported(target models.ReportTarget, targetUid uint, userUid uint) bool {
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s%s
+		WHERE target_type = ? AND target_uid = ? AND from_uid = ? AND solved = 0)`, configs.Env.Prefix, models.TABLE_REPORT)
+	if err := r.db.QueryRow(query, target, targetUid, userUid).Scan(&exists); err != nil {
+		return false
+	}
+	return exists
+}
+
+// 대상에 대한 처리되지 않은 신고들을 모두 처리 완료로 바꾸기
+func (r *NuboReportRepository) ResolveContentReports(target models.ReportTarget, targetUid uint, response string) error {
+	query := fmt.Sprintf(`UPDATE %s%s SET response = ?, solved = 1
+		WHERE target_type = ? AND target_uid = ? AND solved = 0`, configs.Env.Prefix, models.TABLE_REPORT)
+	result, err := r.db.Exec(query, response, target, targetUid)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows < 1 {
+		return fmt.Errorf("report not found")
+	}
+	return nil
+}
+
+// 게시글/댓글 상태 변경하기
+func (r *NuboReportRepository) UpdateContentStatus(target models.ReportTarget, targetUid uint, status models.Status) error {
+	table, err := reportTargetTable(target)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("UPDATE %s%s SET status = ? WHERE uid = ? LIMIT 1", configs.Env.Prefix, table)
+	if _, err = r.db.Exec(query, status, targetUid); err != nil || target != models.REPORT_TARGET_POST {
+		return err
+	}
+	return insertSyncChange(r.db, targetUid, models.SYNC_MODIFIED)
+}
@@ -4,21 +4,37 @@ import "database/sql"
 
 // 모든 리포지토리들을 관리
 type Repository struct {
+	ActivityPub  ActivityPubRepository
 	Admin        AdminRepository
+	Analytics    AnalyticsRepository
+	Approval     ApprovalRepository
+	Archive      ArchiveRepository
 	Auth         AuthRepository
 	Board        BoardRepository
 	BoardEdit    BoardEditRepository
 	BoardView    BoardViewRepository
 	Chat         ChatRepository
 	Comment      CommentRepository
+	Feed         FeedRepository
+	Filter       ContentFilterRepository
+	Hashtag      HashtagRepository
 	Home         HomeRepository
+	ImageVariant ImageVariantRepository
+	Import       ImportRepository
+	LinkPreview  LinkPreviewRepository
 	MailCampaign MailCampaignRepository
 	MailDelivery MailDeliveryRepository
+	Share        ShareRepository
 	SignupInvite SignupInviteRepository
+	Spam         SpamRepository
 	Noti         NotiRepository
 	Push         PushRepository
+	Related      RelatedRepository
+	Report       ReportRepository
+	Role         RoleRepository
 	Sync         SyncRepository
 	Trade        TradeRepository
+	Trending     TrendingRepository
 	User         UserRepository
 }
 
@@ -26,21 +42,37 @@ type Repository struct {
 func NewRepository(db *sql.DB) *Repository {
 	board := NewNuboBoardRepository(db)
 	return &Repository{
+		ActivityPub:  NewNuboActivityPubRepository(db),
 		Admin:        NewNuboAdminRepository(db),
+		Analytics:    NewNuboAnalyticsRepository(db),
+		Approval:     NewNuboApprovalRepository(db),
+		Archive:      NewNuboArchiveRepository(db),
 		Auth:         NewNuboAuthRepository(db),
 		Board:        board,
 		BoardEdit:    NewNuboBoardEditRepository(db, board),
 		BoardView:    NewNuboBoardViewRepository(db, board),
 		Chat:         NewNuboChatRepository(db),
 		Comment:      NewNuboCommentRepository(db, board),
+		Feed:         NewNuboFeedRepository(db),
+		Filter:       NewNuboContentFilterRepository(db),
+		Hashtag:      NewNuboHashtagRepository(db),
 		Home:         NewNuboHomeRepository(db, board),
+		ImageVariant: NewNuboImageVariantRepository(db),
+		Import:       NewNuboImportRepository(db),
+		LinkPreview:  NewNuboLinkPreviewRepository(db),
 		MailCampaign: NewNuboMailCampaignRepository(db),
 		MailDelivery: NewNuboMailDeliveryRepository(db),
+		Share:        NewNuboShareRepository(db),
 		SignupInvite: NewNuboSignupInviteRepository(db),
+		Spam:         NewNuboSpamRepository(db),
 		Noti:         NewNuboNotiRepository(db),
 		Push:         NewNuboPushRepository(db),
+		Related:      NewNuboRelatedRepository(db),
+		Report:       NewNuboReportRepository(db),
+		Role:         NewNuboRoleRepository(db),
 		Sync:         NewNuboSyncRepository(db),
 		Trade:        NewNuboTradeRepository(db),
+		Trending:     NewNuboTrendingRepository(db),
 		User:         NewNuboUserRepository(db),
 	}
 }
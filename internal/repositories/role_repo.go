@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type RoleRepository interface {
+	GetBoardAcl(boardUid uint) ([]models.BoardAclRule, error)
+	GetRoleMembers(roleUid uint) ([]models.BoardWriter, error)
+	GetRoles() ([]models.UserRole, error)
+	InsertRoleMember(roleUid uint, userUid uint) error
+	IsRoleExists(roleUid uint) bool
+	IsRoleNameDuplicated(name string, exceptUid uint) bool
+	RemoveRole(roleUid uint) error
+	RemoveRoleMember(roleUid uint, userUid uint) error
+	ReplaceBoardAcl(boardUid uint, rules []models.BoardAclRule) error
+	SaveRole(param models.AdminRoleSaveParam) (uint, error)
+}
+
+type NuboRoleRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboRoleRepository(db *sql.DB) *NuboRoleRepository {
+	return &NuboRoleRepository{db: db}
+}
+
+// 게시판 활동에 대한 역할 규칙 판정하기 (거부가 허용보다 우선, 허용 규칙이 있으면 나머지 회원은 거부)
+func evaluateBoardAcl(db *sql.DB, boardUid uint, userUid uint, action models.BoardAction) models.BoardAclDecision {
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf(`SELECT a.allow, COUNT(m.user_uid) FROM %s%s a
+		LEFT JOIN %s%s m ON m.role_uid = a.role_uid AND m.user_uid = ?
+		WHERE a.board_uid = ? AND a.action = ? GROUP BY a.allow`,
+		prefix, models.TABLE_BOARD_ACL, prefix, models.TABLE_ROLE_MEMBER)
+	rows, err := db.Query(query, userUid, boardUid, action)
+	if err != nil {
+		return models.BOARD_ACL_NONE
+	}
+	defer rows.Close()
+
+	hasAllowRule, allowed, denied := false, false, false
+	for rows.Next() {
+		var allow bool
+		var matched uint
+		if err := rows.Scan(&allow, &matched); err != nil {
+			return models.BOARD_ACL_NONE
+		}
+		if allow {
+			hasAllowRule = true
+			allowed = matched > 0
+		} else {
+			denied = matched > 0
+		}
+	}
+	switch {
+	case denied:
+		return models.BOARD_ACL_DENY
+	case allowed:
+		return models.BOARD_ACL_ALLOW
+	case hasAllowRule:
+		return models.BOARD_ACL_DENY
+	default:
+		return models.BOARD_ACL_NONE
+	}
+}
+
+// 게시판에 지정된 역할 규칙 목록 가져오기
+func (r *NuboRoleRepository) GetBoardAcl(boardUid uint) ([]models.BoardAclRule, error) {
+	items := make([]models.BoardAclRule, 0)
+	query := fmt.Sprintf("SELECT role_uid, action, allow FROM %s%s WHERE board_uid = ? ORDER BY action ASC, role_uid ASC",
+		configs.Env.Prefix, models.TABLE_BOARD_ACL)
+	rows, err := r.db.Query(query, boardUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.BoardAclRule{}
+		if err := rows.Scan(&item.RoleUid, &item.Action, &item.Allow); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 역할에 속한 회원 목록 가져오기
+func (r *NuboRoleRepository) GetRoleMembers(roleUid uint) ([]models.BoardWriter, error) {
+	items := make([]models.BoardWriter, 0)
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf(`SELECT u.uid, u.name, u.profile, u.signature FROM %s%s m
+		JOIN %s%s u ON u.uid = m.user_uid WHERE m.role_uid = ? ORDER BY m.timestamp ASC`,
+		prefix, models.TABLE_ROLE_MEMBER, prefix, models.TABLE_USER)
+	rows, err := r.db.Query(query, roleUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.BoardWriter{}
+		if err := rows.Scan(&item.UserUid, &item.Name, &item.Profile, &item.Signature); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 회원 역할 목록 가져오기
+func (r *NuboRoleRepository) GetRoles() ([]models.UserRole, error) {
+	items := make([]models.UserRole, 0)
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf(`SELECT r.uid, r.name, r.info, r.timestamp,
+		(SELECT COUNT(*) FROM %s%s m WHERE m.role_uid = r.uid)
+		FROM %s%s r ORDER BY r.uid ASC`, prefix, models.TABLE_ROLE_MEMBER, prefix, models.TABLE_ROLE)
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.UserRole{}
+		if err := rows.Scan(&item.Uid, &item.Name, &item.Info, &item.Timestamp, &item.MemberCount); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 역할에 회원 추가하기 (이미 있으면 무시)
+func (r *NuboRoleRepository) InsertRoleMember(roleUid uint, userUid uint) error {
+	query := fmt.Sprintf("INSERT IGNORE INTO %s%s (role_uid, user_uid, timestamp) VALUES (?, ?, ?)",
+		configs.Env.Prefix, models.TABLE_ROLE_MEMBER)
+	_, err := r.db.Exec(query, roleUid, userUid, time.Now().UnixMilli())
+	return err
+}
+
+// 역할이 존재하는지 확인
+func (r *NuboRoleRepository) IsRoleExists(roleUid uint) bool {
+	var uid uint
+	query := fmt.Sprintf("SELECT uid FROM %s%s WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_ROLE)
+	r.db.QueryRow(query, roleUid).Scan(&uid)
+	return uid > 0
+}
+
+// 같은 이름의 역할이 이미 있는지 확인
+func (r *NuboRoleRepository) IsRoleNameDuplicated(name string, exceptUid uint) bool {
+	var uid uint
+	query := fmt.Sprintf("SELECT uid FROM %s%s WHERE name = ? AND uid != ? LIMIT 1", configs.Env.Prefix, models.TABLE_ROLE)
+	r.db.QueryRow(query, name, exceptUid).Scan(&uid)
+	return uid > 0
+}
+
+// 역할 삭제하기 (구성원과 게시판 규칙은 외래키로 함께 삭제)
+func (r *NuboRoleRepository) RemoveRole(roleUid uint) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_ROLE)
+	_, err := r.db.Exec(query, roleUid)
+	return err
+}
+
+// 역할에서 회원 빼기
+func (r *NuboRoleRepository) RemoveRoleMember(roleUid uint, userUid uint) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE role_uid = ? AND user_uid = ? LIMIT 1",
+		configs.Env.Prefix, models.TABLE_ROLE_MEMBER)
+	_, err := r.db.Exec(query, roleUid, userUid)
+	return err
+}
+
+// 게시판 역할 규칙을 새 목록으로 교체하기
+func (r *NuboRoleRepository) ReplaceBoardAcl(boardUid uint, rules []models.BoardAclRule) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	prefix := configs.Env.Prefix
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE board_uid = ?", prefix, models.TABLE_BOARD_ACL)
+	if _, err := tx.Exec(query, boardUid); err != nil {
+		return err
+	}
+	query = fmt.Sprintf("INSERT INTO %s%s (board_uid, role_uid, action, allow) VALUES (?, ?, ?, ?)",
+		prefix, models.TABLE_BOARD_ACL)
+	for _, rule := range rules {
+		if _, err := tx.Exec(query, boardUid, rule.RoleUid, rule.Action, rule.Allow); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// 역할 추가 또는 수정하기
+func (r *NuboRoleRepository) SaveRole(param models.AdminRoleSaveParam) (uint, error) {
+	if param.Uid > 0 {
+		query := fmt.Sprintf("UPDATE %s%s SET name = ?, info = ? WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_ROLE)
+		_, err := r.db.Exec(query, param.Name, param.Info, param.Uid)
+		return param.Uid, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s%s (name, info, timestamp) VALUES (?, ?, ?)", configs.Env.Prefix, models.TABLE_ROLE)
+	result, err := r.db.Exec(query, param.Name, param.Info, time.Now().UnixMilli())
+	if err != nil {
+		return models.FAILED, err
+	}
+	insertId, err := result.LastInsertId()
+	if err != nil {
+		return models.FAILED, err
+	}
+	return uint(insertId), nil
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+func TestEvaluateBoardAcl(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Prefix = "nubo_"
+	t.Cleanup(func() { configs.Env = previous })
+
+	query := regexp.QuoteMeta("SELECT a.allow, COUNT(m.user_uid) FROM nubo_board_acl a")
+	tests := []struct {
+		name string
+		rows [][2]any
+		want models.BoardAclDecision
+	}{
+		{name: "no rules", want: models.BOARD_ACL_NONE},
+		{name: "member of allowed role", rows: [][2]any{{true, 1}}, want: models.BOARD_ACL_ALLOW},
+		{name: "outside allowed roles", rows: [][2]any{{true, 0}}, want: models.BOARD_ACL_DENY},
+		{name: "member of denied role", rows: [][2]any{{false, 1}}, want: models.BOARD_ACL_DENY},
+		{name: "deny wins over allow", rows: [][2]any{{false, 1}, {true, 1}}, want: models.BOARD_ACL_DENY},
+		{name: "not in denied role", rows: [][2]any{{false, 0}}, want: models.BOARD_ACL_NONE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			rows := sqlmock.NewRows([]string{"allow", "matched"})
+			for _, row := range tt.rows {
+				rows.AddRow(row[0], row[1])
+			}
+			mock.ExpectQuery(query).WithArgs(uint(7), uint(3), models.BOARD_ACTION_WRITE).WillReturnRows(rows)
+
+			if got := evaluateBoardAcl(db, 3, 7, models.BOARD_ACTION_WRITE); got != tt.want {
+				t.Fatalf("evaluateBoardAcl() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
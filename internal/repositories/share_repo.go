@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type ShareRepository interface {
+	ConsumeShareView(shareUid uint) bool
+	GetShare(shareUid uint) (models.PostShareItem, error)
+	GetShareByToken(tokenHash string) (models.PostShareItem, error)
+	GetShareLogs(shareUid uint, limit uint) ([]models.PostShareLog, error)
+	GetShares(postUid uint) ([]models.PostShareItem, error)
+	InsertShare(item models.PostShareItem, tokenHash string) (uint, error)
+	InsertShareLog(item models.PostShareLog) error
+	RevokeShare(shareUid uint) error
+}
+
+type NuboShareRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboShareRepository(db *sql.DB) *NuboShareRepository {
+	return &NuboShareRepository{db: db}
+}
+
+const shareColumns = `uid, board_uid, post_uid, created_by, created, expires, max_views, views,
+	allow_comment, allow_download, revoked`
+
+// 공유 링크 한 행 읽어오기
+func scanShare(row interface{ Scan(...any) error }) (models.PostShareItem, error) {
+	item := models.PostShareItem{}
+	err := row.Scan(&item.Uid, &item.BoardUid, &item.PostUid, &item.CreatedBy, &item.Created, &item.Expires,
+		&item.MaxViews, &item.Views, &item.AllowComment, &item.AllowDownload, &item.Revoked)
+	return item, err
+}
+
+// 열람 제한 횟수 안에서 공유 링크 열람 횟수 올리기 (제한에 도달했으면 false)
+func (r *NuboShareRepository) ConsumeShareView(shareUid uint) bool {
+	query := fmt.Sprintf(`UPDATE %s%s SET views = views + 1
+		WHERE uid = ? AND revoked = 0 AND expires > ? AND (max_views = 0 OR views < max_views) LIMIT 1`,
+		configs.Env.Prefix, models.TABLE_POST_SHARE)
+	result, err := r.db.Exec(query, shareUid, time.Now().UnixMilli())
+	if err != nil {
+		return false
+	}
+	changed, err := result.RowsAffected()
+	return err == nil && changed == 1
+}
+
+// 공유 링크 가져오기
+func (r *NuboShareRepository) GetShare(shareUid uint) (models.PostShareItem, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s%s WHERE uid = ? LIMIT 1", shareColumns, configs.Env.Prefix, models.TABLE_POST_SHARE)
+	return scanShare(r.db.QueryRow(query, shareUid))
+}
+
+// 토큰 해시로 공유 링크 가져오기
+func (r *NuboShareRepository) GetShareByToken(tokenHash string) (models.PostShareItem, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s%s WHERE token_hash = ? LIMIT 1", shareColumns, configs.Env.Prefix, models.TABLE_POST_SHARE)
+	return scanShare(r.db.QueryRow(query, tokenHash))
+}
+
+// 공유 링크 열람 기록 가져오기
+func (r *NuboShareRepository) GetShareLogs(shareUid uint, limit uint) ([]models.PostShareLog, error) {
+	items := make([]models.PostShareLog, 0)
+	query := fmt.Sprintf(`SELECT uid, share_uid, user_uid, ip_hash, agent, timestamp FROM %s%s
+		WHERE share_uid = ? ORDER BY uid DESC LIMIT ?`, configs.Env.Prefix, models.TABLE_POST_SHARE_LOG)
+	rows, err := r.db.Query(query, shareUid, limit)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.PostShareLog{}
+		if err := rows.Scan(&item.Uid, &item.ShareUid, &item.UserUid, &item.IPHash, &item.Agent, &item.Timestamp); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 게시글에 발급된 공유 링크 목록 가져오기
+func (r *NuboShareRepository) GetShares(postUid uint) ([]models.PostShareItem, error) {
+	items := make([]models.PostShareItem, 0)
+	query := fmt.Sprintf("SELECT %s FROM %s%s WHERE post_uid = ? ORDER BY uid DESC", shareColumns, configs.Env.Prefix, models.TABLE_POST_SHARE)
+	rows, err := r.db.Query(query, postUid)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanShare(rows)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 공유 링크 추가하기
+func (r *NuboShareRepository) InsertShare(item models.PostShareItem, tokenHash string) (uint, error) {
+	query := fmt.Sprintf(`INSERT INTO %s%s (board_uid, post_uid, created_by, token_hash, created, expires,
+		max_views, views, allow_comment, allow_download, revoked) VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?, 0)`,
+		configs.Env.Prefix, models.TABLE_POST_SHARE)
+	result, err := r.db.Exec(query, item.BoardUid, item.PostUid, item.CreatedBy, tokenHash, item.Created, item.Expires,
+		item.MaxViews, item.AllowComment, item.AllowDownload)
+	if err != nil {
+		return models.FAILED, err
+	}
+	insertId, err := result.LastInsertId()
+	if err != nil {
+		return models.FAILED, err
+	}
+	return uint(insertId), nil
+}
+
+// 공유 링크 열람 기록 남기기
+func (r *NuboShareRepository) InsertShareLog(item models.PostShareLog) error {
+	query := fmt.Sprintf("INSERT INTO %s%s (share_uid, user_uid, ip_hash, agent, timestamp) VALUES (?, ?, ?, ?, ?)",
+		configs.Env.Prefix, models.TABLE_POST_SHARE_LOG)
+	_, err := r.db.Exec(query, item.ShareUid, item.UserUid, item.IPHash, item.Agent, time.Now().UnixMilli())
+	return err
+}
+
+// 공유 링크 폐기하기
+func (r *NuboShareRepository) RevokeShare(shareUid uint) error {
+	query := fmt.Sprintf("UPDATE %s%s SET revoked = 1 WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_POST_SHARE)
+	_, err := r.db.Exec(query, shareUid)
+	return err
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type SpamRepository interface {
+	CountDuplicates(hash string, since uint64) (int, error)
+	CountRecentWrites(userUid uint, since uint64) (int, error)
+	CountStrikes(userUid uint, since uint64) (int, error)
+	GetSignupTime(userUid uint) (uint64, error)
+	InsertSpamLog(item models.SpamLog) error
+	RemoveSpamLogsBefore(timestamp uint64) error
+}
+
+type NuboSpamRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboSpamRepository(db *sql.DB) *NuboSpamRepository {
+	return &NuboSpamRepository{db: db}
+}
+
+// 지정된 시각 이후 같은 내용이 (작성자, 게시판 구분 없이) 몇 번 작성되었는지 세기
+func (r *NuboSpamRepository) CountDuplicates(hash string, since uint64) (int, error) {
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s WHERE hash = ? AND timestamp >= ?",
+		configs.Env.Prefix, models.TABLE_SPAM_LOG)
+	err := r.db.QueryRow(query, hash, since).Scan(&count)
+	return count, err
+}
+
+// 지정된 시각 이후 사용자가 작성한 글, 댓글, 쪽지 수 세기
+func (r *NuboSpamRepository) CountRecentWrites(userUid uint, since uint64) (int, error) {
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s WHERE user_uid = ? AND timestamp >= ?",
+		configs.Env.Prefix, models.TABLE_SPAM_LOG)
+	err := r.db.QueryRow(query, userUid, since).Scan(&count)
+	return count, err
+}
+
+// 지정된 시각 이후 사용자가 보류 또는 거부 처리된 횟수 세기
+func (r *NuboSpamRepository) CountStrikes(userUid uint, since uint64) (int, error) {
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s WHERE user_uid = ? AND timestamp >= ? AND verdict > ?",
+		configs.Env.Prefix, models.TABLE_SPAM_LOG)
+	err := r.db.QueryRow(query, userUid, since, models.SPAM_VERDICT_ALLOW).Scan(&count)
+	return count, err
+}
+
+// 사용자의 가입 시각 가져오기
+func (r *NuboSpamRepository) GetSignupTime(userUid uint) (uint64, error) {
+	var signup uint64
+	query := fmt.Sprintf("SELECT signup FROM %s%s WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_USER)
+	err := r.db.QueryRow(query, userUid).Scan(&signup)
+	return signup, err
+}
+
+// 스팸 점수 기록 추가하기
+func (r *NuboSpamRepository) InsertSpamLog(item models.SpamLog) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s (user_uid, target, hash, score, verdict, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_SPAM_LOG)
+	_, err := r.db.Exec(query, item.UserUid, item.Target, item.Hash, item.Score, item.Verdict, item.Timestamp)
+	return err
+}
+
+// 판단에 더 이상 쓰이지 않는 오래된 스팸 점수 기록 삭제하기
+func (r *NuboSpamRepository) RemoveSpamLogsBefore(timestamp uint64) error {
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE timestamp < ?", configs.Env.Prefix, models.TABLE_SPAM_LOG)
+	_, err := r.db.Exec(query, timestamp)
+	return err
+}
@@ -3,13 +3,21 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/sirini/goapi/internal/configs"
 	"github.com/sirini/goapi/pkg/models"
 )
 
 type SyncRepository interface {
+	FinishSyncRun(run models.SyncRunLog) error
+	GetChanges(since uint64, boardUid uint, before int64, limit uint) ([]models.SyncChangeRecord, error)
 	GetFileName(fileUid uint) string
+	GetSyncPost(postUid uint) (models.HomePostItem, error)
+	GetSyncRunLogs(limit uint) ([]models.SyncRunLog, error)
+	GetSyncSource(sourceHash string, remote string, boardUid uint) (models.SyncSource, error)
+	GetSyncSources() ([]models.SyncSource, error)
+	SaveSyncCursor(sourceUid uint, cursor uint64) error
 }
 
 type NuboSyncRepository struct {
@@ -21,6 +29,86 @@ func NewNuboSyncRepository(db *sql.DB) *NuboSyncRepository {
 	return &NuboSyncRepository{db: db}
 }
 
+// sql.DB와 sql.Tx 모두에서 변경 기록을 남길 수 있도록 정의
+type syncChangeExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// 게시글 변경 기록 남기기 (게시글이 지금 속한 게시판으로 기록)
+func insertSyncChange(db syncChangeExecer, postUid uint, action models.SyncAction) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s (board_uid, post_uid, action, timestamp)
+		SELECT board_uid, uid, ?, ? FROM %s%s WHERE uid = ? LIMIT 1`,
+		configs.Env.Prefix, models.TABLE_SYNC_CHANGE, configs.Env.Prefix, models.TABLE_POST)
+	_, err := db.Exec(query, action, time.Now().UnixMilli(), postUid)
+	return err
+}
+
+// 해시태그가 달린 게시글들의 변경 기록 한꺼번에 남기기 (해시태그 병합, 이름 변경, 금지할 때 사용)
+func insertHashtagSyncChanges(db syncChangeExecer, hashtagUid uint) error {
+	query := fmt.Sprintf(`INSERT INTO %s%s (board_uid, post_uid, action, timestamp)
+		SELECT p.board_uid, p.uid, ?, ? FROM %s%s p JOIN %s%s ph ON ph.post_uid = p.uid
+		WHERE ph.hashtag_uid = ? AND p.status != ?`,
+		configs.Env.Prefix, models.TABLE_SYNC_CHANGE, configs.Env.Prefix, models.TABLE_POST,
+		configs.Env.Prefix, models.TABLE_POST_HASHTAG)
+	_, err := db.Exec(query, models.SYNC_MODIFIED, time.Now().UnixMilli(), hashtagUid, models.CONTENT_REMOVED)
+	return err
+}
+
+// 가져오기 실행 기록을 남기고 동기화 원본의 누적 글 수와 최근 결과 갱신하기
+func (r *NuboSyncRepository) FinishSyncRun(run models.SyncRunLog) error {
+	lastSuccess := "last_success"
+	if run.Status == models.SYNC_RUN_SUCCESS {
+		lastSuccess = "?"
+	}
+	query := fmt.Sprintf(`UPDATE %s%s SET created = created + ?, modified = modified + ?, removed = removed + ?,
+		last_run = ?, last_success = %s, last_error = ? WHERE uid = ? LIMIT 1`,
+		configs.Env.Prefix, models.TABLE_SYNC_SOURCE, lastSuccess)
+	args := []any{run.Created, run.Modified, run.Removed, run.Finished}
+	if run.Status == models.SYNC_RUN_SUCCESS {
+		args = append(args, run.Finished)
+	}
+	args = append(args, run.Error, run.SourceUid)
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return err
+	}
+	query = fmt.Sprintf(`INSERT INTO %s%s
+		(source_uid, started, finished, from_seq, to_seq, created, modified, removed, images, status, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, configs.Env.Prefix, models.TABLE_SYNC_LOG)
+	_, err := r.db.Exec(query, run.SourceUid, run.Started, run.Finished, run.FromSeq, run.ToSeq,
+		run.Created, run.Modified, run.Removed, run.Images, run.Status, run.Error)
+	return err
+}
+
+// since 이후의 변경 기록을 순서대로 가져오기 (동기화하지 않는 게시판 제외, before 이후 기록은 다음 요청으로 미룸)
+func (r *NuboSyncRepository) GetChanges(since uint64, boardUid uint, before int64, limit uint) ([]models.SyncChangeRecord, error) {
+	items := make([]models.SyncChangeRecord, 0)
+	where := ""
+	args := []any{since, before, models.SYNC_SCOPE_NONE}
+	if boardUid > 0 {
+		where = " AND c.board_uid = ?"
+		args = append(args, boardUid)
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT c.seq, c.board_uid, c.post_uid, c.action, c.timestamp
+		FROM %s%s c JOIN %s%s b ON b.uid = c.board_uid
+		WHERE c.seq > ? AND c.timestamp <= ? AND b.sync_scope != ?%s ORDER BY c.seq ASC LIMIT ?`,
+		configs.Env.Prefix, models.TABLE_SYNC_CHANGE, configs.Env.Prefix, models.TABLE_BOARD, where)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.SyncChangeRecord{}
+		if err := rows.Scan(&item.Seq, &item.BoardUid, &item.PostUid, &item.Action, &item.Timestamp); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
 // 첨부 파일의 원래 파일명 가져오기
 func (r *NuboSyncRepository) GetFileName(fileUid uint) string {
 	var name string
@@ -28,3 +116,83 @@ func (r *NuboSyncRepository) GetFileName(fileUid uint) string {
 	r.db.QueryRow(query, fileUid).Scan(&name)
 	return name
 }
+
+// 동기화할 게시글 하나 가져오기
+func (r *NuboSyncRepository) GetSyncPost(postUid uint) (models.HomePostItem, error) {
+	item := models.HomePostItem{}
+	query := fmt.Sprintf(`SELECT uid, board_uid, user_uid, category_uid, title, content, submitted, modified, hit, status
+		FROM %s%s WHERE uid = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_POST)
+	err := r.db.QueryRow(query, postUid).Scan(&item.Uid, &item.BoardUid, &item.UserUid, &item.CategoryUid,
+		&item.Title, &item.Content, &item.Submitted, &item.Modified, &item.Hit, &item.Status)
+	return item, err
+}
+
+// 최근 가져오기 실행 기록 가져오기
+func (r *NuboSyncRepository) GetSyncRunLogs(limit uint) ([]models.SyncRunLog, error) {
+	items := make([]models.SyncRunLog, 0)
+	query := fmt.Sprintf(`SELECT uid, source_uid, started, finished, from_seq, to_seq, created, modified, removed, images, status, error
+		FROM %s%s ORDER BY uid DESC LIMIT ?`, configs.Env.Prefix, models.TABLE_SYNC_LOG)
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := models.SyncRunLog{}
+		if err := rows.Scan(&item.Uid, &item.SourceUid, &item.Started, &item.Finished, &item.FromSeq, &item.ToSeq,
+			&item.Created, &item.Modified, &item.Removed, &item.Images, &item.Status, &item.Error); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 원격 주소와 대상 게시판에 해당하는 동기화 원본 가져오기 (처음이면 만들기)
+func (r *NuboSyncRepository) GetSyncSource(sourceHash string, remote string, boardUid uint) (models.SyncSource, error) {
+	query := fmt.Sprintf("INSERT IGNORE INTO %s%s (source_hash, remote, board_uid) VALUES (?, ?, ?)",
+		configs.Env.Prefix, models.TABLE_SYNC_SOURCE)
+	if _, err := r.db.Exec(query, sourceHash, remote, boardUid); err != nil {
+		return models.SyncSource{}, err
+	}
+	query = fmt.Sprintf(`SELECT uid, source_hash, remote, board_uid, cursor_seq, created, modified, removed, last_run, last_success, last_error
+		FROM %s%s WHERE source_hash = ? AND board_uid = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_SYNC_SOURCE)
+	return scanSyncSource(r.db.QueryRow(query, sourceHash, boardUid))
+}
+
+// 등록된 동기화 원본 목록 가져오기
+func (r *NuboSyncRepository) GetSyncSources() ([]models.SyncSource, error) {
+	items := make([]models.SyncSource, 0)
+	query := fmt.Sprintf(`SELECT uid, source_hash, remote, board_uid, cursor_seq, created, modified, removed, last_run, last_success, last_error
+		FROM %s%s ORDER BY uid ASC`, configs.Env.Prefix, models.TABLE_SYNC_SOURCE)
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanSyncSource(rows)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// 동기화 원본 한 행 읽기
+func scanSyncSource(row interface{ Scan(...any) error }) (models.SyncSource, error) {
+	item := models.SyncSource{}
+	err := row.Scan(&item.Uid, &item.SourceHash, &item.Remote, &item.BoardUid, &item.Cursor, &item.Created,
+		&item.Modified, &item.Removed, &item.LastRun, &item.LastSuccess, &item.LastError)
+	return item, err
+}
+
+// 마지막으로 반영한 원격 변경 번호 저장하기
+func (r *NuboSyncRepository) SaveSyncCursor(sourceUid uint, cursor uint64) error {
+	query := fmt.Sprintf("UPDATE %s%s SET cursor_seq = ? WHERE uid = ? LIMIT 1", configs.Env.Prefix, models.TABLE_SYNC_SOURCE)
+	_, err := r.db.Exec(query, cursor, sourceUid)
+	return err
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/sirini/goapi/internal/configs"
 	"github.com/sirini/goapi/pkg/models"
-	"github.com/sirini/goapi/pkg/utils"
 )
 
 type TradeRepository interface {
@@ -66,7 +65,7 @@ func (r *NuboTradeRepository) InsertTradePost(param models.TradeWriteParam, poin
 		(board_uid, user_uid, category_uid, title, content, submitted, modified, hit, status)
 		VALUES (?, ?, ?, ?, ?, ?, 0, 0, ?)`, configs.Env.Prefix, models.TABLE_POST)
 	postResult, err := tx.Exec(postQuery, param.BoardUid, param.UserUid, param.CategoryUid, param.Title,
-		param.Content, time.Now().UnixMilli(), utils.GetContentStatus(param.IsNotice, param.IsSecret))
+		param.Content, time.Now().UnixMilli(), writeStatus(param.EditorWriteParam))
 	if err != nil {
 		return models.FAILED, err
 	}
@@ -74,6 +73,9 @@ func (r *NuboTradeRepository) InsertTradePost(param models.TradeWriteParam, poin
 	if err != nil {
 		return models.FAILED, err
 	}
+	if err := insertPostApprovalTx(tx, uint(postUid), param.EditorWriteParam); err != nil {
+		return models.FAILED, err
+	}
 	tradeQuery := fmt.Sprintf(`INSERT INTO %s%s
 		(post_uid, brand, price, price_type, currency, product_condition, location, shipping_type, status, completed)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0)`, configs.Env.Prefix, models.TABLE_TRADE)
@@ -81,6 +83,9 @@ func (r *NuboTradeRepository) InsertTradePost(param models.TradeWriteParam, poin
 		param.ProductCondition, param.Location, param.ShippingType, models.TRADE_AVAILABLE); err != nil {
 		return models.FAILED, err
 	}
+	if err := insertSyncChange(tx, uint(postUid), models.SYNC_CREATED); err != nil {
+		return models.FAILED, err
+	}
 	if err := tx.Commit(); err != nil {
 		return models.FAILED, err
 	}
@@ -96,7 +101,7 @@ func (r *NuboTradeRepository) UpdateTradePost(param models.TradeModifyParam) err
 	postQuery := fmt.Sprintf(`UPDATE %s%s SET category_uid = ?, title = ?, content = ?, modified = ?, status = ?
 		WHERE uid = ? AND board_uid = ? LIMIT 1`, configs.Env.Prefix, models.TABLE_POST)
 	postResult, err := tx.Exec(postQuery, param.CategoryUid, param.Title, param.Content, time.Now().UnixMilli(),
-		utils.GetContentStatus(param.IsNotice, param.IsSecret), param.PostUid, param.BoardUid)
+		writeStatus(param.EditorWriteParam), param.PostUid, param.BoardUid)
 	if err != nil {
 		return err
 	}
@@ -114,6 +119,9 @@ func (r *NuboTradeRepository) UpdateTradePost(param models.TradeModifyParam) err
 	if affected, _ := tradeResult.RowsAffected(); affected != 1 {
 		return fmt.Errorf("trade metadata not found")
 	}
+	if err := insertSyncChange(tx, param.PostUid, models.SYNC_MODIFIED); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type TrendingRepository interface {
+	GetLikedPosts(postUids []uint, userUid uint) (map[uint]bool, error)
+	GetTrendingCandidates(since uint64, maxLevel uint) ([]models.TrendingCandidate, error)
+}
+
+type NuboTrendingRepository struct {
+	db *sql.DB
+}
+
+// sql.DB 포인터 주입받기
+func NewNuboTrendingRepository(db *sql.DB) *NuboTrendingRepository {
+	return &NuboTrendingRepository{db: db}
+}
+
+// 게시글들 중 회원이 좋아요를 누른 글 번호 목록 가져오기
+func (r *NuboTrendingRepository) GetLikedPosts(postUids []uint, userUid uint) (map[uint]bool, error) {
+	items := make(map[uint]bool)
+	if len(postUids) == 0 || userUid < 1 {
+		return items, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(postUids)), ",")
+	args := make([]any, 0, len(postUids)+2)
+	for _, uid := range postUids {
+		args = append(args, uid)
+	}
+	query := fmt.Sprintf("SELECT post_uid FROM %s%s WHERE post_uid IN (%s) AND user_uid = ? AND liked = ?",
+		configs.Env.Prefix, models.TABLE_POST_LIKE, placeholders)
+	rows, err := r.db.Query(query, append(args, userUid, 1)...)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postUid uint
+		if err := rows.Scan(&postUid); err != nil {
+			return items, err
+		}
+		items[postUid] = true
+	}
+	return items, rows.Err()
+}
+
+// 인기글 점수 계산 대상이 되는 최근 공개 게시글들과 좋아요, 댓글 수, 게시판, 카테고리, 커버, 작성자 정보 가져오기 (역할 허용 규칙으로 목록, 보기를 제한한 게시판 제외)
+func (r *NuboTrendingRepository) GetTrendingCandidates(since uint64, maxLevel uint) ([]models.TrendingCandidate, error) {
+	query := fmt.Sprintf(`SELECT p.uid, p.board_uid, p.user_uid, p.category_uid, p.title, p.content, p.submitted, p.modified, p.hit, p.status,
+												(SELECT COUNT(*) FROM %s%s l WHERE l.post_uid = p.uid AND l.liked = 1) AS likes,
+												(SELECT COUNT(*) FROM %s%s c WHERE c.post_uid = p.uid AND c.status != ?) AS comments,
+												b.id, b.type, b.use_category, COALESCE(bc.uid, 0), COALESCE(bc.name, ''),
+												COALESCE((SELECT t.path FROM %s%s t WHERE t.post_uid = p.uid LIMIT 1), ''),
+												COALESCE(u.name, ''), COALESCE(u.profile, ''), COALESCE(u.signature, '')
+												FROM %s%s p JOIN %s%s b ON p.board_uid = b.uid
+												LEFT JOIN %s%s bc ON bc.uid = p.category_uid
+												LEFT JOIN %s%s u ON u.uid = p.user_uid
+												WHERE p.status = ? AND p.submitted >= ? AND b.level_list <= ? AND b.level_view <= ?
+												AND NOT EXISTS (SELECT 1 FROM %s%s a WHERE a.board_uid = b.uid AND a.allow = 1 AND a.action IN (?, ?))`,
+		configs.Env.Prefix, models.TABLE_POST_LIKE,
+		configs.Env.Prefix, models.TABLE_COMMENT,
+		configs.Env.Prefix, models.TABLE_FILE_THUMB,
+		configs.Env.Prefix, models.TABLE_POST,
+		configs.Env.Prefix, models.TABLE_BOARD,
+		configs.Env.Prefix, models.TABLE_BOARD_CAT,
+		configs.Env.Prefix, models.TABLE_USER,
+		configs.Env.Prefix, models.TABLE_BOARD_ACL)
+
+	rows, err := r.db.Query(query, models.CONTENT_REMOVED, models.CONTENT_NORMAL, since, maxLevel, maxLevel,
+		models.BOARD_ACTION_LIST, models.BOARD_ACTION_VIEW)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.TrendingCandidate, 0)
+	for rows.Next() {
+		item := models.TrendingCandidate{}
+		var useCategory uint8
+		err := rows.Scan(&item.Uid, &item.BoardUid, &item.UserUid, &item.CategoryUid, &item.Title, &item.Content,
+			&item.Submitted, &item.Modified, &item.Hit, &item.Status, &item.Like, &item.Comment,
+			&item.Board.Id, &item.Board.Type, &useCategory, &item.Category.Uid, &item.Category.Name, &item.Cover,
+			&item.Writer.Name, &item.Writer.Profile, &item.Writer.Signature)
+		if err != nil {
+			return nil, err
+		}
+		item.Board.UseCategory = useCategory > 0
+		item.Writer.UserUid = item.UserUid
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
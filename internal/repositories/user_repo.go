@@ -32,7 +32,7 @@ type UserRepository interface {
 	IsUserReported(userUid uint) bool
 	LoadUserPermission(userUid uint) models.UserPermissionResult
 	UpdateUserInfoString(userUid uint, name string, signature string) error
-	UpdateUserProfile(userUid uint, imagePath string) error
+	UpdateUserProfile(userUid uint, imagePath string, fallbackPath string) error
 	UpdateUserPermission(userUid uint, perm models.UserPermissionResult) error
 	UpdateUserBlocked(userUid uint, isBlocked bool) error
 	UpdateReportResponse(userUid uint, response string) error
@@ -152,6 +152,8 @@ func (r *NuboUserRepository) DeleteAccount(userUid uint) ([]string, error) {
 		{fmt.Sprintf("DELETE FROM %sexif WHERE post_uid IN (%s)", configs.Env.Prefix, postIDs), []any{userUid}},
 		{fmt.Sprintf("DELETE FROM %simage_description WHERE post_uid IN (%s)", configs.Env.Prefix, postIDs), []any{userUid}},
 		{fmt.Sprintf("DELETE FROM %sfile_thumbnail WHERE post_uid IN (%s)", configs.Env.Prefix, postIDs), []any{userUid}},
+		{fmt.Sprintf("DELETE FROM %simage_variant WHERE file_uid IN (SELECT uid FROM %sfile WHERE post_uid IN (%s))", configs.Env.Prefix, configs.Env.Prefix, postIDs), []any{userUid}},
+		{fmt.Sprintf("DELETE FROM %simage_variant WHERE source IN (SELECT path FROM %simage WHERE user_uid = ? UNION SELECT profile FROM %suser WHERE uid = ?)", configs.Env.Prefix, configs.Env.Prefix, configs.Env.Prefix), []any{userUid, userUid}},
 		{fmt.Sprintf("DELETE FROM %sfile WHERE post_uid IN (%s)", configs.Env.Prefix, postIDs), []any{userUid}},
 		{fmt.Sprintf("DELETE FROM %spost_hashtag WHERE post_uid IN (%s)", configs.Env.Prefix, postIDs), []any{userUid}},
 		{fmt.Sprintf("DELETE FROM %spoint_history WHERE user_uid = ?", configs.Env.Prefix), []any{userUid}},
@@ -215,11 +217,11 @@ func collectAccountFilePaths(tx *sql.Tx, userUid uint) ([]string, error) {
 
 // 다른 사용자를 신고하기
 func (r *NuboUserRepository) InsertReportUser(param models.UserReportParam) error {
-	query := fmt.Sprintf("SELECT uid FROM %s%s WHERE to_uid = ? AND from_uid = ? LIMIT 1",
+	query := fmt.Sprintf("SELECT uid FROM %s%s WHERE to_uid = ? AND from_uid = ? AND target_type = ? LIMIT 1",
 		configs.Env.Prefix, models.TABLE_REPORT)
 
 	var uid uint
-	err := r.db.QueryRow(query, param.TargetUserUid, param.ActionUserUid).Scan(&uid)
+	err := r.db.QueryRow(query, param.TargetUserUid, param.ActionUserUid, models.REPORT_TARGET_USER).Scan(&uid)
 	if err == sql.ErrNoRows {
 		query = fmt.Sprintf(`INSERT INTO %s%s (to_uid, from_uid, request, response, timestamp, solved) 
 												VALUES (?, ?, ?, ? ,? ,?)`, configs.Env.Prefix, models.TABLE_REPORT)
@@ -333,8 +335,9 @@ func (r *NuboUserRepository) IsPermissionAdded(userUid uint) bool {
 // 현재 사용자가 대상 사용자를 이미 신고했는지 확인
 func (r *NuboUserRepository) IsReported(actionUserUid uint, targetUserUid uint) bool {
 	var exists bool
-	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s%s WHERE to_uid = ? AND from_uid = ?)", configs.Env.Prefix, models.TABLE_REPORT)
-	err := r.db.QueryRow(query, targetUserUid, actionUserUid).Scan(&exists)
+	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s%s WHERE to_uid = ? AND from_uid = ? AND target_type = ?)",
+		configs.Env.Prefix, models.TABLE_REPORT)
+	err := r.db.QueryRow(query, targetUserUid, actionUserUid, models.REPORT_TARGET_USER).Scan(&exists)
 	if err != nil {
 		return false
 	}
@@ -344,8 +347,9 @@ func (r *NuboUserRepository) IsReported(actionUserUid uint, targetUserUid uint)
 // 사용자가 받은 신고가 있는지 확인
 func (r *NuboUserRepository) IsUserReported(userUid uint) bool {
 	var exists bool
-	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s%s WHERE to_uid = ?)", configs.Env.Prefix, models.TABLE_REPORT)
-	err := r.db.QueryRow(query, userUid).Scan(&exists)
+	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s%s WHERE to_uid = ? AND target_type = ?)",
+		configs.Env.Prefix, models.TABLE_REPORT)
+	err := r.db.QueryRow(query, userUid, models.REPORT_TARGET_USER).Scan(&exists)
 	if err != nil {
 		return false
 	}
@@ -427,11 +431,11 @@ func (r *NuboUserRepository) UpdateUserInfoString(userUid uint, name string, sig
 	return err
 }
 
-// 사용자 프로필 이미지 변경하기
-func (r *NuboUserRepository) UpdateUserProfile(userUid uint, imagePath string) error {
-	query := fmt.Sprintf("UPDATE %s%s SET profile = ? WHERE uid = ? LIMIT 1",
+// 사용자 프로필 이미지(AVIF이면 대체 형식 경로도 함께) 변경하기
+func (r *NuboUserRepository) UpdateUserProfile(userUid uint, imagePath string, fallbackPath string) error {
+	query := fmt.Sprintf("UPDATE %s%s SET profile = ?, profile_fallback = ? WHERE uid = ? LIMIT 1",
 		configs.Env.Prefix, models.TABLE_USER)
-	_, err := r.db.Exec(query, imagePath, userUid)
+	_, err := r.db.Exec(query, imagePath, fallbackPath, userUid)
 	return err
 }
 
@@ -453,8 +457,8 @@ func (r *NuboUserRepository) UpdateUserBlocked(userUid uint, isBlocked bool) err
 
 // 신고받은 사용자에게 조치 결과 업데이트 해주기
 func (r *NuboUserRepository) UpdateReportResponse(userUid uint, response string) error {
-	query := fmt.Sprintf("UPDATE %s%s SET response = ?, solved = ? WHERE to_uid = ? LIMIT 1",
+	query := fmt.Sprintf("UPDATE %s%s SET response = ?, solved = ? WHERE to_uid = ? AND target_type = ? LIMIT 1",
 		configs.Env.Prefix, models.TABLE_REPORT)
-	_, err := r.db.Exec(query, response, 1, userUid)
+	_, err := r.db.Exec(query, response, 1, userUid, models.REPORT_TARGET_USER)
 	return err
 }
@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/sirini/goapi/internal/handlers"
+)
+
+// ActivityPub(WebFinger, 게시판 액터, 받은편지함 등) 라우터들 등록
+func RegisterActivityPubRouters(api fiber.Router, h *handlers.Handler) {
+	api.Get("/.well-known/webfinger", h.ActivityPub.WebFingerHandler)
+
+	ap := api.Group("/ap/board/:id")
+	ap.Get("/", h.ActivityPub.ActorHandler)
+	ap.Get("/outbox", h.ActivityPub.OutboxHandler)
+	ap.Get("/followers", h.ActivityPub.FollowersHandler)
+	ap.Get("/post/:uid", h.ActivityPub.NoteHandler)
+	ap.Post("/inbox", h.ActivityPub.InboxHandler)
+}
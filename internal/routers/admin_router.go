@@ -12,10 +12,14 @@ func RegisterAdminRouters(api fiber.Router, h *handlers.Handler) {
 	admin := api.Group("/admin", middlewares.AdminMiddleware(h.CanAuthenticate))
 	board := admin.Group("/board")
 	dashboard := admin.Group("/dashboard")
+	filter := admin.Group("/filter")
 	group := admin.Group("/group")
+	importer := admin.Group("/import")
 	latest := admin.Group("/latest")
 	mail := admin.Group("/mail")
 	report := admin.Group("/report")
+	role := admin.Group("/role")
+	tag := admin.Group("/tag")
 	user := admin.Group("/user")
 	skin := admin.Group("/skin")
 	system := admin.Group("/system")
@@ -35,10 +39,19 @@ func RegisterAdminRouters(api fiber.Router, h *handlers.Handler) {
 	board.Post("/modify", h.Admin.ModifyBoardHandler)
 	board.Delete("/remove", h.Admin.RemoveBoardHandler)
 	board.Get("/candidates", h.Admin.GetAdminCandidatesHandler)
+	board.Get("/export", h.Admin.BoardExportHandler)
+	board.Post("/import", h.Admin.BoardImportHandler)
 
 	dashboard.Get("/usage", h.Admin.DashboardUploadUsageHandler)
 	dashboard.Get("/item", h.Admin.DashboardItemLoadHandler)
 	dashboard.Get("/statistic", h.Admin.DashboardStatisticLoadHandler)
+	dashboard.Get("/sync", h.Admin.DashboardSyncStatusHandler)
+	dashboard.Post("/sync", h.Admin.DashboardSyncRunHandler)
+
+	filter.Get("/list", h.Admin.ContentFilterListHandler)
+	filter.Post("/save", h.Admin.ContentFilterSaveHandler)
+	filter.Delete("/remove", h.Admin.ContentFilterRemoveHandler)
+	filter.Get("/logs", h.Admin.ContentFilterLogListHandler)
 
 	group.Get("/load", h.Admin.GroupGeneralLoadHandler)
 	group.Get("/candidates", h.Admin.GetAdminCandidatesHandler)
@@ -50,6 +63,10 @@ func RegisterAdminRouters(api fiber.Router, h *handlers.Handler) {
 	group.Post("/update", h.Admin.ChangeGroupIdHandler)
 	group.Post("/admin", h.Admin.ChangeGroupAdminHandler)
 
+	importer.Post("/wordpress", h.Admin.WordPressImportHandler)
+	importer.Get("/jobs", h.Admin.ImportJobListHandler)
+	importer.Get("/job/:uid", h.Admin.ImportJobLoadHandler)
+
 	latest.Delete("/comment", h.Admin.RemoveCommentHandler)
 	latest.Delete("/post", h.Admin.RemovePostHandler)
 	latest.Get("/comments", h.Admin.LatestCommentSearchHandler)
@@ -57,6 +74,22 @@ func RegisterAdminRouters(api fiber.Router, h *handlers.Handler) {
 
 	report.Get("/reports", h.Admin.ReportListSearchHandler)
 	report.Put("/resolve", h.Admin.ReportResolveHandler)
+	report.Get("/content", h.Admin.ContentReportListHandler)
+	report.Put("/content/resolve", h.Admin.ContentReportResolveHandler)
+
+	role.Get("/list", h.Admin.RoleListHandler)
+	role.Post("/save", h.Admin.RoleSaveHandler)
+	role.Delete("/remove", h.Admin.RoleRemoveHandler)
+	role.Get("/members", h.Admin.RoleMemberListHandler)
+	role.Post("/member", h.Admin.RoleMemberAddHandler)
+	role.Delete("/member", h.Admin.RoleMemberRemoveHandler)
+
+	tag.Get("/list", h.Admin.HashtagListHandler)
+	tag.Put("/rename", h.Admin.HashtagRenameHandler)
+	tag.Put("/merge", h.Admin.HashtagMergeHandler)
+	tag.Put("/ban", h.Admin.HashtagBanHandler)
+	tag.Put("/recount", h.Admin.HashtagRecountHandler)
+	tag.Delete("/orphans", h.Admin.HashtagCleanupHandler)
 
 	user.Post("/create", h.Admin.CreateUserHandler)
 	user.Get("/list", h.Admin.UserListLoadHandler)
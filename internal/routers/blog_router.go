@@ -5,8 +5,14 @@ import (
 	"github.com/sirini/goapi/internal/handlers"
 )
 
-// 게시판과 상호작용에 필요한 라우터들 등록
+// 블로그 RSS와 피드(RSS, Atom, JSON Feed) 라우터들 등록
 func RegisterBlogRouters(api fiber.Router, h *handlers.Handler) {
 	rss := api.Group("/rss")
 	rss.Get("/:id", h.Blog.BlogRssLoadHandler)
+
+	feed := api.Group("/feed")
+	feed.Get("/site/:format", h.Feed.SiteFeedHandler)
+	feed.Get("/board/:id/:format", h.Feed.BoardFeedHandler)
+	feed.Get("/tag/:name/:format", h.Feed.TagFeedHandler)
+	feed.Get("/user/:uid/:format", h.Feed.UserFeedHandler)
 }
@@ -8,17 +8,32 @@ import (
 
 // 게시판과 상호작용에 필요한 라우터들 등록
 func RegisterBoardRouters(api fiber.Router, h *handlers.Handler) {
+	api.Get("/oembed", h.Board.OEmbedHandler)
 	board := api.Group("/board")
 	board.Get("/list", h.Board.BoardListHandler)
+	board.Get("/meta", h.Board.BoardMetaHandler)
 	board.Get("/view", h.Board.BoardViewHandler)
+	board.Post("/view/beacon", h.Board.ReadBeaconHandler)
 	board.Get("/tag/recent", h.Board.BoardRecentTagListHandler)
+	board.Get("/trending", h.Board.TrendingPostsHandler)
 	board.Get("/user/latest", h.Board.LatestUserContentHandler)
 	board.Get("/transfer", h.Board.TransferHandler)
+	board.Get("/share/view", h.Board.SharedViewHandler)
+	board.Get("/share/download", h.Board.SharedDownloadHandler)
 
 	protected := board.Group("/", middlewares.JWTMiddleware(h.CanAuthenticate))
+	protected.Get("/approval/list", h.Board.ApprovalListHandler)
+	protected.Put("/approval/approve", h.Board.ApprovePostHandler)
+	protected.Put("/approval/reject", h.Board.RejectPostHandler)
 	protected.Get("/download", h.Board.DownloadHandler)
+	protected.Get("/view/stats", h.Board.PostStatsHandler)
 	protected.Get("/move/list", h.Board.ListForMoveHandler)
 	protected.Patch("/like", h.Board.LikePostHandler)
 	protected.Post("/move/apply", h.Board.MovePostHandler)
+	protected.Post("/report", h.Board.ReportContentHandler)
+	protected.Post("/share/create", h.Board.ShareCreateHandler)
+	protected.Get("/share/list", h.Board.ShareListHandler)
+	protected.Get("/share/logs", h.Board.ShareLogListHandler)
+	protected.Put("/share/revoke", h.Board.ShareRevokeHandler)
 	protected.Delete("/remove/post", h.Board.RemovePostHandler)
 }
@@ -10,6 +10,7 @@ import (
 func RegisterCommentRouters(api fiber.Router, h *handlers.Handler) {
 	comment := api.Group("/comment")
 	comment.Get("/list", h.Comment.CommentListHandler)
+	comment.Get("/history", h.Comment.CommentHistoryHandler)
 
 	protected := comment.Group("/", middlewares.JWTMiddleware(h.CanAuthenticate))
 	protected.Patch("/like", h.Comment.LikeCommentHandler)
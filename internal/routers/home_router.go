@@ -8,11 +8,14 @@ import (
 
 // 홈화면 및 SEO용 라우터들 등록
 func RegisterHomeRouters(api fiber.Router, h *handlers.Handler) {
+	api.Get("/sitemap.xml", h.Home.SitemapIndexHandler)
+	api.Get("/sitemap/:id/:page.xml", h.Home.SitemapBoardHandler)
 	home := api.Group("/home")
 	home.Get("/nubo", h.Home.ShowVersionHandler)
 	home.Get("/visit", h.Home.CountingVisitorHandler)
 	home.Get("/latest", h.Home.LoadAllPostsHandler)
 	home.Get("/latest/:id", h.Home.LoadPostsByIdHandler)
+	home.Get("/trending", h.Home.LoadTrendingPostsHandler)
 	home.Get("/sidebar/links", h.Home.LoadSidebarLinkHandler)
 
 	// 알림용 라우터들
@@ -10,6 +10,7 @@ func RegisterRouters(api fiber.Router, h *handlers.Handler) {
 	api.Get("/health", h.Status.HealthHandler)
 	api.Get("/ready", h.Status.ReadyHandler)
 	api.Get("/version", h.Status.VersionHandler)
+	RegisterActivityPubRouters(api, h)
 	RegisterAdminRouters(api, h)
 	RegisterAuthRouters(api, h)
 	RegisterBoardRouters(api, h)
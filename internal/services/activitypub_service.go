@@ -0,0 +1,750 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type ActivityPubService interface {
+	DeliverPending() (uint, error)
+	GetActor(boardId string) (models.ApActor, error)
+	GetFollowers(boardId string) (models.ApCollection, error)
+	GetNote(boardId string, postUid uint) (models.ApNote, error)
+	GetOutbox(boardId string) (models.ApCollection, error)
+	GetWebFinger(resource string) (models.ApWebFinger, error)
+	HandleInbox(boardId string, request models.ApInboxRequest) error
+	PublishPost(boardUid uint, postUid uint, activityType string)
+	RunDeliveryJob(ctx context.Context)
+}
+
+type NuboActivityPubService struct {
+	repos  *repositories.Repository
+	feed   *NuboFeedService
+	filter *contentFilter
+	spam   *spamGuard
+	client *http.Client
+	now    func() time.Time
+}
+
+// 리포지토리 묶음과 피드 서비스 주입받기 (게시글을 Note로 바꿀 때 피드 항목을 재사용)
+func NewNuboActivityPubService(repos *repositories.Repository, feed *NuboFeedService) *NuboActivityPubService {
+	return &NuboActivityPubService{
+		repos:  repos,
+		feed:   feed,
+		client: utils.NewPublicHTTPClient(10 * time.Second),
+		now:    time.Now,
+	}
+}
+
+// 연합 기능이 꺼져 있거나 공개 블로그가 아닌 게시판을 요청했을 때의 오류
+var ErrActivityPubNotFound = errors.New("activitypub actor not found")
+
+// HTTP 서명이 없거나 맞지 않을 때의 오류
+var ErrActivityPubUnauthorized = errors.New("activitypub signature is not valid")
+
+// 받은편지함으로 해석할 수 없는 활동이 들어왔을 때의 오류
+var ErrActivityPubInvalid = errors.New("activitypub activity is not valid")
+
+const (
+	apActorMaxBytes    = 1 << 20
+	apActorRefresh     = 24 * time.Hour
+	apDeliveryMaxDelay = 24 * time.Hour
+)
+
+// 게시판 액터 주소 만들기
+func apActorURL(boardId string) string {
+	return fmt.Sprintf("%s/%s/ap/board/%s", siteURL(), configs.Env.GoapiBase, url.PathEscape(boardId))
+}
+
+// 게시글 Note 주소 만들기
+func apNoteURL(boardId string, postUid uint) string {
+	return fmt.Sprintf("%s/post/%d", apActorURL(boardId), postUid)
+}
+
+// 게시판 웹 페이지 주소 만들기
+func apBoardLink(board models.BoardConfig) string {
+	return fmt.Sprintf("%s/%s/%s", siteURL(), board.Type.String(), url.PathEscape(board.Id))
+}
+
+// 밀리초 타임스탬프를 ActivityPub 날짜 형식으로 바꾸기
+func apDate(timestamp int64) string {
+	return time.UnixMilli(timestamp).UTC().Format(time.RFC3339)
+}
+
+// 연합할 수 있는 게시판(누구나 포인트 없이 보는 블로그)인지 확인하고 설정 가져오기
+func (s *NuboActivityPubService) federatedBoard(boardUid uint) (models.BoardConfig, error) {
+	if !configs.GetActivityPubConfig().Enabled || boardUid < 1 {
+		return models.BoardConfig{}, ErrActivityPubNotFound
+	}
+	board := s.repos.Board.GetBoardConfig(boardUid)
+	if board.Uid < 1 || board.Type != models.BOARD_BLOG || board.Level.List > 0 || board.Level.View > 0 ||
+		s.repos.Home.HasRestrictedAcl(boardUid) {
+		return models.BoardConfig{}, ErrActivityPubNotFound
+	}
+	if _, point := s.repos.BoardView.GetNeededLevelPoint(boardUid, 0, models.BOARD_ACTION_VIEW); point < 0 {
+		return models.BoardConfig{}, ErrActivityPubNotFound
+	}
+	return board, nil
+}
+
+// 게시판 아이디로 연합할 수 있는 게시판 설정 가져오기
+func (s *NuboActivityPubService) federatedBoardById(boardId string) (models.BoardConfig, error) {
+	return s.federatedBoard(s.repos.Board.GetBoardUidById(boardId))
+}
+
+// 게시판 액터의 서명 키 가져오기 (없으면 처음 한 번 만들기)
+func (s *NuboActivityPubService) boardKey(boardUid uint) (models.ApKey, error) {
+	key, err := s.repos.ActivityPub.GetKey(boardUid)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return key, err
+	}
+	public, private, err := utils.GenerateActivityPubKey()
+	if err != nil {
+		return key, err
+	}
+	if err := s.repos.ActivityPub.SaveKey(boardUid, models.ApKey{PublicKey: public, PrivateKey: private}); err != nil {
+		return key, err
+	}
+	return s.repos.ActivityPub.GetKey(boardUid)
+}
+
+// 게시판 액터 문서 가져오기
+func (s *NuboActivityPubService) GetActor(boardId string) (models.ApActor, error) {
+	board, err := s.federatedBoardById(boardId)
+	if err != nil {
+		return models.ApActor{}, err
+	}
+	key, err := s.boardKey(board.Uid)
+	if err != nil {
+		return models.ApActor{}, err
+	}
+	actorURL := apActorURL(board.Id)
+	return models.ApActor{
+		Context:           []string{models.AP_CONTEXT, models.AP_SECURITY},
+		Id:                actorURL,
+		Type:              "Person",
+		PreferredUsername: board.Id,
+		Name:              utils.Unescape(board.Name),
+		Summary:           utils.Unescape(board.Info),
+		Url:               apBoardLink(board),
+		Inbox:             actorURL + "/inbox",
+		Outbox:            actorURL + "/outbox",
+		Followers:         actorURL + "/followers",
+		Discoverable:      true,
+		PublicKey: models.ApActorKeyInfo{
+			Id:           actorURL + "#main-key",
+			Owner:        actorURL,
+			PublicKeyPem: key.PublicKey,
+		},
+	}, nil
+}
+
+// 게시판 팔로워 컬렉션 가져오기 (팔로워 목록은 공개하지 않고 수만 알려줌)
+func (s *NuboActivityPubService) GetFollowers(boardId string) (models.ApCollection, error) {
+	board, err := s.federatedBoardById(boardId)
+	if err != nil {
+		return models.ApCollection{}, err
+	}
+	return models.ApCollection{
+		Context:    models.AP_CONTEXT,
+		Id:         apActorURL(board.Id) + "/followers",
+		Type:       "OrderedCollection",
+		TotalItems: s.repos.ActivityPub.CountFollowers(board.Uid),
+	}, nil
+}
+
+// 게시글 하나를 Note로 가져오기
+func (s *NuboActivityPubService) GetNote(boardId string, postUid uint) (models.ApNote, error) {
+	board, err := s.federatedBoardById(boardId)
+	if err != nil {
+		return models.ApNote{}, err
+	}
+	note, err := s.postNote(board, postUid)
+	if err != nil {
+		return models.ApNote{}, err
+	}
+	note.Context = models.AP_CONTEXT
+	return note, nil
+}
+
+// 최근 게시글들을 Create 활동으로 담은 outbox 가져오기
+func (s *NuboActivityPubService) GetOutbox(boardId string) (models.ApCollection, error) {
+	board, err := s.federatedBoardById(boardId)
+	if err != nil {
+		return models.ApCollection{}, err
+	}
+	items, err := s.feed.feedItems(models.FeedPostParam{BoardUid: board.Uid, Limit: models.AP_OUTBOX_ITEMS})
+	if err != nil {
+		return models.ApCollection{}, err
+	}
+	activities := make([]any, 0, len(items))
+	for _, item := range items {
+		note := s.note(board, item)
+		activities = append(activities, models.ApActivity{
+			Id:        note.Id + "#create",
+			Type:      models.AP_CREATE,
+			Actor:     note.AttributedTo,
+			Published: note.Published,
+			To:        note.To,
+			Cc:        note.Cc,
+			Object:    note,
+		})
+	}
+	return models.ApCollection{
+		Context:      models.AP_CONTEXT,
+		Id:           apActorURL(board.Id) + "/outbox",
+		Type:         "OrderedCollection",
+		TotalItems:   uint(len(activities)),
+		OrderedItems: activities,
+	}, nil
+}
+
+// acct:게시판아이디@도메인 형식의 WebFinger 요청에 답하기
+func (s *NuboActivityPubService) GetWebFinger(resource string) (models.ApWebFinger, error) {
+	boardId := ""
+	if account, ok := strings.CutPrefix(resource, "acct:"); ok {
+		name, host, found := strings.Cut(strings.TrimPrefix(account, "@"), "@")
+		site, err := url.Parse(siteURL())
+		if !found || err != nil || !strings.EqualFold(host, site.Host) {
+			return models.ApWebFinger{}, ErrActivityPubNotFound
+		}
+		boardId = name
+	} else if id, ok := strings.CutPrefix(resource, apActorURL("")); ok {
+		boardId, _ = url.PathUnescape(id)
+	}
+	board, err := s.federatedBoardById(boardId)
+	if err != nil {
+		return models.ApWebFinger{}, err
+	}
+	site, _ := url.Parse(siteURL())
+	actorURL := apActorURL(board.Id)
+	return models.ApWebFinger{
+		Subject: fmt.Sprintf("acct:%s@%s", board.Id, site.Host),
+		Aliases: []string{actorURL, apBoardLink(board)},
+		Links: []models.ApWebFingerLink{
+			{Rel: "self", Type: models.AP_CONTENT_TYPE, Href: actorURL},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: apBoardLink(board)},
+		},
+	}, nil
+}
+
+// 게시글을 찾아 Note로 바꾸기 (비공개, 승인 대기 글은 찾지 않음)
+func (s *NuboActivityPubService) postNote(board models.BoardConfig, postUid uint) (models.ApNote, error) {
+	items, err := s.feed.feedItems(models.FeedPostParam{BoardUid: board.Uid, PostUid: postUid, Limit: 1})
+	if err != nil {
+		return models.ApNote{}, err
+	}
+	if len(items) < 1 {
+		return models.ApNote{}, fmt.Errorf("post not found: %w", ErrActivityPubNotFound)
+	}
+	return s.note(board, items[0]), nil
+}
+
+// 피드 항목을 Note로 바꾸기 (요약 모드 게시판은 요약과 원문 링크만 보냄)
+func (s *NuboActivityPubService) note(board models.BoardConfig, item models.FeedItem) models.ApNote {
+	actorURL := apActorURL(board.Id)
+	content := item.Content
+	if len(content) < 1 {
+		content = fmt.Sprintf("<p>%s</p>", html.EscapeString(item.Summary))
+	}
+	note := models.ApNote{
+		Id:           apNoteURL(board.Id, item.PostUid),
+		Type:         "Note",
+		AttributedTo: actorURL,
+		Name:         item.Title,
+		Content: fmt.Sprintf(`<p><strong>%s</strong></p>%s<p><a href="%s">%s</a></p>`,
+			html.EscapeString(item.Title), content, html.EscapeString(item.Link), html.EscapeString(item.Link)),
+		Url:       item.Link,
+		Published: apDate(item.Published),
+		To:        []string{models.AP_PUBLIC},
+		Cc:        []string{actorURL + "/followers"},
+	}
+	if item.Updated > item.Published {
+		note.Updated = apDate(item.Updated)
+	}
+	for _, tag := range item.Tags {
+		note.Tag = append(note.Tag, models.ApTag{
+			Type: "Hashtag",
+			Href: fmt.Sprintf("%s/%s/feed/tag/%s/rss", siteURL(), configs.Env.GoapiBase, url.PathEscape(tag)),
+			Name: "#" + tag,
+		})
+	}
+	for _, enclosure := range item.Enclosures {
+		note.Attachment = append(note.Attachment, models.ApAttachment{
+			Type:      "Image",
+			MediaType: enclosure.Type,
+			Url:       enclosure.URL,
+		})
+	}
+	return note
+}
+
+// 게시글 작성, 수정, 삭제를 팔로워들에게 보내도록 발송 대기열에 넣기 (수정으로 공개 글이 아니게 되면 삭제로 보냄)
+func (s *NuboActivityPubService) PublishPost(boardUid uint, postUid uint, activityType string) {
+	if s == nil {
+		return
+	}
+	board, err := s.federatedBoard(boardUid)
+	if err != nil {
+		return
+	}
+	inboxes, err := s.repos.ActivityPub.GetFollowerInboxes(board.Uid)
+	if err != nil || len(inboxes) < 1 {
+		return
+	}
+
+	actorURL := apActorURL(board.Id)
+	noteId := apNoteURL(board.Id, postUid)
+	var object any
+	switch activityType {
+	case models.AP_CREATE, models.AP_UPDATE:
+		note, err := s.postNote(board, postUid)
+		switch {
+		case err == nil:
+			object = note
+		case activityType == models.AP_UPDATE && errors.Is(err, ErrActivityPubNotFound):
+			activityType = models.AP_DELETE
+			object = map[string]string{"id": noteId, "type": "Tombstone"}
+		default:
+			return
+		}
+	case models.AP_DELETE:
+		object = map[string]string{"id": noteId, "type": "Tombstone"}
+	default:
+		return
+	}
+	activity := models.ApActivity{
+		Context:   models.AP_CONTEXT,
+		Id:        fmt.Sprintf("%s#%s-%d", noteId, strings.ToLower(activityType), s.now().UnixMilli()),
+		Type:      activityType,
+		Actor:     actorURL,
+		Object:    object,
+		Published: apDate(s.now().UnixMilli()),
+		To:        []string{models.AP_PUBLIC},
+		Cc:        []string{actorURL + "/followers"},
+	}
+
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return
+	}
+	if err := s.repos.ActivityPub.InsertDeliveries(board.Uid, inboxes, string(body)); err != nil {
+		log.Printf("activitypub: failed to queue %s of post %d: %v", activityType, postUid, err)
+	}
+}
+
+// 발송 대기열에서 보낼 차례가 된 활동들을 보내고 성공한 수 반환하기
+func (s *NuboActivityPubService) DeliverPending() (uint, error) {
+	config := configs.GetActivityPubConfig()
+	if !config.Enabled {
+		return 0, nil
+	}
+	now := s.now()
+	items, err := s.repos.ActivityPub.GetDueDeliveries(now.UnixMilli(), models.AP_DELIVERY_BULK)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := uint(0)
+	for _, item := range items {
+		permanent, err := s.deliver(item)
+		attempts := item.Attempts + 1
+		if err == nil {
+			delivered++
+			if err := s.repos.ActivityPub.UpdateDelivery(item.Uid, models.AP_DELIVERY_DONE, attempts, now.UnixMilli(), ""); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+
+		status := models.AP_DELIVERY_PENDING
+		if permanent || attempts >= uint(config.MaxAttempts) {
+			status = models.AP_DELIVERY_FAILED
+		}
+		next := now.Add(apRetryDelay(attempts)).UnixMilli()
+		if err := s.repos.ActivityPub.UpdateDelivery(item.Uid, status, attempts, next, utils.CutString(err.Error(), 250)); err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+// 실패 횟수에 따라 다음 발송까지 기다릴 시간 계산하기 (1분부터 두 배씩, 최대 하루)
+func apRetryDelay(attempts uint) time.Duration {
+	delay := time.Minute
+	for i := uint(1); i < attempts && delay < apDeliveryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, apDeliveryMaxDelay)
+}
+
+// 서명한 활동을 받은편지함으로 보내기 (다시 보내도 소용없는 실패면 permanent가 true)
+func (s *NuboActivityPubService) deliver(item models.ApDelivery) (bool, error) {
+	board := s.repos.Board.GetBoardConfig(item.BoardUid)
+	if board.Uid < 1 {
+		return true, fmt.Errorf("board not found")
+	}
+	key, err := s.boardKey(board.Uid)
+	if err != nil {
+		return false, err
+	}
+	body := []byte(item.Activity)
+	req, err := http.NewRequest(http.MethodPost, item.Inbox, bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+	req.Header.Set("Content-Type", models.AP_CONTENT_TYPE)
+	req.Header.Set("Accept", models.AP_ACCEPT_HEADER)
+	req.Header.Set("User-Agent", fmt.Sprintf("NUBO/%s (+%s)", configs.Env.Version, siteURL()))
+	if err := utils.SignActivityPubRequest(req, apActorURL(board.Id)+"#main-key", key.PrivateKey, body, s.now()); err != nil {
+		return true, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	permanent := resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests
+	return permanent, fmt.Errorf("inbox responded with %d", resp.StatusCode)
+}
+
+// 주기적으로 발송 대기열 처리하기 (ctx 종료 시 중단, 연합 기능이 꺼져 있으면 바로 종료)
+func (s *NuboActivityPubService) RunDeliveryJob(ctx context.Context) {
+	if !configs.GetActivityPubConfig().Enabled {
+		return
+	}
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.DeliverPending(); err != nil {
+			log.Printf("activitypub: failed to deliver activities: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 받은편지함으로 들어온 활동의 서명을 확인하고 처리하기
+func (s *NuboActivityPubService) HandleInbox(boardId string, request models.ApInboxRequest) error {
+	board, err := s.federatedBoardById(boardId)
+	if err != nil {
+		return err
+	}
+	incoming := models.ApIncoming{}
+	if err := json.Unmarshal(request.Body, &incoming); err != nil || len(incoming.Type) < 1 || len(incoming.Actor) < 1 {
+		return ErrActivityPubInvalid
+	}
+	keyId, err := utils.ActivityPubSignatureKeyId(request.Headers)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, ErrActivityPubUnauthorized)
+	}
+	actor, err := s.verifiedActor(board, keyId, request)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, ErrActivityPubUnauthorized)
+	}
+	if actor.ActorId != incoming.Actor {
+		return fmt.Errorf("activity actor is not the signer: %w", ErrActivityPubUnauthorized)
+	}
+
+	switch incoming.Type {
+	case models.AP_FOLLOW:
+		return s.acceptFollow(board, actor, incoming, request.Body)
+	case models.AP_UNDO:
+		return s.undo(board, actor, incoming)
+	case models.AP_LIKE, models.AP_ANNOUNCE:
+		postUid := s.postUidFromObject(board, apObjectId(incoming.Object))
+		if postUid < 1 {
+			return nil
+		}
+		_, err := s.repos.ActivityPub.InsertActivity(models.ApReceivedActivity{
+			BoardUid:   board.Uid,
+			PostUid:    postUid,
+			ActorUid:   actor.Uid,
+			Type:       incoming.Type,
+			ActivityId: incoming.Id,
+		})
+		return err
+	case models.AP_CREATE:
+		return s.saveReply(board, actor, incoming)
+	}
+	return nil
+}
+
+// 서명한 원격 액터를 찾고 서명 확인하기 (실패하면 키가 바뀌었을 수 있으니 한 번 더 받아와서 확인)
+func (s *NuboActivityPubService) verifiedActor(board models.BoardConfig, keyId string, request models.ApInboxRequest) (models.ApRemoteActor, error) {
+	actor, err := s.remoteActor(board, keyId, false)
+	if err != nil {
+		return actor, err
+	}
+	verify := func(actor models.ApRemoteActor) error {
+		return utils.VerifyActivityPubRequest(request.Method, request.Path, request.Headers, request.Body, actor.PublicKey, s.now())
+	}
+	if err := verify(actor); err == nil {
+		return actor, nil
+	}
+	if actor, err = s.remoteActor(board, keyId, true); err != nil {
+		return actor, err
+	}
+	return actor, verify(actor)
+}
+
+// 키 주소로 원격 액터 정보 가져오기 (보관한 지 오래됐거나 refresh면 다시 받아오기)
+func (s *NuboActivityPubService) remoteActor(board models.BoardConfig, keyId string, refresh bool) (models.ApRemoteActor, error) {
+	documentURL, _, _ := strings.Cut(keyId, "#")
+	if !refresh {
+		actor, err := s.repos.ActivityPub.FindRemoteActor(documentURL)
+		if err == nil && actor.KeyId == keyId && s.now().Sub(time.UnixMilli(actor.Fetched)) < apActorRefresh {
+			return actor, nil
+		}
+	}
+
+	doc, err := s.fetchActorDocument(board, documentURL)
+	if err != nil {
+		return models.ApRemoteActor{}, err
+	}
+	if len(doc.Owner) > 0 && len(doc.PublicKeyPem) > 0 {
+		if doc.Id != keyId {
+			return models.ApRemoteActor{}, fmt.Errorf("key document does not match the key id")
+		}
+		owner, err := s.fetchActorDocument(board, doc.Owner)
+		if err != nil {
+			return models.ApRemoteActor{}, err
+		}
+		doc = owner
+	}
+	if doc.PublicKey.Id != keyId || doc.PublicKey.Owner != doc.Id || len(doc.Inbox) < 1 {
+		return models.ApRemoteActor{}, fmt.Errorf("actor does not own the key")
+	}
+
+	actor := models.ApRemoteActor{
+		ActorId:     doc.Id,
+		KeyId:       keyId,
+		PublicKey:   doc.PublicKey.PublicKeyPem,
+		Inbox:       doc.Inbox,
+		SharedInbox: doc.Endpoints.SharedInbox,
+		Name:        doc.PreferredUsername,
+		Url:         doc.Url,
+		Fetched:     s.now().UnixMilli(),
+	}
+	if len(actor.Name) < 1 {
+		actor.Name = doc.Name
+	}
+	if len(actor.Url) < 1 {
+		actor.Url = doc.Id
+	}
+	uid, err := s.repos.ActivityPub.SaveRemoteActor(actor)
+	if err != nil {
+		return models.ApRemoteActor{}, err
+	}
+	actor.Uid = uid
+	return actor, nil
+}
+
+// 원격 액터(또는 키) 문서를 서명한 GET 요청으로 받아오기 (문서 주소와 id의 호스트가 같아야 함)
+func (s *NuboActivityPubService) fetchActorDocument(board models.BoardConfig, documentURL string) (models.ApActorDocument, error) {
+	doc := models.ApActorDocument{}
+	target, err := url.Parse(documentURL)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || len(target.Host) < 1 {
+		return doc, fmt.Errorf("invalid actor address")
+	}
+	key, err := s.boardKey(board.Uid)
+	if err != nil {
+		return doc, err
+	}
+	req, err := http.NewRequest(http.MethodGet, documentURL, nil)
+	if err != nil {
+		return doc, err
+	}
+	req.Header.Set("Accept", models.AP_ACCEPT_HEADER)
+	req.Header.Set("User-Agent", fmt.Sprintf("NUBO/%s (+%s)", configs.Env.Version, siteURL()))
+	if err := utils.SignActivityPubRequest(req, apActorURL(board.Id)+"#main-key", key.PrivateKey, nil, s.now()); err != nil {
+		return doc, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return doc, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return doc, fmt.Errorf("actor responded with %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, apActorMaxBytes)).Decode(&doc); err != nil {
+		return doc, err
+	}
+	id, err := url.Parse(doc.Id)
+	if err != nil || !strings.EqualFold(id.Host, target.Host) {
+		return doc, fmt.Errorf("actor id is on another host")
+	}
+	return doc, nil
+}
+
+// Follow를 받아 팔로워로 등록하고 Accept를 보내도록 대기열에 넣기
+func (s *NuboActivityPubService) acceptFollow(board models.BoardConfig, actor models.ApRemoteActor, incoming models.ApIncoming, raw []byte) error {
+	actorURL := apActorURL(board.Id)
+	if apObjectId(incoming.Object) != actorURL {
+		return ErrActivityPubInvalid
+	}
+	if err := s.repos.ActivityPub.AddFollower(board.Uid, actor.Uid, incoming.Id); err != nil {
+		return err
+	}
+	body, err := json.Marshal(models.ApActivity{
+		Context: models.AP_CONTEXT,
+		Id:      fmt.Sprintf("%s#accept-%d-%d", actorURL, actor.Uid, s.now().UnixMilli()),
+		Type:    models.AP_ACCEPT,
+		Actor:   actorURL,
+		Object:  json.RawMessage(raw),
+	})
+	if err != nil {
+		return err
+	}
+	return s.repos.ActivityPub.InsertDeliveries(board.Uid, []string{actor.Inbox}, string(body))
+}
+
+// Undo를 받아 팔로우나 좋아요, 공유 기록 취소하기
+func (s *NuboActivityPubService) undo(board models.BoardConfig, actor models.ApRemoteActor, incoming models.ApIncoming) error {
+	inner := models.ApIncoming{}
+	if err := json.Unmarshal(incoming.Object, &inner); err != nil {
+		return s.repos.ActivityPub.RemoveActivity(actor.Uid, apObjectId(incoming.Object))
+	}
+	if len(inner.Actor) > 0 && inner.Actor != actor.ActorId {
+		return fmt.Errorf("undo of another actor's activity: %w", ErrActivityPubUnauthorized)
+	}
+	if inner.Type == models.AP_FOLLOW {
+		return s.repos.ActivityPub.RemoveFollower(board.Uid, actor.Uid)
+	}
+	return s.repos.ActivityPub.RemoveActivity(actor.Uid, inner.Id)
+}
+
+// 게시글에 단 원격 답글을 댓글로 저장하기 (GOAPI_ACTIVITYPUB_REMOTE_REPLIES가 켜져 있고 비회원도 댓글을 쓸 수 있는 게시판만,
+// 필터나 스팸 검사에서 보류되면 숨긴 채 신고 검토 목록에 올림)
+func (s *NuboActivityPubService) saveReply(board models.BoardConfig, actor models.ApRemoteActor, incoming models.ApIncoming) error {
+	if !configs.GetActivityPubConfig().RemoteReplies {
+		return nil
+	}
+	note := models.ApIncomingNote{}
+	if err := json.Unmarshal(incoming.Object, &note); err != nil || note.Type != "Note" || len(note.Id) < 1 {
+		return nil
+	}
+	if note.AttributedTo != actor.ActorId {
+		return fmt.Errorf("reply is not written by the signer: %w", ErrActivityPubUnauthorized)
+	}
+	postUid := s.postUidFromObject(board, note.InReplyTo)
+	if postUid < 1 || s.repos.ActivityPub.HasActivity(note.Id) {
+		return nil
+	}
+	if level, point := s.repos.BoardView.GetNeededLevelPoint(board.Uid, 0, models.BOARD_ACTION_COMMENT); level > 0 || point < 0 {
+		return nil
+	}
+	content, err := s.filter.Filter(models.FILTER_TARGET_COMMENT, 0, utils.Sanitize(note.Content))
+	held := errors.Is(err, ErrContentHeld)
+	if err != nil && !held {
+		return nil
+	}
+	reason := models.REPORT_REASON_FILTER
+	switch err := s.spam.CheckRemote(content); {
+	case errors.Is(err, ErrSpamHeld):
+		if !held {
+			held, reason = true, models.REPORT_REASON_SPAM
+		}
+	case err != nil:
+		return nil
+	}
+
+	host := ""
+	if id, err := url.Parse(actor.ActorId); err == nil {
+		host = id.Host
+	}
+	now := s.now().UnixMilli()
+	commentUid, err := s.repos.Import.InsertImportedComment(models.ImportCommentParam{
+		BoardUid: board.Uid,
+		PostUid:  postUid,
+		UserUid:  board.Admin.Board,
+		Content: fmt.Sprintf(`<p><strong><a href="%s">@%s@%s</a></strong></p>%s`,
+			html.EscapeString(actor.Url), html.EscapeString(actor.Name), html.EscapeString(host), content),
+		Submitted: now,
+		Modified:  now,
+		Status:    models.CONTENT_NORMAL,
+	})
+	if err != nil {
+		return err
+	}
+	if held {
+		if err := s.repos.Report.HoldContent(models.REPORT_TARGET_COMMENT, commentUid, board.Admin.Board, reason); err != nil {
+			return err
+		}
+	}
+	_, err = s.repos.ActivityPub.InsertActivity(models.ApReceivedActivity{
+		BoardUid:   board.Uid,
+		PostUid:    postUid,
+		CommentUid: commentUid,
+		ActorUid:   actor.Uid,
+		Type:       models.AP_CREATE,
+		ActivityId: note.Id,
+	})
+	return err
+}
+
+// Note 주소나 웹 페이지 주소에서 이 게시판 게시글의 고유번호 찾기 (공개된 글이 아니면 0)
+func (s *NuboActivityPubService) postUidFromObject(board models.BoardConfig, objectId string) uint {
+	for _, prefix := range []string{apActorURL(board.Id) + "/post/", apBoardLink(board) + "/"} {
+		rest, ok := strings.CutPrefix(objectId, prefix)
+		if !ok {
+			continue
+		}
+		uid, err := strconv.ParseUint(rest, 10, 32)
+		if err != nil || uid < 1 {
+			return 0
+		}
+		posts, err := s.repos.Feed.GetFeedPosts(models.FeedPostParam{BoardUid: board.Uid, PostUid: uint(uid), Limit: 1})
+		if err != nil || len(posts) < 1 {
+			return 0
+		}
+		return uint(uid)
+	}
+	return 0
+}
+
+// 활동의 object가 주소 문자열이든 객체든 id 꺼내기
+func apObjectId(raw json.RawMessage) string {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id
+	}
+	object := struct {
+		Id string `json:"id"`
+	}{}
+	_ = json.Unmarshal(raw, &object)
+	return object.Id
+}
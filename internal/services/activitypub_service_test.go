@@ -0,0 +1,354 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type activityPubRepoStub struct {
+	repositories.ActivityPubRepository
+	key        models.ApKey
+	actors     map[string]models.ApRemoteActor
+	followers  map[uint]bool
+	deliveries []models.ApDelivery
+	statuses   map[uint]models.ApDeliveryStatus
+	nexts      map[uint]int64
+	activities []models.ApReceivedActivity
+}
+
+func newActivityPubRepoStub() *activityPubRepoStub {
+	return &activityPubRepoStub{
+		actors:    make(map[string]models.ApRemoteActor),
+		followers: make(map[uint]bool),
+		statuses:  make(map[uint]models.ApDeliveryStatus),
+		nexts:     make(map[uint]int64),
+	}
+}
+
+func (r *activityPubRepoStub) GetKey(uint) (models.ApKey, error) {
+	if len(r.key.PrivateKey) < 1 {
+		return r.key, sql.ErrNoRows
+	}
+	return r.key, nil
+}
+func (r *activityPubRepoStub) SaveKey(_ uint, key models.ApKey) error {
+	r.key = key
+	return nil
+}
+func (r *activityPubRepoStub) FindRemoteActor(actorId string) (models.ApRemoteActor, error) {
+	actor, ok := r.actors[actorId]
+	if !ok {
+		return actor, sql.ErrNoRows
+	}
+	return actor, nil
+}
+func (r *activityPubRepoStub) SaveRemoteActor(actor models.ApRemoteActor) (uint, error) {
+	actor.Uid = 9
+	r.actors[actor.ActorId] = actor
+	return actor.Uid, nil
+}
+func (r *activityPubRepoStub) AddFollower(_ uint, actorUid uint, _ string) error {
+	r.followers[actorUid] = true
+	return nil
+}
+func (r *activityPubRepoStub) RemoveFollower(_ uint, actorUid uint) error {
+	delete(r.followers, actorUid)
+	return nil
+}
+func (r *activityPubRepoStub) InsertDeliveries(boardUid uint, inboxes []string, activity string) error {
+	for _, inbox := range inboxes {
+		uid := uint(len(r.deliveries) + 1)
+		r.deliveries = append(r.deliveries, models.ApDelivery{Uid: uid, BoardUid: boardUid, Inbox: inbox, Activity: activity})
+		r.statuses[uid] = models.AP_DELIVERY_PENDING
+	}
+	return nil
+}
+func (r *activityPubRepoStub) GetDueDeliveries(now int64, _ uint) ([]models.ApDelivery, error) {
+	items := make([]models.ApDelivery, 0)
+	for _, item := range r.deliveries {
+		if r.statuses[item.Uid] == models.AP_DELIVERY_PENDING && r.nexts[item.Uid] <= now {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+func (r *activityPubRepoStub) UpdateDelivery(uid uint, status models.ApDeliveryStatus, attempts uint, nextAttempt int64, _ string) error {
+	r.statuses[uid] = status
+	r.nexts[uid] = nextAttempt
+	r.deliveries[uid-1].Attempts = attempts
+	return nil
+}
+func (r *activityPubRepoStub) InsertActivity(activity models.ApReceivedActivity) (bool, error) {
+	r.activities = append(r.activities, activity)
+	return true, nil
+}
+
+// 원격 액터 문서와 받은편지함을 흉내 내는 가짜 서버
+type fakeInbox struct {
+	mu         sync.Mutex
+	server     *httptest.Server
+	publicKey  string
+	privateKey string
+	status     int
+	received   []*http.Request
+	bodies     [][]byte
+}
+
+func newFakeInbox(t *testing.T) *fakeInbox {
+	public, private, err := utils.GenerateActivityPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inbox := &fakeInbox{publicKey: public, privateKey: private, status: http.StatusAccepted}
+	inbox.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inbox.mu.Lock()
+		defer inbox.mu.Unlock()
+		switch r.URL.Path {
+		case "/users/alice":
+			actorId := inbox.server.URL + "/users/alice"
+			w.Header().Set("Content-Type", models.AP_CONTENT_TYPE)
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": actorId, "type": "Person", "preferredUsername": "alice", "url": inbox.server.URL + "/@alice",
+				"inbox": actorId + "/inbox", "endpoints": map[string]string{"sharedInbox": inbox.server.URL + "/inbox"},
+				"publicKey": map[string]string{"id": actorId + "#main-key", "owner": actorId, "publicKeyPem": public},
+			})
+		case "/users/alice/inbox":
+			body, _ := io.ReadAll(r.Body)
+			inbox.received = append(inbox.received, r)
+			inbox.bodies = append(inbox.bodies, body)
+			w.WriteHeader(inbox.status)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(inbox.server.Close)
+	return inbox
+}
+
+func (f *fakeInbox) actorId() string { return f.server.URL + "/users/alice" }
+
+// 가짜 원격 액터 키로 서명한 받은편지함 요청 만들기
+func (f *fakeInbox) signedRequest(t *testing.T, activity map[string]any, now time.Time) models.ApInboxRequest {
+	body, _ := json.Marshal(activity)
+	req := httptest.NewRequest(http.MethodPost, "https://example.com/goapi/ap/board/diary/inbox", bytes.NewReader(body))
+	if err := utils.SignActivityPubRequest(req, f.actorId()+"#main-key", f.privateKey, body, now); err != nil {
+		t.Fatal(err)
+	}
+	headers := map[string]string{"host": req.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = req.Header.Get(name)
+	}
+	return models.ApInboxRequest{Method: req.Method, Path: req.URL.RequestURI(), Headers: headers, Body: body}
+}
+
+func newActivityPubTestService(t *testing.T, repo *activityPubRepoStub, inbox *fakeInbox, now time.Time) *NuboActivityPubService {
+	previous := configs.Env
+	configs.Env.Domain = "https://example.com"
+	configs.Env.GoapiBase = "goapi"
+	configs.Env.ActivityPub = configs.ActivityPubEnv{Enabled: "true", RemoteReplies: "false", MaxAttempts: "3"}
+	t.Cleanup(func() { configs.Env = previous })
+
+	repos := &repositories.Repository{ActivityPub: repo, Board: sitemapBoardRepo{}, BoardView: replyLevelRepo{}, Home: &sitemapHomeRepo{}, Feed: &feedRepoStub{}}
+	service := NewNuboActivityPubService(repos, NewNuboFeedService(repos))
+	service.client = inbox.server.Client()
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestActivityPubFollowQueuesSignedAccept(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	repo := newActivityPubRepoStub()
+	inbox := newFakeInbox(t)
+	service := newActivityPubTestService(t, repo, inbox, now)
+
+	actor, err := service.GetActor("diary")
+	if err != nil || actor.Inbox != "https://example.com/goapi/ap/board/diary/inbox" || len(actor.PublicKey.PublicKeyPem) < 1 {
+		t.Fatalf("unexpected actor %+v %v", actor, err)
+	}
+	if _, err := service.GetActor("free"); !errors.Is(err, ErrActivityPubNotFound) {
+		t.Fatalf("only blog boards should be federated, got %v", err)
+	}
+	finger, err := service.GetWebFinger("acct:diary@example.com")
+	if err != nil || finger.Links[0].Href != actor.Id {
+		t.Fatalf("unexpected webfinger %+v %v", finger, err)
+	}
+
+	follow := map[string]any{"id": inbox.actorId() + "#follow-1", "type": "Follow", "actor": inbox.actorId(), "object": actor.Id}
+	request := inbox.signedRequest(t, follow, now)
+	request.Body = bytes.Replace(request.Body, []byte("follow-1"), []byte("follow-2"), 1)
+	if err := service.HandleInbox("diary", request); !errors.Is(err, ErrActivityPubUnauthorized) {
+		t.Fatalf("a tampered body should be rejected, got %v", err)
+	}
+	if err := service.HandleInbox("diary", inbox.signedRequest(t, follow, now)); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.followers[9] || len(repo.deliveries) != 1 || repo.deliveries[0].Inbox != inbox.actorId()+"/inbox" ||
+		!strings.Contains(repo.deliveries[0].Activity, `"type":"Accept"`) {
+		t.Fatalf("follow should add a follower and queue an Accept, got %+v %+v", repo.followers, repo.deliveries)
+	}
+
+	delivered, err := service.DeliverPending()
+	if err != nil || delivered != 1 || repo.statuses[1] != models.AP_DELIVERY_DONE || len(inbox.received) != 1 {
+		t.Fatalf("accept should be delivered once, got %d %v %+v", delivered, err, repo.statuses)
+	}
+	received := inbox.received[0]
+	headers := map[string]string{"host": received.Host}
+	for name := range received.Header {
+		headers[strings.ToLower(name)] = received.Header.Get(name)
+	}
+	if err := utils.VerifyActivityPubRequest(received.Method, received.URL.RequestURI(), headers, inbox.bodies[0], actor.PublicKey.PublicKeyPem, now); err != nil {
+		t.Fatalf("delivery should be signed with the board key: %v", err)
+	}
+	if received.Header.Get("Content-Type") != models.AP_CONTENT_TYPE {
+		t.Fatalf("unexpected content type %q", received.Header.Get("Content-Type"))
+	}
+
+	like := map[string]any{"id": inbox.actorId() + "#like-1", "type": "Like", "actor": inbox.actorId(),
+		"object": "https://example.com/goapi/ap/board/diary/post/7"}
+	if err := service.HandleInbox("diary", inbox.signedRequest(t, like, now)); err != nil ||
+		len(repo.activities) != 1 || repo.activities[0].PostUid != 7 || repo.activities[0].ActorUid != 9 {
+		t.Fatalf("like should be recorded against the post, got %v %+v", err, repo.activities)
+	}
+
+	undo := map[string]any{"id": inbox.actorId() + "#undo-1", "type": "Undo", "actor": inbox.actorId(), "object": follow}
+	if err := service.HandleInbox("diary", inbox.signedRequest(t, undo, now)); err != nil || repo.followers[9] {
+		t.Fatalf("undo should remove the follower, got %v %+v", err, repo.followers)
+	}
+}
+
+func TestActivityPubDeliveryRetriesThenFails(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	repo := newActivityPubRepoStub()
+	inbox := newFakeInbox(t)
+	service := newActivityPubTestService(t, repo, inbox, now)
+	service.now = func() time.Time { return now }
+	inbox.status = http.StatusServiceUnavailable
+	repo.InsertDeliveries(2, []string{inbox.actorId() + "/inbox"}, `{"type":"Create"}`)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if _, err := service.DeliverPending(); err != nil {
+			t.Fatal(err)
+		}
+		if attempt < 3 && (repo.statuses[1] != models.AP_DELIVERY_PENDING ||
+			repo.nexts[1] != now.Add(apRetryDelay(uint(attempt))).UnixMilli()) {
+			t.Fatalf("attempt %d should be retried later, got %+v %+v", attempt, repo.statuses, repo.nexts)
+		}
+		now = now.Add(time.Hour)
+	}
+	if repo.statuses[1] != models.AP_DELIVERY_FAILED || repo.deliveries[0].Attempts != 3 || len(inbox.received) != 3 {
+		t.Fatalf("delivery should fail after max attempts, got %+v %+v", repo.statuses, repo.deliveries)
+	}
+
+	inbox.status = http.StatusGone
+	repo.InsertDeliveries(2, []string{inbox.actorId() + "/inbox"}, `{"type":"Create"}`)
+	if _, err := service.DeliverPending(); err != nil || repo.statuses[2] != models.AP_DELIVERY_FAILED {
+		t.Fatalf("a gone inbox should not be retried, got %v %+v", err, repo.statuses)
+	}
+	if apRetryDelay(30) != 24*time.Hour || apRetryDelay(2) != 2*time.Minute {
+		t.Fatalf("unexpected retry delays %v %v", apRetryDelay(30), apRetryDelay(2))
+	}
+}
+
+func (r *activityPubRepoStub) GetFollowerInboxes(uint) ([]string, error) {
+	return []string{"https://remote.example/inbox"}, nil
+}
+func (r *activityPubRepoStub) HasActivity(string) bool { return false }
+
+type hiddenFeedRepo struct{ feedRepoStub }
+
+func (*hiddenFeedRepo) GetFeedPosts(models.FeedPostParam) ([]models.FeedPost, error) {
+	return []models.FeedPost{}, nil
+}
+
+type replyLevelRepo struct {
+	repositories.BoardViewRepository
+	level int
+	point int
+}
+
+func (r replyLevelRepo) GetNeededLevelPoint(uint, uint, models.BoardAction) (int, int) {
+	return r.level, r.point
+}
+
+func TestActivityPubSkipsPaidViewBoards(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	repo := newActivityPubRepoStub()
+	service := newActivityPubTestService(t, repo, newFakeInbox(t), now)
+	service.repos.BoardView = replyLevelRepo{point: -10}
+
+	if _, err := service.federatedBoard(2); !errors.Is(err, ErrActivityPubNotFound) {
+		t.Fatalf("boards that charge points to read should not be federated, got %v", err)
+	}
+	service.PublishPost(2, 7, models.AP_CREATE)
+	if len(repo.deliveries) != 0 {
+		t.Fatalf("posts on paid-view boards should not be delivered, got %+v", repo.deliveries)
+	}
+}
+
+func TestActivityPubUpdateOfHiddenPostSendsDelete(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	repo := newActivityPubRepoStub()
+	service := newActivityPubTestService(t, repo, newFakeInbox(t), now)
+	service.repos.Feed = &hiddenFeedRepo{}
+
+	service.PublishPost(2, 7, models.AP_UPDATE)
+	if len(repo.deliveries) != 1 || !strings.Contains(repo.deliveries[0].Activity, `"type":"Delete"`) ||
+		!strings.Contains(repo.deliveries[0].Activity, `"type":"Tombstone"`) {
+		t.Fatalf("an edit that hides the post should be sent as a Delete, got %+v", repo.deliveries)
+	}
+	service.PublishPost(2, 7, models.AP_CREATE)
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("a post that is not public should not be created remotely, got %+v", repo.deliveries)
+	}
+}
+
+func TestActivityPubRemoteReplyIsCheckedLikeGuestComment(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	repo := newActivityPubRepoStub()
+	service := newActivityPubTestService(t, repo, newFakeInbox(t), now)
+	configs.Env.ActivityPub.RemoteReplies = "true"
+	imports := &importRepoStub{maps: make(map[string]uint), posts: make(map[uint]models.ImportPostParam), contents: make(map[uint]string)}
+	reports := &heldReportRepo{held: map[uint]models.ReportReason{}}
+	service.repos.Import, service.repos.Report = imports, reports
+	service.repos.BoardView = replyLevelRepo{level: 1}
+	service.filter = newContentFilter(&filterRuleRepo{rules: []models.ContentFilterRule{
+		{Uid: 1, Pattern: `casino\.example`, Match: models.FILTER_MATCH_REGEX, Action: models.FILTER_ACTION_HOLD, Enabled: true},
+		{Uid: 2, Pattern: "forbidden", Match: models.FILTER_MATCH_EXACT, Action: models.FILTER_ACTION_BLOCK, Enabled: true},
+	}})
+
+	board := sitemapBoards["diary"]
+	actor := models.ApRemoteActor{Uid: 9, ActorId: "https://remote.example/users/alice", Name: "alice"}
+	reply := func(id string, content string) models.ApIncoming {
+		note, _ := json.Marshal(models.ApIncomingNote{Id: id, Type: "Note", AttributedTo: actor.ActorId,
+			InReplyTo: "https://example.com/goapi/ap/board/diary/post/7", Content: content})
+		return models.ApIncoming{Type: models.AP_CREATE, Actor: actor.ActorId, Object: note}
+	}
+
+	if err := service.saveReply(board, actor, reply("r1", "hello")); err != nil || len(imports.comments) != 0 {
+		t.Fatalf("replies should be dropped when guests cannot comment, got %v %+v", err, imports.comments)
+	}
+	service.repos.BoardView = replyLevelRepo{}
+	if err := service.saveReply(board, actor, reply("r2", "this is forbidden")); err != nil || len(imports.comments) != 0 {
+		t.Fatalf("blocked replies should be dropped, got %v %+v", err, imports.comments)
+	}
+	if err := service.saveReply(board, actor, reply("r3", "visit casino.example")); err != nil || len(imports.comments) != 1 {
+		t.Fatalf("held replies should be saved for review, got %v %+v", err, imports.comments)
+	}
+	if reason, ok := reports.held[imports.nextUid]; !ok || reason != models.REPORT_REASON_FILTER {
+		t.Fatalf("held reply should be queued for review, got %+v", reports.held)
+	}
+}
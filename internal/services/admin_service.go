@@ -18,13 +18,20 @@ import (
 
 type AdminService interface {
 	AddBoardCategory(boardUid uint, name string) uint
+	AddRoleMember(param models.AdminRoleMemberParam) error
+	BanHashtag(param models.AdminHashtagBanParam) error
 	ChangeGroupAdmin(groupUid uint, newAdminUid uint) error
 	ChangeGroupId(param models.AdminGroupChangeParam) error
+	CleanupHashtags() (models.AdminHashtagCleanupResult, error)
 	CreateNewBoard(param models.AdminBoardCreateParam) (uint, error)
 	CreateNewGroup(newGroupId string) (models.AdminGroupConfig, error)
 	CreateNewUser(param models.AdminUserCreateParam) (uint, error)
 	GetBoardAdminCandidates(name string, bunch uint) ([]models.BoardWriter, error)
 	GetBoardList(groupUid uint) ([]models.AdminGroupBoardItem, error)
+	GetContentFilterLogs(param models.ContentFilterLogParam) (models.ContentFilterLogResult, error)
+	GetContentReports(param models.AdminContentReportParam) (models.AdminContentReportResult, error)
+	GetBoardAcl(boardUid uint) ([]models.BoardAclRule, error)
+	GetContentFilters() ([]models.ContentFilterRule, error)
 	GetDashboardUploadUsage(path string) uint64
 	GetDashboardItems(bunch uint) models.AdminDashboardItem
 	GetDashboardStatistics(bunch uint) models.AdminDashboardStatisticResult
@@ -32,10 +39,13 @@ type AdminService interface {
 	GetExistGroupIds(groupId string, bunch uint) []models.Pair
 	GetGroupConfig(groupId string) models.AdminGroupConfig
 	GetGroupList() []models.AdminGroupConfig
+	GetHashtagList(param models.AdminHashtagSearchParam) (models.AdminHashtagListResult, error)
 	GetMailStatus() models.MailStatus
 	GetMailDeliveries(param models.MailDeliveryListParam) (models.MailDeliveryListResult, error)
 	GetMailCampaign(uid uint) (models.MailCampaign, error)
 	GetMailCampaigns(limit uint) (models.MailCampaignListResult, error)
+	GetRoleMembers(roleUid uint) ([]models.BoardWriter, error)
+	GetRoles() ([]models.UserRole, error)
 	PreviewMailCampaign(param models.MailCampaignPreviewParam) (models.MailCampaignPreviewResult, error)
 	SaveMailCampaign(param models.MailCampaignSaveParam) (models.MailCampaign, error)
 	SendMailCampaignTest(uid uint) error
@@ -47,16 +57,25 @@ type AdminService interface {
 	GetUserList(param models.AdminUserParam) models.AdminUserListResult
 	GetUserInfo(userUid uint) models.AdminUserInfo
 	GetSkinSettings() models.SkinSettings
+	SaveContentFilter(param models.ContentFilterSaveParam) (uint, error)
+	SaveRole(param models.AdminRoleSaveParam) (uint, error)
 	SetSkinSetting(param models.AdminSkinSettingParam) error
+	ResolveContentReport(actionUserUid uint, param models.AdminContentReportResolveParam) error
 	ResolveReport(param models.AdminReportResolveParam) error
+	MergeHashtags(param models.AdminHashtagMergeParam) error
 	ModifyExistBoard(param models.AdminBoardModifyParam) error
 	ModifyUserAccount(param models.AdminUserModifyParam) error
+	RecountHashtags() (int64, error)
 	RemoveBoardCategory(boardUid uint, catUid uint) error
 	RemoveBoard(boardUid uint) error
 	RemoveComment(commentUid uint) error
+	RemoveContentFilter(filterUid uint) error
 	RemoveGroup(groupUid uint) error
 	RemovePost(postUid uint) error
+	RemoveRole(roleUid uint) error
+	RemoveRoleMember(param models.AdminRoleMemberParam) error
 	RemoveUser(userUid uint) error
+	RenameHashtag(param models.AdminHashtagRenameParam) error
 }
 
 type NuboAdminService struct {
@@ -64,6 +83,9 @@ type NuboAdminService struct {
 	userService *NuboUserService
 	mailer      utils.Mailer
 	marketing   utils.MarketingMailer
+	filter      *contentFilter
+	federation  *NuboActivityPubService
+	related     *relatedPostCache
 }
 
 // 리포지토리 묶음 주입받기
@@ -315,6 +337,67 @@ func (s *NuboAdminService) ResolveReport(param models.AdminReportResolveParam) e
 	return s.repos.Admin.ResolveReport(param)
 }
 
+// 대상별로 묶인 게시글/댓글 신고 목록 가져오기
+func (s *NuboAdminService) GetContentReports(param models.AdminContentReportParam) (models.AdminContentReportResult, error) {
+	return s.repos.Report.GetContentReports(param)
+}
+
+// 게시글/댓글 신고 처리하기 (기각, 삭제, 작성자 제재)
+func (s *NuboAdminService) ResolveContentReport(actionUserUid uint, param models.AdminContentReportResolveParam) error {
+	if param.Action > models.REPORT_RESOLVE_SANCTION {
+		return fmt.Errorf("invalid report action")
+	}
+	info, err := s.repos.Report.GetReportTarget(param.Target, param.TargetUid)
+	if err != nil {
+		return fmt.Errorf("reported content does not exist")
+	}
+	param.Response = utils.Escape(strings.TrimSpace(param.Response))
+
+	switch param.Action {
+	case models.REPORT_RESOLVE_DISMISS:
+		status, hidden := s.repos.Report.GetHiddenStatus(param.Target, param.TargetUid)
+		if info.Status == models.CONTENT_SECRET && hidden {
+			if err := s.repos.Report.UpdateContentStatus(param.Target, param.TargetUid, status); err != nil {
+				return err
+			}
+			if param.Target == models.REPORT_TARGET_POST {
+				s.federation.PublishPost(info.BoardUid, param.TargetUid, models.AP_CREATE)
+			}
+		}
+	default:
+		if info.Status != models.CONTENT_REMOVED {
+			if err := s.removeReportedContent(param.Target, param.TargetUid); err != nil {
+				return err
+			}
+		}
+		if param.Action == models.REPORT_RESOLVE_SANCTION {
+			if err := s.sanctionWriter(actionUserUid, info.WriterUid, param.Response); err != nil {
+				return err
+			}
+		}
+	}
+	return s.repos.Report.ResolveContentReports(param.Target, param.TargetUid, param.Response)
+}
+
+// 신고된 게시글/댓글 삭제하기
+func (s *NuboAdminService) removeReportedContent(target models.ReportTarget, targetUid uint) error {
+	if target == models.REPORT_TARGET_POST {
+		return s.RemovePost(targetUid)
+	}
+	return s.RemoveComment(targetUid)
+}
+
+// 신고된 내용의 작성자에게서 글, 댓글, 쪽지 작성 권한 회수하기
+func (s *NuboAdminService) sanctionWriter(actionUserUid uint, writerUid uint, response string) error {
+	perm := s.repos.User.LoadUserPermission(writerUid)
+	return s.userService.ChangeUserPermission(actionUserUid, models.UserPermissionManageParam{
+		UserPermissionResult: models.UserPermissionResult{SendReport: perm.SendReport},
+		Login:                !s.repos.User.IsBlocked(writerUid),
+		UserUid:              writerUid,
+		Response:             response,
+	})
+}
+
 // 카테고리 추가하기
 func (s *NuboAdminService) AddBoardCategory(boardUid uint, name string) uint {
 	if isDup := s.repos.Admin.IsAddedCategory(boardUid, name); isDup {
@@ -353,6 +436,11 @@ func (s *NuboAdminService) CreateNewBoard(param models.AdminBoardCreateParam) (u
 	if isAdded := s.repos.Admin.IsAdded(models.TABLE_BOARD, param.Id); isAdded {
 		return 0, fmt.Errorf("already added")
 	}
+	depth, err := normalizeCommentDepth(param.CommentDepth)
+	if err != nil {
+		return 0, err
+	}
+	param.CommentDepth = depth
 
 	boardUid := s.repos.Admin.CreateBoard(param)
 	if boardUid < 1 {
@@ -554,6 +642,11 @@ func (s *NuboAdminService) ModifyExistBoard(param models.AdminBoardModifyParam)
 	if param.Type == models.BOARD_TRADE && (param.SkinKey == "" || param.SkinKey == "nubo-basic-board") {
 		param.SkinKey = "nubo-basic-trade"
 	}
+	depth, err := normalizeCommentDepth(param.CommentDepth)
+	if err != nil {
+		return err
+	}
+	param.CommentDepth = depth
 	boardUid := s.repos.Board.GetBoardUidById(param.Id)
 	oldCats := s.repos.Admin.GetOldCategories(boardUid)
 
@@ -572,8 +665,12 @@ func (s *NuboAdminService) ModifyExistBoard(param models.AdminBoardModifyParam)
 		s.AddBoardCategory(boardUid, newCat)
 	}
 
-	err := s.repos.Admin.ModifyBoard(param)
-	return err
+	if param.Acl != nil {
+		if err := s.replaceBoardAcl(boardUid, param.Acl); err != nil {
+			return err
+		}
+	}
+	return s.repos.Admin.ModifyBoard(param)
 }
 
 // 사용자 정보 수정하기
@@ -649,10 +746,133 @@ func (s *NuboAdminService) RemoveGroup(groupUid uint) error {
 
 // 게시글 삭제하기
 func (s *NuboAdminService) RemovePost(postUid uint) error {
-	return s.repos.BoardView.RemovePost(postUid)
+	boardUid := s.repos.Admin.FindBoardUidByPostUid(postUid)
+	if err := s.repos.BoardView.RemovePost(postUid); err != nil {
+		return err
+	}
+	s.repos.BoardView.RemovePostTags(postUid)
+	s.related.Invalidate(postUid)
+	s.federation.PublishPost(boardUid, postUid, models.AP_DELETE)
+	return nil
 }
 
 // 사용자 삭제하기
 func (s *NuboAdminService) RemoveUser(userUid uint) error {
 	return s.repos.Admin.RemoveUser(userUid)
 }
+
+// 해시태그 목록 가져오기
+func (s *NuboAdminService) GetHashtagList(param models.AdminHashtagSearchParam) (models.AdminHashtagListResult, error) {
+	return s.repos.Hashtag.GetHashtagList(param)
+}
+
+// 해시태그 이름 변경하기 (같은 이름의 태그가 이미 있으면 병합을 사용해야 함)
+func (s *NuboAdminService) RenameHashtag(param models.AdminHashtagRenameParam) error {
+	name := utils.Purify(param.Name)
+	if param.HashtagUid < 1 || len([]rune(name)) < 2 || len([]rune(name)) > 30 {
+		return fmt.Errorf("invalid hashtag name")
+	}
+	if existUid := s.repos.BoardEdit.FindTagUidByName(name); existUid > 0 && existUid != param.HashtagUid {
+		return fmt.Errorf("hashtag already exists, merge them instead")
+	}
+	return s.repos.Hashtag.RenameHashtag(param.HashtagUid, name)
+}
+
+// 중복 해시태그들을 이미 있는 대상 해시태그 하나로 병합하기
+func (s *NuboAdminService) MergeHashtags(param models.AdminHashtagMergeParam) error {
+	if param.TargetUid < 1 || len(s.repos.BoardView.GetTagName(param.TargetUid)) < 1 {
+		return fmt.Errorf("invalid target hashtag")
+	}
+	seen := map[uint]bool{param.TargetUid: true}
+	sources := make([]uint, 0, len(param.SourceUids))
+	for _, uid := range param.SourceUids {
+		if uid < 1 || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		sources = append(sources, uid)
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("no hashtags to merge")
+	}
+	return s.repos.Hashtag.MergeHashtags(param.TargetUid, sources)
+}
+
+// 해시태그를 추천 목록에서 금지하거나 해제하기
+func (s *NuboAdminService) BanHashtag(param models.AdminHashtagBanParam) error {
+	if param.HashtagUid < 1 {
+		return fmt.Errorf("invalid hashtag")
+	}
+	return s.repos.Hashtag.UpdateBanned(param.HashtagUid, param.Banned)
+}
+
+// 해시태그 사용 횟수 다시 계산하기
+func (s *NuboAdminService) RecountHashtags() (int64, error) {
+	return s.repos.Hashtag.RecountUsage()
+}
+
+// 게시글이 없는 해시태그들을 정리하고 사용 횟수 다시 계산하기
+func (s *NuboAdminService) CleanupHashtags() (models.AdminHashtagCleanupResult, error) {
+	result := models.AdminHashtagCleanupResult{}
+	purged, err := s.repos.Hashtag.PurgeOrphans()
+	if err != nil {
+		return result, err
+	}
+	result.Purged = purged
+
+	recounted, err := s.repos.Hashtag.RecountUsage()
+	if err != nil {
+		return result, err
+	}
+	result.Recounted = recounted
+	return result, nil
+}
+
+// 금지어 필터 목록 가져오기
+func (s *NuboAdminService) GetContentFilters() ([]models.ContentFilterRule, error) {
+	return s.repos.Filter.GetFilterRules(false)
+}
+
+// 금지어 필터 추가 또는 수정하기
+func (s *NuboAdminService) SaveContentFilter(param models.ContentFilterSaveParam) (uint, error) {
+	param.Pattern = strings.TrimSpace(param.Pattern)
+	if len([]rune(param.Pattern)) > 200 {
+		return models.FAILED, fmt.Errorf("filter pattern is too long")
+	}
+	if param.Match > models.FILTER_MATCH_JAMO {
+		return models.FAILED, fmt.Errorf("invalid filter match type")
+	}
+	if param.Action < models.FILTER_ACTION_MASK || param.Action > models.FILTER_ACTION_BLOCK {
+		return models.FAILED, fmt.Errorf("invalid filter action")
+	}
+	if _, err := utils.NewContentMatcher(models.ContentFilterRule{Pattern: param.Pattern, Match: param.Match}); err != nil {
+		return models.FAILED, err
+	}
+
+	filterUid, err := s.repos.Filter.SaveFilterRule(param)
+	if err != nil {
+		return models.FAILED, err
+	}
+	return filterUid, s.reloadContentFilter()
+}
+
+// 금지어 필터 삭제하기
+func (s *NuboAdminService) RemoveContentFilter(filterUid uint) error {
+	if err := s.repos.Filter.RemoveFilterRule(filterUid); err != nil {
+		return err
+	}
+	return s.reloadContentFilter()
+}
+
+// 금지어 필터 적중 기록 가져오기
+func (s *NuboAdminService) GetContentFilterLogs(param models.ContentFilterLogParam) (models.ContentFilterLogResult, error) {
+	return s.repos.Filter.GetFilterLogs(param)
+}
+
+// 변경된 필터 규칙을 작성 중인 서비스들에 바로 반영하기
+func (s *NuboAdminService) reloadContentFilter() error {
+	if s.filter == nil {
+		return nil
+	}
+	return s.filter.Reload()
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+	"golang.org/x/net/idna"
+)
+
+const (
+	analyticsDefaultDays  = 30
+	analyticsMaxDays      = 365
+	analyticsReferrerTop  = 20
+	readBeaconWindow      = 6 * time.Hour
+	readBeaconMaxSeconds  = 30 * 60
+	referrerHostMaxLength = 255
+)
+
+// 검색 유입으로 보는 도메인 (google은 국가별 도메인이 많아 따로 확인)
+var searchReferrers = []string{
+	"bing.com", "search.naver.com", "search.daum.net", "duckduckgo.com",
+	"search.yahoo.com", "baidu.com", "yandex.com", "yandex.ru", "ecosia.org",
+}
+
+// 소셜 유입으로 보는 도메인
+var socialReferrers = []string{
+	"facebook.com", "fb.com", "instagram.com", "twitter.com", "x.com", "t.co",
+	"threads.net", "bsky.app", "mastodon.social", "reddit.com", "linkedin.com",
+	"lnkd.in", "youtube.com", "tiktok.com", "pinterest.com", "kakao.com",
+	"band.us", "blog.naver.com", "cafe.naver.com",
+}
+
+type AnalyticsService interface {
+	GetPostStats(param models.PostStatParam) (models.PostStatResult, error)
+	RecordReadTime(param models.PostReadBeaconParam) error
+	RollupStats() error
+	RunRollupJob(ctx context.Context)
+}
+
+type NuboAnalyticsService struct {
+	repos *repositories.Repository
+	now   func() time.Time
+}
+
+// 리포지토리 묶음 주입받기
+func NewNuboAnalyticsService(repos *repositories.Repository) *NuboAnalyticsService {
+	return &NuboAnalyticsService{repos: repos, now: time.Now}
+}
+
+// 통계 집계에 쓰는 YYYYMMDD 형식의 날짜 반환
+func analyticsDay(t time.Time) uint {
+	year, month, day := t.Date()
+	return uint(year*10000 + int(month)*100 + day)
+}
+
+// 참조 주소를 유입 경로와 저장할 도메인으로 분류하기 (직접 방문과 사이트 내부 이동은 도메인을 남기지 않음)
+func classifyReferrer(referrer string, siteHost string) (models.TrafficSource, string) {
+	parsed, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return models.TRAFFIC_DIRECT, ""
+	}
+	host, err := idna.ToASCII(strings.ToLower(strings.TrimSuffix(parsed.Hostname(), ".")))
+	if err != nil || host == "" {
+		return models.TRAFFIC_DIRECT, ""
+	}
+	host = strings.TrimPrefix(host, "www.")
+	if len(host) > referrerHostMaxLength {
+		host = host[:referrerHostMaxLength]
+	}
+	if siteHost != "" && host == strings.TrimPrefix(strings.ToLower(siteHost), "www.") {
+		return models.TRAFFIC_INTERNAL, ""
+	}
+	if slices.Contains(strings.Split(host, "."), "google") || matchesReferrer(host, searchReferrers) {
+		return models.TRAFFIC_SEARCH, host
+	}
+	if matchesReferrer(host, socialReferrers) {
+		return models.TRAFFIC_SOCIAL, host
+	}
+	return models.TRAFFIC_REFERRAL, host
+}
+
+// 도메인 자신이거나 그 하위 도메인인지 확인
+func matchesReferrer(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// 게시글 열람 이벤트를 남기고 열람 시간 비콘에 쓸 토큰 발급하기
+type postReadRecorder struct {
+	repo     repositories.AnalyticsRepository
+	siteHost string
+	now      func() time.Time
+}
+
+func newPostReadRecorder(repo repositories.AnalyticsRepository) *postReadRecorder {
+	siteHost := ""
+	if parsed, err := url.Parse(configs.Env.Domain); err == nil {
+		siteHost = parsed.Hostname()
+	}
+	return &postReadRecorder{repo: repo, siteHost: siteHost, now: time.Now}
+}
+
+// 열람 이벤트 기록하기, counted는 이번 열람으로 조회수가 올랐는지 여부 (봇이거나 기록에 실패하면 빈 토큰 반환)
+func (r *postReadRecorder) Record(postUid uint, viewerHash string, referrer string, counted bool) string {
+	if r == nil || viewerHash == "" {
+		return ""
+	}
+	now := r.now()
+	token := uuid.New().String()
+	source, host := classifyReferrer(referrer, r.siteHost)
+	event := models.PostReadEvent{
+		PostUid:      postUid,
+		ViewerHash:   viewerHash,
+		Source:       source,
+		ReferrerHost: host,
+		Counted:      counted,
+		TokenHash:    utils.GetHashedString(token),
+		Day:          analyticsDay(now),
+		Timestamp:    now.UnixMilli(),
+	}
+	if err := r.repo.InsertReadEvent(event); err != nil {
+		log.Printf("analytics: failed to record read of post %d: %v", postUid, err)
+		return ""
+	}
+	return token
+}
+
+// 게시글 작성자나 게시판 관리자에게 기간별 열람 통계 반환
+func (s *NuboAnalyticsService) GetPostStats(param models.PostStatParam) (models.PostStatResult, error) {
+	result := models.PostStatResult{PostUid: param.PostUid}
+	if !s.repos.BoardView.IsPostInBoard(param.PostUid, param.BoardUid) {
+		return result, fmt.Errorf("post does not belong to this board")
+	}
+	isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid)
+	isWriter := s.repos.BoardView.IsWriter(models.TABLE_POST, param.PostUid, param.UserUid)
+	if !isAdmin && !isWriter {
+		return result, fmt.Errorf("you have no permission to see statistics of this post")
+	}
+
+	days := param.Days
+	if days < 1 {
+		days = analyticsDefaultDays
+	}
+	days = min(days, analyticsMaxDays)
+	fromDay := analyticsDay(s.now().AddDate(0, 0, -int(days-1)))
+
+	var err error
+	result.Views, result.Readers, result.AvgReadSeconds = s.repos.Analytics.GetPostStatTotal(param.PostUid, fromDay)
+	if result.Daily, err = s.repos.Analytics.GetPostDailyStats(param.PostUid, fromDay); err != nil {
+		return result, err
+	}
+	if result.Sources, err = s.repos.Analytics.GetPostSourceStats(param.PostUid, fromDay); err != nil {
+		return result, err
+	}
+	result.Referrers, err = s.repos.Analytics.GetPostReferrerStats(param.PostUid, fromDay, analyticsReferrerTop)
+	return result, err
+}
+
+// 페이지를 떠날 때 보내는 비콘으로 열람 시간 기록하기 (토큰당 한 번, 최대 30분까지만 인정)
+func (s *NuboAnalyticsService) RecordReadTime(param models.PostReadBeaconParam) error {
+	token := strings.TrimSpace(param.Token)
+	if token == "" || param.Seconds < 1 {
+		return fmt.Errorf("invalid read beacon")
+	}
+	seconds := min(param.Seconds, readBeaconMaxSeconds)
+	since := s.now().Add(-readBeaconWindow).UnixMilli()
+	recorded, err := s.repos.Analytics.UpdateReadDuration(utils.GetHashedString(token), seconds, since)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		return fmt.Errorf("read token is expired or already used")
+	}
+	return nil
+}
+
+// 어제와 오늘의 통계를 원본 이벤트로 다시 집계하고 보관 기간이 지난 원본 지우기
+func (s *NuboAnalyticsService) RollupStats() error {
+	now := s.now()
+	if err := s.repos.Analytics.RollupPostStats(analyticsDay(now.AddDate(0, 0, -1))); err != nil {
+		return err
+	}
+	retention := configs.GetAnalyticsConfig().RetentionDays
+	return s.repos.Analytics.RemoveReadEventsBefore(now.AddDate(0, 0, -retention).UnixMilli())
+}
+
+// 주기적으로 게시글 통계를 집계하기 (ctx 종료 시 중단)
+func (s *NuboAnalyticsService) RunRollupJob(ctx context.Context) {
+	interval := time.Duration(configs.GetAnalyticsConfig().RollupMinutes) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RollupStats(); err != nil {
+			log.Printf("analytics: failed to roll up post stats: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type analyticsRepoStub struct {
+	repositories.AnalyticsRepository
+	events  []models.PostReadEvent
+	seconds uint
+	fromDay uint
+}
+
+func (r *analyticsRepoStub) InsertReadEvent(event models.PostReadEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *analyticsRepoStub) UpdateReadDuration(tokenHash string, seconds uint, _ int64) (bool, error) {
+	if r.seconds > 0 || len(r.events) == 0 || r.events[0].TokenHash != tokenHash {
+		return false, nil
+	}
+	r.seconds = seconds
+	return true, nil
+}
+
+func (r *analyticsRepoStub) GetPostStatTotal(_ uint, fromDay uint) (uint, uint, uint) {
+	r.fromDay = fromDay
+	return 3, 2, 40
+}
+func (r *analyticsRepoStub) GetPostDailyStats(uint, uint) ([]models.PostStatDaily, error) {
+	return []models.PostStatDaily{}, nil
+}
+func (r *analyticsRepoStub) GetPostSourceStats(uint, uint) ([]models.PostStatSource, error) {
+	return []models.PostStatSource{}, nil
+}
+func (r *analyticsRepoStub) GetPostReferrerStats(uint, uint, uint) ([]models.PostStatReferrer, error) {
+	return []models.PostStatReferrer{}, nil
+}
+
+type analyticsViewRepo struct {
+	repositories.BoardViewRepository
+}
+
+func (analyticsViewRepo) IsPostInBoard(uint, uint) bool { return true }
+func (analyticsViewRepo) IsWriter(_ models.Table, _ uint, userUid uint) bool {
+	return userUid == 7
+}
+
+func TestClassifyReferrer(t *testing.T) {
+	cases := []struct {
+		referrer string
+		source   models.TrafficSource
+		host     string
+	}{
+		{"", models.TRAFFIC_DIRECT, ""},
+		{"android-app://com.example", models.TRAFFIC_DIRECT, ""},
+		{"https://www.community.example.com/board/free", models.TRAFFIC_INTERNAL, ""},
+		{"https://www.google.co.kr/", models.TRAFFIC_SEARCH, "google.co.kr"},
+		{"https://m.search.naver.com/search.naver?query=nubo", models.TRAFFIC_SEARCH, "m.search.naver.com"},
+		{"https://t.co/abc", models.TRAFFIC_SOCIAL, "t.co"},
+		{"https://blog.naver.com/someone", models.TRAFFIC_SOCIAL, "blog.naver.com"},
+		{"https://News.Example.org/article", models.TRAFFIC_REFERRAL, "news.example.org"},
+		{"https://한국.example/", models.TRAFFIC_REFERRAL, "xn--3e0b707e.example"},
+	}
+	for _, tc := range cases {
+		source, host := classifyReferrer(tc.referrer, "community.example.com")
+		if source != tc.source || host != tc.host {
+			t.Errorf("%q: got (%s, %q), want (%s, %q)", tc.referrer, source, host, tc.source, tc.host)
+		}
+	}
+}
+
+func TestReadBeaconAndStatsAccess(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.Local)
+	repo := &analyticsRepoStub{}
+	recorder := &postReadRecorder{repo: repo, now: func() time.Time { return now }}
+	if token := recorder.Record(1, "", "", true); token != "" || len(repo.events) != 0 {
+		t.Fatal("bot views should not be recorded")
+	}
+	token := recorder.Record(1, "viewer", "https://t.co/x", true)
+	if token == "" || len(repo.events) != 1 || repo.events[0].Day != 20260305 || repo.events[0].Source != models.TRAFFIC_SOCIAL {
+		t.Fatalf("unexpected read event %+v", repo.events)
+	}
+	if recorder.Record(1, "viewer", "", false) == "" || len(repo.events) != 2 || repo.events[1].Counted {
+		t.Fatal("a repeated view should be recorded without counting as a new view")
+	}
+
+	service := &NuboAnalyticsService{
+		repos: &repositories.Repository{Analytics: repo, Auth: threadAuthRepo{}, BoardView: analyticsViewRepo{}},
+		now:   func() time.Time { return now },
+	}
+	if err := service.RecordReadTime(models.PostReadBeaconParam{Token: token, Seconds: 4000}); err != nil || repo.seconds != readBeaconMaxSeconds {
+		t.Fatalf("read time should be capped, got %d err %v", repo.seconds, err)
+	}
+	if err := service.RecordReadTime(models.PostReadBeaconParam{Token: token, Seconds: 10}); err == nil {
+		t.Fatal("a read token should only be accepted once")
+	}
+
+	if _, err := service.GetPostStats(models.PostStatParam{BoardUid: 1, PostUid: 1, UserUid: 8}); err == nil {
+		t.Fatal("readers other than the writer or board admins should be rejected")
+	}
+	result, err := service.GetPostStats(models.PostStatParam{BoardUid: 1, PostUid: 1, UserUid: 7, Days: 7})
+	if err != nil || result.Views != 3 || result.Readers != 2 || repo.fromDay != 20260227 {
+		t.Fatalf("writer should see stats from the last 7 days, got %+v from %d err %v", result, repo.fromDay, err)
+	}
+}
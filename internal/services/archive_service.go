@@ -0,0 +1,706 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+const archiveDataLimit = 512 << 20
+
+// 보관 파일에서 첨부파일로도 받지 않는 확장자 (브라우저가 그대로 실행할 수 있는 형식)
+var archiveBlockedExts = map[string]bool{
+	".htm": true, ".html": true, ".xhtml": true, ".xht": true, ".shtml": true, ".svg": true, ".svgz": true,
+	".js": true, ".mjs": true, ".xml": true, ".php": true, ".phtml": true, ".cgi": true, ".pl": true,
+}
+
+type ArchiveService interface {
+	ExportBoard(boardUid uint, output io.Writer) error
+	ImportBoard(source io.ReaderAt, size int64, param models.BoardArchiveImportParam) (models.BoardArchiveReport, error)
+}
+
+type NuboArchiveService struct {
+	repos *repositories.Repository
+	board BoardService
+	now   func() time.Time
+}
+
+// 리포지토리 묶음과 게시판 서비스 주입받기
+func NewNuboArchiveService(repos *repositories.Repository, board BoardService) *NuboArchiveService {
+	return &NuboArchiveService{repos: repos, board: board, now: time.Now}
+}
+
+// 게시판 전체를 보관 파일(board.json + 업로드 파일들)로 만들어 output에 쓰기
+func (s *NuboArchiveService) ExportBoard(boardUid uint, output io.Writer) error {
+	archive, err := s.collectArchive(boardUid)
+	if err != nil {
+		return err
+	}
+	writer := zip.NewWriter(output)
+	entry, err := writer.Create(models.BOARD_ARCHIVE_DATA)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(archive); err != nil {
+		return err
+	}
+
+	for _, publicPath := range archivePaths(archive) {
+		if err := addArchiveFile(writer, publicPath); err != nil {
+			log.Printf("archive: skip %s of board %d: %v", publicPath, boardUid, err)
+		}
+	}
+	return writer.Close()
+}
+
+// 게시판 보관 파일을 새 게시판으로 가져오기 (uid는 새로 매기고 회원은 아이디로 다시 연결)
+func (s *NuboArchiveService) ImportBoard(source io.ReaderAt, size int64, param models.BoardArchiveImportParam) (models.BoardArchiveReport, error) {
+	reader, err := zip.NewReader(source, size)
+	if err != nil {
+		return models.BoardArchiveReport{}, fmt.Errorf("invalid board archive: %w", err)
+	}
+	archive, err := readBoardArchive(reader)
+	if err != nil {
+		return models.BoardArchiveReport{}, err
+	}
+	run := &boardArchiveImport{
+		service:    s,
+		archive:    archive,
+		files:      archiveEntries(reader),
+		param:      param,
+		users:      make(map[uint]uint),
+		matched:    make(map[uint]bool),
+		categories: make(map[uint]uint),
+		posts:      make(map[uint]uint),
+		comments:   make(map[uint]uint),
+		paths:      make(map[string]string),
+		report:     models.BoardArchiveReport{Warnings: make([]string, 0)},
+	}
+	return run.execute()
+}
+
+// 내보낼 게시판의 설정, 글, 댓글, 파일 정보를 한 번에 모으기
+func (s *NuboArchiveService) collectArchive(boardUid uint) (models.BoardArchive, error) {
+	archive := models.BoardArchive{
+		Format:   models.BOARD_ARCHIVE_FORMAT,
+		Version:  models.BOARD_ARCHIVE_VERSION,
+		Exported: s.now().UnixMilli(),
+		Source:   configs.Env.Domain,
+	}
+	board, groupId, err := s.repos.Archive.GetArchiveBoard(boardUid)
+	if err != nil {
+		return archive, fmt.Errorf("failed to load board %d: %w", boardUid, err)
+	}
+	archive.Board, archive.GroupId = board, groupId
+
+	if archive.Categories, err = s.repos.Archive.GetArchiveCategories(boardUid); err != nil {
+		return archive, err
+	}
+	if archive.Posts, err = s.repos.Archive.GetArchivePosts(boardUid); err != nil {
+		return archive, err
+	}
+	tags, err := s.repos.Archive.GetArchiveTags(boardUid)
+	if err != nil {
+		return archive, err
+	}
+	postLikes, err := s.repos.Archive.GetArchiveLikes(models.TABLE_POST_LIKE, boardUid)
+	if err != nil {
+		return archive, err
+	}
+	files, err := s.repos.Archive.GetArchiveFiles(boardUid)
+	if err != nil {
+		return archive, err
+	}
+	exported := make(map[uint]bool, len(archive.Posts))
+	for i := range archive.Posts {
+		post := &archive.Posts[i]
+		post.Tags, post.Likes, post.Files = tags[post.Uid], postLikes[post.Uid], files[post.Uid]
+		exported[post.Uid] = true
+	}
+
+	comments, err := s.repos.Archive.GetArchiveComments(boardUid)
+	if err != nil {
+		return archive, err
+	}
+	commentLikes, err := s.repos.Archive.GetArchiveLikes(models.TABLE_COMMENT_LIKE, boardUid)
+	if err != nil {
+		return archive, err
+	}
+	archive.Comments = make([]models.ArchiveComment, 0, len(comments))
+	for _, comment := range comments {
+		if !exported[comment.PostUid] {
+			continue
+		}
+		comment.Likes = commentLikes[comment.Uid]
+		archive.Comments = append(archive.Comments, comment)
+	}
+	if archive.Images, err = s.repos.Archive.GetArchiveImages(boardUid); err != nil {
+		return archive, err
+	}
+	archive.Users, err = s.repos.Archive.GetArchiveUsers(archiveUserUids(archive))
+	return archive, err
+}
+
+// 보관 파일에 등장하는 모든 회원 고유번호 모으기
+func archiveUserUids(archive models.BoardArchive) []uint {
+	seen := map[uint]bool{archive.Board.AdminUid: true}
+	addLikes := func(likes []models.ArchiveLike) {
+		for _, like := range likes {
+			seen[like.UserUid] = true
+		}
+	}
+	for _, post := range archive.Posts {
+		seen[post.UserUid] = true
+		addLikes(post.Likes)
+	}
+	for _, comment := range archive.Comments {
+		seen[comment.UserUid] = true
+		addLikes(comment.Likes)
+	}
+	for _, image := range archive.Images {
+		seen[image.UserUid] = true
+	}
+	uids := make([]uint, 0, len(seen))
+	for uid := range seen {
+		if uid > 0 {
+			uids = append(uids, uid)
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	return uids
+}
+
+// 보관 파일에 함께 담을 업로드 파일 경로들 (중복 제거, 기록된 순서 유지)
+func archivePaths(archive models.BoardArchive) []string {
+	paths := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(publicPath string) {
+		if publicPath != "" && !seen[publicPath] {
+			seen[publicPath] = true
+			paths = append(paths, publicPath)
+		}
+	}
+	for _, post := range archive.Posts {
+		for _, file := range post.Files {
+			add(file.Path)
+			if file.Thumbnail != nil {
+				add(file.Thumbnail.Small)
+				add(file.Thumbnail.Large)
+			}
+		}
+	}
+	for _, image := range archive.Images {
+		add(image.Path)
+	}
+	return paths
+}
+
+// 공개 업로드 경로를 보관 파일 안의 항목 이름으로 바꾸기
+func archiveEntryName(publicPath string) (string, bool) {
+	cleanPath := path.Clean("/" + strings.TrimSpace(publicPath))
+	if !strings.HasPrefix(cleanPath, "/upload/") {
+		return "", false
+	}
+	return models.BOARD_ARCHIVE_FILE_DIR + strings.TrimPrefix(cleanPath, "/upload/"), true
+}
+
+// 업로드 파일 하나를 보관 파일에 추가하기
+func addArchiveFile(writer *zip.Writer, publicPath string) error {
+	name, ok := archiveEntryName(publicPath)
+	if !ok {
+		return fmt.Errorf("not an upload path")
+	}
+	filePath, err := utils.UploadFilePath(publicPath)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+// 보관 파일에서 board.json을 읽고 형식과 버전 확인하기
+func readBoardArchive(reader *zip.Reader) (models.BoardArchive, error) {
+	archive := models.BoardArchive{}
+	entry, err := reader.Open(models.BOARD_ARCHIVE_DATA)
+	if err != nil {
+		return archive, fmt.Errorf("%s is missing in the board archive", models.BOARD_ARCHIVE_DATA)
+	}
+	defer entry.Close()
+
+	if err := json.NewDecoder(io.LimitReader(entry, archiveDataLimit)).Decode(&archive); err != nil {
+		return archive, fmt.Errorf("invalid %s: %w", models.BOARD_ARCHIVE_DATA, err)
+	}
+	if archive.Format != models.BOARD_ARCHIVE_FORMAT {
+		return archive, fmt.Errorf("unknown archive format %q", archive.Format)
+	}
+	if archive.Version < 1 || archive.Version > models.BOARD_ARCHIVE_VERSION {
+		return archive, fmt.Errorf("unsupported archive version %d", archive.Version)
+	}
+	return archive, nil
+}
+
+// 보관 파일 안의 업로드 파일 항목들을 이름으로 찾을 수 있게 정리
+func archiveEntries(reader *zip.Reader) map[string]*zip.File {
+	entries := make(map[string]*zip.File)
+	for _, file := range reader.File {
+		if strings.HasPrefix(file.Name, models.BOARD_ARCHIVE_FILE_DIR) && !file.FileInfo().IsDir() {
+			entries[file.Name] = file
+		}
+	}
+	return entries
+}
+
+// 게시판 보관 파일 한 번을 가져오는 동안의 상태 (원래 uid → 새 uid 연결)
+type boardArchiveImport struct {
+	service    *NuboArchiveService
+	archive    models.BoardArchive
+	files      map[string]*zip.File
+	param      models.BoardArchiveImportParam
+	boardUid   uint
+	boardId    string
+	users      map[uint]uint
+	matched    map[uint]bool
+	categories map[uint]uint
+	posts      map[uint]uint
+	comments   map[uint]uint
+	paths      map[string]string
+	saved      []string
+	links      *regexp.Regexp
+	report     models.BoardArchiveReport
+}
+
+func (a *boardArchiveImport) warn(format string, args ...any) {
+	if len(a.report.Warnings) < importWarningLimit {
+		a.report.Warnings = append(a.report.Warnings, fmt.Sprintf(format, args...))
+	}
+}
+
+// 게시판 생성부터 삽입 이미지 기록까지 순서대로 가져오기 (중간에 실패하면 만든 게시판과 파일을 지워 다시 시도할 수 있게 함)
+func (a *boardArchiveImport) execute() (models.BoardArchiveReport, error) {
+	a.mapUsers()
+	if err := a.createBoard(); err != nil {
+		return a.report, err
+	}
+	if err := a.fill(); err != nil {
+		a.rollback()
+		return a.report, err
+	}
+	return a.report, nil
+}
+
+// 새 게시판에 카테고리, 파일, 글, 댓글, 삽입 이미지 채우기
+func (a *boardArchiveImport) fill() error {
+	if err := a.importCategories(); err != nil {
+		return err
+	}
+	a.copyFiles()
+	if err := a.importPosts(); err != nil {
+		return err
+	}
+	if err := a.importComments(); err != nil {
+		return err
+	}
+	a.importImages()
+	return nil
+}
+
+// 가져오다 실패한 게시판의 데이터와 복사한 파일 지우기
+func (a *boardArchiveImport) rollback() {
+	if err := a.service.repos.Admin.RemoveBoardData(a.boardUid); err != nil {
+		log.Printf("archive: failed to clean up board %d: %v", a.boardUid, err)
+	}
+	for _, savePath := range a.saved {
+		_ = os.Remove(savePath)
+	}
+	a.saved = nil
+	a.report.BoardUid, a.report.BoardId = 0, ""
+}
+
+// 보관 파일의 회원을 아이디(이메일)로 찾고, 없으면 가져오기를 실행한 관리자로 연결
+func (a *boardArchiveImport) mapUsers() {
+	for _, user := range a.archive.Users {
+		if uid := a.service.repos.Import.FindUserUidByEmail(user.Id); uid > 0 {
+			a.users[user.Uid] = uid
+			a.matched[user.Uid] = true
+			a.report.MatchedUsers++
+			continue
+		}
+		a.users[user.Uid] = a.param.ActorUid
+		a.report.FallbackUsers++
+	}
+}
+
+func (a *boardArchiveImport) user(uid uint) uint {
+	if mapped, ok := a.users[uid]; ok {
+		return mapped
+	}
+	return a.param.ActorUid
+}
+
+// 좋아요는 같은 회원을 찾은 경우에만 옮기기 (대신 연결된 관리자 이름으로 남기지 않음)
+func (a *boardArchiveImport) likes(items []models.ArchiveLike) []models.ArchiveLike {
+	result := make([]models.ArchiveLike, 0, len(items))
+	for _, like := range items {
+		if a.matched[like.UserUid] {
+			like.UserUid = a.users[like.UserUid]
+			result = append(result, like)
+		}
+	}
+	return result
+}
+
+// 보관된 설정으로 새 게시판 만들기
+func (a *boardArchiveImport) createBoard() error {
+	repos := a.service.repos
+	board := a.archive.Board
+	if a.param.BoardId != "" {
+		board.Id = a.param.BoardId
+	}
+	if board.Id == "" || len(board.Id) > 30 {
+		return fmt.Errorf("invalid board id %q", board.Id)
+	}
+	if board.Type > models.BOARD_TRADE {
+		return fmt.Errorf("invalid board type")
+	}
+	if repos.Admin.IsAdded(models.TABLE_BOARD, board.Id) {
+		return fmt.Errorf("board id %q is already in use", board.Id)
+	}
+	depth, err := normalizeCommentDepth(board.CommentDepth)
+	if err != nil {
+		return err
+	}
+	groupId := a.param.GroupId
+	if groupId == "" {
+		groupId = a.archive.GroupId
+	}
+	groupUid, _ := repos.Admin.FindGroupUidAdminUidById(groupId)
+	if groupUid < 1 {
+		groupUid, _ = repos.Admin.FindGroupUidAdminUidById("boards")
+	}
+	if groupUid < 1 {
+		return fmt.Errorf("cannot find group %q", groupId)
+	}
+
+	board.GroupUid = groupUid
+	board.AdminUid = a.user(board.AdminUid)
+	board.CommentDepth = depth
+	board.Categories = ""
+	a.boardUid = repos.Admin.CreateBoard(board)
+	if a.boardUid < 1 {
+		return fmt.Errorf("failed to create board %q", board.Id)
+	}
+	a.boardId = board.Id
+	a.report.BoardUid, a.report.BoardId = a.boardUid, board.Id
+	a.links = regexp.MustCompile(`/(board|blog)/` + regexp.QuoteMeta(a.archive.Board.Id) + `/(\d+)`)
+	return nil
+}
+
+// 카테고리를 새로 만들고 연결해두기
+func (a *boardArchiveImport) importCategories() error {
+	for _, category := range a.archive.Categories {
+		uid, err := a.service.repos.Import.InsertCategory(a.boardUid, category.Name)
+		if err != nil {
+			return err
+		}
+		a.categories[category.Uid] = uid
+		a.report.Categories++
+	}
+	if len(a.categories) == 0 {
+		uid, err := a.service.repos.Import.InsertCategory(a.boardUid, "기타")
+		if err != nil {
+			return err
+		}
+		a.categories[0] = uid
+	}
+	return nil
+}
+
+// 연결할 카테고리가 없으면 첫 번째 카테고리 사용
+func (a *boardArchiveImport) category(uid uint) uint {
+	if mapped, ok := a.categories[uid]; ok {
+		return mapped
+	}
+	if len(a.archive.Categories) > 0 {
+		return a.categories[a.archive.Categories[0].Uid]
+	}
+	return a.categories[0]
+}
+
+// 보관 파일 안의 업로드 파일들을 새 이름으로 저장하고 경로 연결해두기 (복사하지 못한 파일은 연결하지 않음)
+func (a *boardArchiveImport) copyFiles() {
+	for _, publicPath := range archivePaths(a.archive) {
+		savedPath, err := a.copyFile(publicPath)
+		if err != nil {
+			a.warn("%s: %v", publicPath, err)
+			continue
+		}
+		a.paths[publicPath] = savedPath
+	}
+}
+
+// 업로드 종류에 맞는 확장자인지 확인 (이미지 폴더는 이미지만, 첨부파일은 실행될 수 있는 형식 제외)
+func archiveFileAllowed(category models.UploadCategory, name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	if category == models.UPLOAD_IMAGE || category == models.UPLOAD_THUMB {
+		return utils.IsImage(name)
+	}
+	if ext == "" {
+		return true
+	}
+	if len(ext) > 10 || archiveBlockedExts[ext] {
+		return false
+	}
+	for _, r := range ext[1:] {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *boardArchiveImport) copyFile(publicPath string) (string, error) {
+	name, ok := archiveEntryName(publicPath)
+	if !ok {
+		return "", fmt.Errorf("not an upload path")
+	}
+	entry := a.files[name]
+	if entry == nil {
+		return "", fmt.Errorf("file is missing in the archive")
+	}
+	limit := int64(configs.GetFileSizeLimit())
+	if entry.UncompressedSize64 > uint64(limit) {
+		return "", fmt.Errorf("file is larger than %d bytes", limit)
+	}
+
+	category := models.UPLOAD_ATTACH
+	switch segment := strings.SplitN(strings.TrimPrefix(name, models.BOARD_ARCHIVE_FILE_DIR), "/", 2)[0]; segment {
+	case string(models.UPLOAD_IMAGE), string(models.UPLOAD_THUMB):
+		category = models.UploadCategory(segment)
+	}
+	if !archiveFileAllowed(category, name) {
+		return "", fmt.Errorf("file type %q is not allowed", path.Ext(name))
+	}
+	dirPath, err := utils.MakeSavePath(category)
+	if err != nil {
+		return "", err
+	}
+	savePath := fmt.Sprintf("%s/%s%s", dirPath, uuid.New().String()[:8], strings.ToLower(path.Ext(name)))
+
+	src, err := entry.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	dest, err := os.Create(savePath)
+	if err != nil {
+		return "", err
+	}
+	written, err := io.Copy(dest, io.LimitReader(src, limit+1))
+	if closeErr := dest.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > limit {
+		err = fmt.Errorf("file is larger than %d bytes", limit)
+	}
+	if err != nil {
+		_ = os.Remove(savePath)
+		return "", err
+	}
+	savedPath, err := utils.PublicUploadPath(savePath)
+	if err != nil {
+		_ = os.Remove(savePath)
+		return "", err
+	}
+	a.saved = append(a.saved, savePath)
+	return savedPath, nil
+}
+
+// 본문의 업로드 경로를 새 경로로 바꾸기
+func (a *boardArchiveImport) rewritePaths(content string) string {
+	for oldPath, newPath := range a.paths {
+		content = strings.ReplaceAll(content, oldPath, newPath)
+	}
+	return content
+}
+
+// 본문의 같은 게시판 글 링크를 새 게시판 ID와 글 번호로 바꾸기
+func (a *boardArchiveImport) rewriteLinks(content string) string {
+	return a.links.ReplaceAllStringFunc(content, func(link string) string {
+		match := a.links.FindStringSubmatch(link)
+		var oldUid uint
+		if _, err := fmt.Sscan(match[2], &oldUid); err != nil {
+			return link
+		}
+		newUid, ok := a.posts[oldUid]
+		if !ok {
+			return link
+		}
+		return fmt.Sprintf("/%s/%s/%d", match[1], a.boardId, newUid)
+	})
+}
+
+// 게시글과 태그, 좋아요, 첨부파일을 가져오고 글 사이의 링크 고치기
+func (a *boardArchiveImport) importPosts() error {
+	repos := a.service.repos
+	contents := make(map[uint]string, len(a.archive.Posts))
+	for _, post := range a.archive.Posts {
+		content := utils.Sanitize(a.rewritePaths(post.Content))
+		postUid, err := repos.Import.InsertImportedPost(models.ImportPostParam{
+			BoardUid:    a.boardUid,
+			UserUid:     a.user(post.UserUid),
+			CategoryUid: a.category(post.CategoryUid),
+			Title:       utils.CutString(utils.Sanitize(post.Title), 299),
+			Content:     content,
+			Status:      post.Status,
+			Submitted:   post.Submitted,
+			Modified:    post.Modified,
+			Hit:         post.Hit,
+		})
+		if err != nil {
+			return fmt.Errorf("post %d: %w", post.Uid, err)
+		}
+		a.posts[post.Uid] = postUid
+		contents[postUid] = content
+		a.report.Posts++
+
+		if err := a.service.board.SaveTags(a.boardUid, postUid, post.Tags); err != nil {
+			a.warn("post %d: failed to save tags: %v", post.Uid, err)
+		}
+		likes := a.likes(post.Likes)
+		if err := repos.Archive.InsertArchiveLikes(models.TABLE_POST_LIKE, a.boardUid, postUid, likes); err != nil {
+			a.warn("post %d: failed to save likes: %v", post.Uid, err)
+		} else {
+			a.report.Likes += uint(len(likes))
+		}
+		a.importFiles(post, postUid)
+	}
+
+	for postUid, content := range contents {
+		rewritten := a.rewriteLinks(content)
+		if rewritten == content {
+			continue
+		}
+		if err := repos.Import.UpdatePostContent(postUid, rewritten); err != nil {
+			a.warn("post %d: failed to rewrite links: %v", postUid, err)
+		}
+	}
+	return nil
+}
+
+// 첨부파일과 썸네일, EXIF, 이미지 설명 가져오기
+func (a *boardArchiveImport) importFiles(post models.ArchivePost, postUid uint) {
+	edit := a.service.repos.BoardEdit
+	for _, file := range post.Files {
+		filePath, ok := a.paths[file.Path]
+		if !ok {
+			continue
+		}
+		fileUid, err := edit.InsertFile(models.EditorSaveFileParam{
+			BoardUid: a.boardUid,
+			PostUid:  postUid,
+			Name:     file.Name,
+			Path:     filePath,
+		})
+		if err != nil {
+			a.warn("file %d: %v", file.Uid, err)
+			continue
+		}
+		a.report.Files++
+		if file.Thumbnail != nil && a.paths[file.Thumbnail.Small] != "" && a.paths[file.Thumbnail.Large] != "" {
+			thumb := models.BoardThumbnail{Small: a.paths[file.Thumbnail.Small], Large: a.paths[file.Thumbnail.Large]}
+			if err := edit.InsertFileThumbnail(models.EditorSaveThumbnailParam{
+				BoardThumbnail: thumb, FileUid: fileUid, PostUid: postUid,
+			}); err != nil {
+				a.warn("file %d: failed to save thumbnail: %v", file.Uid, err)
+			}
+		}
+		if file.Exif != nil {
+			if err := edit.InsertExif(fileUid, postUid, *file.Exif); err != nil {
+				a.warn("file %d: failed to save exif: %v", file.Uid, err)
+			}
+		}
+		if file.Description != "" {
+			if err := edit.InsertImageDescription(fileUid, postUid, file.Description); err != nil {
+				a.warn("file %d: failed to save description: %v", file.Uid, err)
+			}
+		}
+	}
+}
+
+// 댓글을 작성 순서대로 가져와서 답글 스레드 다시 만들기
+func (a *boardArchiveImport) importComments() error {
+	repos := a.service.repos
+	comments := append([]models.ArchiveComment(nil), a.archive.Comments...)
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].Uid < comments[j].Uid })
+	for _, comment := range comments {
+		postUid, ok := a.posts[comment.PostUid]
+		if !ok {
+			continue
+		}
+		commentUid, err := repos.Import.InsertImportedComment(models.ImportCommentParam{
+			BoardUid:  a.boardUid,
+			PostUid:   postUid,
+			ParentUid: a.comments[comment.ParentUid],
+			UserUid:   a.user(comment.UserUid),
+			Content:   utils.Sanitize(a.rewriteLinks(a.rewritePaths(comment.Content))),
+			Submitted: comment.Submitted,
+			Modified:  comment.Modified,
+			Status:    comment.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("comment %d: %w", comment.Uid, err)
+		}
+		a.comments[comment.Uid] = commentUid
+		a.report.Comments++
+
+		likes := a.likes(comment.Likes)
+		if err := repos.Archive.InsertArchiveLikes(models.TABLE_COMMENT_LIKE, a.boardUid, commentUid, likes); err != nil {
+			a.warn("comment %d: failed to save likes: %v", comment.Uid, err)
+		} else {
+			a.report.Likes += uint(len(likes))
+		}
+	}
+	return nil
+}
+
+// 본문 삽입 이미지 기록 가져오기
+func (a *boardArchiveImport) importImages() {
+	for _, image := range a.archive.Images {
+		savedPath, ok := a.paths[image.Path]
+		if !ok {
+			continue
+		}
+		image.Path = savedPath
+		if err := a.service.repos.Archive.InsertArchiveImage(a.boardUid, a.user(image.UserUid), image); err != nil {
+			a.warn("image %s: %v", image.Path, err)
+			continue
+		}
+		a.report.Images++
+	}
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type archiveRepoStub struct {
+	repositories.ArchiveRepository
+	likes  map[models.Table][]models.ArchiveLike
+	images []models.ArchiveImage
+}
+
+func (archiveRepoStub) GetArchiveBoard(uint) (models.AdminBoardCreateParam, string, error) {
+	return models.AdminBoardCreateParam{Id: "photo", AdminUid: 1, Type: models.BOARD_GALLERY, CommentDepth: 2}, "boards", nil
+}
+func (archiveRepoStub) GetArchiveCategories(uint) ([]models.Pair, error) {
+	return []models.Pair{{Uid: 7, Name: "travel"}}, nil
+}
+func (archiveRepoStub) GetArchivePosts(uint) ([]models.ArchivePost, error) {
+	return []models.ArchivePost{
+		{Uid: 10, UserUid: 1, CategoryUid: 7, Title: "Trip", Submitted: 1000, Hit: 42,
+			Content: `<img src="/upload/images/2024/01/01/a.webp"> <a href="/board/photo/11">next</a>`},
+		{Uid: 11, UserUid: 2, CategoryUid: 7, Title: "Next", Status: models.CONTENT_SECRET},
+	}, nil
+}
+func (archiveRepoStub) GetArchiveTags(uint) (map[uint][]string, error) {
+	return map[uint][]string{10: {"travel"}}, nil
+}
+func (archiveRepoStub) GetArchiveLikes(table models.Table, _ uint) (map[uint][]models.ArchiveLike, error) {
+	if table == models.TABLE_POST_LIKE {
+		return map[uint][]models.ArchiveLike{10: {{UserUid: 1, Liked: true}, {UserUid: 2, Liked: true}}}, nil
+	}
+	return map[uint][]models.ArchiveLike{}, nil
+}
+func (archiveRepoStub) GetArchiveFiles(uint) (map[uint][]models.ArchiveFile, error) {
+	return map[uint][]models.ArchiveFile{10: {{Uid: 3, Name: "a.jpg", Path: "/upload/attachments/2024/01/01/a.jpg",
+		Thumbnail:   &models.BoardThumbnail{Small: "/upload/thumbnails/2024/01/01/s.webp", Large: "/upload/thumbnails/2024/01/01/l.webp"},
+		Exif:        &models.BoardExif{Make: "Nikon"},
+		Description: "sunset"}}}, nil
+}
+func (archiveRepoStub) GetArchiveComments(uint) ([]models.ArchiveComment, error) {
+	return []models.ArchiveComment{
+		{Uid: 21, ParentUid: 20, PostUid: 10, UserUid: 1, Content: "reply"},
+		{Uid: 20, PostUid: 10, UserUid: 2, Content: "first"},
+		{Uid: 22, PostUid: 99, UserUid: 2, Content: "removed post"},
+	}, nil
+}
+func (archiveRepoStub) GetArchiveImages(uint) ([]models.ArchiveImage, error) {
+	return []models.ArchiveImage{{UserUid: 1, Path: "/upload/images/2024/01/01/a.webp", Timestamp: 500}}, nil
+}
+func (archiveRepoStub) GetArchiveUsers(uids []uint) ([]models.ArchiveUser, error) {
+	users := []models.ArchiveUser{{Uid: 1, Id: "member@example.com"}, {Uid: 2, Id: "gone@example.com"}}
+	if len(uids) != len(users) {
+		return nil, fmt.Errorf("unexpected user uids %v", uids)
+	}
+	return users, nil
+}
+func (r *archiveRepoStub) InsertArchiveLikes(table models.Table, _ uint, _ uint, likes []models.ArchiveLike) error {
+	r.likes[table] = append(r.likes[table], likes...)
+	return nil
+}
+func (r *archiveRepoStub) InsertArchiveImage(_ uint, _ uint, image models.ArchiveImage) error {
+	r.images = append(r.images, image)
+	return nil
+}
+
+type archiveAdminRepo struct {
+	repositories.AdminRepository
+	created models.AdminBoardCreateParam
+	removed uint
+}
+
+func (archiveAdminRepo) IsAdded(_ models.Table, id string) bool { return id == "photo" }
+func (archiveAdminRepo) FindGroupUidAdminUidById(id string) (uint, uint) {
+	if id == "boards" {
+		return 1, 1
+	}
+	return 0, 0
+}
+func (r *archiveAdminRepo) CreateBoard(param models.AdminBoardCreateParam) uint {
+	r.created = param
+	return 30
+}
+func (r *archiveAdminRepo) RemoveBoardData(boardUid uint) error {
+	r.removed = boardUid
+	return nil
+}
+
+type failingImportRepo struct{ *importRepoStub }
+
+func (failingImportRepo) InsertImportedPost(models.ImportPostParam) (uint, error) {
+	return 0, fmt.Errorf("disk full")
+}
+
+type archiveEditRepo struct {
+	repositories.BoardEditRepository
+	files  []models.EditorSaveFileParam
+	thumbs []models.EditorSaveThumbnailParam
+	extras uint
+}
+
+func (r *archiveEditRepo) InsertFile(param models.EditorSaveFileParam) (uint, error) {
+	r.files = append(r.files, param)
+	return 100, nil
+}
+func (r *archiveEditRepo) InsertFileThumbnail(param models.EditorSaveThumbnailParam) error {
+	r.thumbs = append(r.thumbs, param)
+	return nil
+}
+func (r *archiveEditRepo) InsertExif(uint, uint, models.BoardExif) error { r.extras++; return nil }
+func (r *archiveEditRepo) InsertImageDescription(uint, uint, string) error {
+	r.extras++
+	return nil
+}
+
+func TestBoardArchiveRoundTrip(t *testing.T) {
+	previous := configs.Env
+	configs.Env.UploadDir = t.TempDir()
+	configs.Env.FileSizeLimit = "1024"
+	t.Cleanup(func() { configs.Env = previous })
+
+	archiveRepo := &archiveRepoStub{likes: make(map[models.Table][]models.ArchiveLike)}
+	for _, publicPath := range []string{"/upload/images/2024/01/01/a.webp", "/upload/attachments/2024/01/01/a.jpg",
+		"/upload/thumbnails/2024/01/01/s.webp"} {
+		filePath, _ := utils.UploadFilePath(publicPath)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(publicPath), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	importRepo := &importRepoStub{maps: make(map[string]uint), posts: make(map[uint]models.ImportPostParam), contents: make(map[uint]string)}
+	adminRepo := &archiveAdminRepo{}
+	editRepo := &archiveEditRepo{}
+	service := NewNuboArchiveService(&repositories.Repository{
+		Admin:     adminRepo,
+		Archive:   archiveRepo,
+		BoardEdit: editRepo,
+		Import:    importRepo,
+	}, importBoardService{})
+	service.now = func() time.Time { return time.UnixMilli(1_700_000_000_000) }
+
+	var buffer bytes.Buffer
+	if err := service.ExportBoard(1, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.Bytes()
+	if _, err := service.ImportBoard(bytes.NewReader(data), int64(len(data)), models.BoardArchiveImportParam{ActorUid: 9}); err == nil {
+		t.Fatal("import should refuse a board id that is already in use")
+	}
+
+	report, err := service.ImportBoard(bytes.NewReader(data), int64(len(data)),
+		models.BoardArchiveImportParam{ActorUid: 9, BoardId: "photo2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.BoardUid != 30 || report.Posts != 2 || report.Comments != 2 || report.Files != 1 || report.Images != 1 ||
+		report.MatchedUsers != 1 || report.FallbackUsers != 1 || len(report.Warnings) != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if adminRepo.created.Id != "photo2" || adminRepo.created.GroupUid != 1 || adminRepo.created.AdminUid != 5 {
+		t.Fatalf("board should be created with the new id, group and remapped admin, got %+v", adminRepo.created)
+	}
+
+	uids := make(map[string]uint)
+	for uid, post := range importRepo.posts {
+		uids[post.Title] = uid
+	}
+	trip, next := importRepo.posts[uids["Trip"]], importRepo.posts[uids["Next"]]
+	if trip.UserUid != 5 || next.UserUid != 9 || trip.Hit != 42 || next.Status != models.CONTENT_SECRET || trip.CategoryUid < 1 {
+		t.Fatalf("posts should keep fields and remap writers, got %+v %+v", trip, next)
+	}
+	if strings.Contains(trip.Content, "/upload/images/2024/01/01/a.webp") || !strings.Contains(trip.Content, "/upload/images/") {
+		t.Fatalf("inserted images should point to the copied files, got %q", trip.Content)
+	}
+	if !strings.Contains(importRepo.contents[uids["Trip"]], fmt.Sprintf(`href="/board/photo2/%d"`, uids["Next"])) {
+		t.Fatalf("links inside the board should be rewritten, got %v", importRepo.contents)
+	}
+	copied, err := utils.UploadFilePath(editRepo.files[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, err := os.ReadFile(copied); err != nil || string(content) != "/upload/attachments/2024/01/01/a.jpg" {
+		t.Fatalf("attachment should be copied from the archive, got %q %v", content, err)
+	}
+	if len(editRepo.thumbs) != 0 || editRepo.extras != 2 {
+		t.Fatalf("thumbnail with a missing file should be skipped and exif/description should be saved, got %+v", editRepo.thumbs)
+	}
+	if len(importRepo.comments) != 2 || importRepo.comments[0].Content != "first" || importRepo.comments[1].ParentUid == 0 {
+		t.Fatalf("comments should be imported in order with replies attached, got %+v", importRepo.comments)
+	}
+	if likes := archiveRepo.likes[models.TABLE_POST_LIKE]; len(likes) != 1 || likes[0].UserUid != 5 {
+		t.Fatalf("only likes from matched members should be kept, got %+v", likes)
+	}
+	if archiveRepo.images[0].Timestamp != 500 || !strings.Contains(trip.Content, archiveRepo.images[0].Path) {
+		t.Fatalf("inserted image record should follow the copied file, got %+v", archiveRepo.images)
+	}
+}
+
+func TestBoardArchiveImportCleansUpOnFailure(t *testing.T) {
+	previous := configs.Env
+	configs.Env.UploadDir = t.TempDir()
+	configs.Env.FileSizeLimit = "1024"
+	t.Cleanup(func() { configs.Env = previous })
+
+	publicPath := "/upload/images/2024/01/01/a.webp"
+	filePath, _ := utils.UploadFilePath(publicPath)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filePath, []byte("image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	adminRepo := &archiveAdminRepo{}
+	repos := &repositories.Repository{
+		Admin:     adminRepo,
+		Archive:   &archiveRepoStub{likes: make(map[models.Table][]models.ArchiveLike)},
+		BoardEdit: &archiveEditRepo{},
+		Import:    &importRepoStub{maps: make(map[string]uint), posts: make(map[uint]models.ImportPostParam), contents: make(map[uint]string)},
+	}
+	service := NewNuboArchiveService(repos, importBoardService{})
+	var buffer bytes.Buffer
+	if err := service.ExportBoard(1, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	repos.Import = failingImportRepo{repos.Import.(*importRepoStub)}
+	data := buffer.Bytes()
+	report, err := service.ImportBoard(bytes.NewReader(data), int64(len(data)), models.BoardArchiveImportParam{ActorUid: 9, BoardId: "photo2"})
+	if err == nil || adminRepo.removed != 30 || report.BoardUid != 0 {
+		t.Fatalf("a failed import should remove the new board, got %+v removed %d err %v", report, adminRepo.removed, err)
+	}
+	copied := make([]string, 0)
+	filepath.WalkDir(configs.Env.UploadDir, func(walked string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			copied = append(copied, walked)
+		}
+		return err
+	})
+	if len(copied) != 0 {
+		t.Fatalf("copied files should be removed after a failed import, got %v", copied)
+	}
+}
+
+func TestArchiveFileAllowed(t *testing.T) {
+	cases := []struct {
+		category models.UploadCategory
+		name     string
+		allowed  bool
+	}{
+		{models.UPLOAD_IMAGE, "files/images/a.webp", true},
+		{models.UPLOAD_THUMB, "files/thumbnails/a.pdf", false},
+		{models.UPLOAD_ATTACH, "files/attachments/a.PDF", true},
+		{models.UPLOAD_ATTACH, "files/attachments/README", true},
+		{models.UPLOAD_ATTACH, "files/attachments/a.html", false},
+		{models.UPLOAD_ATTACH, "files/attachments/a.SVG", false},
+		{models.UPLOAD_ATTACH, "files/attachments/a.p%20", false},
+	}
+	for _, tc := range cases {
+		if got := archiveFileAllowed(tc.category, tc.name); got != tc.allowed {
+			t.Errorf("%s %s: got %v, want %v", tc.category, tc.name, got, tc.allowed)
+		}
+	}
+}
@@ -89,6 +89,7 @@ func (s *NuboAuthService) RotateTokens(userUid uint, oldRefreshToken string) (mo
 type NuboAuthService struct {
 	repos  *repositories.Repository
 	mailer utils.Mailer
+	filter *contentFilter
 }
 
 // 리포지토리 묶음 주입받기
@@ -251,7 +252,11 @@ func (s *NuboAuthService) Signup(param models.SignupParam) (models.SignupResult,
 		return signupResult, fmt.Errorf("email(%s) is already in use", param.ID)
 	}
 
-	name := utils.Escape(param.Name)
+	filteredName, err := s.filter.Filter(models.FILTER_TARGET_USER_NAME, 0, param.Name)
+	if err != nil {
+		return signupResult, err
+	}
+	name := utils.Escape(filteredName)
 	isDupName := s.repos.User.IsNameDuplicated(name, 0)
 	if isDupName {
 		return signupResult, fmt.Errorf("name(%s) is already in use", name)
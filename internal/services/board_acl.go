@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+// 게시판 역할 규칙 가져오기
+func (s *NuboAdminService) GetBoardAcl(boardUid uint) ([]models.BoardAclRule, error) {
+	return s.repos.Role.GetBoardAcl(boardUid)
+}
+
+// 회원 역할 목록 가져오기
+func (s *NuboAdminService) GetRoles() ([]models.UserRole, error) {
+	return s.repos.Role.GetRoles()
+}
+
+// 회원 역할 추가 또는 수정하기
+func (s *NuboAdminService) SaveRole(param models.AdminRoleSaveParam) (uint, error) {
+	param.Name = strings.TrimSpace(param.Name)
+	param.Info = strings.TrimSpace(param.Info)
+	if len([]rune(param.Name)) < 2 || len([]rune(param.Name)) > 30 {
+		return models.FAILED, fmt.Errorf("role name must be between 2 and 30 characters")
+	}
+	if len([]rune(param.Info)) > 100 {
+		return models.FAILED, fmt.Errorf("role description is too long")
+	}
+	if param.Uid > 0 && !s.repos.Role.IsRoleExists(param.Uid) {
+		return models.FAILED, fmt.Errorf("role does not exist")
+	}
+	if s.repos.Role.IsRoleNameDuplicated(param.Name, param.Uid) {
+		return models.FAILED, fmt.Errorf("duplicated role name")
+	}
+	param.Name = utils.Escape(param.Name)
+	param.Info = utils.Escape(param.Info)
+	return s.repos.Role.SaveRole(param)
+}
+
+// 회원 역할 삭제하기
+func (s *NuboAdminService) RemoveRole(roleUid uint) error {
+	return s.repos.Role.RemoveRole(roleUid)
+}
+
+// 역할에 속한 회원 목록 가져오기
+func (s *NuboAdminService) GetRoleMembers(roleUid uint) ([]models.BoardWriter, error) {
+	return s.repos.Role.GetRoleMembers(roleUid)
+}
+
+// 역할에 회원 추가하기
+func (s *NuboAdminService) AddRoleMember(param models.AdminRoleMemberParam) error {
+	if !s.repos.Role.IsRoleExists(param.RoleUid) {
+		return fmt.Errorf("role does not exist")
+	}
+	return s.repos.Role.InsertRoleMember(param.RoleUid, param.UserUid)
+}
+
+// 역할에서 회원 빼기
+func (s *NuboAdminService) RemoveRoleMember(param models.AdminRoleMemberParam) error {
+	return s.repos.Role.RemoveRoleMember(param.RoleUid, param.UserUid)
+}
+
+// 게시판 역할 규칙 검사 후 교체하기
+func (s *NuboAdminService) replaceBoardAcl(boardUid uint, rules []models.BoardAclRule) error {
+	seen := make(map[models.BoardAclRule]bool, len(rules))
+	for _, rule := range rules {
+		if rule.Action > models.BOARD_ACTION_MANAGE {
+			return fmt.Errorf("invalid board action in access rule")
+		}
+		if !s.repos.Role.IsRoleExists(rule.RoleUid) {
+			return fmt.Errorf("role %d does not exist", rule.RoleUid)
+		}
+		key := models.BoardAclRule{RoleUid: rule.RoleUid, Action: rule.Action}
+		if seen[key] {
+			return fmt.Errorf("duplicated access rule for role %d", rule.RoleUid)
+		}
+		seen[key] = true
+	}
+	return s.repos.Role.ReplaceBoardAcl(boardUid, rules)
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log"
 	"mime/multipart"
+	"net/url"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/sirini/goapi/internal/configs"
@@ -15,7 +17,10 @@ import (
 )
 
 type BoardService interface {
+	ApprovePost(param models.BoardApprovalDecisionParam) error
+	CreateShare(param models.PostShareCreateParam) (models.PostShareCreated, error)
 	Download(boardUid uint, fileUid uint, userUid uint) (models.BoardViewDownloadResult, error)
+	DownloadSharedFile(token string, fileUid uint) (models.BoardViewDownloadResult, error)
 	GetBoardConfig(boardUid uint) models.BoardConfig
 	GetBoardList(boardUid uint, userUid uint) ([]models.BoardItem, error)
 	GetBoardUid(id string) uint
@@ -24,7 +29,12 @@ type BoardService interface {
 	GetLatestUserContents(userUid uint, limit uint) models.BoardWriterLatestContent
 	GetListItem(param models.BoardListParam) (models.BoardListResult, error)
 	GetMaxUid() uint
+	GetOEmbed(param models.OEmbedParam) (models.OEmbedResult, error)
+	GetPendingPosts(param models.BoardApprovalParam) (models.BoardApprovalResult, error)
+	GetPostMeta(param models.BoardViewParam) (models.BoardMetaResult, error)
 	GetRecentTags(boardUid uint, limit uint) ([]models.BoardTag, error)
+	GetShareLogs(param models.PostShareManageParam) ([]models.PostShareLog, error)
+	GetShares(param models.PostShareManageParam) ([]models.PostShareItem, error)
 	GetSuggestionTags(input string, bunch uint) []models.EditorTagItem
 	GetSuggestionTitles(input string, bunch uint) []string
 	GetThumbnailImage(fileUid uint, userUid uint) (string, error)
@@ -36,16 +46,27 @@ type BoardService interface {
 	RemoveAttachedFile(param models.EditorRemoveAttachedParam) error
 	RemoveInsertedImage(imageUid uint, userUid uint)
 	RemovePost(boardUid uint, postUid uint, userUid uint) error
+	RejectPost(param models.BoardApprovalDecisionParam) error
+	ReportContent(param models.ContentReportParam) error
+	RevokeShare(param models.PostShareManageParam) error
 	SaveAttachments(param models.EditorSaveAttachedParam) error
 	SaveTags(boardUid uint, postUid uint, tags []string) error
 	SaveThumbnail(fileUid uint, postUid uint, path string) models.BoardThumbnail
 	UploadInsertImage(boardUid uint, userUid uint, images []*multipart.FileHeader) ([]string, error)
+	ViewSharedPost(param models.PostShareViewParam) (models.PostShareViewResult, error)
 	WritePost(param models.EditorWriteParam) (uint, error)
 }
 
 type NuboBoardService struct {
 	repos                  *repositories.Repository
 	notifications          *notificationPublisher
+	federation             *NuboActivityPubService
+	filter                 *contentFilter
+	spam                   *spamGuard
+	links                  *linkPreviewer
+	views                  *postViewCounter
+	readers                *postReadRecorder
+	related                *relatedPostCache
 	imageDescriptionConfig configs.ImageDescriptionConfig
 	imageDescriptionSlots  chan struct{}
 	describeImage          func(context.Context, string, string) (utils.ImageDescriptionResult, error)
@@ -64,6 +85,7 @@ func newNuboBoardService(
 	return &NuboBoardService{
 		repos:                  repos,
 		notifications:          newNotificationPublisher(repos, disabledPushSender{}),
+		related:                newRelatedPostCache(relatedCacheTTL, relatedCacheLimit),
 		imageDescriptionConfig: config,
 		imageDescriptionSlots:  make(chan struct{}, config.MaxConcurrent),
 		describeImage:          describeImage,
@@ -110,7 +132,7 @@ func (s *NuboBoardService) Download(boardUid uint, fileUid uint, userUid uint) (
 	if postUid < 1 || status == models.CONTENT_REMOVED {
 		return result, fmt.Errorf("file is not available")
 	}
-	if status == models.CONTENT_SECRET {
+	if status == models.CONTENT_SECRET || status == models.CONTENT_PENDING {
 		isAdmin := s.repos.Auth.CheckPermissionByUid(userUid, boardUid)
 		isWriter := s.repos.BoardView.IsWriter(models.TABLE_POST, postUid, userUid)
 		if !isAdmin && !isWriter {
@@ -118,7 +140,7 @@ func (s *NuboBoardService) Download(boardUid uint, fileUid uint, userUid uint) (
 		}
 	}
 	userLv, userPt := s.repos.User.GetUserLevelPoint(userUid)
-	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(boardUid, models.BOARD_ACTION_DOWNLOAD)
+	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(boardUid, userUid, models.BOARD_ACTION_DOWNLOAD)
 	if userLv < needLv {
 		return result, fmt.Errorf("level restriction")
 	}
@@ -192,7 +214,7 @@ func (s *NuboBoardService) GetEditorConfig(boardUid uint, userUid uint) models.E
 func (s *NuboBoardService) GetInsertedImages(param models.EditorInsertImageParam) (models.EditorInsertImageResult, error) {
 	result := models.EditorInsertImageResult{}
 	userLv, _ := s.repos.User.GetUserLevelPoint(param.UserUid)
-	needLv, _ := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, models.BOARD_ACTION_WRITE)
+	needLv, _ := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_WRITE)
 	if userLv < needLv {
 		return result, fmt.Errorf("level restriction")
 	}
@@ -234,6 +256,12 @@ func (s *NuboBoardService) GetListItem(param models.BoardListParam) (models.Boar
 	var err error
 
 	result := models.BoardListResult{}
+	userLv, _ := s.repos.User.GetUserLevelPoint(param.UserUid)
+	needLv, _ := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_LIST)
+	if userLv < needLv {
+		return result, fmt.Errorf("level restriction")
+	}
+
 	notices, err := s.repos.Board.GetNoticePosts(param.BoardUid, param.UserUid)
 	if err != nil {
 		return result, err
@@ -245,6 +273,7 @@ func (s *NuboBoardService) GetListItem(param models.BoardListParam) (models.Boar
 	if err != nil {
 		return result, err
 	}
+	s.attachCoverVariants(notices, posts)
 
 	result = models.BoardListResult{
 		TotalPostCount: totalPostCount,
@@ -291,27 +320,72 @@ func (s *NuboBoardService) GetThumbnailImage(fileUid uint, userUid uint) (string
 
 // 게시글 가져오기
 func (s *NuboBoardService) GetViewItem(param models.BoardViewParam) (models.BoardViewResult, error) {
-	result := models.BoardViewResult{}
+	userLv, userPt, needPt, err := s.checkViewLevel(param)
+	if err != nil {
+		return models.BoardViewResult{}, err
+	}
+	// 잔액 확인은 조회자 구분과 상관없이 항상 하고, 차감은 조회수가 올라간 첫 조회에만 하기
+	if needPt < 0 && userPt < utils.Abs(needPt) {
+		return models.BoardViewResult{}, fmt.Errorf("not enough point")
+	}
+	viewerHash := postViewerHash(param.UserUid, param.IP, param.Agent)
+
+	result, err := s.loadViewItem(param, userLv)
+	if err != nil {
+		return result, err
+	}
+	post := result.Post
+	result.PrevPostUid = s.repos.BoardView.GetPrevPostUid(param.BoardUid, param.PostUid)
+	result.NextPostUid = s.repos.BoardView.GetNextPostUid(param.BoardUid, param.PostUid)
+	result.WriterPosts, _ = s.repos.BoardView.GetWriterLatestPost(post.Writer.UserUid, param.LatestLimit)
+	result.WriterComments, _ = s.repos.BoardView.GetWriterLatestComment(post.Writer.UserUid, param.LatestLimit)
+	result.Related = s.getRelatedPosts(param.PostUid, param.UserUid, param.RelatedLimit)
+	result.Previews = s.links.Previews(result.Post.Content)
+	counted := s.views.Count(param.PostUid, viewerHash)
+	result.ReadToken = s.readers.Record(param.PostUid, viewerHash, param.Referrer, counted)
+	if !counted {
+		return result, nil
+	}
+	if err := applyPointChange(s.repos.User, models.UpdatePointParam{
+		UserUid:  param.UserUid,
+		BoardUid: param.BoardUid,
+		Action:   models.POINT_ACTION_VIEW,
+		Point:    needPt,
+	}); err != nil {
+		return models.BoardViewResult{}, err
+	}
+	result.Post.Hit++
+	return result, nil
+}
+
+// 게시판 소속, 작성자 차단, 읽기 레벨 확인하고 보는 사람의 레벨, 포인트와 글 보기에 필요한 포인트 반환
+func (s *NuboBoardService) checkViewLevel(param models.BoardViewParam) (int, int, int, error) {
 	if !s.repos.BoardView.IsPostInBoard(param.PostUid, param.BoardUid) {
-		return result, fmt.Errorf("post does not belong to this board")
+		return 0, 0, 0, fmt.Errorf("post does not belong to this board")
 	}
 	if isBanned := s.repos.BoardView.CheckBannedByWriter(param.PostUid, param.UserUid); isBanned {
-		return result, fmt.Errorf("you have been blocked by writer")
+		return 0, 0, 0, fmt.Errorf("you have been blocked by writer")
 	}
 
 	userLv, userPt := s.repos.User.GetUserLevelPoint(param.UserUid)
-	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, models.BOARD_ACTION_VIEW)
+	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_VIEW)
 	if userLv < needLv {
-		return result, fmt.Errorf("level restriction")
-	}
-	if needPt < 0 && userPt < utils.Abs(needPt) {
-		return result, fmt.Errorf("not enough point")
+		return userLv, userPt, needPt, fmt.Errorf("level restriction")
 	}
+	return userLv, userPt, needPt, nil
+}
 
+// 조회수나 포인트를 바꾸지 않고 게시글, 첨부파일, 이미지, 태그 가져오기 (권한 없는 비밀글은 내용을 가림)
+func (s *NuboBoardService) loadViewItem(param models.BoardViewParam, userLv int) (models.BoardViewResult, error) {
+	result := models.BoardViewResult{}
 	post, err := s.repos.BoardView.GetPostItem(param.PostUid, param.UserUid)
 	if err != nil {
 		return result, err
 	}
+	if post.Status == models.CONTENT_PENDING && post.Writer.UserUid != param.UserUid &&
+		!s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid) {
+		return result, fmt.Errorf("post is waiting for approval")
+	}
 
 	config := s.repos.Board.GetBoardConfig(param.BoardUid)
 	result.Config = config
@@ -320,7 +394,7 @@ func (s *NuboBoardService) GetViewItem(param models.BoardViewParam) (models.Boar
 	result.Files = make([]models.BoardAttachment, 0)
 	result.Images = make([]models.BoardAttachedImage, 0)
 
-	if config.Level.Download <= userLv {
+	if downloadLv, _ := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_DOWNLOAD); downloadLv <= userLv {
 		files, err := s.repos.BoardView.GetAttachments(param.PostUid)
 		if err != nil {
 			return result, err
@@ -334,10 +408,6 @@ func (s *NuboBoardService) GetViewItem(param models.BoardViewParam) (models.Boar
 	}
 	result.Images = images
 
-	if param.NeedUpdateHit {
-		s.repos.BoardView.UpdatePostHit(param.PostUid)
-	}
-
 	if post.Status == models.CONTENT_SECRET {
 		isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid)
 		isWriter := post.Writer.UserUid == param.UserUid
@@ -350,22 +420,54 @@ func (s *NuboBoardService) GetViewItem(param models.BoardViewParam) (models.Boar
 		}
 	}
 
+	result.ContentImages = s.contentImageVariants(result.Post.Content)
 	result.Tags = s.repos.BoardView.GetTags(param.PostUid)
-	result.PrevPostUid = s.repos.BoardView.GetPrevPostUid(param.BoardUid, param.PostUid)
-	result.NextPostUid = s.repos.BoardView.GetNextPostUid(param.BoardUid, param.PostUid)
-	result.WriterPosts, _ = s.repos.BoardView.GetWriterLatestPost(post.Writer.UserUid, param.LatestLimit)
-	result.WriterComments, _ = s.repos.BoardView.GetWriterLatestComment(post.Writer.UserUid, param.LatestLimit)
-	if err := applyPointChange(s.repos.User, models.UpdatePointParam{
-		UserUid:  param.UserUid,
-		BoardUid: param.BoardUid,
-		Action:   models.POINT_ACTION_VIEW,
-		Point:    needPt,
-	}); err != nil {
-		return models.BoardViewResult{}, err
-	}
 	return result, nil
 }
 
+// 본문에 삽입한 업로드 이미지들의 반응형 변형 찾기 (키는 /upload로 시작하는 공개 경로)
+func (s *NuboBoardService) contentImageVariants(content string) map[string][]models.ImageVariant {
+	paths := make([]string, 0)
+	for _, source := range utils.ExtractImageSources(utils.Unescape(content), siteURL()+"/") {
+		parsed, err := url.Parse(source)
+		if err == nil && strings.HasPrefix(parsed.Path, "/upload/") {
+			paths = append(paths, parsed.Path)
+		}
+	}
+	if len(paths) < 1 {
+		return map[string][]models.ImageVariant{}
+	}
+	variants, err := s.repos.ImageVariant.GetImageVariants(paths)
+	if err != nil {
+		return map[string][]models.ImageVariant{}
+	}
+	return variants
+}
+
+// 목록의 대표 이미지마다 반응형 변형 붙이기
+func (s *NuboBoardService) attachCoverVariants(lists ...[]models.BoardListItem) {
+	covers := make([]string, 0)
+	for _, items := range lists {
+		for _, item := range items {
+			if item.Cover != "" {
+				covers = append(covers, item.Cover)
+			}
+		}
+	}
+	if len(covers) < 1 {
+		return
+	}
+	variants, err := s.repos.ImageVariant.GetImageVariants(covers)
+	if err != nil {
+		return
+	}
+	for _, items := range lists {
+		for i := range items {
+			items[i].CoverVariants = variants[items[i].Cover]
+		}
+	}
+}
+
 // 글 작성자에게 차단당했는지 확인
 func (s *NuboBoardService) IsBannedByWriter(postUid uint, viewerUid uint) bool {
 	return s.repos.BoardView.CheckBannedByWriter(postUid, viewerUid)
@@ -444,7 +546,13 @@ func (s *NuboBoardService) MovePost(param models.BoardMovePostParam) error {
 	if !s.repos.Auth.CheckPermissionByUid(param.UserUid, param.TargetBoardUid) {
 		return fmt.Errorf("you have no permission to move posts into the target board")
 	}
-	return s.repos.BoardView.MovePost(param.TargetBoardUid, param.PostUid)
+	if err := s.repos.BoardView.MovePost(param.TargetBoardUid, param.PostUid); err != nil {
+		return err
+	}
+	s.related.Invalidate(param.PostUid)
+	s.federation.PublishPost(param.BoardUid, param.PostUid, models.AP_DELETE)
+	s.federation.PublishPost(param.TargetBoardUid, param.PostUid, models.AP_CREATE)
+	return nil
 }
 
 // 게시글 수정하기
@@ -470,23 +578,40 @@ func (s *NuboBoardService) ModifyPost(param models.EditorModifyParam) error {
 			param.IsNotice = false
 		}
 	}
+	wasPending := s.repos.Comment.GetPostStatus(param.PostUid) == models.CONTENT_PENDING
+	param.IsPending = wasPending
+	_, param.IsHidden = s.repos.Report.GetHiddenStatus(models.REPORT_TARGET_POST, param.PostUid)
+	if err := s.filter.FilterPost(&param.EditorWriteParam); err != nil {
+		return err
+	}
 	s.repos.BoardView.RemovePostTags(param.PostUid)
 	err := s.repos.BoardEdit.UpdatePost(param)
 	if err != nil {
 		return err
 	}
+	if err := saveEditedApproval(s.repos, param.PostUid, param.EditorWriteParam, wasPending); err != nil {
+		return err
+	}
+	s.related.Invalidate(param.PostUid)
 
 	err = s.SaveTags(param.BoardUid, param.PostUid, param.Tags)
 	if err != nil {
 		return err
 	}
 
-	return s.SaveAttachments(models.EditorSaveAttachedParam{
+	err = s.SaveAttachments(models.EditorSaveAttachedParam{
 		Context:  param.Context,
 		BoardUid: param.BoardUid,
 		PostUid:  param.PostUid,
 		Files:    param.Files,
 	})
+	if err != nil {
+		return err
+	}
+	if !param.IsHidden {
+		s.federation.PublishPost(param.BoardUid, param.PostUid, models.AP_UPDATE)
+	}
+	return nil
 }
 
 // 게시글 수정 시 첨부했던 파일 삭제하기
@@ -514,12 +639,14 @@ func (s *NuboBoardService) RemoveAttachedFile(param models.EditorRemoveAttachedP
 
 // 게시글에 삽입한 이미지 삭제하기
 func (s *NuboBoardService) RemoveInsertedImage(imageUid uint, userUid uint) {
-	removePath, err := s.repos.BoardEdit.RemoveInsertedImage(imageUid, userUid)
+	removes, err := s.repos.BoardEdit.RemoveInsertedImage(imageUid, userUid)
 	if err != nil {
 		return
 	}
-	if len(removePath) > 0 {
-		_ = utils.RemoveUploadFile(removePath)
+	for _, target := range removes {
+		if len(target) > 0 {
+			_ = utils.RemoveUploadFile(target)
+		}
 	}
 }
 
@@ -537,6 +664,7 @@ func (s *NuboBoardService) RemovePost(boardUid uint, postUid uint, userUid uint)
 	if err := s.repos.BoardView.RemovePost(postUid); err != nil {
 		return err
 	}
+	s.related.Invalidate(postUid)
 	s.repos.BoardView.RemoveComments(postUid)
 	s.repos.BoardView.RemovePostTags(postUid)
 	removes := s.repos.BoardView.RemoveAttachments(postUid)
@@ -544,6 +672,7 @@ func (s *NuboBoardService) RemovePost(boardUid uint, postUid uint, userUid uint)
 	for _, path := range removes {
 		_ = utils.RemoveUploadFile(path)
 	}
+	s.federation.PublishPost(boardUid, postUid, models.AP_DELETE)
 	return nil
 }
 
@@ -611,18 +740,12 @@ func (s *NuboBoardService) SaveAttachments(param models.EditorSaveAttachedParam)
 					return
 				}
 
-				publicLarge, largePathErr := utils.PublicUploadPath(thumb.Large)
-				publicSmall, smallPathErr := utils.PublicUploadPath(thumb.Small)
-				if largePathErr != nil || smallPathErr != nil {
-					if largePathErr != nil {
-						err = largePathErr
-					} else {
-						err = smallPathErr
-					}
+				publicThumb, err := utils.PublicThumbnail(thumb)
+				if err != nil {
 					mu.Lock()
 					saved = append(saved, savedAttachment{
 						fileUid: fileUid, filePath: publicSavedPath,
-						extraPath: []string{thumb.Small, thumb.Large},
+						extraPath: []string{thumb.Small, thumb.Large, thumb.SmallFallback, thumb.LargeFallback},
 					})
 					errors = append(errors, err)
 					mu.Unlock()
@@ -630,17 +753,14 @@ func (s *NuboBoardService) SaveAttachments(param models.EditorSaveAttachedParam)
 				}
 
 				if err := s.repos.BoardEdit.InsertFileThumbnail(models.EditorSaveThumbnailParam{
-					BoardThumbnail: models.BoardThumbnail{
-						Large: publicLarge,
-						Small: publicSmall,
-					},
-					FileUid: fileUid,
-					PostUid: param.PostUid,
+					BoardThumbnail: publicThumb,
+					FileUid:        fileUid,
+					PostUid:        param.PostUid,
 				}); err != nil {
 					mu.Lock()
 					saved = append(saved, savedAttachment{
 						fileUid: fileUid, filePath: publicSavedPath,
-						extraPath: []string{thumb.Small, thumb.Large},
+						extraPath: []string{thumb.Small, thumb.Large, thumb.SmallFallback, thumb.LargeFallback},
 					})
 					errors = append(errors, err)
 					mu.Unlock()
@@ -651,7 +771,7 @@ func (s *NuboBoardService) SaveAttachments(param models.EditorSaveAttachedParam)
 					mu.Lock()
 					saved = append(saved, savedAttachment{
 						fileUid: fileUid, filePath: publicSavedPath,
-						extraPath: []string{thumb.Small, thumb.Large},
+						extraPath: []string{thumb.Small, thumb.Large, thumb.SmallFallback, thumb.LargeFallback},
 					})
 					errors = append(errors, err)
 					mu.Unlock()
@@ -659,7 +779,12 @@ func (s *NuboBoardService) SaveAttachments(param models.EditorSaveAttachedParam)
 				}
 
 				if _, shouldDescribe := descriptionCandidates[f]; shouldDescribe {
-					result, descriptionErr := s.requestImageDescription(param.Context, thumb.Small)
+					// OpenAI가 AVIF를 받지 않으므로 대체 형식 썸네일이 있으면 그것으로 설명 요청
+					describePath := thumb.Small
+					if thumb.SmallFallback != "" {
+						describePath = thumb.SmallFallback
+					}
+					result, descriptionErr := s.requestImageDescription(param.Context, describePath)
 					if descriptionErr != nil {
 						log.Printf("ai: image description failed post_uid=%d file_uid=%d model=%s: %v", param.PostUid, fileUid, s.imageDescriptionConfig.Model, descriptionErr)
 					} else if insertErr := s.repos.BoardEdit.InsertImageDescription(fileUid, param.PostUid, result.Description); insertErr != nil {
@@ -672,7 +797,7 @@ func (s *NuboBoardService) SaveAttachments(param models.EditorSaveAttachedParam)
 				mu.Lock()
 				saved = append(saved, savedAttachment{
 					fileUid: fileUid, filePath: publicSavedPath,
-					extraPath: []string{thumb.Small, thumb.Large},
+					extraPath: []string{thumb.Small, thumb.Large, thumb.SmallFallback, thumb.LargeFallback},
 				})
 				mu.Unlock()
 				return
@@ -737,18 +862,14 @@ func (s *NuboBoardService) SaveThumbnail(fileUid uint, postUid uint, path string
 	if err != nil {
 		return thumb
 	}
-	publicSmall, smallPathErr := utils.PublicUploadPath(thumb.Small)
-	publicLarge, largePathErr := utils.PublicUploadPath(thumb.Large)
-	if smallPathErr != nil || largePathErr != nil {
+	publicThumb, err := utils.PublicThumbnail(thumb)
+	if err != nil {
 		return models.BoardThumbnail{}
 	}
 	s.repos.BoardEdit.InsertFileThumbnail(models.EditorSaveThumbnailParam{
-		BoardThumbnail: models.BoardThumbnail{
-			Small: publicSmall,
-			Large: publicLarge,
-		},
-		FileUid: fileUid,
-		PostUid: postUid,
+		BoardThumbnail: publicThumb,
+		FileUid:        fileUid,
+		PostUid:        postUid,
 	})
 	return thumb
 }
@@ -769,7 +890,7 @@ func (s *NuboBoardService) UploadInsertImage(boardUid uint, userUid uint, images
 	}
 
 	userLv, userPt := s.repos.User.GetUserLevelPoint(userUid)
-	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(boardUid, models.BOARD_ACTION_WRITE)
+	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(boardUid, userUid, models.BOARD_ACTION_WRITE)
 	if userLv < needLv {
 		return imagePaths, fmt.Errorf("level restriction")
 	}
@@ -780,6 +901,7 @@ func (s *NuboBoardService) UploadInsertImage(boardUid uint, userUid uint, images
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	tempPaths := make([]string, 0)
+	savedImages := make([]models.SavedImage, 0)
 	errors := make([]error, 0)
 
 	for _, header := range images {
@@ -805,7 +927,7 @@ func (s *NuboBoardService) UploadInsertImage(boardUid uint, userUid uint, images
 				return
 			}
 
-			imagePath, err := utils.SaveInsertImage(tempPath)
+			saved, err := utils.SaveInsertImage(tempPath)
 			if err != nil {
 				mu.Lock()
 				errors = append(errors, err)
@@ -817,14 +939,15 @@ func (s *NuboBoardService) UploadInsertImage(boardUid uint, userUid uint, images
 
 			mu.Lock()
 			tempPaths = append(tempPaths, tempPath)
-			publicImagePath, pathErr := utils.PublicUploadPath(imagePath)
-			if pathErr != nil {
-				errors = append(errors, pathErr)
-				_ = os.Remove(imagePath)
+			publicImage, err := utils.PublicSavedImage(saved)
+			if err != nil {
+				errors = append(errors, err)
+				utils.RemoveSavedImage(saved)
 				mu.Unlock()
 				return
 			}
-			imagePaths = append(imagePaths, publicImagePath)
+			imagePaths = append(imagePaths, publicImage.Path)
+			savedImages = append(savedImages, publicImage)
 			mu.Unlock()
 
 		}(header)
@@ -842,12 +965,17 @@ func (s *NuboBoardService) UploadInsertImage(boardUid uint, userUid uint, images
 		return nil, errors[0]
 	}
 
-	if err := s.repos.BoardEdit.InsertImagePaths(boardUid, userUid, imagePaths); err != nil {
+	if err := s.repos.BoardEdit.InsertImagePaths(boardUid, userUid, savedImages); err != nil {
 		for _, imagePath := range imagePaths {
 			_ = utils.RemoveUploadFile(imagePath)
 		}
 		return nil, err
 	}
+	for _, image := range savedImages {
+		if err := s.repos.ImageVariant.InsertImageVariants(0, image.Path, image.Variants); err != nil {
+			log.Printf("image: failed to record responsive variants path=%s: %v", image.Path, err)
+		}
+	}
 
 	for _, tempPath := range tempPaths {
 		_ = os.Remove(tempPath)
@@ -872,17 +1000,23 @@ func (s *NuboBoardService) WritePost(param models.EditorWriteParam) (uint, error
 	}
 
 	userLv, userPt := s.repos.User.GetUserLevelPoint(param.UserUid)
-	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, models.BOARD_ACTION_WRITE)
+	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_WRITE)
 	if userLv < needLv {
 		return models.FAILED, fmt.Errorf("level restriction")
 	}
 	if needPt < 0 && userPt < utils.Abs(needPt) {
 		return models.FAILED, fmt.Errorf("not enough point")
 	}
-	if param.IsNotice {
-		if isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid); !isAdmin {
-			param.IsNotice = false
-		}
+	isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid)
+	if param.IsNotice && !isAdmin {
+		param.IsNotice = false
+	}
+	param.IsPending = s.repos.Board.GetBoardConfig(param.BoardUid).RequireApproval && !isAdmin
+	if err := s.filter.FilterPost(&param); err != nil {
+		return models.FAILED, err
+	}
+	if err := s.spam.CheckPost(&param); err != nil {
+		return models.FAILED, err
 	}
 
 	postUid, err := s.repos.BoardEdit.InsertPost(param, models.UpdatePointParam{
@@ -901,5 +1035,6 @@ func (s *NuboBoardService) WritePost(param models.EditorWriteParam) (uint, error
 		PostUid:  postUid,
 		Files:    param.Files,
 	})
+	s.federation.PublishPost(param.BoardUid, postUid, models.AP_CREATE)
 	return postUid, nil
 }
@@ -15,6 +15,9 @@ type ChatService interface {
 type NuboChatService struct {
 	repos         *repositories.Repository
 	notifications *notificationPublisher
+	filter        *contentFilter
+	spam          *spamGuard
+	links         *linkPreviewer
 }
 
 // 리포지토리 묶음 주입받기
@@ -36,7 +39,14 @@ func (s *NuboChatService) GetChattingHistory(actionUserUid uint, targetUserUid u
 	if s.hasBlockRelation(actionUserUid, targetUserUid) {
 		return []models.ChatHistory{}, nil
 	}
-	return s.repos.Chat.LoadChatHistory(actionUserUid, targetUserUid, limit)
+	history, err := s.repos.Chat.LoadChatHistory(actionUserUid, targetUserUid, limit)
+	if err != nil {
+		return history, err
+	}
+	for i := range history {
+		history[i].Previews = s.links.Previews(history[i].Message)
+	}
+	return history, nil
 }
 
 // 다른 사용자에게 쪽지 남기기
@@ -44,6 +54,13 @@ func (s *NuboChatService) SaveChatMessage(actionUserUid uint, targetUserUid uint
 	if s.hasBlockRelation(actionUserUid, targetUserUid) {
 		return 0
 	}
+	message, err := s.filter.Filter(models.FILTER_TARGET_CHAT, actionUserUid, message)
+	if err != nil {
+		return 0
+	}
+	if err := s.spam.Check(models.SPAM_TARGET_CHAT, actionUserUid, message); err != nil {
+		return 0
+	}
 	insertId := s.repos.Chat.InsertNewChat(actionUserUid, targetUserUid, utils.Escape(message))
 	parameter := models.InsertNotificationParam{
 		ActionUserUid: actionUserUid,
@@ -54,6 +71,7 @@ func (s *NuboChatService) SaveChatMessage(actionUserUid uint, targetUserUid uint
 	}
 	if insertId > 0 {
 		s.notifications.Save(parameter, false)
+		s.links.Previews(message)
 	}
 	return insertId
 }
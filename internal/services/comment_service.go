@@ -1,8 +1,11 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"github.com/sirini/goapi/internal/configs"
 	"github.com/sirini/goapi/internal/repositories"
@@ -12,6 +15,7 @@ import (
 )
 
 type CommentService interface {
+	History(param models.CommentHistoryParam) (models.CommentHistoryResult, error)
 	Like(param models.CommentLikeParam) error
 	List(param models.CommentListParam) (models.CommentListResult, error)
 	Modify(param models.CommentModifyParam) error
@@ -24,6 +28,8 @@ type NuboCommentService struct {
 	repos         *repositories.Repository
 	mailer        utils.Mailer
 	notifications *notificationPublisher
+	filter        *contentFilter
+	spam          *spamGuard
 }
 
 // 리포지토리 묶음 주입받기
@@ -70,16 +76,17 @@ func (s *NuboCommentService) List(param models.CommentListParam) (models.Comment
 		return result, fmt.Errorf("post does not belong to this board")
 	}
 	userLv, _ := s.repos.User.GetUserLevelPoint(param.UserUid)
-	needLv, _ := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, models.BOARD_ACTION_VIEW)
+	needLv, _ := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_VIEW)
 	if userLv < needLv {
 		return result, fmt.Errorf("level restriction")
 	}
 
 	status := s.repos.Comment.GetPostStatus(param.PostUid)
-	if status == models.CONTENT_SECRET {
+	if status == models.CONTENT_SECRET || status == models.CONTENT_PENDING {
 		isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid)
 		isAuthor := s.repos.BoardView.IsWriter(models.TABLE_POST, param.PostUid, param.UserUid)
-		if !isAdmin && !isAuthor {
+		isShared := status == models.CONTENT_SECRET && s.canCommentByShare(param.ShareToken, param.PostUid)
+		if !isAdmin && !isAuthor && !isShared {
 			return result, fmt.Errorf("you have no permission to read comments on this post")
 		}
 	}
@@ -87,16 +94,57 @@ func (s *NuboCommentService) List(param models.CommentListParam) (models.Comment
 		return result, fmt.Errorf("post has been removed")
 	}
 
+	if param.Sort > models.COMMENT_SORT_LIKES {
+		return result, fmt.Errorf("invalid sort option")
+	}
+	if param.ParentUid > 0 && !s.repos.Comment.IsCommentInPost(param.ParentUid, param.PostUid, param.BoardUid) {
+		return result, fmt.Errorf("parent comment does not belong to this post")
+	}
+	if param.Page < 1 {
+		param.Page = 1
+	}
+	if param.Limit < 1 || param.Limit > 100 {
+		param.Limit = 20
+	}
+	maxDepth := s.repos.Board.GetBoardConfig(param.BoardUid).CommentDepth
+	maxDepth, _ = normalizeCommentDepth(maxDepth)
+	expand := param.Expand
+	if expand == 0 || expand > maxDepth {
+		expand = maxDepth
+	}
+
 	result.BoardUid = param.BoardUid
 	result.TotalCommentCount = s.repos.Board.GetCommentCount(param.PostUid)
-	comments, err := s.repos.Comment.GetComments(param)
+	result.ThreadCount = s.repos.Comment.GetThreadCount(param.PostUid, param.ParentUid)
+	comments, err := s.repos.Comment.GetComments(param, expand)
 	if err != nil {
 		return result, err
 	}
+	isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid)
+	for i := range comments {
+		switch {
+		case comments[i].Status == models.CONTENT_REMOVED:
+			comments[i].Content = ""
+			comments[i].Writer = models.BoardWriter{}
+			comments[i].Like, comments[i].Liked = 0, false
+		case comments[i].Status == models.CONTENT_SECRET && !isAdmin && comments[i].Writer.UserUid != param.UserUid:
+			comments[i].Content = ""
+			comments[i].Hidden = true
+		}
+	}
 	result.Comments = comments
 	return result, nil
 }
 
+// 작성자가 댓글을 허용한 공유 링크인지 확인
+func (s *NuboCommentService) canCommentByShare(token string, postUid uint) bool {
+	if token == "" {
+		return false
+	}
+	share, err := findActiveShare(s.repos.Share, token)
+	return err == nil && share.PostUid == postUid && share.AllowComment
+}
+
 // 기존 댓글 수정하기
 func (s *NuboCommentService) Modify(param models.CommentModifyParam) error {
 	if !s.repos.Comment.IsCommentInPost(param.ModifyTargetUid, param.PostUid, param.BoardUid) {
@@ -107,8 +155,63 @@ func (s *NuboCommentService) Modify(param models.CommentModifyParam) error {
 	if !isAdmin && !isAuthor {
 		return fmt.Errorf("you have no permission to edit this comment")
 	}
-	s.repos.Comment.UpdateComment(param.ModifyTargetUid, param.Content)
-	return nil
+	content, err := s.filter.Filter(models.FILTER_TARGET_COMMENT, param.UserUid, param.Content)
+	held := errors.Is(err, ErrContentHeld)
+	if err != nil && !held {
+		return err
+	}
+	if err := s.repos.Comment.UpdateComment(param.ModifyTargetUid, param.UserUid, content); err != nil || !held {
+		return err
+	}
+	_, writerUid := s.repos.Comment.FindPostUserUidByUid(param.ModifyTargetUid)
+	return s.repos.Report.HoldContent(models.REPORT_TARGET_COMMENT, param.ModifyTargetUid, writerUid, models.REPORT_REASON_FILTER)
+}
+
+// 댓글 수정 이력 가져오기 (게시판 정책에 따라 관리자 또는 모두에게 공개)
+func (s *NuboCommentService) History(param models.CommentHistoryParam) (models.CommentHistoryResult, error) {
+	result := models.CommentHistoryResult{CommentUid: param.CommentUid}
+	if !s.repos.Comment.IsCommentInBoard(param.CommentUid, param.BoardUid) {
+		return result, fmt.Errorf("comment does not belong to this board")
+	}
+	isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid)
+	if !isAdmin {
+		if !s.repos.Board.GetBoardConfig(param.BoardUid).PublicCommentHistory {
+			return result, fmt.Errorf("edit history is only visible to board managers")
+		}
+		userLv, _ := s.repos.User.GetUserLevelPoint(param.UserUid)
+		needLv, _ := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_VIEW)
+		if userLv < needLv {
+			return result, fmt.Errorf("level restriction")
+		}
+		thread, err := s.repos.Comment.GetCommentThread(param.CommentUid)
+		if err != nil {
+			return result, err
+		}
+		isAuthor := s.repos.BoardView.IsWriter(models.TABLE_COMMENT, param.CommentUid, param.UserUid)
+		switch thread.Status {
+		case models.CONTENT_REMOVED:
+			return result, fmt.Errorf("comment has been removed")
+		case models.CONTENT_SECRET:
+			if !isAuthor {
+				return result, fmt.Errorf("you have no permission to read this comment")
+			}
+		}
+		switch s.repos.Comment.GetPostStatus(thread.PostUid) {
+		case models.CONTENT_REMOVED:
+			return result, fmt.Errorf("post has been removed")
+		case models.CONTENT_SECRET, models.CONTENT_PENDING:
+			if !s.repos.BoardView.IsWriter(models.TABLE_POST, thread.PostUid, param.UserUid) {
+				return result, fmt.Errorf("you have no permission to read comments on this post")
+			}
+		}
+	}
+
+	history, err := s.repos.Comment.GetCommentHistory(param.CommentUid)
+	if err != nil {
+		return result, err
+	}
+	result.History = history
+	return result, nil
 }
 
 // 댓글 삭제하기
@@ -122,12 +225,8 @@ func (s *NuboCommentService) Remove(param models.CommentRemoveParam) error {
 		return fmt.Errorf("you have no permission to remove this comment")
 	}
 
-	if hasReply := s.repos.Comment.HasReplyComment(param.RemoveTargetUid); hasReply {
-		s.repos.Comment.UpdateComment(param.RemoveTargetUid, "(deleted)")
-	} else {
-		s.repos.Comment.RemoveComment(param.RemoveTargetUid)
-	}
-	return nil
+	// 답글이 달린 댓글도 삭제 상태로 바꾸면 목록에서 자리 표시용으로만 남음
+	return s.repos.Comment.RemoveComment(param.RemoveTargetUid)
 }
 
 // 새로운 답글 작성하기
@@ -135,7 +234,48 @@ func (s *NuboCommentService) Reply(param models.CommentReplyParam) (uint, error)
 	if !s.repos.Comment.IsCommentInPost(param.ReplyTargetUid, param.PostUid, param.BoardUid) {
 		return models.FAILED, fmt.Errorf("reply target does not belong to this post")
 	}
-	return s.write(param.CommentWriteParam, param.ReplyTargetUid)
+	target, err := s.repos.Comment.GetCommentThread(param.ReplyTargetUid)
+	if err != nil {
+		return models.FAILED, err
+	}
+	if target.Status == models.CONTENT_REMOVED {
+		return models.FAILED, fmt.Errorf("replying to a removed comment is not allowed")
+	}
+
+	// 게시판 최대 깊이를 넘으면 허용되는 가장 깊은 조상 아래에 답글을 붙임
+	maxDepth, _ := normalizeCommentDepth(s.repos.Board.GetBoardConfig(param.BoardUid).CommentDepth)
+	parentUid := target.Uid
+	if target.Depth+1 > maxDepth {
+		parentUid = commentAncestorAt(target.Path, maxDepth-1)
+		if parentUid == 0 {
+			return models.FAILED, fmt.Errorf("invalid reply target")
+		}
+	}
+	return s.write(param.CommentWriteParam, parentUid)
+}
+
+// 댓글 경로에서 지정한 깊이(0부터 시작)의 조상 고유 번호 찾기
+func commentAncestorAt(path string, depth uint) uint {
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if int(depth) >= len(segments) {
+		return 0
+	}
+	uid, err := strconv.ParseUint(segments[depth], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(uid)
+}
+
+// 게시판 답글 깊이 설정값 확인하기 (0이면 기본값 사용)
+func normalizeCommentDepth(depth uint) (uint, error) {
+	if depth == 0 {
+		return models.COMMENT_DEPTH_DEFAULT, nil
+	}
+	if depth > models.COMMENT_DEPTH_MAX {
+		return models.COMMENT_DEPTH_MAX, fmt.Errorf("comment depth must be %d or less", models.COMMENT_DEPTH_MAX)
+	}
+	return depth, nil
 }
 
 // 새로운 댓글 작성하기
@@ -143,7 +283,7 @@ func (s *NuboCommentService) Write(param models.CommentWriteParam) (uint, error)
 	return s.write(param, 0)
 }
 
-func (s *NuboCommentService) write(param models.CommentWriteParam, replyUid uint) (uint, error) {
+func (s *NuboCommentService) write(param models.CommentWriteParam, parentUid uint) (uint, error) {
 	if !s.repos.BoardView.IsPostInBoard(param.PostUid, param.BoardUid) {
 		return models.FAILED, fmt.Errorf("post does not belong to this board")
 	}
@@ -153,19 +293,43 @@ func (s *NuboCommentService) write(param models.CommentWriteParam, replyUid uint
 	if isBanned := s.repos.BoardView.CheckBannedByWriter(param.PostUid, param.UserUid); isBanned {
 		return models.FAILED, fmt.Errorf("you have been blocked by writer")
 	}
-	if status := s.repos.Comment.GetPostStatus(param.PostUid); status == models.CONTENT_REMOVED {
+	switch s.repos.Comment.GetPostStatus(param.PostUid) {
+	case models.CONTENT_REMOVED:
 		return models.FAILED, fmt.Errorf("leaving a comment on a removed post is not allowed")
+	case models.CONTENT_PENDING:
+		return models.FAILED, fmt.Errorf("leaving a comment on a post waiting for approval is not allowed")
+	case models.CONTENT_SECRET:
+		isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid)
+		isAuthor := s.repos.BoardView.IsWriter(models.TABLE_POST, param.PostUid, param.UserUid)
+		if !isAdmin && !isAuthor && !s.canCommentByShare(param.ShareToken, param.PostUid) {
+			return models.FAILED, fmt.Errorf("you have no permission to comment on this post")
+		}
 	}
 
 	userLv, userPt := s.repos.User.GetUserLevelPoint(param.UserUid)
-	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, models.BOARD_ACTION_COMMENT)
+	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_COMMENT)
 	if userLv < needLv {
 		return models.FAILED, fmt.Errorf("level restriction")
 	}
 	if needPt < 0 && userPt < utils.Abs(needPt) {
 		return models.FAILED, fmt.Errorf("not enough point")
 	}
-	insertId, err := s.repos.Comment.InsertComment(param, replyUid, models.UpdatePointParam{
+	content, err := s.filter.Filter(models.FILTER_TARGET_COMMENT, param.UserUid, param.Content)
+	held := errors.Is(err, ErrContentHeld)
+	if err != nil && !held {
+		return models.FAILED, err
+	}
+	reason := models.REPORT_REASON_FILTER
+	switch err := s.spam.Check(models.SPAM_TARGET_COMMENT, param.UserUid, content); {
+	case errors.Is(err, ErrSpamHeld):
+		if !held {
+			held, reason = true, models.REPORT_REASON_SPAM
+		}
+	case err != nil:
+		return models.FAILED, err
+	}
+	param.Content = content
+	insertId, err := s.repos.Comment.InsertComment(param, parentUid, models.UpdatePointParam{
 		UserUid:  param.UserUid,
 		BoardUid: param.BoardUid,
 		Action:   models.POINT_ACTION_COMMENT,
@@ -174,6 +338,10 @@ func (s *NuboCommentService) write(param models.CommentWriteParam, replyUid uint
 	if err != nil {
 		return models.FAILED, err
 	}
+	if held {
+		// 보류된 댓글은 검토 전까지 숨기고 관리자 신고 목록에 올리며, 알림도 보내지 않음
+		return insertId, s.repos.Report.HoldContent(models.REPORT_TARGET_COMMENT, insertId, param.UserUid, reason)
+	}
 
 	targetUserUid := s.repos.Comment.GetPostWriterUid(param.PostUid)
 	if param.UserUid != targetUserUid {
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type threadAuthRepo struct{ repositories.AuthRepository }
+
+func (threadAuthRepo) CheckPermissionByUid(uint, uint) bool                  { return false }
+func (threadAuthRepo) CheckPermissionForAction(uint, models.UserAction) bool { return true }
+
+type threadBoardRepo struct{ repositories.BoardRepository }
+
+func (threadBoardRepo) GetBoardConfig(boardUid uint) models.BoardConfig {
+	return models.BoardConfig{Uid: boardUid, CommentDepth: 2}
+}
+func (threadBoardRepo) GetCommentCount(uint) uint { return 4 }
+
+type threadViewRepo struct {
+	repositories.BoardViewRepository
+}
+
+func (threadViewRepo) IsPostInBoard(uint, uint) bool       { return true }
+func (threadViewRepo) CheckBannedByWriter(uint, uint) bool { return false }
+func (threadViewRepo) GetNeededLevelPoint(uint, uint, models.BoardAction) (int, int) {
+	return 0, 0
+}
+
+type threadUserRepo struct{ repositories.UserRepository }
+
+func (threadUserRepo) GetUserLevelPoint(uint) (int, int) { return 1, 0 }
+
+type threadCommentRepo struct {
+	repositories.CommentRepository
+	threads  map[uint]models.CommentThread
+	parent   uint
+	expand   uint
+	comments []models.CommentItem
+}
+
+func (r *threadCommentRepo) IsCommentInPost(commentUid uint, _ uint, _ uint) bool {
+	_, ok := r.threads[commentUid]
+	return ok
+}
+func (r *threadCommentRepo) GetCommentThread(commentUid uint) (models.CommentThread, error) {
+	return r.threads[commentUid], nil
+}
+func (r *threadCommentRepo) GetPostStatus(uint) models.Status { return models.CONTENT_NORMAL }
+func (r *threadCommentRepo) GetPostWriterUid(uint) uint       { return 1 }
+func (r *threadCommentRepo) GetThreadCount(uint, uint) uint   { return 1 }
+func (r *threadCommentRepo) InsertComment(_ models.CommentWriteParam, parentUid uint, _ models.UpdatePointParam) (uint, error) {
+	r.parent = parentUid
+	return 99, nil
+}
+func (r *threadCommentRepo) GetComments(_ models.CommentListParam, expand uint) ([]models.CommentItem, error) {
+	r.expand = expand
+	return r.comments, nil
+}
+
+func TestCommentThreadDepthAndPlaceholder(t *testing.T) {
+	comments := &threadCommentRepo{threads: map[uint]models.CommentThread{
+		10: {Uid: 10, Depth: 0, Path: "0000000010/"},
+		11: {Uid: 11, ParentUid: 10, Depth: 1, Path: "0000000010/0000000011/"},
+		12: {Uid: 12, ParentUid: 11, Depth: 2, Path: "0000000010/0000000011/0000000012/"},
+		13: {Uid: 13, Depth: 0, Path: "0000000013/", Status: models.CONTENT_REMOVED},
+	}}
+	service := &NuboCommentService{repos: &repositories.Repository{
+		Auth:      threadAuthRepo{},
+		Board:     threadBoardRepo{},
+		BoardView: threadViewRepo{},
+		Comment:   comments,
+		User:      threadUserRepo{},
+	}}
+	reply := func(target uint) (uint, error) {
+		param := models.CommentReplyParam{ReplyTargetUid: target}
+		param.BoardUid, param.PostUid, param.UserUid, param.Content = 1, 2, 1, "reply"
+		return service.Reply(param)
+	}
+
+	if _, err := reply(11); err != nil || comments.parent != 11 {
+		t.Fatalf("reply within depth should attach to target, got parent %d err %v", comments.parent, err)
+	}
+	if _, err := reply(12); err != nil || comments.parent != 11 {
+		t.Fatalf("reply beyond board depth should attach to deepest allowed ancestor, got parent %d err %v", comments.parent, err)
+	}
+	if _, err := reply(13); err == nil {
+		t.Fatal("reply to a removed comment should be rejected")
+	}
+
+	removed := models.CommentItem{Uid: 13, Status: models.CONTENT_REMOVED, Content: "secret words", ReplyCount: 1}
+	removed.Writer.UserUid, removed.Writer.Name = 5, "writer"
+	comments.comments = []models.CommentItem{removed}
+	result, err := service.List(models.CommentListParam{BoardUid: 1, PostUid: 2, Sort: models.COMMENT_SORT_LIKES})
+	if err != nil {
+		t.Fatalf("list comments: %v", err)
+	}
+	if comments.expand != 2 {
+		t.Fatalf("default expand should follow board depth, got %d", comments.expand)
+	}
+	if got := result.Comments[0]; got.Content != "" || got.Status != models.CONTENT_REMOVED || got.Writer.UserUid != 0 || got.ReplyCount != 1 {
+		t.Fatalf("removed parent should stay as a placeholder, got %+v", got)
+	}
+
+	hidden := models.CommentItem{Uid: 14, Status: models.CONTENT_SECRET, Content: "reported words"}
+	hidden.Writer.UserUid = 5
+	comments.comments = []models.CommentItem{hidden}
+	result, err = service.List(models.CommentListParam{BoardUid: 1, PostUid: 2, UserUid: 3})
+	if err != nil {
+		t.Fatalf("list comments: %v", err)
+	}
+	if got := result.Comments[0]; !got.Hidden || got.Content != "" {
+		t.Fatalf("hidden comment should be flagged with an empty body, got %+v", got)
+	}
+	comments.comments = []models.CommentItem{hidden}
+	if result, err = service.List(models.CommentListParam{BoardUid: 1, PostUid: 2, UserUid: 5}); err != nil || result.Comments[0].Hidden {
+		t.Fatalf("writer should still see their hidden comment, got %+v %v", result.Comments, err)
+	}
+	if _, err := service.List(models.CommentListParam{BoardUid: 1, PostUid: 2, Sort: 9}); err == nil {
+		t.Fatal("unknown sort mode should be rejected")
+	}
+}
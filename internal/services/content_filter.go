@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+var (
+	ErrContentBlocked = errors.New("content contains blocked words")
+	ErrContentHeld    = errors.New("content is held for review")
+)
+
+const filterExcerptPadding = 40
+
+// 관리자가 등록한 금지어 필터 규칙들을 메모리에 들고 있다가 작성 내용에 적용
+type contentFilter struct {
+	repo     repositories.ContentFilterRepository
+	mu       sync.RWMutex
+	matchers []*utils.ContentMatcher
+	loaded   bool
+}
+
+func newContentFilter(repo repositories.ContentFilterRepository) *contentFilter {
+	return &contentFilter{repo: repo}
+}
+
+// 활성화된 필터 규칙들 다시 불러오기 (잘못된 규칙은 건너뜀)
+func (f *contentFilter) Reload() error {
+	rules, err := f.repo.GetFilterRules(true)
+	if err != nil {
+		return err
+	}
+	matchers := make([]*utils.ContentMatcher, 0, len(rules))
+	for _, rule := range rules {
+		matcher, err := utils.NewContentMatcher(rule)
+		if err != nil {
+			log.Printf("filter: skipped rule %d: %v", rule.Uid, err)
+			continue
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	f.mu.Lock()
+	f.matchers = matchers
+	f.loaded = true
+	f.mu.Unlock()
+	return nil
+}
+
+// 텍스트에 필터를 적용해서 가림 처리된 텍스트와 가장 강한 처리 방식 반환하기
+func (f *contentFilter) Check(target models.FilterTarget, userUid uint, text string) (string, models.FilterAction) {
+	if f == nil || len(text) == 0 {
+		return text, models.FILTER_ACTION_NONE
+	}
+	f.mu.RLock()
+	loaded := f.loaded
+	f.mu.RUnlock()
+	if !loaded {
+		if err := f.Reload(); err != nil {
+			log.Printf("filter: failed to load rules: %v", err)
+			return text, models.FILTER_ACTION_NONE
+		}
+	}
+
+	f.mu.RLock()
+	matchers := f.matchers
+	f.mu.RUnlock()
+
+	action := models.FILTER_ACTION_NONE
+	masks := make([]utils.FilterSpan, 0)
+	for _, matcher := range matchers {
+		spans := matcher.Find(text)
+		if len(spans) == 0 {
+			continue
+		}
+		if matcher.Rule.Action > action {
+			action = matcher.Rule.Action
+		}
+		if matcher.Rule.Action == models.FILTER_ACTION_MASK {
+			masks = append(masks, spans...)
+		}
+		if err := f.repo.InsertFilterLog(models.ContentFilterLog{
+			FilterUid: matcher.Rule.Uid,
+			Target:    target,
+			Action:    matcher.Rule.Action,
+			UserUid:   userUid,
+			Excerpt:   filterExcerpt(text, spans[0]),
+		}); err != nil {
+			log.Printf("filter: failed to log hit for rule %d: %v", matcher.Rule.Uid, err)
+		}
+	}
+	return utils.MaskFilterSpans(text, masks), action
+}
+
+// 댓글, 쪽지, 이름, 서명에 필터 적용하기 (보류 대상은 가림 처리된 텍스트와 ErrContentHeld 반환,
+// 검토 목록에 올릴 수 있는 댓글 외에는 호출한 쪽에서 거부)
+func (f *contentFilter) Filter(target models.FilterTarget, userUid uint, text string) (string, error) {
+	filtered, action := f.Check(target, userUid, text)
+	switch action {
+	case models.FILTER_ACTION_BLOCK:
+		return "", ErrContentBlocked
+	case models.FILTER_ACTION_HOLD:
+		return filtered, ErrContentHeld
+	}
+	return filtered, nil
+}
+
+// 게시글 제목과 본문에 필터 적용하기 (보류 대상은 관리자 검토 전까지 승인 대기열에 저장)
+func (f *contentFilter) FilterPost(param *models.EditorWriteParam) error {
+	title, titleAction := f.Check(models.FILTER_TARGET_POST_TITLE, param.UserUid, param.Title)
+	content, contentAction := f.Check(models.FILTER_TARGET_POST_CONTENT, param.UserUid, param.Content)
+	if titleAction == models.FILTER_ACTION_BLOCK || contentAction == models.FILTER_ACTION_BLOCK {
+		return ErrContentBlocked
+	}
+	if titleAction == models.FILTER_ACTION_HOLD || contentAction == models.FILTER_ACTION_HOLD {
+		param.IsPending = true
+	}
+	param.Title = title
+	param.Content = content
+	return nil
+}
+
+// 적중 구간 앞뒤를 조금 포함한 기록용 발췌문 만들기
+func filterExcerpt(text string, span utils.FilterSpan) string {
+	runes := []rune(text)
+	start := max(span.Start-filterExcerptPadding, 0)
+	end := min(span.End+filterExcerptPadding, len(runes))
+	return string(runes[start:end])
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type filterRuleRepo struct {
+	repositories.ContentFilterRepository
+	rules []models.ContentFilterRule
+	logs  []models.ContentFilterLog
+}
+
+func (r *filterRuleRepo) GetFilterRules(enabledOnly bool) ([]models.ContentFilterRule, error) {
+	return r.rules, nil
+}
+
+func (r *filterRuleRepo) InsertFilterLog(log models.ContentFilterLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func TestContentFilterMasksAndLogsHits(t *testing.T) {
+	repo := &filterRuleRepo{rules: []models.ContentFilterRule{
+		{Uid: 1, Pattern: "바보", Match: models.FILTER_MATCH_JAMO, Action: models.FILTER_ACTION_MASK, Enabled: true},
+	}}
+	filter := newContentFilter(repo)
+
+	text, err := filter.Filter(models.FILTER_TARGET_COMMENT, 7, "너 바 보 같아")
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if text != "너 * * 같아" {
+		t.Fatalf("masked text = %q", text)
+	}
+	if len(repo.logs) != 1 || repo.logs[0].FilterUid != 1 || repo.logs[0].UserUid != 7 {
+		t.Fatalf("unexpected logs: %+v", repo.logs)
+	}
+}
+
+func TestContentFilterHoldsAndBlocksPosts(t *testing.T) {
+	repo := &filterRuleRepo{rules: []models.ContentFilterRule{
+		{Uid: 1, Pattern: `casino\.example`, Match: models.FILTER_MATCH_REGEX, Action: models.FILTER_ACTION_HOLD, Enabled: true},
+		{Uid: 2, Pattern: "scam", Match: models.FILTER_MATCH_EXACT, Action: models.FILTER_ACTION_BLOCK, Enabled: true},
+	}}
+	filter := newContentFilter(repo)
+
+	param := models.EditorWriteParam{Title: "hello", Content: "visit casino.example", IsNotice: true}
+	if err := filter.FilterPost(&param); err != nil {
+		t.Fatalf("filter post: %v", err)
+	}
+	if !param.IsPending || param.IsSecret || !param.IsNotice {
+		t.Fatalf("held post should wait for approval with its original status: %+v", param)
+	}
+
+	param = models.EditorWriteParam{Title: "SCAM alert", Content: "body"}
+	if err := filter.FilterPost(&param); !errors.Is(err, ErrContentBlocked) {
+		t.Fatalf("expected blocked error, got %v", err)
+	}
+	if _, err := filter.Filter(models.FILTER_TARGET_CHAT, 1, "casino.example"); !errors.Is(err, ErrContentHeld) {
+		t.Fatalf("expected held error, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+const reportRequestMaxRunes = 500
+
+// 게시글이나 댓글 신고하기 (서로 다른 신고자가 기준 이상이면 일반글, 공지글 모두 검토 전까지 숨김)
+func (s *NuboBoardService) ReportContent(param models.ContentReportParam) error {
+	if param.Target != models.REPORT_TARGET_POST && param.Target != models.REPORT_TARGET_COMMENT {
+		return fmt.Errorf("invalid report target")
+	}
+	if param.Reason > models.REPORT_REASON_COPYRIGHT {
+		return fmt.Errorf("invalid report reason")
+	}
+	if hasPerm := s.repos.Auth.CheckPermissionForAction(param.UserUid, models.USER_ACTION_SEND_REPORT); !hasPerm {
+		return fmt.Errorf("you have no permission to send a report")
+	}
+
+	info, err := s.repos.Report.GetReportTarget(param.Target, param.TargetUid)
+	if err != nil || info.Status == models.CONTENT_REMOVED {
+		return fmt.Errorf("reported content does not exist")
+	}
+	if info.WriterUid == param.UserUid {
+		return fmt.Errorf("you cannot report your own content")
+	}
+	if s.repos.Report.IsContentReported(param.Target, param.TargetUid, param.UserUid) {
+		return fmt.Errorf("you have already reported this content")
+	}
+
+	request := []rune(strings.TrimSpace(param.Content))
+	if len(request) > reportRequestMaxRunes {
+		request = request[:reportRequestMaxRunes]
+	}
+	param.Content = utils.Escape(string(request))
+	if err := s.repos.Report.InsertContentReport(param, info.WriterUid); err != nil {
+		return err
+	}
+
+	threshold := configs.GetReportHideThreshold()
+	if threshold < 1 || (info.Status != models.CONTENT_NORMAL && info.Status != models.CONTENT_NOTICE) {
+		return nil
+	}
+	reporters, err := s.repos.Report.CountContentReporters(param.Target, param.TargetUid)
+	if err != nil || reporters < uint(threshold) {
+		return nil
+	}
+	if err := s.repos.Report.HideReportedContent(param.Target, param.TargetUid, info.Status); err != nil {
+		log.Printf("report: failed to hide reported content %d: %v", param.TargetUid, err)
+		return nil
+	}
+	if param.Target == models.REPORT_TARGET_POST {
+		s.related.Invalidate(param.TargetUid)
+		s.federation.PublishPost(info.BoardUid, param.TargetUid, models.AP_DELETE)
+	}
+	return nil
+}
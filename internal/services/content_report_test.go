@@ -0,0 +1,246 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type reportAuthRepo struct{ repositories.AuthRepository }
+
+func (reportAuthRepo) CheckPermissionForAction(uint, models.UserAction) bool { return true }
+
+type contentReportRepo struct {
+	repositories.ReportRepository
+	info         models.ReportTargetInfo
+	reporters    map[uint]bool
+	hidden       bool
+	hiddenStatus models.Status
+	resolved     bool
+}
+
+func (r *contentReportRepo) GetReportTarget(models.ReportTarget, uint) (models.ReportTargetInfo, error) {
+	return r.info, nil
+}
+
+func (r *contentReportRepo) IsContentReported(_ models.ReportTarget, _ uint, userUid uint) bool {
+	return r.reporters[userUid]
+}
+
+func (r *contentReportRepo) InsertContentReport(param models.ContentReportParam, writerUid uint) error {
+	r.reporters[param.UserUid] = true
+	return nil
+}
+
+func (r *contentReportRepo) CountContentReporters(models.ReportTarget, uint) (uint, error) {
+	return uint(len(r.reporters)), nil
+}
+
+func (r *contentReportRepo) HideReportedContent(_ models.ReportTarget, _ uint, status models.Status) error {
+	r.hidden = true
+	r.hiddenStatus = status
+	r.info.Status = models.CONTENT_SECRET
+	return nil
+}
+
+func (r *contentReportRepo) GetHiddenStatus(models.ReportTarget, uint) (models.Status, bool) {
+	return r.hiddenStatus, r.hidden && !r.resolved
+}
+
+func (r *contentReportRepo) UpdateContentStatus(_ models.ReportTarget, _ uint, status models.Status) error {
+	r.info.Status = status
+	return nil
+}
+
+func (r *contentReportRepo) ResolveContentReports(models.ReportTarget, uint, string) error {
+	r.resolved = true
+	return nil
+}
+
+func TestReportContentHidesAfterThreshold(t *testing.T) {
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.ReportHideThreshold = "2"
+
+	reports := &contentReportRepo{
+		info:      models.ReportTargetInfo{BoardUid: 1, PostUid: 3, WriterUid: 9, Status: models.CONTENT_NORMAL},
+		reporters: make(map[uint]bool),
+	}
+	service := &NuboBoardService{repos: &repositories.Repository{Auth: reportAuthRepo{}, Report: reports}}
+	param := models.ContentReportParam{Target: models.REPORT_TARGET_COMMENT, TargetUid: 5, Reason: models.REPORT_REASON_SPAM}
+
+	param.UserUid = 9
+	if err := service.ReportContent(param); err == nil {
+		t.Fatal("writer should not be able to report own content")
+	}
+	param.UserUid = 2
+	if err := service.ReportContent(param); err != nil {
+		t.Fatalf("first report: %v", err)
+	}
+	if err := service.ReportContent(param); err == nil {
+		t.Fatal("duplicated report should be rejected")
+	}
+	if reports.hidden {
+		t.Fatal("content hidden before threshold")
+	}
+	param.UserUid = 3
+	if err := service.ReportContent(param); err != nil {
+		t.Fatalf("second report: %v", err)
+	}
+	if !reports.hidden {
+		t.Fatal("content should be hidden once threshold is reached")
+	}
+	param.Reason = models.REPORT_REASON_COPYRIGHT + 1
+	param.UserUid = 4
+	if err := service.ReportContent(param); err == nil {
+		t.Fatal("unknown reason should be rejected")
+	}
+}
+
+type heldReportRepo struct {
+	repositories.ReportRepository
+	held map[uint]models.ReportReason
+}
+
+func (r *heldReportRepo) HoldContent(target models.ReportTarget, targetUid uint, _ uint, reason models.ReportReason) error {
+	if target == models.REPORT_TARGET_COMMENT {
+		r.held[targetUid] = reason
+	}
+	return nil
+}
+
+type heldViewRepo struct {
+	repositories.BoardViewRepository
+}
+
+func (heldViewRepo) IsPostInBoard(uint, uint) bool                                 { return true }
+func (heldViewRepo) CheckBannedByWriter(uint, uint) bool                           { return false }
+func (heldViewRepo) GetNeededLevelPoint(uint, uint, models.BoardAction) (int, int) { return 0, 0 }
+
+type heldCommentRepo struct{ repositories.CommentRepository }
+
+func (heldCommentRepo) GetPostStatus(uint) models.Status { return models.CONTENT_NORMAL }
+func (heldCommentRepo) GetPostWriterUid(uint) uint       { return 1 }
+func (heldCommentRepo) InsertComment(models.CommentWriteParam, uint, models.UpdatePointParam) (uint, error) {
+	return 99, nil
+}
+
+type heldUserRepo struct{ repositories.UserRepository }
+
+func (heldUserRepo) GetUserLevelPoint(uint) (int, int) { return 1, 0 }
+
+func TestHeldCommentIsHiddenForReview(t *testing.T) {
+	reports := &heldReportRepo{held: map[uint]models.ReportReason{}}
+	service := &NuboCommentService{
+		repos: &repositories.Repository{
+			Auth:      reportAuthRepo{},
+			BoardView: heldViewRepo{},
+			Comment:   heldCommentRepo{},
+			Report:    reports,
+			User:      heldUserRepo{},
+		},
+		filter: newContentFilter(&filterRuleRepo{rules: []models.ContentFilterRule{
+			{Uid: 1, Pattern: `casino\.example`, Match: models.FILTER_MATCH_REGEX, Action: models.FILTER_ACTION_HOLD, Enabled: true},
+		}}),
+	}
+	param := models.CommentWriteParam{BoardUid: 1, PostUid: 2, UserUid: 1, Content: "visit casino.example"}
+	uid, err := service.Write(param)
+	if err != nil || uid != 99 {
+		t.Fatalf("held comment should be saved, got %d %v", uid, err)
+	}
+	if reason, ok := reports.held[99]; !ok || reason != models.REPORT_REASON_FILTER {
+		t.Fatalf("held comment should be queued for review, got %+v", reports.held)
+	}
+	param.Content = "hello"
+	if _, err := service.Write(param); err != nil || len(reports.held) != 1 {
+		t.Fatalf("clean comment should not be held, got %+v %v", reports.held, err)
+	}
+}
+
+func TestReportedNoticeIsHiddenAndRestoredOnDismiss(t *testing.T) {
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.ReportHideThreshold = "1"
+
+	reports := &contentReportRepo{
+		info:      models.ReportTargetInfo{BoardUid: 1, PostUid: 3, WriterUid: 9, Status: models.CONTENT_NOTICE},
+		reporters: make(map[uint]bool),
+	}
+	service := &NuboBoardService{repos: &repositories.Repository{Auth: reportAuthRepo{}, Report: reports}}
+	if err := service.ReportContent(models.ContentReportParam{Target: models.REPORT_TARGET_POST, TargetUid: 3, UserUid: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if !reports.hidden || reports.hiddenStatus != models.CONTENT_NOTICE || reports.info.Status != models.CONTENT_SECRET {
+		t.Fatalf("reported notices should be hidden too, got %+v", reports)
+	}
+
+	admin := &NuboAdminService{repos: &repositories.Repository{Report: reports}}
+	if err := admin.ResolveContentReport(1, models.AdminContentReportResolveParam{
+		Target: models.REPORT_TARGET_POST, TargetUid: 3, Action: models.REPORT_RESOLVE_DISMISS,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if reports.info.Status != models.CONTENT_NOTICE || !reports.resolved {
+		t.Fatalf("dismissing should restore the notice, got %+v", reports.info)
+	}
+}
+
+type hiddenEditBoardRepo struct{ repositories.BoardRepository }
+
+func (hiddenEditBoardRepo) GetBoardConfig(boardUid uint) models.BoardConfig {
+	return models.BoardConfig{Uid: boardUid, Type: models.BOARD_BOARD}
+}
+
+type hiddenEditAuthRepo struct{ repositories.AuthRepository }
+
+func (hiddenEditAuthRepo) CheckPermissionByUid(uint, uint) bool                  { return false }
+func (hiddenEditAuthRepo) CheckPermissionForAction(uint, models.UserAction) bool { return true }
+
+type hiddenEditViewRepo struct {
+	repositories.BoardViewRepository
+}
+
+func (hiddenEditViewRepo) IsPostInBoard(uint, uint) bool          { return true }
+func (hiddenEditViewRepo) IsWriter(models.Table, uint, uint) bool { return true }
+func (hiddenEditViewRepo) RemovePostTags(uint)                    {}
+
+type hiddenEditRepo struct {
+	repositories.BoardEditRepository
+	updated models.EditorModifyParam
+}
+
+func (r *hiddenEditRepo) UpdatePost(param models.EditorModifyParam) error {
+	r.updated = param
+	return nil
+}
+
+func TestEditingReportHiddenPostKeepsItHidden(t *testing.T) {
+	edit := &hiddenEditRepo{}
+	reports := &contentReportRepo{hidden: true, hiddenStatus: models.CONTENT_NORMAL}
+	service := &NuboBoardService{repos: &repositories.Repository{
+		Auth:      hiddenEditAuthRepo{},
+		Board:     hiddenEditBoardRepo{},
+		BoardEdit: edit,
+		BoardView: hiddenEditViewRepo{},
+		Comment:   &approvalCommentRepo{status: models.CONTENT_SECRET},
+		Report:    reports,
+	}}
+	param := models.EditorModifyParam{PostUid: 3}
+	param.BoardUid, param.UserUid, param.Title, param.Content = 1, 9, "Edited", "clean"
+	if err := service.ModifyPost(param); err != nil {
+		t.Fatal(err)
+	}
+	if !edit.updated.IsHidden || edit.updated.IsPending {
+		t.Fatalf("edits of report-hidden posts should keep them hidden, got %+v", edit.updated.EditorWriteParam)
+	}
+
+	reports.resolved = true
+	if err := service.ModifyPost(param); err != nil {
+		t.Fatal(err)
+	}
+	if edit.updated.IsHidden {
+		t.Fatal("posts should be editable normally once the reports are resolved")
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type FeedService interface {
+	GetFeed(param models.FeedParam) (models.Feed, error)
+}
+
+type NuboFeedService struct {
+	repos *repositories.Repository
+	now   func() time.Time
+}
+
+// 리포지토리 묶음 주입받기
+func NewNuboFeedService(repos *repositories.Repository) *NuboFeedService {
+	return &NuboFeedService{repos: repos, now: time.Now}
+}
+
+// 없는 피드(비공개 게시판, 없는 회원, 지원하지 않는 형식 등)를 요청했을 때의 오류
+var ErrFeedNotFound = errors.New("feed not found")
+
+// 본문 속 사이트 내부 주소(/upload/... 등)를 찾는 정규식
+var feedRelativeLink = regexp.MustCompile(`(src|href)="/([^/"][^"]*)?"`)
+
+// 지원하는 피드 형식인지 확인하기
+func IsFeedFormat(format models.FeedFormat) bool {
+	switch format {
+	case models.FEED_RSS, models.FEED_ATOM, models.FEED_JSON:
+		return true
+	}
+	return false
+}
+
+// 범위에 맞는 피드 만들기
+func (s *NuboFeedService) GetFeed(param models.FeedParam) (models.Feed, error) {
+	if !IsFeedFormat(param.Format) {
+		return models.Feed{}, fmt.Errorf("unsupported feed format: %w", ErrFeedNotFound)
+	}
+	config := configs.GetFeedConfig()
+	feed := models.Feed{
+		Link:      siteURL(),
+		Language:  config.Language,
+		Location:  config.Location,
+		Generator: fmt.Sprintf("NUBO %s", configs.Env.Version),
+	}
+	postParam := models.FeedPostParam{Limit: uint(config.Items)}
+	path := ""
+
+	switch param.Scope {
+	case models.FEED_SITE:
+		feed.Title = configs.Env.Title
+		feed.Description = configs.Env.Title
+		path = "site"
+	case models.FEED_BOARD:
+		boardUid := s.repos.Board.GetBoardUidById(param.BoardId)
+		if boardUid < 1 {
+			return models.Feed{}, fmt.Errorf("board not found: %w", ErrFeedNotFound)
+		}
+		board := s.repos.Board.GetBoardConfig(boardUid)
+		if board.Uid < 1 || board.Level.List > 0 || board.Level.View > 0 || s.repos.Home.HasRestrictedAcl(boardUid) {
+			return models.Feed{}, fmt.Errorf("board not found: %w", ErrFeedNotFound)
+		}
+		feed.Title = utils.Unescape(board.Name)
+		feed.Description = utils.Unescape(board.Info)
+		feed.Link = fmt.Sprintf("%s/%s/%s", siteURL(), board.Type.String(), url.PathEscape(board.Id))
+		postParam.BoardUid = boardUid
+		path = "board/" + url.PathEscape(board.Id)
+	case models.FEED_TAG:
+		tag := strings.TrimSpace(strings.TrimPrefix(param.Hashtag, "#"))
+		if len(tag) < 1 {
+			return models.Feed{}, fmt.Errorf("hashtag is empty: %w", ErrFeedNotFound)
+		}
+		feed.Title = fmt.Sprintf("#%s - %s", tag, configs.Env.Title)
+		feed.Description = feed.Title
+		postParam.Hashtag = tag
+		path = "tag/" + url.PathEscape(tag)
+	case models.FEED_USER:
+		name, err := s.repos.Feed.GetFeedUserName(param.UserUid)
+		if param.UserUid < 1 || err != nil {
+			return models.Feed{}, fmt.Errorf("user not found: %w", ErrFeedNotFound)
+		}
+		feed.Title = fmt.Sprintf("%s - %s", name, configs.Env.Title)
+		feed.Description = feed.Title
+		postParam.UserUid = param.UserUid
+		path = fmt.Sprintf("user/%d", param.UserUid)
+	default:
+		return models.Feed{}, fmt.Errorf("unsupported feed scope: %w", ErrFeedNotFound)
+	}
+	feed.FeedURL = fmt.Sprintf("%s/%s/feed/%s/%s", siteURL(), configs.Env.GoapiBase, path, param.Format)
+
+	items, err := s.feedItems(postParam)
+	if err != nil {
+		return models.Feed{}, err
+	}
+	feed.Items = items
+	for _, item := range items {
+		if item.Updated > feed.Updated {
+			feed.Updated = item.Updated
+		}
+	}
+	if feed.Updated == 0 {
+		feed.Updated = s.now().UnixMilli()
+	}
+	return feed, nil
+}
+
+// 게시글들을 태그와 첨부 이미지를 붙인 피드 항목으로 바꾸기
+func (s *NuboFeedService) feedItems(param models.FeedPostParam) ([]models.FeedItem, error) {
+	posts, err := s.repos.Feed.GetFeedPosts(param)
+	if err != nil {
+		return nil, err
+	}
+	postUids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		postUids = append(postUids, post.Uid)
+	}
+	tags, err := s.repos.Feed.GetFeedTags(postUids)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.repos.Feed.GetFeedFiles(postUids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.FeedItem, 0, len(posts))
+	for _, post := range posts {
+		link := fmt.Sprintf("%s/%s/%s/%d", siteURL(), post.BoardType.String(), url.PathEscape(post.BoardId), post.Uid)
+		content := utils.Unescape(post.Content)
+		item := models.FeedItem{
+			PostUid:    post.Uid,
+			Id:         link,
+			Link:       link,
+			Title:      utils.Unescape(post.Title),
+			Summary:    utils.CutString(utils.PlainText(content), models.FEED_SUMMARY_LENGTH),
+			Author:     post.UserName,
+			Published:  post.Submitted,
+			Updated:    max(post.Submitted, post.Modified),
+			Tags:       tags[post.Uid],
+			Enclosures: feedEnclosures(files[post.Uid]),
+		}
+		if !post.FeedSummary {
+			item.Content = absoluteFeedLinks(content)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// 첨부파일 중 이미지만 골라 피드 첨부로 바꾸기
+func feedEnclosures(files []models.FeedFile) []models.FeedEnclosure {
+	items := make([]models.FeedEnclosure, 0)
+	for _, file := range files {
+		if !utils.IsImage(file.Path) {
+			continue
+		}
+		mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(file.Path)))
+		if len(mimeType) < 1 {
+			mimeType = "application/octet-stream"
+		}
+		items = append(items, models.FeedEnclosure{
+			URL:    siteURL() + file.Path,
+			Type:   mimeType,
+			Length: utils.GetFileSize(file.Path),
+		})
+	}
+	return items
+}
+
+// 피드 리더에서도 열리도록 본문의 상대 주소를 절대 주소로 바꾸기
+func absoluteFeedLinks(content string) string {
+	return feedRelativeLink.ReplaceAllString(content, fmt.Sprintf(`$1="%s/$2"`, siteURL()))
+}
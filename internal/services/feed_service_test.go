@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type feedRepoStub struct {
+	repositories.FeedRepository
+	params []models.FeedPostParam
+}
+
+func (r *feedRepoStub) GetFeedPosts(param models.FeedPostParam) ([]models.FeedPost, error) {
+	r.params = append(r.params, param)
+	return []models.FeedPost{
+		{Uid: 7, BoardId: "diary", BoardType: models.BOARD_BLOG, UserName: "Kim", Title: "Tom &amp; Jerry",
+			Content:   `<p>Hello <img src="/upload/images/a.webp"> <a href="https://other.example.com/x">x</a></p>`,
+			Submitted: 1_600_000_000_000, Modified: 1_700_000_000_000},
+		{Uid: 5, BoardId: "free", FeedSummary: true, UserName: "Lee", Title: "Short",
+			Content: "<p>" + strings.Repeat("가", models.FEED_SUMMARY_LENGTH+10) + "</p>", Submitted: 1_500_000_000_000},
+	}, nil
+}
+func (*feedRepoStub) GetFeedTags([]uint) (map[uint][]string, error) {
+	return map[uint][]string{7: {"travel"}}, nil
+}
+func (*feedRepoStub) GetFeedFiles([]uint) (map[uint][]models.FeedFile, error) {
+	return map[uint][]models.FeedFile{7: {{Name: "a.jpg", Path: "/upload/attachments/a.jpg"}, {Name: "b.zip", Path: "/upload/attachments/b.zip"}}}, nil
+}
+func (*feedRepoStub) GetFeedUserName(userUid uint) (string, error) {
+	if userUid == 3 {
+		return "Kim", nil
+	}
+	return "", fmt.Errorf("not found")
+}
+
+func TestFeedBuildsItemsForEachScope(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Domain = "https://example.com/"
+	configs.Env.GoapiBase = "goapi"
+	configs.Env.Title = "Nubo"
+	configs.Env.Feed = configs.FeedEnv{Language: "en-US", Items: "20"}
+	t.Cleanup(func() { configs.Env = previous })
+
+	repo := &feedRepoStub{}
+	service := NewNuboFeedService(&repositories.Repository{Board: sitemapBoardRepo{}, Home: &sitemapHomeRepo{}, Feed: repo})
+
+	feed, err := service.GetFeed(models.FeedParam{Scope: models.FEED_BOARD, Format: models.FEED_ATOM, BoardId: "diary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Link != "https://example.com/blog/diary" || feed.FeedURL != "https://example.com/goapi/feed/board/diary/atom" ||
+		feed.Language != "en-US" || feed.Updated != 1_700_000_000_000 || repo.params[0].BoardUid != 2 || repo.params[0].Limit != 20 {
+		t.Fatalf("unexpected board feed %+v %+v", feed, repo.params)
+	}
+	full, summary := feed.Items[0], feed.Items[1]
+	if full.Link != "https://example.com/blog/diary/7" || full.Title != "Tom & Jerry" ||
+		!strings.Contains(full.Content, `src="https://example.com/upload/images/a.webp"`) ||
+		!strings.Contains(full.Content, `href="https://other.example.com/x"`) || full.Tags[0] != "travel" {
+		t.Fatalf("full-content item should keep markup with absolute links, got %+v", full)
+	}
+	if len(full.Enclosures) != 1 || full.Enclosures[0].URL != "https://example.com/upload/attachments/a.jpg" ||
+		full.Enclosures[0].Type != "image/jpeg" {
+		t.Fatalf("only attached images should become enclosures, got %+v", full.Enclosures)
+	}
+	if summary.Content != "" || len([]rune(summary.Summary)) != models.FEED_SUMMARY_LENGTH || summary.Updated != 1_500_000_000_000 {
+		t.Fatalf("summary boards should only publish a plain summary, got %+v", summary)
+	}
+
+	for _, id := range []string{"members", "staff", "missing"} {
+		if _, err := service.GetFeed(models.FeedParam{Scope: models.FEED_BOARD, Format: models.FEED_RSS, BoardId: id}); err == nil {
+			t.Fatalf("board %q is not public and should have no feed", id)
+		}
+	}
+	if _, err := service.GetFeed(models.FeedParam{Scope: models.FEED_SITE, Format: "xml"}); err == nil {
+		t.Fatal("unknown formats should be rejected")
+	}
+
+	if feed, err = service.GetFeed(models.FeedParam{Scope: models.FEED_TAG, Format: models.FEED_JSON, Hashtag: "#travel"}); err != nil ||
+		feed.Title != "#travel - Nubo" || repo.params[len(repo.params)-1].Hashtag != "travel" {
+		t.Fatalf("unexpected tag feed %+v %v", feed, err)
+	}
+	if feed, err = service.GetFeed(models.FeedParam{Scope: models.FEED_USER, Format: models.FEED_RSS, UserUid: 3}); err != nil ||
+		feed.FeedURL != "https://example.com/goapi/feed/user/3/rss" || repo.params[len(repo.params)-1].UserUid != 3 {
+		t.Fatalf("unexpected user feed %+v %v", feed, err)
+	}
+	if _, err = service.GetFeed(models.FeedParam{Scope: models.FEED_USER, Format: models.FEED_RSS, UserUid: 4}); err == nil {
+		t.Fatal("unknown users should have no feed")
+	}
+	if feed, err = service.GetFeed(models.FeedParam{Scope: models.FEED_SITE, Format: models.FEED_RSS}); err != nil ||
+		feed.Link != "https://example.com" || len(feed.Items) != 2 {
+		t.Fatalf("unexpected site feed %+v %v", feed, err)
+	}
+}
@@ -1,6 +1,11 @@
 package services
 
 import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
 	"github.com/sirini/goapi/internal/repositories"
 	"github.com/sirini/goapi/pkg/models"
 )
@@ -9,6 +14,8 @@ type HomeService interface {
 	AddVisitorLog(userUid uint)
 	GetLatestPosts(param models.HomePostParam) ([]models.BoardHomePostItem, error)
 	GetSidebarLinks() ([]models.HomeSidebarGroupResult, error)
+	GetSitemapIndex() []models.SitemapIndexItem
+	GetSitemapPage(boardId string, page uint) ([]models.SitemapURL, error)
 }
 
 type NuboHomeService struct {
@@ -82,3 +89,67 @@ func (s *NuboHomeService) GetLatestPosts(param models.HomePostParam) ([]models.B
 func (s *NuboHomeService) GetSidebarLinks() ([]models.HomeSidebarGroupResult, error) {
 	return s.repos.Home.GetGroupBoardLinks()
 }
+
+// 사이트맵에 공개할 수 있는 게시판 설정 반환 (비회원이 목록이나 글을 볼 수 없는 게시판 제외)
+func (s *NuboHomeService) sitemapBoard(boardId string) (models.BoardConfig, bool) {
+	boardUid := s.repos.Board.GetBoardUidById(boardId)
+	if boardUid < 1 {
+		return models.BoardConfig{}, false
+	}
+	config := s.repos.Board.GetBoardConfig(boardUid)
+	if config.Uid < 1 || config.Level.List > 0 || config.Level.View > 0 || s.repos.Home.HasRestrictedAcl(boardUid) {
+		return config, false
+	}
+	return config, true
+}
+
+// 밀리초 시간을 사이트맵 날짜 형식으로 바꾸기
+func sitemapDate(timestamp int64) string {
+	return time.UnixMilli(timestamp).UTC().Format(time.RFC3339)
+}
+
+// 공개 게시판마다 5만 개씩 나눈 사이트맵 목록 반환
+func (s *NuboHomeService) GetSitemapIndex() []models.SitemapIndexItem {
+	items := make([]models.SitemapIndexItem, 0)
+	for _, id := range s.repos.Home.GetBoardIDs() {
+		config, ok := s.sitemapBoard(id)
+		if !ok {
+			continue
+		}
+		stat := s.repos.Home.GetSitemapBoardStat(config.Uid)
+		pages := (stat.Count + models.SITEMAP_URL_LIMIT - 1) / models.SITEMAP_URL_LIMIT
+		for page := uint(1); page <= pages; page++ {
+			items = append(items, models.SitemapIndexItem{
+				Loc:     fmt.Sprintf("%s/%s/sitemap/%s/%d.xml", siteURL(), configs.Env.GoapiBase, url.PathEscape(id), page),
+				LastMod: sitemapDate(stat.Modified),
+			})
+		}
+	}
+	return items
+}
+
+// 게시판 사이트맵 한 페이지의 게시글 주소들 반환
+func (s *NuboHomeService) GetSitemapPage(boardId string, page uint) ([]models.SitemapURL, error) {
+	config, ok := s.sitemapBoard(boardId)
+	if !ok || page < 1 {
+		return nil, fmt.Errorf("sitemap not found")
+	}
+	posts, err := s.repos.Home.GetSitemapPosts(config.Uid, (page-1)*models.SITEMAP_URL_LIMIT, models.SITEMAP_URL_LIMIT)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, fmt.Errorf("sitemap not found")
+	}
+
+	items := make([]models.SitemapURL, 0, len(posts))
+	for _, post := range posts {
+		items = append(items, models.SitemapURL{
+			Loc:        fmt.Sprintf("%s/%s/%s/%d", siteURL(), config.Type.String(), url.PathEscape(config.Id), post.Uid),
+			LastMod:    sitemapDate(post.Modified),
+			ChangeFreq: "weekly",
+			Priority:   "0.5",
+		})
+	}
+	return items, nil
+}
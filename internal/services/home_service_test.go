@@ -0,0 +1,85 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type sitemapBoardRepo struct{ repositories.BoardRepository }
+
+var sitemapBoards = map[string]models.BoardConfig{
+	"free":    {Uid: 1, Id: "free"},
+	"diary":   {Uid: 2, Id: "diary", Type: models.BOARD_BLOG},
+	"members": {Uid: 3, Id: "members", Level: models.BoardActionLevel{List: 1}},
+	"staff":   {Uid: 4, Id: "staff"},
+}
+
+func (sitemapBoardRepo) GetBoardUidById(id string) uint { return sitemapBoards[id].Uid }
+func (sitemapBoardRepo) GetBoardConfig(boardUid uint) models.BoardConfig {
+	for _, config := range sitemapBoards {
+		if config.Uid == boardUid {
+			return config
+		}
+	}
+	return models.BoardConfig{}
+}
+
+type sitemapHomeRepo struct {
+	repositories.HomeRepository
+	offsets []uint
+}
+
+func (*sitemapHomeRepo) GetBoardIDs() []string               { return []string{"free", "diary", "members", "staff"} }
+func (*sitemapHomeRepo) HasRestrictedAcl(boardUid uint) bool { return boardUid == 4 }
+func (*sitemapHomeRepo) GetSitemapBoardStat(boardUid uint) models.SitemapBoardStat {
+	if boardUid == 1 {
+		return models.SitemapBoardStat{Count: models.SITEMAP_URL_LIMIT + 1, Modified: 1_700_000_000_000}
+	}
+	return models.SitemapBoardStat{Count: 1, Modified: 1_600_000_000_000}
+}
+func (r *sitemapHomeRepo) GetSitemapPosts(_ uint, offset uint, _ uint) ([]models.SitemapPost, error) {
+	r.offsets = append(r.offsets, offset)
+	if offset > models.SITEMAP_URL_LIMIT {
+		return nil, nil
+	}
+	return []models.SitemapPost{{Uid: 7, Modified: 1_600_000_000_000}}, nil
+}
+
+func TestSitemapSkipsRestrictedBoardsAndPaginates(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Domain = "https://example.com/"
+	configs.Env.GoapiBase = "goapi"
+	t.Cleanup(func() { configs.Env = previous })
+
+	home := &sitemapHomeRepo{}
+	service := NewNuboHomeService(&repositories.Repository{Board: sitemapBoardRepo{}, Home: home})
+
+	index := service.GetSitemapIndex()
+	if len(index) != 3 || index[0].Loc != "https://example.com/goapi/sitemap/free/1.xml" ||
+		index[1].Loc != "https://example.com/goapi/sitemap/free/2.xml" || index[2].LastMod != "2020-09-13T12:26:40Z" {
+		t.Fatalf("unexpected sitemap index %+v", index)
+	}
+	for _, item := range index {
+		if strings.Contains(item.Loc, "members") || strings.Contains(item.Loc, "staff") {
+			t.Fatalf("restricted boards should not be listed, got %+v", index)
+		}
+	}
+
+	items, err := service.GetSitemapPage("diary", 1)
+	if err != nil || len(items) != 1 || items[0].Loc != "https://example.com/blog/diary/7" {
+		t.Fatalf("unexpected sitemap page %+v %v", items, err)
+	}
+	if _, err := service.GetSitemapPage("free", 2); err != nil || home.offsets[len(home.offsets)-1] != models.SITEMAP_URL_LIMIT {
+		t.Fatalf("second page should start after the first 50k posts, got %v %v", home.offsets, err)
+	}
+	if _, err := service.GetSitemapPage("members", 1); err == nil {
+		t.Fatal("restricted board sitemap should not be served")
+	}
+	if _, err := service.GetSitemapPage("free", 3); err == nil {
+		t.Fatal("empty sitemap page should not be served")
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+const (
+	imageVariantBatchSize  = 100
+	imageVariantReportErrs = 20
+)
+
+type ImageVariantService interface {
+	Backfill(category models.UploadCategory) (models.ImageVariantBackfillReport, error)
+}
+
+type NuboImageVariantService struct {
+	repos *repositories.Repository
+}
+
+// 리포지토리 묶음 주입받기
+func NewNuboImageVariantService(repos *repositories.Repository) *NuboImageVariantService {
+	return &NuboImageVariantService{repos: repos}
+}
+
+// 설정한 너비의 변형이 빠진 기존 이미지들에 변형을 만들어 기록하기 (실패한 이미지는 보고서에 남기고 다음 실행에서 다시 시도)
+func (s *NuboImageVariantService) Backfill(category models.UploadCategory) (models.ImageVariantBackfillReport, error) {
+	report := models.ImageVariantBackfillReport{Category: category, Errors: make([]string, 0)}
+	widths := backfillWidths(category)
+	var afterUid uint
+	for {
+		targets, err := s.repos.ImageVariant.GetVariantTargets(category, widths, afterUid, imageVariantBatchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(targets) < 1 {
+			return report, nil
+		}
+		for _, target := range targets {
+			afterUid = target.Uid
+			report.Scanned++
+			created, err := s.backfillTarget(category, target)
+			if err != nil {
+				report.Failed++
+				if len(report.Errors) < imageVariantReportErrs {
+					report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", target.Source, err))
+				}
+				continue
+			}
+			if created {
+				report.Created++
+			}
+		}
+	}
+}
+
+// 업로드 종류별 주 이미지 너비
+func primaryWidths(category models.UploadCategory) []uint {
+	switch category {
+	case models.UPLOAD_THUMB:
+		return []uint{configs.SIZE_THUMBNAIL.Number(), configs.SIZE_FULL.Number()}
+	case models.UPLOAD_PROFILE:
+		return []uint{configs.SIZE_PROFILE.Number()}
+	default:
+		return []uint{configs.SIZE_CONTENT_INSERT.Number()}
+	}
+}
+
+// 이미지마다 기록되어 있어야 할 너비 목록 (주 이미지 너비와 설정한 반응형 너비)
+func backfillWidths(category models.UploadCategory) []uint {
+	config := configs.GetImageWidthsConfig()
+	ladder := config.Insert
+	switch category {
+	case models.UPLOAD_THUMB:
+		ladder = config.Thumbnail
+	case models.UPLOAD_PROFILE:
+		ladder = config.Profile
+	}
+	widths := primaryWidths(category)
+	for _, width := range ladder {
+		if !slices.Contains(widths, width) {
+			widths = append(widths, width)
+		}
+	}
+	return widths
+}
+
+// 이미지 하나의 기존 파일들 중 기록되지 않은 것을 모으고 빠진 너비를 만들어 기록하기 (첨부 썸네일은 가능하면 원본 첨부 파일에서 만들고, 원본보다 넓은 너비는 만들지 않음)
+func (s *NuboImageVariantService) backfillTarget(category models.UploadCategory, target models.ImageVariantTarget) (bool, error) {
+	paths := []string{target.Source}
+	if category == models.UPLOAD_THUMB {
+		paths = append(paths, target.Large)
+	}
+	primaries := make([]models.ImageVariant, 0, len(paths))
+	for i, width := range primaryWidths(category) {
+		path, err := utils.UploadFilePath(paths[i])
+		if err != nil {
+			return false, err
+		}
+		primaries = append(primaries, models.ImageVariant{Path: path, Width: width})
+	}
+
+	inputPath := primaries[len(primaries)-1].Path
+	if _, err := os.Stat(inputPath); err != nil {
+		return false, fmt.Errorf("image file is missing")
+	}
+	if original, err := utils.UploadFilePath(target.Original); err == nil && utils.IsImage(original) {
+		if _, err := os.Stat(original); err == nil {
+			inputPath = original
+		}
+	}
+
+	existing, err := s.repos.ImageVariant.GetImageVariants([]string{target.Source})
+	if err != nil {
+		return false, err
+	}
+	recorded := make([]uint, 0)
+	for _, variant := range existing[target.Source] {
+		if !slices.Contains(recorded, variant.Width) {
+			recorded = append(recorded, variant.Width)
+		}
+	}
+
+	variants, err := utils.BackfillImageVariants(category, inputPath, primaries, recorded)
+	if err != nil {
+		return false, err
+	}
+	if len(variants) < 1 {
+		return false, nil
+	}
+	publicVariants, err := utils.PublicImageVariants(variants)
+	if err != nil {
+		return false, err
+	}
+	return true, s.repos.ImageVariant.InsertImageVariants(target.FileUid, target.Source, publicVariants)
+}
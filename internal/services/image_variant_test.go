@@ -0,0 +1,162 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type imageVariantRepoStub struct {
+	repositories.ImageVariantRepository
+	stored   map[string][]models.ImageVariant
+	targets  []models.ImageVariantTarget
+	inserted map[string]uint
+	widths   []uint
+}
+
+func (r *imageVariantRepoStub) GetImageVariants(sources []string) (map[string][]models.ImageVariant, error) {
+	result := make(map[string][]models.ImageVariant)
+	for _, source := range sources {
+		if variants, ok := r.stored[source]; ok {
+			result[source] = variants
+		}
+	}
+	return result, nil
+}
+
+func (r *imageVariantRepoStub) GetVariantTargets(_ models.UploadCategory, widths []uint, afterUid uint, _ uint) ([]models.ImageVariantTarget, error) {
+	r.widths = widths
+	items := make([]models.ImageVariantTarget, 0)
+	for _, target := range r.targets {
+		if target.Uid > afterUid {
+			items = append(items, target)
+		}
+	}
+	return items, nil
+}
+
+func (r *imageVariantRepoStub) InsertImageVariants(fileUid uint, source string, variants []models.ImageVariant) error {
+	r.stored[source] = append(r.stored[source], variants...)
+	r.inserted[source] = fileUid
+	return nil
+}
+
+func TestContentAndCoverVariantsUseUploadPaths(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Domain = "https://example.com"
+	t.Cleanup(func() { configs.Env = previous })
+
+	ladder := []models.ImageVariant{{Path: "/upload/images/a_w320.webp", Width: 320, Type: "image/webp"}}
+	repo := &imageVariantRepoStub{stored: map[string][]models.ImageVariant{
+		"/upload/images/a.webp":     ladder,
+		"/upload/thumbnails/t1.jpg": ladder,
+	}}
+	service := &NuboBoardService{repos: &repositories.Repository{ImageVariant: repo}}
+
+	content := `&lt;p&gt;&lt;img src=&quot;https://example.com/upload/images/a.webp&quot;&gt;&lt;img src=&quot;/upload/images/none.webp&quot;&gt;&lt;img src=&quot;https://cdn.example.net/b.webp&quot;&gt;&lt;/p&gt;`
+	images := service.contentImageVariants(content)
+	if len(images) != 1 || !slices.Equal(images["/upload/images/a.webp"], ladder) {
+		t.Fatalf("unexpected content variants %+v", images)
+	}
+	if images := service.contentImageVariants("no images"); images == nil || len(images) != 0 {
+		t.Fatalf("posts without images should have an empty map, got %#v", images)
+	}
+
+	notices := []models.BoardListItem{{BoardCommonListItem: models.BoardCommonListItem{Cover: "/upload/thumbnails/t1.jpg"}}}
+	posts := []models.BoardListItem{{}, {BoardCommonListItem: models.BoardCommonListItem{Cover: "/upload/thumbnails/t2.jpg"}}}
+	service.attachCoverVariants(notices, posts)
+	if !slices.Equal(notices[0].CoverVariants, ladder) || posts[0].CoverVariants != nil || posts[1].CoverVariants != nil {
+		t.Fatalf("unexpected cover variants %+v %+v", notices, posts)
+	}
+}
+
+func TestImageVariantBackfillRecordsExistingFilesAndFailures(t *testing.T) {
+	previous := configs.Env
+	configs.Env.UploadDir = t.TempDir()
+	configs.Env.ThumbnailSize = "512"
+	configs.Env.FullSize = "2400"
+	configs.Env.ImageWidths = configs.ImageWidthsEnv{}
+	t.Cleanup(func() { configs.Env = previous })
+
+	for _, name := range []string{"thumbnails/t1.avif", "thumbnails/t1.webp", "thumbnails/f1.avif", "attachments/p.jpg"} {
+		path := filepath.Join(configs.Env.UploadDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("image"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repo := &imageVariantRepoStub{stored: map[string][]models.ImageVariant{}, inserted: map[string]uint{}, targets: []models.ImageVariantTarget{
+		{Uid: 3, FileUid: 7, Source: "/upload/thumbnails/t1.avif", Large: "/upload/thumbnails/f1.avif", Original: "/upload/attachments/p.jpg"},
+		{Uid: 5, FileUid: 8, Source: "/upload/thumbnails/t2.webp", Large: "/upload/thumbnails/f2.webp", Original: "/upload/attachments/q.jpg"},
+	}}
+	service := NewNuboImageVariantService(&repositories.Repository{ImageVariant: repo})
+	report, err := service.Backfill(models.UPLOAD_THUMB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Scanned != 2 || report.Created != 1 || report.Failed != 1 || len(report.Errors) != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	want := []models.ImageVariant{
+		{Path: "/upload/thumbnails/t1.avif", Width: 512, Type: "image/avif"},
+		{Path: "/upload/thumbnails/t1.webp", Width: 512, Type: "image/webp"},
+		{Path: "/upload/thumbnails/f1.avif", Width: 2400, Type: "image/avif"},
+	}
+	if got := repo.stored["/upload/thumbnails/t1.avif"]; !slices.Equal(got, want) || repo.inserted["/upload/thumbnails/t1.avif"] != 7 {
+		t.Fatalf("unexpected recorded variants %+v", got)
+	}
+}
+
+func TestImageVariantBackfillAddsOnlyMissingWidths(t *testing.T) {
+	previous := configs.Env
+	configs.Env.UploadDir = t.TempDir()
+	configs.Env.ThumbnailSize = "512"
+	configs.Env.FullSize = "2400"
+	configs.Env.ImageWidths = configs.ImageWidthsEnv{Thumbnail: "800, 512"}
+	t.Cleanup(func() { configs.Env = previous })
+
+	repo := &imageVariantRepoStub{stored: map[string][]models.ImageVariant{}, inserted: map[string]uint{}}
+	service := NewNuboImageVariantService(&repositories.Repository{ImageVariant: repo})
+	if _, err := service.Backfill(models.UPLOAD_THUMB); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(repo.widths, []uint{512, 2400, 800}) {
+		t.Fatalf("targets should be selected by primary and configured widths, got %v", repo.widths)
+	}
+
+	configs.Env.ImageWidths = configs.ImageWidthsEnv{}
+	for _, name := range []string{"thumbnails/t1.webp", "thumbnails/f1.webp"} {
+		path := filepath.Join(configs.Env.UploadDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("image"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorded := models.ImageVariant{Path: "/upload/thumbnails/t1.webp", Width: 512, Type: "image/webp"}
+	repo.stored["/upload/thumbnails/t1.webp"] = []models.ImageVariant{recorded}
+	repo.targets = []models.ImageVariantTarget{{Uid: 3, FileUid: 7, Source: "/upload/thumbnails/t1.webp", Large: "/upload/thumbnails/f1.webp"}}
+	report, err := service.Backfill(models.UPLOAD_THUMB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.ImageVariant{recorded, {Path: "/upload/thumbnails/f1.webp", Width: 2400, Type: "image/webp"}}
+	if report.Created != 1 || !slices.Equal(repo.stored["/upload/thumbnails/t1.webp"], want) {
+		t.Fatalf("only the missing width should be recorded, got %+v %+v", report, repo.stored)
+	}
+
+	if report, err = service.Backfill(models.UPLOAD_THUMB); err != nil || report.Scanned != 1 || report.Created != 0 ||
+		len(repo.stored["/upload/thumbnails/t1.webp"]) != 2 {
+		t.Fatalf("images with nothing left to add should not be recorded again, got %+v %v", report, err)
+	}
+}
@@ -0,0 +1,527 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+const importWarningLimit = 100
+
+type ImportService interface {
+	GetImportJob(uid uint) (models.ImportJob, error)
+	GetImportJobs(limit uint) ([]models.ImportJob, error)
+	ImportWordPress(param models.WordPressImportParam) (models.ImportJob, error)
+}
+
+type NuboImportService struct {
+	repos      *repositories.Repository
+	board      BoardService
+	fetchImage func(imageURL string, outputPath string, width uint) error
+	saveImage  func(category models.UploadCategory, imageURL string, width uint) (models.SavedImage, error)
+	now        func() time.Time
+	mu         sync.Mutex
+	running    map[uint]uint
+}
+
+// 리포지토리 묶음과 게시판 서비스 주입받기
+func NewNuboImportService(repos *repositories.Repository, board BoardService) *NuboImportService {
+	return &NuboImportService{
+		repos:      repos,
+		board:      board,
+		fetchImage: utils.DownloadImage,
+		saveImage:  utils.SaveRemoteImage,
+		now:        time.Now,
+		running:    make(map[uint]uint),
+	}
+}
+
+// 가져오기 작업 상태 반환 (서버 재시작으로 중단된 작업은 실패로 표시)
+func (s *NuboImportService) GetImportJob(uid uint) (models.ImportJob, error) {
+	job, err := s.repos.Import.GetImportJob(uid)
+	if err != nil {
+		return job, err
+	}
+	return s.markInterrupted(job), nil
+}
+
+// 최근 가져오기 작업 목록 반환
+func (s *NuboImportService) GetImportJobs(limit uint) ([]models.ImportJob, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	jobs, err := s.repos.Import.GetImportJobs(limit)
+	for i := range jobs {
+		jobs[i] = s.markInterrupted(jobs[i])
+	}
+	return jobs, err
+}
+
+func (s *NuboImportService) markInterrupted(job models.ImportJob) models.ImportJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job.Status == models.IMPORT_RUNNING && s.running[job.BoardUid] != job.Uid {
+		job.Status = models.IMPORT_FAILED
+		job.LastError = "import was interrupted, run it again to continue"
+	}
+	return job
+}
+
+// 워드프레스 내보내기 파일을 블로그 게시판으로 가져오기 (dryRun이면 보고서만 바로 반환하고, 아니면 백그라운드 작업 시작)
+func (s *NuboImportService) ImportWordPress(param models.WordPressImportParam) (models.ImportJob, error) {
+	job := models.ImportJob{BoardUid: param.BoardUid, UserUid: param.UserUid, Source: param.Site.Link}
+	config := s.repos.Board.GetBoardConfig(param.BoardUid)
+	if config.Uid < 1 || config.Type != models.BOARD_BLOG {
+		return job, fmt.Errorf("posts can only be imported into a blog board")
+	}
+	if config.Admin.Board < 1 {
+		return job, fmt.Errorf("blog board has no owner to write imported posts")
+	}
+	run := newWordPressImport(s, param, config)
+	now := s.now().UnixMilli()
+	job.Created, job.Updated = now, now
+
+	if param.DryRun {
+		job.Report, _ = run.execute()
+		job.Status = models.IMPORT_DONE
+		return job, nil
+	}
+
+	s.mu.Lock()
+	if s.running[param.BoardUid] > 0 {
+		s.mu.Unlock()
+		return job, fmt.Errorf("another import is already running on this board")
+	}
+	uid, err := s.repos.Import.CreateImportJob(param.BoardUid, param.UserUid, param.Site.Link)
+	if err != nil {
+		s.mu.Unlock()
+		return job, err
+	}
+	s.running[param.BoardUid] = uid
+	s.mu.Unlock()
+
+	job.Uid = uid
+	job.Status = models.IMPORT_RUNNING
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.running, param.BoardUid)
+			s.mu.Unlock()
+		}()
+		status, lastError := models.IMPORT_DONE, ""
+		report, err := run.execute()
+		if err != nil {
+			status, lastError = models.IMPORT_FAILED, utils.CutString(err.Error(), 1000)
+		}
+		if err := s.repos.Import.FinishImportJob(uid, status, report, lastError); err != nil {
+			log.Printf("import: failed to finish job %d: %v", uid, err)
+		}
+	}()
+	return job, nil
+}
+
+// 워드프레스 가져오기 한 번의 실행 상태
+type wordPressImport struct {
+	service    *NuboImportService
+	param      models.WordPressImportParam
+	config     models.BoardConfig
+	sourceHash string
+	report     models.ImportReport
+	posts      map[uint]uint
+	images     map[string]string
+	categories map[string]uint
+	tags       map[string]bool
+	created    []importedPost
+}
+
+// 이번 실행에서 새로 만들었거나 이전 실행에서 가져온 글 (가져온 글 사이의 링크를 마지막에 고치기 위해 보관)
+type importedPost struct {
+	uid     uint
+	content string
+}
+
+func newWordPressImport(s *NuboImportService, param models.WordPressImportParam, config models.BoardConfig) *wordPressImport {
+	return &wordPressImport{
+		service:    s,
+		param:      param,
+		config:     config,
+		sourceHash: utils.GetHashedString(param.Site.Link),
+		report: models.ImportReport{
+			DryRun:     param.DryRun,
+			Categories: make([]string, 0),
+			Tags:       make([]string, 0),
+			Warnings:   make([]string, 0),
+		},
+		posts:      make(map[uint]uint),
+		images:     make(map[string]string),
+		categories: make(map[string]uint),
+		tags:       make(map[string]bool),
+		created:    make([]importedPost, 0),
+	}
+}
+
+func (w *wordPressImport) warn(format string, args ...any) {
+	if len(w.report.Warnings) < importWarningLimit {
+		w.report.Warnings = append(w.report.Warnings, fmt.Sprintf(format, args...))
+	}
+}
+
+func (w *wordPressImport) findMap(kind string, key string) (uint, string, bool) {
+	return w.service.repos.Import.FindImportMap(w.param.BoardUid, w.sourceHash, kind, utils.GetHashedString(key))
+}
+
+func (w *wordPressImport) saveMap(kind string, key string, targetUid uint, targetPath string) error {
+	return w.service.repos.Import.SaveImportMap(w.param.BoardUid, w.sourceHash, kind, utils.GetHashedString(key), targetUid, targetPath)
+}
+
+// 글, 이미지, 댓글을 차례로 가져오고 마지막에 글 사이 링크 고치기 (이미 가져온 항목은 건너뜀)
+func (w *wordPressImport) execute() (models.ImportReport, error) {
+	for _, post := range w.param.Site.Posts {
+		if post.Type != "post" || (post.Status != "publish" && post.Status != "private") {
+			w.report.IgnoredItems++
+			continue
+		}
+		if postUid, _, ok := w.findMap(models.IMPORT_KIND_POST, fmt.Sprint(post.Id)); ok {
+			w.report.SkippedPosts++
+			w.posts[post.Id] = postUid
+			// 이전 실행이 링크를 고치기 전에 멈췄을 수 있으므로 이미 가져온 글의 링크도 다시 확인함
+			if content, err := w.service.repos.Import.GetPostContent(postUid); err == nil {
+				w.created = append(w.created, importedPost{uid: postUid, content: content})
+			}
+			if err := w.importComments(postUid, post); err != nil {
+				return w.report, err
+			}
+			continue
+		}
+		if err := w.importPost(post); err != nil {
+			return w.report, err
+		}
+	}
+	return w.report, w.rewritePostLinks()
+}
+
+// 글 하나 가져오기
+func (w *wordPressImport) importPost(post models.WXRPost) error {
+	w.report.Posts++
+	categoryUid, err := w.categoryUid(post.Categories)
+	if err != nil {
+		return err
+	}
+	for _, tag := range post.Tags {
+		if !w.tags[tag] {
+			w.tags[tag] = true
+			w.report.Tags = append(w.report.Tags, tag)
+		}
+	}
+	content := w.importImages(utils.WordPressContentToHTML(post.Content))
+	if w.param.DryRun {
+		w.posts[post.Id] = 0
+		w.created = append(w.created, importedPost{content: content})
+		return w.importComments(0, post)
+	}
+
+	title := utils.CutString(utils.Escape(post.Title), 299)
+	if title == "" {
+		title = fmt.Sprintf("WordPress #%d", post.Id)
+	}
+	status := models.CONTENT_NORMAL
+	if post.Status == "private" {
+		status = models.CONTENT_SECRET
+	}
+	submitted := post.Submitted
+	if submitted < 1 {
+		submitted = w.service.now().UnixMilli()
+	}
+	modified := int64(0)
+	if post.Modified > submitted {
+		modified = post.Modified
+	}
+	content = utils.Sanitize(content)
+	postUid, err := w.service.repos.Import.InsertImportedPost(models.ImportPostParam{
+		BoardUid:    w.param.BoardUid,
+		UserUid:     w.config.Admin.Board,
+		CategoryUid: categoryUid,
+		Title:       title,
+		Content:     content,
+		Status:      status,
+		Submitted:   submitted,
+		Modified:    modified,
+		SourceHash:  w.sourceHash,
+		SourceKey:   utils.GetHashedString(fmt.Sprint(post.Id)),
+	})
+	if err != nil {
+		return err
+	}
+	w.posts[post.Id] = postUid
+	w.created = append(w.created, importedPost{uid: postUid, content: content})
+	if err := w.service.board.SaveTags(w.param.BoardUid, postUid, post.Tags); err != nil {
+		w.warn("post %d: failed to save tags: %v", post.Id, err)
+	}
+	w.importThumbnail(postUid, post)
+	return w.importComments(postUid, post)
+}
+
+// 첫 번째 카테고리를 게시판 카테고리로 연결하고, 없으면 새로 만들기 (카테고리가 없는 글은 게시판 기본 카테고리)
+func (w *wordPressImport) categoryUid(names []string) (uint, error) {
+	defaultUid := uint(0)
+	if len(w.config.Category) > 0 {
+		defaultUid = w.config.Category[0].Uid
+	}
+	if len(names) == 0 {
+		return defaultUid, nil
+	}
+	name := utils.CutString(utils.Escape(names[0]), 30)
+	if uid, ok := w.categories[name]; ok {
+		return uid, nil
+	}
+	uid := w.service.repos.Import.FindCategoryUid(w.param.BoardUid, name)
+	if uid < 1 {
+		w.report.Categories = append(w.report.Categories, name)
+		if !w.param.DryRun {
+			created, err := w.service.repos.Import.InsertCategory(w.param.BoardUid, name)
+			if err != nil {
+				return defaultUid, err
+			}
+			uid = created
+		}
+	}
+	w.categories[name] = uid
+	return uid, nil
+}
+
+// 본문 이미지를 내려받아 삽입 이미지로 다시 저장하고 본문 주소 바꾸기 (실패한 이미지는 원래 주소 유지)
+func (w *wordPressImport) importImages(content string) string {
+	replaced := make(map[string]string)
+	for _, source := range utils.ExtractImageSources(content, w.param.Site.Link) {
+		if publicPath, ok := w.images[source]; ok {
+			replaced[source] = publicPath
+			continue
+		}
+		if _, publicPath, ok := w.findMap(models.IMPORT_KIND_IMAGE, source); ok {
+			w.images[source] = publicPath
+			replaced[source] = publicPath
+			continue
+		}
+		w.report.Images++
+		if w.param.DryRun {
+			w.images[source] = ""
+			continue
+		}
+		publicPath, err := w.saveInsertImage(source)
+		if err != nil {
+			w.report.FailedImages++
+			w.warn("image %s: %v", source, err)
+			continue
+		}
+		w.images[source] = publicPath
+		replaced[source] = publicPath
+	}
+	if w.param.DryRun || len(replaced) == 0 {
+		return content
+	}
+	content, _ = utils.RewriteHTMLLinks(content, w.param.Site.Link, func(link string) (string, bool) {
+		publicPath, ok := replaced[link]
+		return publicPath, ok && publicPath != ""
+	})
+	return content
+}
+
+// 이미지를 본문 삽입 크기로 내려받아 저장하고 삽입 이미지 목록에 등록하기
+func (w *wordPressImport) saveInsertImage(source string) (string, error) {
+	publicPath, err := saveRemoteInsertImage(w.service.repos, w.service.saveImage, w.param.BoardUid, w.config.Admin.Board, source)
+	if err != nil {
+		return "", err
+	}
+	if err := w.saveMap(models.IMPORT_KIND_IMAGE, source, 0, publicPath); err != nil {
+		return "", err
+	}
+	return publicPath, nil
+}
+
+// 원격 이미지를 본문 삽입 형식과 크기로 내려받아 반응형 변형과 함께 저장하고 삽입 이미지 목록에 등록하기 (워드프레스 가져오기와 동기화 가져오기에서 함께 사용)
+func saveRemoteInsertImage(repos *repositories.Repository, save func(models.UploadCategory, string, uint) (models.SavedImage, error), boardUid uint, userUid uint, source string) (string, error) {
+	saved, err := save(models.UPLOAD_IMAGE, source, configs.SIZE_CONTENT_INSERT.Number())
+	if err != nil {
+		return "", err
+	}
+	image, err := utils.PublicSavedImage(saved)
+	if err != nil {
+		utils.RemoveSavedImage(saved)
+		return "", err
+	}
+	if err := repos.BoardEdit.InsertImagePaths(boardUid, userUid, []models.SavedImage{image}); err != nil {
+		_ = utils.RemoveUploadFile(image.Path)
+		return "", err
+	}
+	if err := repos.ImageVariant.InsertImageVariants(0, image.Path, image.Variants); err != nil {
+		log.Printf("image: failed to record responsive variants path=%s: %v", image.Path, err)
+	}
+	return image.Path, nil
+}
+
+// 대표 이미지(없으면 본문 첫 이미지)를 첨부파일로 저장하고 목록용 썸네일 만들기
+func (w *wordPressImport) importThumbnail(postUid uint, post models.WXRPost) {
+	source := w.param.Site.Attachments[post.ThumbnailId]
+	if source == "" {
+		sources := utils.ExtractImageSources(post.Content, w.param.Site.Link)
+		if len(sources) == 0 {
+			return
+		}
+		source = sources[0]
+	}
+	if _, err := utils.ValidatePublicURL(source); err != nil {
+		return
+	}
+	if err := saveRemoteAttachment(w.service.repos, w.service.board, w.service.fetchImage, w.param.BoardUid, postUid, source); err != nil {
+		w.warn("post %d: %v", post.Id, err)
+	}
+}
+
+// 원격 이미지를 첨부파일로 내려받아 저장하고 목록용 썸네일 만들기 (워드프레스 가져오기와 동기화 가져오기에서 함께 사용)
+func saveRemoteAttachment(repos *repositories.Repository, board BoardService, fetch func(string, string, uint) error, boardUid uint, postUid uint, source string) error {
+	dirPath, err := utils.MakeSavePath(models.UPLOAD_ATTACH)
+	if err != nil {
+		return fmt.Errorf("failed to prepare thumbnail: %w", err)
+	}
+	savePath := fmt.Sprintf("%s/%s.webp", dirPath, uuid.New().String())
+	if err := fetch(source, savePath, configs.SIZE_FULL.Number()); err != nil {
+		return fmt.Errorf("failed to download thumbnail: %w", err)
+	}
+	publicPath, err := utils.PublicUploadPath(savePath)
+	if err != nil {
+		_ = os.Remove(savePath)
+		return fmt.Errorf("failed to save thumbnail: %w", err)
+	}
+	name := path.Base(source)
+	if parsed, err := url.Parse(source); err == nil {
+		name = path.Base(parsed.Path)
+	}
+	fileUid, err := repos.BoardEdit.InsertFile(models.EditorSaveFileParam{
+		BoardUid: boardUid,
+		PostUid:  postUid,
+		Name:     utils.CutString(name, 100),
+		Path:     publicPath,
+	})
+	if err != nil {
+		_ = os.Remove(savePath)
+		return fmt.Errorf("failed to save thumbnail: %w", err)
+	}
+	board.SaveThumbnail(fileUid, postUid, savePath)
+	return nil
+}
+
+// 승인된 댓글을 원래 순서와 답글 관계대로 가져오기 (회원 이메일과 일치하지 않는 작성자는 블로그 주인 이름으로 남기고 원래 이름 표시)
+func (w *wordPressImport) importComments(postUid uint, post models.WXRPost) error {
+	comments := make([]models.WXRComment, 0, len(post.Comments))
+	for _, comment := range post.Comments {
+		if comment.Approved && comment.Type != "pingback" && comment.Type != "trackback" {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].Id < comments[j].Id })
+
+	commentUids := make(map[uint]uint)
+	for _, comment := range comments {
+		key := fmt.Sprintf("%d:%d", post.Id, comment.Id)
+		if commentUid, _, ok := w.findMap(models.IMPORT_KIND_COMMENT, key); ok {
+			w.report.SkippedComments++
+			commentUids[comment.Id] = commentUid
+			continue
+		}
+		w.report.Comments++
+		if w.param.DryRun {
+			continue
+		}
+
+		content := utils.Sanitize(utils.WordPressContentToHTML(comment.Content))
+		writerUid := uint(0)
+		if comment.AuthorEmail != "" {
+			writerUid = w.service.repos.Import.FindUserUidByEmail(comment.AuthorEmail)
+		}
+		if writerUid < 1 {
+			writerUid = w.config.Admin.Board
+			content = fmt.Sprintf("<p><strong>%s</strong></p>%s", utils.Escape(utils.CutString(comment.Author, 50)), content)
+		}
+		submitted := comment.Submitted
+		if submitted < 1 {
+			submitted = post.Submitted
+		}
+		commentUid, err := w.service.repos.Import.InsertImportedComment(models.ImportCommentParam{
+			BoardUid:  w.param.BoardUid,
+			PostUid:   postUid,
+			ParentUid: commentUids[comment.ParentId],
+			UserUid:   writerUid,
+			Content:   content,
+			Submitted: submitted,
+		})
+		if err != nil {
+			return err
+		}
+		if err := w.saveMap(models.IMPORT_KIND_COMMENT, key, commentUid, ""); err != nil {
+			return err
+		}
+		commentUids[comment.Id] = commentUid
+	}
+	return nil
+}
+
+// 원래 블로그 글을 가리키는 링크를 가져온 글 주소로 바꾸기
+func (w *wordPressImport) rewritePostLinks() error {
+	links := make(map[string]string)
+	for _, post := range w.param.Site.Posts {
+		postUid, ok := w.posts[post.Id]
+		if !ok {
+			continue
+		}
+		target := fmt.Sprintf("/blog/%s/%d", w.config.Id, postUid)
+		for _, link := range []string{post.Link, fmt.Sprintf("%s/?p=%d", w.param.Site.Link, post.Id)} {
+			if key := normalizeImportLink(link); key != "" {
+				links[key] = target
+			}
+		}
+	}
+	for _, post := range w.created {
+		content, count := utils.RewriteHTMLLinks(post.content, w.param.Site.Link, func(link string) (string, bool) {
+			key := normalizeImportLink(link)
+			target, ok := links[key]
+			return target, ok && key != ""
+		})
+		if count == 0 {
+			continue
+		}
+		w.report.RewrittenLinks += count
+		if w.param.DryRun {
+			continue
+		}
+		if err := w.service.repos.Import.UpdatePostContent(post.uid, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// http/https, www 여부와 끝의 슬래시, #앵커를 무시하고 같은 글 주소인지 비교할 수 있게 정리하기
+func normalizeImportLink(link string) string {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	normalized := host + strings.TrimRight(parsed.EscapedPath(), "/")
+	if parsed.RawQuery != "" {
+		normalized += "?" + parsed.RawQuery
+	}
+	return normalized
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type importRepoStub struct {
+	repositories.ImportRepository
+	maps     map[string]uint
+	posts    map[uint]models.ImportPostParam
+	comments []models.ImportCommentParam
+	contents map[uint]string
+	nextUid  uint
+}
+
+func (r *importRepoStub) FindImportMap(_ uint, _ string, kind string, keyHash string) (uint, string, bool) {
+	uid, ok := r.maps[kind+keyHash]
+	return uid, fmt.Sprintf("/upload/images/%d.webp", uid), ok
+}
+func (r *importRepoStub) SaveImportMap(_ uint, _ string, kind string, keyHash string, targetUid uint, _ string) error {
+	r.maps[kind+keyHash] = targetUid
+	return nil
+}
+func (r *importRepoStub) FindCategoryUid(uint, string) uint { return 0 }
+func (r *importRepoStub) InsertCategory(uint, string) (uint, error) {
+	r.nextUid++
+	return r.nextUid, nil
+}
+func (r *importRepoStub) FindUserUidByEmail(email string) uint {
+	if email == "member@example.com" {
+		return 5
+	}
+	return 0
+}
+func (r *importRepoStub) InsertImportedPost(param models.ImportPostParam) (uint, error) {
+	r.nextUid++
+	r.posts[r.nextUid] = param
+	if param.SourceKey != "" {
+		r.maps[models.IMPORT_KIND_POST+param.SourceKey] = r.nextUid
+	}
+	return r.nextUid, nil
+}
+func (r *importRepoStub) GetPostContent(postUid uint) (string, error) {
+	if content, ok := r.contents[postUid]; ok {
+		return content, nil
+	}
+	return r.posts[postUid].Content, nil
+}
+func (r *importRepoStub) InsertImportedComment(param models.ImportCommentParam) (uint, error) {
+	r.nextUid++
+	r.comments = append(r.comments, param)
+	return r.nextUid, nil
+}
+func (r *importRepoStub) UpdateImportedPost(postUid uint, param models.ImportPostParam) error {
+	post := r.posts[postUid]
+	post.Title, post.Content, post.Modified = param.Title, param.Content, param.Modified
+	r.posts[postUid] = post
+	return nil
+}
+func (r *importRepoStub) UpdatePostContent(postUid uint, content string) error {
+	r.contents[postUid] = content
+	return nil
+}
+
+type importBoardRepo struct{ repositories.BoardRepository }
+
+func (importBoardRepo) GetBoardConfig(boardUid uint) models.BoardConfig {
+	return models.BoardConfig{Uid: boardUid, Id: "diary", Type: models.BOARD_BLOG, Admin: models.BoardAdminUid{Board: 2}}
+}
+
+type importEditRepo struct {
+	repositories.BoardEditRepository
+	images []models.SavedImage
+}
+
+func (r *importEditRepo) InsertImagePaths(_ uint, _ uint, images []models.SavedImage) error {
+	r.images = append(r.images, images...)
+	return nil
+}
+func (*importEditRepo) InsertFile(models.EditorSaveFileParam) (uint, error) { return 100, nil }
+
+// 원격 이미지를 AVIF와 대체 WebP로 저장한 것처럼 업로드 폴더 안의 경로만 돌려주기
+func stubRemoteImage(sources *[]string) func(models.UploadCategory, string, uint) (models.SavedImage, error) {
+	return func(category models.UploadCategory, source string, width uint) (models.SavedImage, error) {
+		*sources = append(*sources, source)
+		dir, err := utils.MakeSavePath(category)
+		if err != nil {
+			return models.SavedImage{}, err
+		}
+		base := fmt.Sprintf("%s/r%d", dir, len(*sources))
+		return models.SavedImage{Path: base + ".avif", Fallback: base + ".webp", Variants: []models.ImageVariant{
+			{Path: base + ".avif", Width: width, Type: "image/avif"},
+			{Path: base + ".webp", Width: width, Type: "image/webp"},
+		}}, nil
+	}
+}
+
+type importBoardService struct{ BoardService }
+
+func (importBoardService) SaveTags(uint, uint, []string) error { return nil }
+func (importBoardService) SaveThumbnail(uint, uint, string) models.BoardThumbnail {
+	return models.BoardThumbnail{}
+}
+
+func TestWordPressImportDryRunAndRerun(t *testing.T) {
+	previous := configs.Env
+	configs.Env.UploadDir = t.TempDir()
+	t.Cleanup(func() { configs.Env = previous })
+
+	repo := &importRepoStub{maps: make(map[string]uint), posts: make(map[uint]models.ImportPostParam), contents: make(map[uint]string)}
+	fetched := make([]string, 0)
+	edit := &importEditRepo{}
+	variants := &imageVariantRepoStub{stored: map[string][]models.ImageVariant{}, inserted: map[string]uint{}}
+	service := NewNuboImportService(&repositories.Repository{
+		Board:        importBoardRepo{},
+		BoardEdit:    edit,
+		ImageVariant: variants,
+		Import:       repo,
+	}, importBoardService{})
+	service.fetchImage = func(source string, _ string, _ uint) error {
+		fetched = append(fetched, source)
+		return nil
+	}
+	service.saveImage = stubRemoteImage(&fetched)
+	service.now = func() time.Time { return time.UnixMilli(1_700_000_000_000) }
+
+	site := models.WXRSite{Link: "https://old.example.com", Attachments: map[uint]string{}, Posts: []models.WXRPost{
+		{Id: 1, Title: "First", Link: "https://old.example.com/first/", Type: "post", Status: "publish", Submitted: 1000,
+			Content: `<p>See <a href="https://www.old.example.com/second">second</a> <img src="/a.jpg"></p>`,
+			Comments: []models.WXRComment{
+				{Id: 2, ParentId: 1, Author: "Lee", Content: "reply", Approved: true},
+				{Id: 1, Author: "Member", AuthorEmail: "member@example.com", Content: "hello", Approved: true},
+				{Id: 3, Content: "spam", Approved: false},
+			}},
+		{Id: 2, Title: "Second", Link: "https://old.example.com/second/", Type: "post", Status: "private", Submitted: 2000,
+			Categories: []string{"Diary"}, Content: `<p>Back to <a href="https://old.example.com/?p=1">first</a></p>`},
+		{Id: 3, Title: "Draft", Type: "post", Status: "draft"},
+		{Id: 4, Title: "About", Type: "page", Status: "publish"},
+	}}
+	param := models.WordPressImportParam{BoardUid: 1, UserUid: 2, DryRun: true, Site: site}
+
+	job, err := service.ImportWordPress(param)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := job.Report
+	if report.Posts != 2 || report.IgnoredItems != 2 || report.Comments != 2 || report.Images != 1 || report.RewrittenLinks != 2 {
+		t.Fatalf("unexpected dry-run report %+v", report)
+	}
+	if len(repo.posts) != 0 || len(fetched) != 0 || len(repo.maps) != 0 {
+		t.Fatal("dry run should not write anything")
+	}
+
+	param.DryRun = false
+	run := newWordPressImport(service, param, importBoardRepo{}.GetBoardConfig(1))
+	if report, err = run.execute(); err != nil {
+		t.Fatal(err)
+	}
+	if report.Posts != 2 || report.Comments != 2 || len(repo.posts) != 2 || len(repo.comments) != 2 {
+		t.Fatalf("unexpected import report %+v", report)
+	}
+	uids := make(map[string]uint)
+	for uid, post := range repo.posts {
+		uids[post.Title] = uid
+	}
+	first, second := repo.posts[uids["First"]], repo.posts[uids["Second"]]
+	if first.Submitted != 1000 || first.UserUid != 2 || second.Status != models.CONTENT_SECRET || second.CategoryUid < 1 {
+		t.Fatalf("posts should keep timestamps, owner, status and category: %+v %+v", first, second)
+	}
+	if !strings.Contains(first.Content, `src="/upload/images/`) || !strings.Contains(first.Content, `r1.avif"`) {
+		t.Fatalf("images should point to re-hosted copies, got %q", first.Content)
+	}
+	if len(edit.images) != 1 || !strings.HasPrefix(edit.images[0].Path, "/upload/images/") || !strings.HasSuffix(edit.images[0].Fallback, "/r1.webp") {
+		t.Fatalf("inserted images should be recorded with their fallback, got %+v", edit.images)
+	}
+	if len(variants.stored[edit.images[0].Path]) != 2 {
+		t.Fatalf("responsive variants should be recorded when the image is saved, got %+v", variants.stored)
+	}
+	if !strings.Contains(repo.contents[uids["First"]], fmt.Sprintf(`href="/blog/diary/%d"`, uids["Second"])) ||
+		!strings.Contains(repo.contents[uids["Second"]], fmt.Sprintf(`href="/blog/diary/%d"`, uids["First"])) {
+		t.Fatalf("links between imported posts should be rewritten, got %v", repo.contents)
+	}
+	if repo.comments[0].UserUid != 5 || repo.comments[1].UserUid != 2 || repo.comments[1].ParentUid == 0 ||
+		!strings.Contains(repo.comments[1].Content, "<strong>Lee</strong>") {
+		t.Fatalf("comments should match members by email and keep replies, got %+v", repo.comments)
+	}
+
+	imported := len(repo.posts)
+	run = newWordPressImport(service, param, importBoardRepo{}.GetBoardConfig(1))
+	if report, err = run.execute(); err != nil {
+		t.Fatal(err)
+	}
+	if report.Posts != 0 || report.SkippedPosts != 2 || report.SkippedComments != 2 || len(repo.posts) != imported {
+		t.Fatalf("re-run should skip everything already imported, got %+v", report)
+	}
+}
+
+func TestWordPressImportRewritesLinksInEarlierRuns(t *testing.T) {
+	previous := configs.Env
+	configs.Env.UploadDir = t.TempDir()
+	t.Cleanup(func() { configs.Env = previous })
+
+	repo := &importRepoStub{maps: make(map[string]uint), posts: make(map[uint]models.ImportPostParam), contents: make(map[uint]string)}
+	service := NewNuboImportService(&repositories.Repository{
+		Board:     importBoardRepo{},
+		BoardEdit: &importEditRepo{},
+		Import:    repo,
+	}, importBoardService{})
+
+	// 이전 실행이 첫 글만 저장하고 링크를 고치기 전에 멈춘 상태
+	repo.nextUid = 10
+	repo.posts[10] = models.ImportPostParam{Title: "First", Content: `<p><a href="https://old.example.com/second/">second</a></p>`}
+	repo.maps[models.IMPORT_KIND_POST+utils.GetHashedString("1")] = 10
+
+	site := models.WXRSite{Link: "https://old.example.com", Attachments: map[uint]string{}, Posts: []models.WXRPost{
+		{Id: 1, Title: "First", Link: "https://old.example.com/first/", Type: "post", Status: "publish", Submitted: 1000},
+		{Id: 2, Title: "Second", Link: "https://old.example.com/second/", Type: "post", Status: "publish", Submitted: 2000},
+	}}
+	run := newWordPressImport(service, models.WordPressImportParam{BoardUid: 1, UserUid: 2, Site: site}, importBoardRepo{}.GetBoardConfig(1))
+	report, err := run.execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Posts != 1 || report.SkippedPosts != 1 || report.RewrittenLinks != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if !strings.Contains(repo.contents[10], `href="/blog/diary/11"`) {
+		t.Fatalf("links in posts from earlier runs should be rewritten, got %q", repo.contents[10])
+	}
+	if _, _, ok := repo.FindImportMap(1, "", models.IMPORT_KIND_POST, utils.GetHashedString("2")); !ok {
+		t.Fatal("the new post should be linked to its source together with the insert")
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+const (
+	linkPreviewRetryAfter  = time.Hour
+	linkPreviewConcurrency = 2
+)
+
+// 본문이나 쪽지의 링크 미리보기를 캐시에서 꺼내주고, 없거나 오래된 항목은 백그라운드에서 가져오기
+type linkPreviewer struct {
+	repo      repositories.LinkPreviewRepository
+	config    configs.LinkPreviewConfig
+	fetch     func(pageURL string) (models.LinkPreview, string, error)
+	saveImage func(imageURL string) (string, error)
+	now       func() time.Time
+	mu        sync.Mutex
+	pending   map[string]struct{}
+	slots     chan struct{}
+}
+
+func newLinkPreviewer(repo repositories.LinkPreviewRepository) *linkPreviewer {
+	config := configs.GetLinkPreviewConfig()
+	client := utils.NewPublicHTTPClient(time.Duration(config.TimeoutSeconds) * time.Second)
+	return &linkPreviewer{
+		repo:   repo,
+		config: config,
+		fetch: func(pageURL string) (models.LinkPreview, string, error) {
+			return utils.FetchLinkPreview(client, pageURL)
+		},
+		saveImage: saveLinkPreviewImage,
+		now:       time.Now,
+		pending:   make(map[string]struct{}),
+		slots:     make(chan struct{}, linkPreviewConcurrency),
+	}
+}
+
+// 미리보기 대표 이미지를 썸네일 크기로 줄여 저장하고 공개 경로 반환
+func saveLinkPreviewImage(imageURL string) (string, error) {
+	dirPath, err := utils.MakeSavePath(models.UPLOAD_PREVIEW)
+	if err != nil {
+		return "", err
+	}
+	savePath := fmt.Sprintf("%s/%s.webp", dirPath, uuid.New().String())
+	if err := utils.DownloadImage(imageURL, savePath, configs.SIZE_THUMBNAIL.Number()); err != nil {
+		return "", err
+	}
+	publicPath, err := utils.PublicUploadPath(savePath)
+	if err != nil {
+		_ = os.Remove(savePath)
+		return "", err
+	}
+	return publicPath, nil
+}
+
+// 본문에 포함된 링크들의 미리보기 목록 반환 (아직 캐시에 없는 링크는 다음 요청부터 포함됨)
+func (p *linkPreviewer) Previews(text string) []models.LinkPreview {
+	previews := make([]models.LinkPreview, 0)
+	if p == nil || !p.config.Enabled {
+		return previews
+	}
+	for _, link := range utils.ExtractLinks(text, p.config.MaxLinks) {
+		record, err := p.repo.GetLinkPreview(utils.GetHashedString(link))
+		if err != nil {
+			p.refresh(link)
+			continue
+		}
+		age := p.now().Sub(time.UnixMilli(record.Fetched))
+		if record.Failed {
+			if age > linkPreviewRetryAfter {
+				p.refresh(link)
+			}
+			continue
+		}
+		if age > time.Duration(p.config.CacheHours)*time.Hour {
+			p.refresh(link)
+		}
+		previews = append(previews, record.LinkPreview)
+	}
+	return previews
+}
+
+// 같은 주소를 동시에 두 번 가져오지 않도록 하고 백그라운드에서 미리보기 갱신하기
+func (p *linkPreviewer) refresh(link string) {
+	p.mu.Lock()
+	if _, exists := p.pending[link]; exists {
+		p.mu.Unlock()
+		return
+	}
+	p.pending[link] = struct{}{}
+	p.mu.Unlock()
+
+	go func() {
+		defer func() {
+			p.mu.Lock()
+			delete(p.pending, link)
+			p.mu.Unlock()
+		}()
+		p.slots <- struct{}{}
+		defer func() { <-p.slots }()
+		p.update(link)
+	}()
+}
+
+// 링크 미리보기를 가져와 캐시에 저장하기 (실패도 기록해서 재시도 간격을 둠)
+func (p *linkPreviewer) update(link string) {
+	record := models.LinkPreviewRecord{UrlHash: utils.GetHashedString(link), Fetched: p.now().UnixMilli()}
+	preview, imageURL, err := p.fetch(link)
+	record.LinkPreview = preview
+	record.Url = link
+	if err != nil {
+		record.Failed = true
+	} else if imageURL != "" {
+		if image, err := p.saveImage(imageURL); err == nil {
+			record.Image = image
+		}
+	}
+	if err := p.repo.SaveLinkPreview(record); err != nil {
+		log.Printf("link preview: failed to save %s: %v", link, err)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type linkPreviewRepoStub struct {
+	repositories.LinkPreviewRepository
+	mu      sync.Mutex
+	records map[string]models.LinkPreviewRecord
+	saved   chan models.LinkPreviewRecord
+}
+
+func (r *linkPreviewRepoStub) GetLinkPreview(urlHash string) (models.LinkPreviewRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.records[urlHash]
+	if !ok {
+		return record, sql.ErrNoRows
+	}
+	return record, nil
+}
+
+func (r *linkPreviewRepoStub) SaveLinkPreview(record models.LinkPreviewRecord) error {
+	r.saved <- record
+	return nil
+}
+
+func TestLinkPreviewerUsesCacheAndRefreshesInBackground(t *testing.T) {
+	now := time.UnixMilli(10_000_000)
+	cached := models.LinkPreviewRecord{Fetched: now.UnixMilli()}
+	cached.Url, cached.Title = "https://example.com/cached", "Cached"
+	failed := models.LinkPreviewRecord{Failed: true, Fetched: now.UnixMilli()}
+	repo := &linkPreviewRepoStub{
+		records: map[string]models.LinkPreviewRecord{
+			utils.GetHashedString("https://example.com/cached"): cached,
+			utils.GetHashedString("https://example.com/broken"): failed,
+		},
+		saved: make(chan models.LinkPreviewRecord, 1),
+	}
+	previewer := &linkPreviewer{
+		repo:   repo,
+		config: configs.LinkPreviewConfig{Enabled: true, MaxLinks: 3, CacheHours: 24},
+		fetch: func(pageURL string) (models.LinkPreview, string, error) {
+			return models.LinkPreview{Url: pageURL, Title: "Fresh"}, "https://example.com/cover.png", nil
+		},
+		saveImage: func(string) (string, error) { return "/upload/previews/cover.webp", nil },
+		now:       func() time.Time { return now },
+		pending:   make(map[string]struct{}),
+		slots:     make(chan struct{}, 1),
+	}
+
+	previews := previewer.Previews("https://example.com/cached https://example.com/broken https://example.com/new")
+	if len(previews) != 1 || previews[0].Title != "Cached" {
+		t.Fatalf("only fresh cached previews should be returned, got %+v", previews)
+	}
+	select {
+	case saved := <-repo.saved:
+		if saved.Url != "https://example.com/new" || saved.Title != "Fresh" || saved.Image != "/upload/previews/cover.webp" || saved.Failed {
+			t.Fatalf("unexpected saved preview: %+v", saved)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("missing preview was not fetched in background")
+	}
+
+	var disabled *linkPreviewer
+	if got := disabled.Previews("https://example.com/cached"); len(got) != 0 {
+		t.Fatalf("nil previewer should return no previews, got %+v", got)
+	}
+}
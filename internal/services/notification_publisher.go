@@ -78,6 +78,8 @@ func notificationBody(name string, notificationType models.Noti) string {
 		models.NOTI_LEAVE_COMMENT: "내 사진에 댓글을 남겼습니다",
 		models.NOTI_REPLY_COMMENT: "내 댓글에 답글을 남겼습니다",
 		models.NOTI_CHAT_MESSAGE:  "나에게 메시지를 보냈습니다",
+		models.NOTI_POST_APPROVED: "내 글을 승인했습니다",
+		models.NOTI_POST_REJECTED: "내 글을 반려했습니다",
 	}[notificationType]
 	if action == "" {
 		action = "새로운 활동을 남겼습니다"
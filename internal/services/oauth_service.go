@@ -1,8 +1,6 @@
 package services
 
 import (
-	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/google/uuid"
@@ -32,22 +30,20 @@ func NewNuboOAuthService(repos *repositories.Repository) *NuboOAuthService {
 
 // OAuth 계정에 프로필 이미지가 있다면 가져와 저장하기
 func (s *NuboOAuthService) SaveProfileImage(userUid uint, profile string) {
-	dirPath, err := utils.MakeSavePath(models.UPLOAD_PROFILE)
+	saved, err := utils.SaveRemoteImage(models.UPLOAD_PROFILE, profile, configs.SIZE_PROFILE.Number())
 	if err != nil {
 		return
 	}
-	newSavePath := fmt.Sprintf("%s/%s.webp", dirPath, uuid.New().String())
-	if err := utils.DownloadImage(profile, newSavePath, configs.SIZE_PROFILE.Number()); err != nil {
-		return
-	}
-	publicPath, err := utils.PublicUploadPath(newSavePath)
+	publicProfile, err := utils.PublicSavedImage(saved)
 	if err != nil {
-		_ = os.Remove(newSavePath)
+		utils.RemoveSavedImage(saved)
 		return
 	}
-	if err := s.repos.User.UpdateUserProfile(userUid, publicPath); err != nil {
-		_ = os.Remove(newSavePath)
+	if err := s.repos.User.UpdateUserProfile(userUid, publicProfile.Path, publicProfile.Fallback); err != nil {
+		_ = utils.RemoveUploadFile(publicProfile.Path)
+		return
 	}
+	_ = s.repos.ImageVariant.InsertImageVariants(0, publicProfile.Path, publicProfile.Variants)
 }
 
 // OAuth 로그인 시 미가입 상태이면 바로 등록해주기 (프로필도 있으면 함께)
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+const approvalReasonMaxRunes = 500
+
+// 승인 대기 게시글 목록 가져오기 (관리자가 아니면 내 글만)
+func (s *NuboBoardService) GetPendingPosts(param models.BoardApprovalParam) (models.BoardApprovalResult, error) {
+	if s.repos.Board.GetBoardConfig(param.BoardUid).Uid < 1 {
+		return models.BoardApprovalResult{}, fmt.Errorf("board does not exist")
+	}
+	if param.Page < 1 {
+		param.Page = 1
+	}
+	if param.Limit < 1 || param.Limit > 100 {
+		param.Limit = 20
+	}
+	if s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid) {
+		param.UserUid = 0
+	}
+	return s.repos.Approval.GetPendingPosts(param)
+}
+
+// 승인 대기 게시글을 공개하고 작성자에게 알리기
+func (s *NuboBoardService) ApprovePost(param models.BoardApprovalDecisionParam) error {
+	writerUid, err := s.checkApprovalDecision(param)
+	if err != nil {
+		return err
+	}
+	if err := s.repos.Approval.ApprovePost(param.PostUid); err != nil {
+		return err
+	}
+	s.related.Invalidate(param.PostUid)
+	s.federation.PublishPost(param.BoardUid, param.PostUid, models.AP_CREATE)
+	s.notifyApprovalDecision(param, writerUid, models.NOTI_POST_APPROVED)
+	return nil
+}
+
+// 승인 대기 게시글을 반려(삭제)하고 작성자에게 사유 알리기
+func (s *NuboBoardService) RejectPost(param models.BoardApprovalDecisionParam) error {
+	if len([]rune(strings.TrimSpace(param.Reason))) < 2 {
+		return fmt.Errorf("reason for rejection is required")
+	}
+	writerUid, err := s.checkApprovalDecision(param)
+	if err != nil {
+		return err
+	}
+	if err := s.RemovePost(param.BoardUid, param.PostUid, param.UserUid); err != nil {
+		return err
+	}
+	if err := s.repos.Approval.RemoveApproval(param.PostUid); err != nil {
+		return err
+	}
+	s.notifyApprovalDecision(param, writerUid, models.NOTI_POST_REJECTED)
+	return nil
+}
+
+// 승인/반려 권한과 대상 글 상태 확인 후 작성자 고유 번호 반환
+func (s *NuboBoardService) checkApprovalDecision(param models.BoardApprovalDecisionParam) (uint, error) {
+	if !s.repos.BoardView.IsPostInBoard(param.PostUid, param.BoardUid) {
+		return 0, fmt.Errorf("post does not belong to this board")
+	}
+	if !s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid) {
+		return 0, fmt.Errorf("only board administrator can review pending posts")
+	}
+	if s.repos.Comment.GetPostStatus(param.PostUid) != models.CONTENT_PENDING {
+		return 0, fmt.Errorf("post is not waiting for approval")
+	}
+	return s.repos.Comment.GetPostWriterUid(param.PostUid), nil
+}
+
+// 승인/반려 결과를 알림으로 보내고, 사유가 있으면 채팅 메시지로도 전달하기
+func (s *NuboBoardService) notifyApprovalDecision(param models.BoardApprovalDecisionParam, writerUid uint, notiType models.Noti) {
+	if writerUid < 1 || writerUid == param.UserUid {
+		return
+	}
+	s.notifications.Save(models.InsertNotificationParam{
+		ActionUserUid: param.UserUid,
+		TargetUserUid: writerUid,
+		NotiType:      notiType,
+		PostUid:       param.PostUid,
+	}, true)
+
+	reason := []rune(strings.TrimSpace(param.Reason))
+	if len(reason) < 1 {
+		return
+	}
+	if len(reason) > approvalReasonMaxRunes {
+		reason = reason[:approvalReasonMaxRunes]
+	}
+	s.repos.Chat.InsertNewChat(param.UserUid, writerUid, utils.Escape(string(reason)))
+}
+
+// 수정한 글이 승인 대기 중이면 승인 후 적용할 상태를 바꾸고, 수정하면서 새로 보류되었으면 승인 대기열에 넣기
+func saveEditedApproval(repos *repositories.Repository, postUid uint, param models.EditorWriteParam, wasPending bool) error {
+	if !param.IsPending {
+		return nil
+	}
+	if wasPending {
+		return repos.Approval.UpdateApprovalStatus(postUid, utils.GetContentStatus(param.IsNotice, param.IsSecret))
+	}
+	param.UserUid = repos.Comment.GetPostWriterUid(postUid)
+	return repos.Approval.InsertApproval(postUid, param)
+}
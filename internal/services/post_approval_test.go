@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type approvalAuthRepo struct{ repositories.AuthRepository }
+
+func (approvalAuthRepo) CheckPermissionByUid(userUid uint, _ uint) bool { return userUid == 1 }
+
+type approvalBoardRepo struct{ repositories.BoardRepository }
+
+func (approvalBoardRepo) GetBoardConfig(boardUid uint) models.BoardConfig {
+	return models.BoardConfig{Uid: boardUid, RequireApproval: true}
+}
+
+type approvalViewRepo struct {
+	repositories.BoardViewRepository
+}
+
+func (approvalViewRepo) IsPostInBoard(uint, uint) bool { return true }
+
+type approvalCommentRepo struct {
+	repositories.CommentRepository
+	status models.Status
+}
+
+func (r *approvalCommentRepo) GetPostStatus(uint) models.Status { return r.status }
+func (r *approvalCommentRepo) GetPostWriterUid(uint) uint       { return 1 }
+
+type approvalQueueRepo struct {
+	repositories.ApprovalRepository
+	listed   models.BoardApprovalParam
+	approved uint
+}
+
+func (r *approvalQueueRepo) GetPendingPosts(param models.BoardApprovalParam) (models.BoardApprovalResult, error) {
+	r.listed = param
+	return models.BoardApprovalResult{}, nil
+}
+
+func (r *approvalQueueRepo) ApprovePost(postUid uint) error {
+	r.approved = postUid
+	return nil
+}
+
+func TestPostApprovalQueue(t *testing.T) {
+	comments := &approvalCommentRepo{status: models.CONTENT_NORMAL}
+	queue := &approvalQueueRepo{}
+	service := &NuboBoardService{repos: &repositories.Repository{
+		Approval:  queue,
+		Auth:      approvalAuthRepo{},
+		Board:     approvalBoardRepo{},
+		BoardView: approvalViewRepo{},
+		Comment:   comments,
+	}}
+
+	if _, err := service.GetPendingPosts(models.BoardApprovalParam{BoardUid: 2, UserUid: 7}); err != nil {
+		t.Fatalf("list pending posts: %v", err)
+	}
+	if queue.listed.UserUid != 7 || queue.listed.Page != 1 || queue.listed.Limit != 20 {
+		t.Fatalf("member should only see own pending posts with default paging, got %+v", queue.listed)
+	}
+	if _, err := service.GetPendingPosts(models.BoardApprovalParam{BoardUid: 2, UserUid: 1, Limit: 500}); err != nil {
+		t.Fatalf("list pending posts as admin: %v", err)
+	}
+	if queue.listed.UserUid != 0 || queue.listed.Limit != 20 {
+		t.Fatalf("admin should see every pending post, got %+v", queue.listed)
+	}
+
+	decision := models.BoardApprovalDecisionParam{BoardUid: 2, PostUid: 5, UserUid: 1}
+	if err := service.ApprovePost(decision); err == nil {
+		t.Fatal("post that is not pending should not be approved")
+	}
+	comments.status = models.CONTENT_PENDING
+	decision.UserUid = 7
+	if err := service.ApprovePost(decision); err == nil {
+		t.Fatal("member should not approve pending posts")
+	}
+	if err := service.RejectPost(models.BoardApprovalDecisionParam{BoardUid: 2, PostUid: 5, UserUid: 1, Reason: " "}); err == nil {
+		t.Fatal("rejection without a reason should fail")
+	}
+	decision.UserUid = 1
+	if err := service.ApprovePost(decision); err != nil {
+		t.Fatalf("approve pending post: %v", err)
+	}
+	if queue.approved != 5 {
+		t.Fatalf("approved post = %d, want 5", queue.approved)
+	}
+}
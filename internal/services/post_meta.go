@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+// 없는 글이거나 이 사이트의 게시글 주소가 아닐 때의 오류
+var ErrPostMetaNotFound = errors.New("post not found")
+
+// 비밀글이거나 레벨 제한 등으로 볼 수 없는 글일 때의 오류
+var ErrPostMetaPrivate = errors.New("post is not public")
+
+// 조회수나 포인트를 바꾸지 않고 게시글 보기 결과로 OpenGraph 메타데이터 만들기 (볼 수 없는 비밀글, 포인트가 모자란 유료글은 거부)
+func (s *NuboBoardService) GetPostMeta(param models.BoardViewParam) (models.BoardMetaResult, error) {
+	if !s.repos.BoardView.IsPostInBoard(param.PostUid, param.BoardUid) {
+		return models.BoardMetaResult{}, ErrPostMetaNotFound
+	}
+	userLv, userPt, needPt, err := s.checkViewLevel(param)
+	if err != nil {
+		return models.BoardMetaResult{}, fmt.Errorf("%w: %v", ErrPostMetaPrivate, err)
+	}
+	if needPt < 0 && userPt < utils.Abs(needPt) {
+		return models.BoardMetaResult{}, fmt.Errorf("%w: not enough point", ErrPostMetaPrivate)
+	}
+	view, err := s.loadViewItem(param, userLv)
+	if err != nil {
+		return models.BoardMetaResult{}, fmt.Errorf("%w: %v", ErrPostMetaNotFound, err)
+	}
+	if view.Post.Status == models.CONTENT_SECRET && !view.IsAdmin && view.Post.Writer.UserUid != param.UserUid {
+		return models.BoardMetaResult{}, ErrPostMetaPrivate
+	}
+	return postMeta(view), nil
+}
+
+// 게시글 보기 결과를 메타데이터로 바꾸기
+func postMeta(view models.BoardViewResult) models.BoardMetaResult {
+	content := utils.Unescape(view.Post.Content)
+	title := utils.Unescape(view.Post.Title)
+	result := models.BoardMetaResult{
+		SiteName: configs.Env.Title,
+		Board: models.BoardBasicConfig{
+			Id:   view.Config.Id,
+			Type: view.Config.Type,
+			Name: view.Config.Name,
+		},
+		PostUid:       view.Post.Uid,
+		Title:         title,
+		Description:   utils.CutString(utils.PlainText(content), models.META_DESCRIPTION_LENGTH),
+		Canonical:     fmt.Sprintf("%s/%s/%s/%d", siteURL(), view.Config.Type.String(), url.PathEscape(view.Config.Id), view.Post.Uid),
+		Cover:         metaCover(view, content),
+		PublishedTime: metaTime(view.Post.Submitted),
+		ModifiedTime:  metaTime(max(view.Post.Submitted, view.Post.Modified)),
+		Tags:          make([]string, 0, len(view.Tags)),
+	}
+	result.Author.Name = view.Post.Writer.Name
+	if view.Post.Writer.Profile != "" {
+		result.Author.Profile = metaAbsoluteURL(view.Post.Writer.Profile)
+	}
+	if result.Cover.URL != "" && result.Cover.Alt == "" {
+		result.Cover.Alt = title
+	}
+	for _, tag := range view.Tags {
+		result.Tags = append(result.Tags, tag.Name)
+	}
+	return result
+}
+
+// 대표 이미지 고르기 (첫 첨부 이미지의 큰 썸네일, 목록 커버, 본문 첫 이미지 순서)
+func metaCover(view models.BoardViewResult, content string) models.BoardMetaImage {
+	if len(view.Images) > 0 && view.Images[0].Thumbnail.Large != "" {
+		image := view.Images[0]
+		cover := models.BoardMetaImage{URL: metaAbsoluteURL(image.Thumbnail.Large), Alt: image.Description}
+		if image.Exif.Width > 0 && image.Exif.Height > 0 {
+			cover.Width = min(image.Exif.Width, configs.SIZE_FULL.Number())
+			cover.Height = image.Exif.Height * cover.Width / image.Exif.Width
+		}
+		return cover
+	}
+	if view.Post.Cover != "" {
+		return models.BoardMetaImage{URL: metaAbsoluteURL(view.Post.Cover)}
+	}
+	if sources := utils.ExtractImageSources(content, siteURL()+"/"); len(sources) > 0 {
+		return models.BoardMetaImage{URL: sources[0]}
+	}
+	return models.BoardMetaImage{}
+}
+
+// 업로드 경로 같은 상대 주소를 사이트 절대 주소로 바꾸기
+func metaAbsoluteURL(link string) string {
+	if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
+		return link
+	}
+	return siteURL() + "/" + strings.TrimPrefix(link, "/")
+}
+
+// 밀리초 시간을 RFC3339 형식으로 바꾸기 (시간이 없으면 빈 문자열)
+func metaTime(timestamp uint64) string {
+	if timestamp < 1 {
+		return ""
+	}
+	return time.UnixMilli(int64(timestamp)).UTC().Format(time.RFC3339)
+}
+
+// 이 사이트의 게시글 주소(/<게시판 종류>/<게시판 아이디>/<글 번호>)에서 게시판 종류, 아이디, 글 번호 꺼내기
+func parsePostURL(link string) (string, string, uint, bool) {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", "", 0, false
+	}
+	site, err := url.Parse(siteURL())
+	if err != nil || !strings.EqualFold(parsed.Hostname(), site.Hostname()) {
+		return "", "", 0, false
+	}
+	path := strings.TrimPrefix(parsed.Path, strings.TrimRight(site.Path, "/"))
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 {
+		return "", "", 0, false
+	}
+	postUid, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil || postUid < 1 {
+		return "", "", 0, false
+	}
+	return parts[0], parts[1], uint(postUid), true
+}
+
+// 최대 크기를 넘지 않도록 가로세로 비율을 유지하며 줄이기 (최대 크기가 0이면 제한 없음)
+func fitOEmbedSize(width, height, maxWidth, maxHeight uint) (uint, uint) {
+	if maxWidth > 0 && width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+	if maxHeight > 0 && height > maxHeight {
+		width = width * maxHeight / height
+		height = maxHeight
+	}
+	return width, height
+}
+
+// 게시글 주소에 대한 oEmbed 응답 만들기 (크기를 아는 사진이 있는 갤러리 글은 photo, 나머지는 rich)
+func (s *NuboBoardService) GetOEmbed(param models.OEmbedParam) (models.OEmbedResult, error) {
+	boardType, boardId, postUid, ok := parsePostURL(param.URL)
+	if !ok {
+		return models.OEmbedResult{}, ErrPostMetaNotFound
+	}
+	boardUid := s.repos.Board.GetBoardUidById(boardId)
+	if boardUid < 1 {
+		return models.OEmbedResult{}, ErrPostMetaNotFound
+	}
+	config := s.repos.Board.GetBoardConfig(boardUid)
+	if config.Type.String() != boardType {
+		return models.OEmbedResult{}, ErrPostMetaNotFound
+	}
+	meta, err := s.GetPostMeta(models.BoardViewParam{BoardUid: boardUid, PostUid: postUid})
+	if err != nil {
+		return models.OEmbedResult{}, err
+	}
+
+	result := models.OEmbedResult{
+		Version:      models.OEMBED_VERSION,
+		Title:        meta.Title,
+		AuthorName:   meta.Author.Name,
+		ProviderName: meta.SiteName,
+		ProviderURL:  siteURL(),
+		CacheAge:     models.OEMBED_CACHE_AGE,
+	}
+	cover := meta.Cover
+	hasSize := cover.URL != "" && cover.Width > 0 && cover.Height > 0
+	if hasSize {
+		result.ThumbnailURL = cover.URL
+		result.ThumbnailWidth, result.ThumbnailHeight = fitOEmbedSize(cover.Width, cover.Height, param.MaxWidth, param.MaxHeight)
+	}
+	if config.Type == models.BOARD_GALLERY && hasSize {
+		result.Type = "photo"
+		result.URL = cover.URL
+		result.Width, result.Height = result.ThumbnailWidth, result.ThumbnailHeight
+		return result, nil
+	}
+
+	result.Type = "rich"
+	result.Width, result.Height = fitOEmbedSize(models.OEMBED_RICH_WIDTH, models.OEMBED_RICH_HEIGHT, param.MaxWidth, param.MaxHeight)
+	result.HTML = fmt.Sprintf(`<blockquote class="nubo-embed"><p><a href="%s">%s</a></p><p>%s</p><footer>%s · %s</footer></blockquote>`,
+		html.EscapeString(meta.Canonical), html.EscapeString(meta.Title), html.EscapeString(meta.Description),
+		html.EscapeString(meta.Author.Name), html.EscapeString(meta.SiteName))
+	return result, nil
+}
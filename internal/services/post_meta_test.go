@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type metaBoardRepo struct{ repositories.BoardRepository }
+
+var metaBoards = map[uint]models.BoardConfig{
+	1: {Uid: 1, Id: "diary", Name: "Diary", Type: models.BOARD_BLOG},
+	2: {Uid: 2, Id: "photo", Name: "Photo", Type: models.BOARD_GALLERY},
+}
+
+func (metaBoardRepo) GetBoardUidById(id string) uint {
+	for uid, config := range metaBoards {
+		if config.Id == id {
+			return uid
+		}
+	}
+	return 0
+}
+func (metaBoardRepo) GetBoardConfig(boardUid uint) models.BoardConfig { return metaBoards[boardUid] }
+
+type metaViewRepo struct {
+	repositories.BoardViewRepository
+	posts  map[uint]models.BoardListItem
+	needLv int
+	needPt int
+}
+
+func (r metaViewRepo) IsPostInBoard(postUid uint, boardUid uint) bool {
+	return postUid/10 == boardUid && r.posts[postUid].Uid > 0
+}
+func (metaViewRepo) CheckBannedByWriter(uint, uint) bool { return false }
+func (r metaViewRepo) GetNeededLevelPoint(uint, uint, models.BoardAction) (int, int) {
+	return r.needLv, r.needPt
+}
+func (r metaViewRepo) GetPostItem(postUid uint, _ uint) (models.BoardListItem, error) {
+	return r.posts[postUid], nil
+}
+func (metaViewRepo) GetAttachments(uint) ([]models.BoardAttachment, error) {
+	return []models.BoardAttachment{}, nil
+}
+func (metaViewRepo) GetAttachedImages(postUid uint) ([]models.BoardAttachedImage, error) {
+	if postUid != 21 {
+		return []models.BoardAttachedImage{}, nil
+	}
+	return []models.BoardAttachedImage{{
+		Thumbnail:   models.BoardThumbnail{Large: "/upload/thumbnails/f1.webp"},
+		Exif:        models.BoardExif{Width: 4000, Height: 3000},
+		Description: "A lake",
+	}}, nil
+}
+func (metaViewRepo) GetTags(uint) []models.Pair { return []models.Pair{{Uid: 1, Name: "travel"}} }
+
+type metaUserRepo struct{ repositories.UserRepository }
+
+func (metaUserRepo) GetUserLevelPoint(uint) (int, int) { return 0, 0 }
+
+type metaAuthRepo struct{ repositories.AuthRepository }
+
+func (metaAuthRepo) CheckPermissionByUid(uint, uint) bool { return false }
+
+func TestPostMetaAndOEmbedHonorRestrictions(t *testing.T) {
+	previous := configs.Env
+	configs.Env.Domain = "https://example.com/"
+	configs.Env.Title = "NUBO"
+	t.Cleanup(func() { configs.Env = previous })
+
+	writer := models.BoardWriter{UserBasicInfo: models.UserBasicInfo{UserUid: 3, Name: "Kim", Profile: "/upload/profile/kim.webp"}}
+	view := metaViewRepo{posts: map[uint]models.BoardListItem{
+		11: {BoardCommonPostItem: models.BoardCommonPostItem{Uid: 11, Title: "Tom &amp; Jerry", Status: models.CONTENT_NORMAL,
+			Content: `<p>Hello <b>world</b></p><img src="/upload/images/a.webp">`, Submitted: 1_700_000_000_000},
+			BoardCommonListItem: models.BoardCommonListItem{Writer: writer}},
+		12: {BoardCommonPostItem: models.BoardCommonPostItem{Uid: 12, Title: "Hidden", Status: models.CONTENT_SECRET},
+			BoardCommonListItem: models.BoardCommonListItem{Writer: writer}},
+		21: {BoardCommonPostItem: models.BoardCommonPostItem{Uid: 21, Title: "Lake", Status: models.CONTENT_NORMAL,
+			Submitted: 1_700_000_000_000, Modified: 1_700_000_100_000}, BoardCommonListItem: models.BoardCommonListItem{Writer: writer}},
+	}}
+	service := &NuboBoardService{repos: &repositories.Repository{
+		Auth:      metaAuthRepo{},
+		Board:     metaBoardRepo{},
+		BoardView: view,
+		User:      metaUserRepo{},
+		ImageVariant: &imageVariantRepoStub{stored: map[string][]models.ImageVariant{
+			"/upload/images/a.webp": {{Path: "/upload/images/a_w320.webp", Width: 320, Type: "image/webp"}},
+		}},
+	}}
+
+	meta, err := service.GetPostMeta(models.BoardViewParam{BoardUid: 1, PostUid: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "Tom & Jerry" || meta.Description != "Hello world" || meta.Canonical != "https://example.com/blog/diary/11" ||
+		meta.Cover.URL != "https://example.com/upload/images/a.webp" || meta.Author.Profile != "https://example.com/upload/profile/kim.webp" ||
+		meta.PublishedTime != "2023-11-14T22:13:20Z" || meta.ModifiedTime != meta.PublishedTime || meta.Tags[0] != "travel" {
+		t.Fatalf("unexpected meta %+v", meta)
+	}
+	if _, err := service.GetPostMeta(models.BoardViewParam{BoardUid: 1, PostUid: 12}); !errors.Is(err, ErrPostMetaPrivate) {
+		t.Fatalf("secret posts should be private, got %v", err)
+	}
+	if meta, err := service.GetPostMeta(models.BoardViewParam{BoardUid: 1, PostUid: 12, UserUid: 3}); err != nil || meta.Title != "Hidden" {
+		t.Fatalf("writers should see their secret post meta, got %+v %v", meta, err)
+	}
+
+	rich, err := service.GetOEmbed(models.OEmbedParam{URL: "https://EXAMPLE.com/blog/diary/11?ref=x", MaxWidth: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rich.Type != "rich" || rich.Width != 300 || rich.Height != 100 || rich.ThumbnailURL != "" ||
+		!strings.Contains(rich.HTML, `href="https://example.com/blog/diary/11"`) || !strings.Contains(rich.HTML, "Tom &amp; Jerry") {
+		t.Fatalf("unexpected rich embed %+v", rich)
+	}
+	photo, err := service.GetOEmbed(models.OEmbedParam{URL: "https://example.com/gallery/photo/21", MaxWidth: 800})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if photo.Type != "photo" || photo.URL != "https://example.com/upload/thumbnails/f1.webp" || photo.Width != 800 || photo.Height != 600 {
+		t.Fatalf("gallery posts with a sized image should embed as photos, got %+v", photo)
+	}
+
+	for _, link := range []string{"https://other.com/blog/diary/11", "https://example.com/gallery/diary/11", "https://example.com/blog/diary/21"} {
+		if _, err := service.GetOEmbed(models.OEmbedParam{URL: link}); !errors.Is(err, ErrPostMetaNotFound) {
+			t.Fatalf("%s should not be found, got %v", link, err)
+		}
+	}
+	if _, err := service.GetOEmbed(models.OEmbedParam{URL: "https://example.com/blog/diary/12"}); !errors.Is(err, ErrPostMetaPrivate) {
+		t.Fatalf("secret posts should not be embedded, got %v", err)
+	}
+	view.needPt = -5
+	service.repos.BoardView = view
+	if _, err := service.GetPostMeta(models.BoardViewParam{BoardUid: 1, PostUid: 11}); !errors.Is(err, ErrPostMetaPrivate) {
+		t.Fatalf("point-charged posts should be private to viewers who cannot pay, got %v", err)
+	}
+	if _, err := service.GetOEmbed(models.OEmbedParam{URL: "https://example.com/blog/diary/11"}); !errors.Is(err, ErrPostMetaPrivate) {
+		t.Fatalf("point-charged posts should not be embedded, got %v", err)
+	}
+	view.needPt = 0
+	view.needLv = 1
+	service.repos.BoardView = view
+	if _, err := service.GetOEmbed(models.OEmbedParam{URL: "https://example.com/blog/diary/11"}); !errors.Is(err, ErrPostMetaPrivate) {
+		t.Fatalf("level-restricted posts should not be embedded, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+const (
+	shareDefaultHours = 24
+	shareMaxHours     = 24 * 30
+	shareMaxViews     = 1000
+	shareLogLimit     = 100
+)
+
+var errShareInvalid = fmt.Errorf("share link is invalid, expired or revoked")
+
+// 유효한(폐기·만료되지 않은) 공유 링크 찾기, 열람 횟수 제한은 글 보기에서만 적용
+func findActiveShare(repo repositories.ShareRepository, token string) (models.PostShareItem, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return models.PostShareItem{}, errShareInvalid
+	}
+	share, err := repo.GetShareByToken(utils.GetHashedString(token))
+	if err != nil || share.Revoked || share.Expires <= time.Now().UnixMilli() {
+		return share, errShareInvalid
+	}
+	return share, nil
+}
+
+// 비밀글 공유 링크 만들기 (작성자 또는 관리자만)
+func (s *NuboBoardService) CreateShare(param models.PostShareCreateParam) (models.PostShareCreated, error) {
+	result := models.PostShareCreated{}
+	if err := s.checkShareOwner(param.BoardUid, param.PostUid, param.UserUid); err != nil {
+		return result, err
+	}
+	if s.repos.Comment.GetPostStatus(param.PostUid) != models.CONTENT_SECRET {
+		return result, fmt.Errorf("only secret posts can be shared by link")
+	}
+	if param.Hours == 0 {
+		param.Hours = shareDefaultHours
+	}
+	if param.Hours > shareMaxHours {
+		return result, fmt.Errorf("share link expiry must be within %d hours", shareMaxHours)
+	}
+	if param.MaxViews > shareMaxViews {
+		return result, fmt.Errorf("view limit must be %d or less", shareMaxViews)
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return result, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(random)
+	created := time.Now()
+	item := models.PostShareItem{
+		BoardUid:      param.BoardUid,
+		PostUid:       param.PostUid,
+		CreatedBy:     param.UserUid,
+		Created:       created.UnixMilli(),
+		Expires:       created.Add(time.Duration(param.Hours) * time.Hour).UnixMilli(),
+		MaxViews:      param.MaxViews,
+		AllowComment:  param.AllowComment,
+		AllowDownload: param.AllowDownload,
+	}
+	uid, err := s.repos.Share.InsertShare(item, utils.GetHashedString(token))
+	if err != nil {
+		return result, err
+	}
+	item.Uid = uid
+	config := s.repos.Board.GetBoardConfig(param.BoardUid)
+	result.PostShareItem = item
+	result.Token = token
+	result.URL = fmt.Sprintf("%s/board/%s/%d?share=%s", siteURL(), config.Id, param.PostUid, token)
+	return result, nil
+}
+
+// 게시글에 발급한 공유 링크 목록 가져오기
+func (s *NuboBoardService) GetShares(param models.PostShareManageParam) ([]models.PostShareItem, error) {
+	if err := s.checkShareOwner(param.BoardUid, param.PostUid, param.UserUid); err != nil {
+		return nil, err
+	}
+	return s.repos.Share.GetShares(param.PostUid)
+}
+
+// 공유 링크 열람 기록 가져오기
+func (s *NuboBoardService) GetShareLogs(param models.PostShareManageParam) ([]models.PostShareLog, error) {
+	if _, err := s.findOwnedShare(param); err != nil {
+		return nil, err
+	}
+	return s.repos.Share.GetShareLogs(param.ShareUid, shareLogLimit)
+}
+
+// 공유 링크 폐기하기
+func (s *NuboBoardService) RevokeShare(param models.PostShareManageParam) error {
+	if _, err := s.findOwnedShare(param); err != nil {
+		return err
+	}
+	return s.repos.Share.RevokeShare(param.ShareUid)
+}
+
+// 공유 링크로 비밀글 보기 (열람 횟수를 차감하고 기록 남기기)
+func (s *NuboBoardService) ViewSharedPost(param models.PostShareViewParam) (models.PostShareViewResult, error) {
+	result := models.PostShareViewResult{}
+	share, err := findActiveShare(s.repos.Share, param.Token)
+	if err != nil {
+		return result, err
+	}
+	status := s.repos.Comment.GetPostStatus(share.PostUid)
+	if status == models.CONTENT_REMOVED || status == models.CONTENT_PENDING {
+		return result, fmt.Errorf("shared post is not available")
+	}
+	if !s.repos.Share.ConsumeShareView(share.Uid) {
+		return result, fmt.Errorf("share link has reached its view limit")
+	}
+	ipHash := ""
+	if param.IP != "" {
+		ipHash = utils.GetHashedString(param.IP)
+	}
+	if err := s.repos.Share.InsertShareLog(models.PostShareLog{
+		ShareUid: share.Uid,
+		UserUid:  param.UserUid,
+		IPHash:   ipHash,
+		Agent:    utils.CutString(param.Agent, 200),
+	}); err != nil {
+		log.Printf("share: failed to log view of share %d: %v", share.Uid, err)
+	}
+
+	post, err := s.repos.BoardView.GetPostItem(share.PostUid, param.UserUid)
+	if err != nil {
+		return result, err
+	}
+	images, err := s.repos.BoardView.GetAttachedImages(share.PostUid)
+	if err != nil {
+		return result, err
+	}
+	result.Files = make([]models.BoardAttachment, 0)
+	if share.AllowDownload {
+		if result.Files, err = s.repos.BoardView.GetAttachments(share.PostUid); err != nil {
+			return result, err
+		}
+	}
+	result.Board = s.repos.BoardView.GetBasicBoardConfig(share.BoardUid)
+	result.Post = post
+	result.Images = images
+	result.Tags = s.repos.BoardView.GetTags(share.PostUid)
+	result.Expires = share.Expires
+	result.AllowComment = share.AllowComment
+	result.AllowDownload = share.AllowDownload
+	return result, nil
+}
+
+// 공유 링크로 첨부파일 다운로드 정보 가져오기 (작성자가 허용한 경우만)
+func (s *NuboBoardService) DownloadSharedFile(token string, fileUid uint) (models.BoardViewDownloadResult, error) {
+	var result models.BoardViewDownloadResult
+	share, err := findActiveShare(s.repos.Share, token)
+	if err != nil {
+		return result, err
+	}
+	if !share.AllowDownload {
+		return result, fmt.Errorf("download is not allowed for this share link")
+	}
+	if s.repos.BoardView.GetFilePostUid(fileUid, share.BoardUid) != share.PostUid {
+		return result, fmt.Errorf("file does not belong to the shared post")
+	}
+	if s.repos.Comment.GetPostStatus(share.PostUid) == models.CONTENT_REMOVED {
+		return result, fmt.Errorf("file is not available")
+	}
+	result = s.repos.BoardView.GetDownloadInfo(fileUid)
+	if utils.GetFileSize(result.Path) < 1 {
+		return result, fmt.Errorf("file not found")
+	}
+	return result, nil
+}
+
+// 공유 링크를 관리할 수 있는 작성자 또는 관리자인지 확인
+func (s *NuboBoardService) checkShareOwner(boardUid uint, postUid uint, userUid uint) error {
+	if !s.repos.BoardView.IsPostInBoard(postUid, boardUid) {
+		return fmt.Errorf("post does not belong to this board")
+	}
+	isAdmin := s.repos.Auth.CheckPermissionByUid(userUid, boardUid)
+	isWriter := s.repos.BoardView.IsWriter(models.TABLE_POST, postUid, userUid)
+	if !isAdmin && !isWriter {
+		return fmt.Errorf("only the writer can manage share links of this post")
+	}
+	return nil
+}
+
+// 관리 권한이 있는 공유 링크 가져오기
+func (s *NuboBoardService) findOwnedShare(param models.PostShareManageParam) (models.PostShareItem, error) {
+	share, err := s.repos.Share.GetShare(param.ShareUid)
+	if err != nil || share.BoardUid != param.BoardUid {
+		return share, fmt.Errorf("share link does not exist")
+	}
+	return share, s.checkShareOwner(share.BoardUid, share.PostUid, param.UserUid)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type shareRepoStub struct {
+	repositories.ShareRepository
+	shares map[string]models.PostShareItem
+	logs   []models.PostShareLog
+}
+
+func (r *shareRepoStub) GetShareByToken(tokenHash string) (models.PostShareItem, error) {
+	share, ok := r.shares[tokenHash]
+	if !ok {
+		return share, errShareInvalid
+	}
+	return share, nil
+}
+
+func (r *shareRepoStub) ConsumeShareView(shareUid uint) bool {
+	for hash, share := range r.shares {
+		if share.Uid == shareUid && (share.MaxViews == 0 || share.Views < share.MaxViews) {
+			share.Views++
+			r.shares[hash] = share
+			return true
+		}
+	}
+	return false
+}
+
+func (r *shareRepoStub) InsertShareLog(item models.PostShareLog) error {
+	r.logs = append(r.logs, item)
+	return nil
+}
+
+type shareViewRepo struct {
+	repositories.BoardViewRepository
+}
+
+func (shareViewRepo) GetPostItem(postUid uint, _ uint) (models.BoardListItem, error) {
+	item := models.BoardListItem{}
+	item.Uid, item.Title = postUid, "secret"
+	return item, nil
+}
+func (shareViewRepo) GetAttachedImages(uint) ([]models.BoardAttachedImage, error) {
+	return []models.BoardAttachedImage{}, nil
+}
+func (shareViewRepo) GetAttachments(uint) ([]models.BoardAttachment, error) {
+	return []models.BoardAttachment{{Pair: models.Pair{Uid: 1, Name: "a.pdf"}}}, nil
+}
+func (shareViewRepo) GetBasicBoardConfig(uint) models.BoardBasicConfig {
+	return models.BoardBasicConfig{Id: "free"}
+}
+func (shareViewRepo) GetTags(uint) []models.Pair { return []models.Pair{} }
+
+type shareCommentRepo struct{ repositories.CommentRepository }
+
+func (shareCommentRepo) GetPostStatus(uint) models.Status { return models.CONTENT_SECRET }
+
+func TestViewSharedPostHonorsExpiryAndViewCap(t *testing.T) {
+	future := time.Now().Add(time.Hour).UnixMilli()
+	shares := &shareRepoStub{shares: map[string]models.PostShareItem{
+		utils.GetHashedString("capped"):  {Uid: 1, BoardUid: 2, PostUid: 5, Expires: future, MaxViews: 1},
+		utils.GetHashedString("expired"): {Uid: 2, BoardUid: 2, PostUid: 5, Expires: time.Now().Add(-time.Minute).UnixMilli()},
+		utils.GetHashedString("revoked"): {Uid: 3, BoardUid: 2, PostUid: 5, Expires: future, Revoked: true},
+		utils.GetHashedString("files"):   {Uid: 4, BoardUid: 2, PostUid: 5, Expires: future, AllowDownload: true, AllowComment: true},
+	}}
+	service := &NuboBoardService{repos: &repositories.Repository{
+		BoardView: shareViewRepo{},
+		Comment:   shareCommentRepo{},
+		Share:     shares,
+	}}
+
+	result, err := service.ViewSharedPost(models.PostShareViewParam{Token: "capped", IP: "127.0.0.1", Agent: "test"})
+	if err != nil {
+		t.Fatalf("first shared view: %v", err)
+	}
+	if len(result.Files) != 0 || result.AllowDownload {
+		t.Fatal("files should be hidden unless the writer allowed downloads")
+	}
+	if len(shares.logs) != 1 || shares.logs[0].IPHash == "127.0.0.1" {
+		t.Fatalf("shared view should be logged with a hashed address, got %+v", shares.logs)
+	}
+	if _, err := service.ViewSharedPost(models.PostShareViewParam{Token: "capped"}); err == nil {
+		t.Fatal("view cap should stop the second view")
+	}
+	for _, token := range []string{"expired", "revoked", "unknown", ""} {
+		if _, err := service.ViewSharedPost(models.PostShareViewParam{Token: token}); err == nil {
+			t.Fatalf("token %q should be rejected", token)
+		}
+	}
+
+	result, err = service.ViewSharedPost(models.PostShareViewParam{Token: "files"})
+	if err != nil {
+		t.Fatalf("shared view with downloads: %v", err)
+	}
+	if len(result.Files) != 1 || !result.AllowComment {
+		t.Fatalf("writer-allowed files and comments should be exposed, got %+v", result)
+	}
+
+	comments := &NuboCommentService{repos: service.repos}
+	if !comments.canCommentByShare("files", 5) {
+		t.Fatal("share link allowing comments should grant comment access")
+	}
+	if comments.canCommentByShare("files", 6) || comments.canCommentByShare("capped", 5) {
+		t.Fatal("share link must not grant comments on other posts or when not allowed")
+	}
+}
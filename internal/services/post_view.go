@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+const postViewPruneInterval = time.Hour
+
+// 같은 조회자가 기간 안에 다시 본 글은 조회수와 포인트 차감에서 제외하기
+type postViewCounter struct {
+	repo   repositories.BoardViewRepository
+	window func() time.Duration
+	now    func() time.Time
+	mu     sync.Mutex
+	pruned time.Time
+}
+
+func newPostViewCounter(repo repositories.BoardViewRepository) *postViewCounter {
+	return &postViewCounter{
+		repo:   repo,
+		window: func() time.Duration { return time.Duration(configs.GetViewWindowHours()) * time.Hour },
+		now:    time.Now,
+	}
+}
+
+// 회원은 고유 번호로, 비회원은 IP와 User-Agent로 조회자를 구분하는 해시 만들기 (봇이면 빈 문자열)
+func postViewerHash(userUid uint, ip string, agent string) string {
+	if userUid > 0 {
+		return utils.GetHashedString(fmt.Sprintf("user:%d", userUid))
+	}
+	if utils.IsBotAgent(agent) {
+		return ""
+	}
+	return utils.GetHashedString(fmt.Sprintf("guest:%s|%s", ip, agent))
+}
+
+// 조회를 기록하고 실제로 조회수가 올라갔는지 반환하기
+func (c *postViewCounter) Count(postUid uint, viewerHash string) bool {
+	if viewerHash == "" {
+		return false
+	}
+	if c == nil {
+		return true
+	}
+	counted, err := c.repo.CountPostView(postUid, viewerHash, c.since())
+	if err != nil {
+		log.Printf("view: failed to count view of post %d: %v", postUid, err)
+	}
+	c.prune()
+	return counted
+}
+
+func (c *postViewCounter) since() int64 {
+	return c.now().Add(-c.window()).UnixMilli()
+}
+
+// 한 시간에 한 번 중복 확인 기간이 지난 기록 정리하기
+func (c *postViewCounter) prune() {
+	now := c.now()
+	c.mu.Lock()
+	if now.Sub(c.pruned) < postViewPruneInterval {
+		c.mu.Unlock()
+		return
+	}
+	c.pruned = now
+	c.mu.Unlock()
+
+	if err := c.repo.RemovePostViewsBefore(c.since()); err != nil {
+		log.Printf("view: failed to prune old views: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type postViewRepoStub struct {
+	repositories.BoardViewRepository
+	viewed map[string]int64
+	hits   uint
+	pruned int64
+}
+
+func (r *postViewRepoStub) CountPostView(_ uint, viewerHash string, since int64) (bool, error) {
+	if viewed, ok := r.viewed[viewerHash]; ok && viewed >= since {
+		return false, nil
+	}
+	r.viewed[viewerHash] = since + int64(time.Hour/time.Millisecond)
+	r.hits++
+	return true, nil
+}
+
+func (r *postViewRepoStub) RemovePostViewsBefore(before int64) error {
+	r.pruned = before
+	return nil
+}
+
+func TestPostViewCounterDeduplicatesViewers(t *testing.T) {
+	now := time.UnixMilli(100_000_000)
+	repo := &postViewRepoStub{viewed: make(map[string]int64)}
+	counter := &postViewCounter{
+		repo:   repo,
+		window: func() time.Duration { return time.Hour },
+		now:    func() time.Time { return now },
+	}
+
+	browser := "Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0"
+	member := postViewerHash(7, "203.0.113.1", browser)
+	guest := postViewerHash(0, "203.0.113.1", browser)
+	if member == guest || postViewerHash(7, "198.51.100.2", "") != member {
+		t.Fatal("members should be identified by uid regardless of ip and agent")
+	}
+	if postViewerHash(0, "203.0.113.1", "Mozilla/5.0 (compatible; Googlebot/2.1)") != "" ||
+		postViewerHash(0, "203.0.113.1", "") != "" {
+		t.Fatal("bots and empty agents should not be counted")
+	}
+
+	if !counter.Count(1, member) {
+		t.Fatal("first view should be counted")
+	}
+	if counter.Count(1, member) {
+		t.Fatal("repeated view within the window should not be counted")
+	}
+	if !counter.Count(1, guest) || counter.Count(1, "") {
+		t.Fatal("guest view should be counted once and bot views never")
+	}
+	if repo.hits != 2 || repo.pruned != now.Add(-time.Hour).UnixMilli() {
+		t.Fatalf("unexpected hits %d or prune boundary %d", repo.hits, repo.pruned)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if !counter.Count(1, member) {
+		t.Fatal("view after the window should be counted again")
+	}
+}
+
+func TestViewPointCheckDoesNotDependOnViewerHash(t *testing.T) {
+	service := &NuboBoardService{repos: &repositories.Repository{
+		BoardView: metaViewRepo{posts: map[uint]models.BoardListItem{
+			11: {BoardCommonPostItem: models.BoardCommonPostItem{Uid: 11, Status: models.CONTENT_NORMAL}},
+		}, needPt: -5},
+		User: metaUserRepo{},
+	}}
+	for _, agent := range []string{"", "curl/8", "Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0"} {
+		_, err := service.GetViewItem(models.BoardViewParam{BoardUid: 1, PostUid: 11, IP: "203.0.113.1", Agent: agent})
+		if err == nil || err.Error() != "not enough point" {
+			t.Fatalf("guest with agent %q should not read a paid post without points, got %v", agent, err)
+		}
+	}
+}
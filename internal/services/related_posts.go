@@ -0,0 +1,244 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sirini/goapi/pkg/models"
+)
+
+const (
+	relatedDefaultLimit    = 5
+	relatedMaxLimit        = 20
+	relatedCandidateLimit  = 30
+	relatedDescriptionScan = 200
+	relatedCacheTTL        = 30 * time.Minute
+	relatedCacheLimit      = 2000
+	relatedTagWeight       = 3.0
+	relatedCategoryWeight  = 1.0
+	relatedImageWeight     = 4.0
+)
+
+// 게시글별 관련 게시글 후보 캐시 (수정, 삭제 시 무효화, 만료되거나 가득 차면 오래된 것부터 제거)
+type relatedPostCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	limit   int
+	now     func() time.Time
+	entries map[uint]relatedPostEntry
+}
+
+type relatedPostEntry struct {
+	items   []models.BoardRelatedCandidate
+	updated time.Time
+}
+
+// 관련 게시글 캐시 만들기
+func newRelatedPostCache(ttl time.Duration, limit int) *relatedPostCache {
+	return &relatedPostCache{ttl: ttl, limit: limit, now: time.Now, entries: make(map[uint]relatedPostEntry)}
+}
+
+// 관련 게시글 후보 저장하기 (가득 찼으면 만료된 것, 그래도 없으면 가장 오래된 것 제거)
+func (c *relatedPostCache) Set(postUid uint, items []models.BoardRelatedCandidate) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if _, ok := c.entries[postUid]; !ok && len(c.entries) >= c.limit {
+		var oldestUid uint
+		var oldest time.Time
+		for uid, entry := range c.entries {
+			if now.Sub(entry.updated) > c.ttl {
+				delete(c.entries, uid)
+				continue
+			}
+			if oldest.IsZero() || entry.updated.Before(oldest) {
+				oldestUid, oldest = uid, entry.updated
+			}
+		}
+		if len(c.entries) >= c.limit {
+			delete(c.entries, oldestUid)
+		}
+	}
+	c.entries[postUid] = relatedPostEntry{items: items, updated: now}
+}
+
+// 만료되지 않은 관련 게시글 후보 가져오기 (만료된 것은 제거)
+func (c *relatedPostCache) Get(postUid uint) ([]models.BoardRelatedCandidate, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[postUid]
+	if !ok {
+		return nil, false
+	}
+	if c.now().Sub(entry.updated) > c.ttl {
+		delete(c.entries, postUid)
+		return nil, false
+	}
+	return entry.items, true
+}
+
+// 지정된 게시글 및 그 게시글을 후보로 가진 캐시들 무효화하기
+func (c *relatedPostCache) Invalidate(postUid uint) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, postUid)
+	for uid, entry := range c.entries {
+		for _, item := range entry.items {
+			if item.PostUid == postUid {
+				delete(c.entries, uid)
+				break
+			}
+		}
+	}
+}
+
+// 게시글 보기 화면에 보여줄 관련 게시글 가져오기 (보는 사람의 권한과 차단 목록 반영)
+func (s *NuboBoardService) getRelatedPosts(postUid uint, userUid uint, limit uint) []models.BoardRelatedPost {
+	if limit < 1 {
+		limit = relatedDefaultLimit
+	}
+	if limit > relatedMaxLimit {
+		limit = relatedMaxLimit
+	}
+
+	candidates, ok := s.related.Get(postUid)
+	if !ok {
+		candidates = s.rankRelatedCandidates(postUid)
+		s.related.Set(postUid, candidates)
+	}
+
+	userLv, _ := s.repos.User.GetUserLevelPoint(userUid)
+	allowed := make(map[uint]bool)
+	items := make([]models.BoardRelatedPost, 0, limit)
+	for _, candidate := range candidates {
+		if uint(len(items)) >= limit {
+			break
+		}
+		canView, checked := allowed[candidate.BoardUid]
+		if !checked {
+			needLv, _ := s.repos.BoardView.GetNeededLevelPoint(candidate.BoardUid, userUid, models.BOARD_ACTION_VIEW)
+			canView = userLv >= needLv
+			allowed[candidate.BoardUid] = canView
+		}
+		if !canView {
+			continue
+		}
+		if userUid > 0 && s.repos.BoardView.CheckBannedByWriter(candidate.PostUid, userUid) {
+			continue
+		}
+
+		items = append(items, models.BoardRelatedPost{
+			Board:     s.repos.BoardView.GetBasicBoardConfig(candidate.BoardUid),
+			PostUid:   candidate.PostUid,
+			Title:     candidate.Title,
+			Cover:     s.repos.Board.GetCoverImage(candidate.PostUid),
+			Submitted: candidate.Submitted,
+			Score:     relatedScore(candidate),
+		})
+	}
+	return items
+}
+
+// 태그 겹침, 같은 카테고리, 이미지 설명 유사도를 합산해서 관련 게시글 후보 정렬하기
+func (s *NuboBoardService) rankRelatedCandidates(postUid uint) []models.BoardRelatedCandidate {
+	merged := make(map[uint]*models.BoardRelatedCandidate)
+	merge := func(item models.BoardRelatedCandidate) *models.BoardRelatedCandidate {
+		if exist, ok := merged[item.PostUid]; ok {
+			return exist
+		}
+		merged[item.PostUid] = &models.BoardRelatedCandidate{
+			PostUid:   item.PostUid,
+			BoardUid:  item.BoardUid,
+			UserUid:   item.UserUid,
+			Title:     item.Title,
+			Submitted: item.Submitted,
+		}
+		return merged[item.PostUid]
+	}
+
+	if tagged, err := s.repos.Related.FindBySharedTags(postUid, relatedCandidateLimit); err == nil {
+		for _, item := range tagged {
+			merge(item).SharedTags = item.SharedTags
+		}
+	}
+	if categorized, err := s.repos.Related.FindByCategory(postUid, relatedCandidateLimit); err == nil {
+		for _, item := range categorized {
+			merge(item).SameCategory = true
+		}
+	}
+	if words := descriptionWords(s.repos.Related.GetImageDescription(postUid)); len(words) > 0 {
+		if described, err := s.repos.Related.FindByImageDescription(postUid, relatedDescriptionScan); err == nil {
+			for _, item := range described {
+				if similarity := jaccardSimilarity(words, descriptionWords(item.Description)); similarity > 0 {
+					merge(item).Similarity = similarity
+				}
+			}
+		}
+	}
+
+	items := make([]models.BoardRelatedCandidate, 0, len(merged))
+	for _, item := range merged {
+		items = append(items, *item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		left, right := relatedScore(items[i]), relatedScore(items[j])
+		if left == right {
+			return items[i].PostUid > items[j].PostUid
+		}
+		return left > right
+	})
+	if len(items) > relatedCandidateLimit {
+		items = items[:relatedCandidateLimit]
+	}
+	return items
+}
+
+// 관련 게시글 후보의 점수 계산하기
+func relatedScore(item models.BoardRelatedCandidate) float64 {
+	score := float64(item.SharedTags)*relatedTagWeight + item.Similarity*relatedImageWeight
+	if item.SameCategory {
+		score += relatedCategoryWeight
+	}
+	return score
+}
+
+// 이미지 설명 텍스트를 비교용 단어 집합으로 만들기
+func descriptionWords(text string) map[string]struct{} {
+	words := make(map[string]struct{})
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, field := range fields {
+		if len([]rune(field)) < 2 {
+			continue
+		}
+		words[field] = struct{}{}
+	}
+	return words
+}
+
+// 두 단어 집합의 자카드 유사도 계산하기
+func jaccardSimilarity(left, right map[string]struct{}) float64 {
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range left {
+		if _, ok := right[word]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(left)+len(right)-shared)
+}
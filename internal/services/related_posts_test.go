@@ -0,0 +1,118 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type relatedRepo struct {
+	repositories.RelatedRepository
+	calls int
+}
+
+func (r *relatedRepo) FindBySharedTags(uint, uint) ([]models.BoardRelatedCandidate, error) {
+	r.calls++
+	return []models.BoardRelatedCandidate{
+		{PostUid: 11, BoardUid: 1, Title: "two tags", SharedTags: 2},
+		{PostUid: 12, BoardUid: 2, Title: "members only", SharedTags: 3},
+		{PostUid: 13, BoardUid: 1, Title: "blocked writer", SharedTags: 3},
+	}, nil
+}
+
+func (r *relatedRepo) FindByCategory(uint, uint) ([]models.BoardRelatedCandidate, error) {
+	return []models.BoardRelatedCandidate{{PostUid: 14, BoardUid: 1, Title: "same category"}}, nil
+}
+
+func (r *relatedRepo) GetImageDescription(uint) string { return "a red bicycle near the river" }
+
+func (r *relatedRepo) FindByImageDescription(uint, uint) ([]models.BoardRelatedCandidate, error) {
+	return []models.BoardRelatedCandidate{
+		{PostUid: 14, BoardUid: 1, Description: "red bicycle"},
+		{PostUid: 15, BoardUid: 1, Description: "mountain lake"},
+	}, nil
+}
+
+type relatedBoardViewRepo struct {
+	repositories.BoardViewRepository
+}
+
+func (relatedBoardViewRepo) GetNeededLevelPoint(boardUid uint, _ uint, _ models.BoardAction) (int, int) {
+	if boardUid == 2 {
+		return 5, 0
+	}
+	return 0, 0
+}
+
+func (relatedBoardViewRepo) CheckBannedByWriter(postUid uint, _ uint) bool { return postUid == 13 }
+
+func (relatedBoardViewRepo) GetBasicBoardConfig(boardUid uint) models.BoardBasicConfig {
+	return models.BoardBasicConfig{Id: "free"}
+}
+
+type relatedUserRepo struct{ repositories.UserRepository }
+
+func (relatedUserRepo) GetUserLevelPoint(uint) (int, int) { return 1, 0 }
+
+type relatedCoverRepo struct{ repositories.BoardRepository }
+
+func (relatedCoverRepo) GetCoverImage(uint) string { return "" }
+
+func TestRelatedPostsRespectViewerAndInvalidateOnEdit(t *testing.T) {
+	related := &relatedRepo{}
+	repos := &repositories.Repository{
+		Board:     relatedCoverRepo{},
+		BoardView: relatedBoardViewRepo{},
+		Related:   related,
+		User:      relatedUserRepo{},
+	}
+	service := NewNuboBoardService(repos)
+
+	items := service.getRelatedPosts(10, 7, 5)
+	got := make([]uint, 0, len(items))
+	for _, item := range items {
+		got = append(got, item.PostUid)
+	}
+	want := []uint{11, 14}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("related posts = %v, want %v", got, want)
+	}
+
+	service.getRelatedPosts(10, 7, 5)
+	if related.calls != 1 {
+		t.Fatalf("related candidates were recomputed %d times, want cached", related.calls)
+	}
+	service.related.Invalidate(14)
+	service.getRelatedPosts(10, 7, 5)
+	if related.calls != 2 {
+		t.Fatal("editing a related post did not invalidate the cached list")
+	}
+}
+
+func TestRelatedPostCacheEvictsExpiredAndOldestEntries(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cache := newRelatedPostCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.Set(1, nil)
+	now = now.Add(10 * time.Second)
+	cache.Set(2, nil)
+	now = now.Add(10 * time.Second)
+	cache.Set(3, nil)
+	if _, ok := cache.Get(1); ok || len(cache.entries) != 2 {
+		t.Fatalf("a full cache should drop its oldest entry, got %v", cache.entries)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.Get(2); ok || len(cache.entries) != 1 {
+		t.Fatalf("expired entries should be removed on read, got %v", cache.entries)
+	}
+	cache.Set(4, nil)
+	now = now.Add(10 * time.Second)
+	cache.Set(5, nil)
+	if _, ok := cache.entries[4]; !ok || len(cache.entries) != 2 {
+		t.Fatalf("a full cache should drop expired entries before fresh ones, got %v", cache.entries)
+	}
+}
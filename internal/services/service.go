@@ -14,19 +14,26 @@ import (
 
 // 모든 서비스들을 관리
 type Service struct {
-	Admin   AdminService
-	Auth    AuthService
-	Board   BoardService
-	Blog    BlogService
-	Chat    ChatService
-	Comment CommentService
-	Home    HomeService
-	Noti    NotiService
-	OAuth   OAuthService
-	Push    PushService
-	Sync    SyncService
-	Trade   TradeService
-	User    UserService
+	ActivityPub ActivityPubService
+	Admin       AdminService
+	Analytics   AnalyticsService
+	Archive     ArchiveService
+	Auth        AuthService
+	Board       BoardService
+	Blog        BlogService
+	Chat        ChatService
+	Comment     CommentService
+	Feed        FeedService
+	Home        HomeService
+	Image       ImageVariantService
+	Import      ImportService
+	Noti        NotiService
+	OAuth       OAuthService
+	Push        PushService
+	Sync        SyncService
+	Trade       TradeService
+	Trending    TrendingService
+	User        UserService
 }
 
 func applyPointChange(repo repositories.UserRepository, param models.UpdatePointParam) error {
@@ -59,19 +66,55 @@ func NewService(repos *repositories.Repository) *Service {
 	board.notifications = notifications
 	chat.notifications = notifications
 	comment.notifications = notifications
+
+	filter := newContentFilter(repos.Filter)
+	spam := newSpamGuard(repos.Spam, repos.User)
+	links := newLinkPreviewer(repos.LinkPreview)
+	feed := NewNuboFeedService(repos)
+	activityPub := NewNuboActivityPubService(repos, feed)
+	admin := newNuboAdminService(repos, user, mailer, mailer)
+	auth := newNuboAuthService(repos, transactionalMailer)
+	trade := NewNuboTradeService(repos, board)
+	admin.filter = filter
+	auth.filter = filter
+	board.filter = filter
+	chat.filter = filter
+	comment.filter = filter
+	trade.filter = filter
+	user.filter = filter
+	board.spam = spam
+	chat.spam = spam
+	comment.spam = spam
+	trade.spam = spam
+	board.federation = activityPub
+	admin.federation = activityPub
+	admin.related = board.related
+	activityPub.filter = filter
+	activityPub.spam = spam
+	board.links = links
+	board.views = newPostViewCounter(repos.BoardView)
+	board.readers = newPostReadRecorder(repos.Analytics)
+	chat.links = links
 	return &Service{
-		Admin:   newNuboAdminService(repos, user, mailer, mailer),
-		Auth:    newNuboAuthService(repos, transactionalMailer),
-		Board:   board,
-		Blog:    NewNuboBlogService(repos),
-		Chat:    chat,
-		Comment: comment,
-		Home:    NewNuboHomeService(repos),
-		Noti:    &NuboNotiService{repos: repos, publisher: notifications},
-		OAuth:   NewNuboOAuthService(repos),
-		Push:    NewNuboPushService(repos.Push),
-		Sync:    NewNuboSyncService(repos),
-		Trade:   NewNuboTradeService(repos, board),
-		User:    user,
+		ActivityPub: activityPub,
+		Admin:       admin,
+		Analytics:   NewNuboAnalyticsService(repos),
+		Archive:     NewNuboArchiveService(repos, board),
+		Auth:        auth,
+		Board:       board,
+		Blog:        NewNuboBlogService(repos),
+		Chat:        chat,
+		Comment:     comment,
+		Feed:        feed,
+		Home:        NewNuboHomeService(repos),
+		Image:       NewNuboImageVariantService(repos),
+		Import:      NewNuboImportService(repos, board),
+		Noti:        &NuboNotiService{repos: repos, publisher: notifications},
+		OAuth:       NewNuboOAuthService(repos),
+		Push:        NewNuboPushService(repos.Push),
+		Sync:        NewNuboSyncService(repos, board),
+		Trade:       trade,
+		Trending:    NewNuboTrendingService(repos),
+		User:        user,
 	}
 }
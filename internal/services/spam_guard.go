@@ -0,0 +1,245 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+var (
+	ErrSpamRejected = errors.New("content was rejected as spam")
+	ErrSpamHeld     = errors.New("content is held for spam review")
+)
+
+const (
+	spamDuplicateWindow  = 24 * time.Hour
+	spamPruneInterval    = time.Hour
+	spamMinHashRunes     = 20
+	spamMaxLinkScore     = 5
+	spamManyLinks        = 3
+	spamMaxDuplicate     = 6
+	spamMaxVelocityScore = 4
+)
+
+var spamLinkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s"'<>()]+`)
+
+// 가입 직후 계정이나 낮은 레벨 사용자의 작성 내용에 스팸 점수를 매겨 보류/거부하기
+type spamGuard struct {
+	repo   repositories.SpamRepository
+	users  repositories.UserRepository
+	now    func() time.Time
+	mu     sync.Mutex
+	pruned time.Time
+}
+
+func newSpamGuard(repo repositories.SpamRepository, users repositories.UserRepository) *spamGuard {
+	return &spamGuard{repo: repo, users: users, now: time.Now}
+}
+
+// 작성 내용의 스팸 점수를 계산하고 기록한 후 처리 결과 반환하기
+func (g *spamGuard) Evaluate(target models.SpamTarget, userUid uint, text string) models.SpamVerdict {
+	if g == nil || userUid < 2 {
+		return models.SPAM_VERDICT_ALLOW
+	}
+	config := configs.GetSpamConfig()
+	level, _ := g.users.GetUserLevelPoint(userUid)
+	if config.TrustedLevel > 0 && level >= config.TrustedLevel {
+		return models.SPAM_VERDICT_ALLOW
+	}
+
+	now := g.now()
+	nowMilli := uint64(now.UnixMilli())
+	signal := models.SpamSignal{AccountHours: -1, Level: level, Links: countSpamLinks(text)}
+	if signup, err := g.repo.GetSignupTime(userUid); err == nil && signup > 0 {
+		signal.AccountHours = max(now.Sub(time.UnixMilli(int64(signup))).Hours(), 0)
+	}
+	hash := spamContentHash(text)
+	if len(hash) > 0 {
+		signal.Duplicates, _ = g.repo.CountDuplicates(hash, nowMilli-uint64(spamDuplicateWindow.Milliseconds()))
+	}
+	velocityWindow := time.Duration(config.VelocityMinutes) * time.Minute
+	signal.RecentWrites, _ = g.repo.CountRecentWrites(userUid, nowMilli-uint64(velocityWindow.Milliseconds()))
+
+	score := spamScore(signal, config)
+	verdict := spamVerdict(score, config)
+	if err := g.repo.InsertSpamLog(models.SpamLog{
+		UserUid:   userUid,
+		Target:    target,
+		Hash:      hash,
+		Score:     score,
+		Verdict:   verdict,
+		Timestamp: nowMilli,
+	}); err != nil {
+		log.Printf("spam: failed to record score for user %d: %v", userUid, err)
+	}
+	if verdict != models.SPAM_VERDICT_ALLOW {
+		g.revokeRepeatOffender(userUid, now, config)
+	}
+	g.pruneLogs(now, config)
+	return verdict
+}
+
+// 댓글이나 쪽지의 스팸 검사하기 (보류 대상은 ErrSpamHeld 반환, 검토 목록에 올릴 수 있는 댓글 외에는 호출한 쪽에서 거부)
+func (g *spamGuard) Check(target models.SpamTarget, userUid uint, text string) error {
+	switch g.Evaluate(target, userUid, text) {
+	case models.SPAM_VERDICT_REJECT:
+		return ErrSpamRejected
+	case models.SPAM_VERDICT_HOLD:
+		return ErrSpamHeld
+	}
+	return nil
+}
+
+// 원격(ActivityPub) 답글의 스팸 검사하기 (계정 정보가 없으므로 비회원처럼 링크 수와 중복 내용으로만 판단하고 권한 회수는 하지 않음)
+func (g *spamGuard) CheckRemote(text string) error {
+	if g == nil {
+		return nil
+	}
+	config := configs.GetSpamConfig()
+	now := g.now()
+	nowMilli := uint64(now.UnixMilli())
+	signal := models.SpamSignal{AccountHours: -1, Links: countSpamLinks(text)}
+	hash := spamContentHash(text)
+	if len(hash) > 0 {
+		signal.Duplicates, _ = g.repo.CountDuplicates(hash, nowMilli-uint64(spamDuplicateWindow.Milliseconds()))
+	}
+	score := spamScore(signal, config)
+	verdict := spamVerdict(score, config)
+	if err := g.repo.InsertSpamLog(models.SpamLog{
+		Target:    models.SPAM_TARGET_COMMENT,
+		Hash:      hash,
+		Score:     score,
+		Verdict:   verdict,
+		Timestamp: nowMilli,
+	}); err != nil {
+		log.Printf("spam: failed to record score for a remote reply: %v", err)
+	}
+	g.pruneLogs(now, config)
+
+	switch verdict {
+	case models.SPAM_VERDICT_REJECT:
+		return ErrSpamRejected
+	case models.SPAM_VERDICT_HOLD:
+		return ErrSpamHeld
+	}
+	return nil
+}
+
+// 게시글 스팸 검사하기 (보류 대상은 관리자 검토 전까지 승인 대기열에 저장)
+func (g *spamGuard) CheckPost(param *models.EditorWriteParam) error {
+	switch g.Evaluate(models.SPAM_TARGET_POST, param.UserUid, param.Title+"\n"+param.Content) {
+	case models.SPAM_VERDICT_REJECT:
+		return ErrSpamRejected
+	case models.SPAM_VERDICT_HOLD:
+		param.IsPending = true
+	}
+	return nil
+}
+
+// 기간 내 보류/거부가 반복된 사용자의 글, 댓글, 쪽지 작성 권한 회수하기
+func (g *spamGuard) revokeRepeatOffender(userUid uint, now time.Time, config configs.SpamConfig) {
+	if config.StrikeLimit < 1 {
+		return
+	}
+	since := now.AddDate(0, 0, -config.StrikeDays).UnixMilli()
+	strikes, err := g.repo.CountStrikes(userUid, uint64(since))
+	if err != nil || strikes < config.StrikeLimit {
+		return
+	}
+
+	perm := g.users.LoadUserPermission(userUid)
+	if !perm.WritePost && !perm.WriteComment && !perm.SendChatMessage {
+		return
+	}
+	perm.WritePost = false
+	perm.WriteComment = false
+	perm.SendChatMessage = false
+	if g.users.IsPermissionAdded(userUid) {
+		err = g.users.UpdateUserPermission(userUid, perm)
+	} else {
+		err = g.users.InsertUserPermission(userUid, perm)
+	}
+	if err != nil {
+		log.Printf("spam: failed to revoke permissions of user %d: %v", userUid, err)
+		return
+	}
+	log.Printf("spam: revoked write permissions of user %d after %d strikes", userUid, strikes)
+}
+
+// 판단 기간이 지난 스팸 점수 기록은 한 시간에 한 번씩 정리하기
+func (g *spamGuard) pruneLogs(now time.Time, config configs.SpamConfig) {
+	g.mu.Lock()
+	if now.Sub(g.pruned) < spamPruneInterval {
+		g.mu.Unlock()
+		return
+	}
+	g.pruned = now
+	g.mu.Unlock()
+
+	keep := max(time.Duration(config.StrikeDays)*24*time.Hour, spamDuplicateWindow)
+	if err := g.repo.RemoveSpamLogsBefore(uint64(now.Add(-keep).UnixMilli())); err != nil {
+		log.Printf("spam: failed to prune old logs: %v", err)
+	}
+}
+
+// 계정 나이, 레벨, 링크 수, 중복 내용, 작성 속도를 합산해서 스팸 점수 계산하기
+func spamScore(signal models.SpamSignal, config configs.SpamConfig) int {
+	score := 0
+	if signal.AccountHours >= 0 && signal.AccountHours < float64(config.NewAccountHours) {
+		score++
+		if signal.AccountHours < 1 {
+			score++
+		}
+	}
+	if signal.Level <= 1 {
+		score++
+	}
+	score += min(signal.Links, spamMaxLinkScore)
+	if signal.Links >= spamManyLinks {
+		score += 2
+	}
+	score += min(signal.Duplicates*2, spamMaxDuplicate)
+	if signal.RecentWrites >= config.VelocityLimit {
+		score += min(2+signal.RecentWrites-config.VelocityLimit, spamMaxVelocityScore)
+	}
+	return score
+}
+
+// 스팸 점수에 따른 처리 결과
+func spamVerdict(score int, config configs.SpamConfig) models.SpamVerdict {
+	if score >= config.RejectScore {
+		return models.SPAM_VERDICT_REJECT
+	}
+	if score >= config.HoldScore {
+		return models.SPAM_VERDICT_HOLD
+	}
+	return models.SPAM_VERDICT_ALLOW
+}
+
+// 본문에 포함된 서로 다른 링크 수 세기
+func countSpamLinks(text string) int {
+	links := make(map[string]struct{})
+	for _, link := range spamLinkPattern.FindAllString(text, -1) {
+		link = strings.TrimRight(strings.ToLower(link), ".,!?;:")
+		link = strings.TrimPrefix(strings.TrimPrefix(link, "https://"), "http://")
+		links[strings.TrimPrefix(link, "www.")] = struct{}{}
+	}
+	return len(links)
+}
+
+// 공백과 대소문자 차이를 무시한 내용 해시 만들기 (너무 짧은 내용은 중복 검사 제외)
+func spamContentHash(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	if len([]rune(normalized)) < spamMinHashRunes {
+		return ""
+	}
+	return utils.GetHashedString(normalized)
+}
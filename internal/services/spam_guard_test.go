@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type spamLogRepo struct {
+	repositories.SpamRepository
+	signup  uint64
+	logs    []models.SpamLog
+	strikes int
+}
+
+func (r *spamLogRepo) GetSignupTime(uint) (uint64, error) { return r.signup, nil }
+
+func (r *spamLogRepo) CountDuplicates(hash string, since uint64) (int, error) {
+	count := 0
+	for _, item := range r.logs {
+		if item.Hash == hash && item.Timestamp >= since {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *spamLogRepo) CountRecentWrites(userUid uint, since uint64) (int, error) {
+	count := 0
+	for _, item := range r.logs {
+		if item.UserUid == userUid && item.Timestamp >= since {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *spamLogRepo) CountStrikes(uint, uint64) (int, error) { return r.strikes, nil }
+
+func (r *spamLogRepo) InsertSpamLog(item models.SpamLog) error {
+	r.logs = append(r.logs, item)
+	if item.Verdict != models.SPAM_VERDICT_ALLOW {
+		r.strikes++
+	}
+	return nil
+}
+
+func (r *spamLogRepo) RemoveSpamLogsBefore(uint64) error { return nil }
+
+type spamUserRepo struct {
+	repositories.UserRepository
+	level   int
+	perm    models.UserPermissionResult
+	revoked bool
+}
+
+func (r *spamUserRepo) GetUserLevelPoint(uint) (int, int) { return r.level, 0 }
+
+func (r *spamUserRepo) LoadUserPermission(uint) models.UserPermissionResult { return r.perm }
+
+func (r *spamUserRepo) IsPermissionAdded(uint) bool { return false }
+
+func (r *spamUserRepo) InsertUserPermission(_ uint, perm models.UserPermissionResult) error {
+	r.perm = perm
+	r.revoked = true
+	return nil
+}
+
+func newTestSpamGuard(t *testing.T, accountAge time.Duration, level int) (*spamGuard, *spamLogRepo, *spamUserRepo) {
+	t.Helper()
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.Spam = configs.SpamEnv{}
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &spamLogRepo{signup: uint64(now.Add(-accountAge).UnixMilli())}
+	users := &spamUserRepo{level: level, perm: models.UserPermissionResult{
+		WritePost: true, WriteComment: true, SendChatMessage: true, SendReport: true,
+	}}
+	guard := newSpamGuard(repo, users)
+	guard.now = func() time.Time { return now }
+	return guard, repo, users
+}
+
+func TestSpamGuardScoresFreshLinkSpam(t *testing.T) {
+	guard, _, _ := newTestSpamGuard(t, 10*time.Minute, 1)
+
+	if err := guard.Check(models.SPAM_TARGET_COMMENT, 5, "반갑습니다 잘 부탁드려요 https://example.com"); err != nil {
+		t.Fatalf("single link from a new member should pass: %v", err)
+	}
+	param := models.EditorWriteParam{UserUid: 5, Title: "hi", Content: "https://a.example www.b.example http://c.example/d"}
+	err := guard.CheckPost(&param)
+	if !errors.Is(err, ErrSpamRejected) {
+		t.Fatalf("expected rejection for many links, got %v", err)
+	}
+}
+
+func TestSpamGuardHoldsDuplicatesAndRevokesRepeatOffenders(t *testing.T) {
+	guard, repo, users := newTestSpamGuard(t, 30*24*time.Hour, 2)
+	content := "오늘만 특가 판매합니다 연락 주세요 오픈채팅 ABC123"
+
+	for i := 0; i < 3; i++ {
+		param := models.EditorWriteParam{UserUid: 9, Title: "특가", Content: content}
+		if err := guard.CheckPost(&param); err != nil || param.IsPending {
+			t.Fatalf("write %d should pass: err=%v pending=%v", i, err, param.IsPending)
+		}
+	}
+	param := models.EditorWriteParam{UserUid: 9, Title: "특가", Content: content, IsNotice: true}
+	if err := guard.CheckPost(&param); err != nil {
+		t.Fatalf("held post should not error: %v", err)
+	}
+	if !param.IsPending || param.IsSecret {
+		t.Fatalf("duplicated post should wait for approval instead of turning secret: %+v", param)
+	}
+	if users.revoked {
+		t.Fatal("permissions revoked before strike limit")
+	}
+
+	for i := 0; i < 2; i++ {
+		_ = guard.Check(models.SPAM_TARGET_COMMENT, 9, "특가 "+content)
+		_ = guard.Check(models.SPAM_TARGET_COMMENT, 9, "특가 "+content)
+	}
+	if repo.strikes < 3 || !users.revoked {
+		t.Fatalf("expected revocation after strikes, strikes=%d revoked=%v", repo.strikes, users.revoked)
+	}
+	if users.perm.WritePost || users.perm.WriteComment || users.perm.SendChatMessage || !users.perm.SendReport {
+		t.Fatalf("unexpected permission after revocation: %+v", users.perm)
+	}
+}
+
+func TestSpamGuardSkipsTrustedMembers(t *testing.T) {
+	guard, repo, _ := newTestSpamGuard(t, 5*time.Minute, 3)
+	if err := guard.Check(models.SPAM_TARGET_CHAT, 7, "a.example www.b.example http://c.example/d http://e.example"); err != nil {
+		t.Fatalf("trusted member should not be checked: %v", err)
+	}
+	if len(repo.logs) != 0 {
+		t.Fatalf("trusted member writes should not be logged: %+v", repo.logs)
+	}
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+// 원격 주소, 키, 대상 게시판이 설정되지 않았을 때의 오류
+var ErrSyncConsumerDisabled = errors.New("sync consumer is not configured")
+
+// 이미 다른 가져오기가 실행 중일 때의 오류
+var ErrSyncConsumerRunning = errors.New("sync consumer is already running")
+
+// 원격 응답의 서명이 맞지 않을 때의 오류
+var ErrSyncSignature = errors.New("sync response signature is not valid")
+
+const (
+	syncPullLimit    = 100
+	syncPullMaxPages = 20
+	syncPullMaxBytes = 32 << 20
+	syncErrorMaxLen  = 500
+)
+
+// 원격 변경 피드의 공통 응답 형식
+type syncChangesResponse struct {
+	Success bool                    `json:"success"`
+	Error   string                  `json:"error"`
+	Result  models.SyncChangeResult `json:"result"`
+}
+
+// 한 번의 가져오기 실행에 필요한 설정과 결과
+type syncPull struct {
+	service *NuboSyncService
+	remote  configs.SyncConsumerConfig
+	config  models.BoardConfig
+	source  models.SyncSource
+	run     models.SyncRunLog
+}
+
+// 관리화면에 보여줄 동기화 가져오기 설정, 원본별 상태, 최근 실행 기록 가져오기
+func (s *NuboSyncService) GetConsumerStatus(limit uint) (models.SyncConsumerStatus, error) {
+	remote := configs.GetSyncConsumerConfig()
+	status := models.SyncConsumerStatus{
+		Enabled:         remote.Enabled,
+		Running:         s.pulling.Load(),
+		Remote:          remote.RemoteURL,
+		RemoteBoard:     remote.RemoteBoard,
+		TargetBoard:     remote.TargetBoard,
+		IntervalMinutes: remote.IntervalMinutes,
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	sources, err := s.repos.Sync.GetSyncSources()
+	if err != nil {
+		return status, err
+	}
+	status.Sources = sources
+	status.Logs, err = s.repos.Sync.GetSyncRunLogs(limit)
+	return status, err
+}
+
+// 원격 NUBO의 변경 피드를 마지막으로 읽은 위치부터 가져와 대상 게시판에 반영하고 실행 기록 남기기
+func (s *NuboSyncService) PullRemote() (models.SyncRunLog, error) {
+	remote := configs.GetSyncConsumerConfig()
+	if !remote.Enabled {
+		return models.SyncRunLog{}, ErrSyncConsumerDisabled
+	}
+	if !s.pulling.CompareAndSwap(false, true) {
+		return models.SyncRunLog{}, ErrSyncConsumerRunning
+	}
+	defer s.pulling.Store(false)
+
+	boardUid := s.repos.Board.GetBoardUidById(remote.TargetBoard)
+	if boardUid < 1 {
+		return models.SyncRunLog{}, fmt.Errorf("target board not found")
+	}
+	sourceName := remote.RemoteURL
+	if remote.RemoteBoard != "" {
+		sourceName = fmt.Sprintf("%s?board=%s", remote.RemoteURL, url.QueryEscape(remote.RemoteBoard))
+	}
+	source, err := s.repos.Sync.GetSyncSource(utils.GetHashedString(sourceName), sourceName, boardUid)
+	if err != nil {
+		return models.SyncRunLog{}, err
+	}
+
+	pull := &syncPull{
+		service: s,
+		remote:  remote,
+		config:  s.repos.Board.GetBoardConfig(boardUid),
+		source:  source,
+		run: models.SyncRunLog{
+			SourceUid: source.Uid,
+			Started:   s.now().UnixMilli(),
+			FromSeq:   source.Cursor,
+			ToSeq:     source.Cursor,
+		},
+	}
+	err = pull.execute()
+	pull.run.Finished = s.now().UnixMilli()
+	pull.run.Status = models.SYNC_RUN_SUCCESS
+	if err != nil {
+		pull.run.Status = models.SYNC_RUN_FAILED
+		pull.run.Error = utils.CutString(err.Error(), syncErrorMaxLen)
+	}
+	if finishErr := s.repos.Sync.FinishSyncRun(pull.run); finishErr != nil && err == nil {
+		err = finishErr
+	}
+	return pull.run, err
+}
+
+// 설정한 주기마다 원격 NUBO의 변경 내역 가져오기 (설정이 없으면 바로 종료)
+func (s *NuboSyncService) RunConsumerJob(ctx context.Context) {
+	remote := configs.GetSyncConsumerConfig()
+	if !remote.Enabled {
+		return
+	}
+	ticker := time.NewTicker(time.Duration(remote.IntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PullRemote(); err != nil && !errors.Is(err, ErrSyncConsumerRunning) {
+			log.Printf("sync: failed to pull changes from %s: %v", remote.RemoteURL, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 변경 피드를 여러 번 나눠 읽으면서 반영하기 (반영한 위치까지는 실패해도 저장)
+func (p *syncPull) execute() error {
+	for page := 0; page < syncPullMaxPages; page++ {
+		result, err := p.fetch(p.run.ToSeq)
+		if err != nil {
+			return err
+		}
+		for _, change := range result.Changes {
+			if err := p.apply(change); err != nil {
+				_ = p.service.repos.Sync.SaveSyncCursor(p.source.Uid, p.run.ToSeq)
+				return fmt.Errorf("change %d (%s/%d): %w", change.Seq, change.Id, change.No, err)
+			}
+			p.run.ToSeq = change.Seq
+		}
+		if result.Next > p.run.ToSeq {
+			p.run.ToSeq = result.Next
+		}
+		if err := p.service.repos.Sync.SaveSyncCursor(p.source.Uid, p.run.ToSeq); err != nil {
+			return err
+		}
+		if !result.HasMore {
+			return nil
+		}
+	}
+	return nil
+}
+
+// 원격 변경 피드 한 쪽 가져오기 (응답 본문의 서명을 먼저 확인)
+func (p *syncPull) fetch(since uint64) (models.SyncChangeResult, error) {
+	query := url.Values{}
+	query.Set("since", strconv.FormatUint(since, 10))
+	query.Set("limit", strconv.Itoa(syncPullLimit))
+	if p.remote.RemoteBoard != "" {
+		query.Set("board", p.remote.RemoteBoard)
+	}
+	req, err := http.NewRequest(http.MethodGet, p.remote.RemoteURL+"/sync/changes?"+query.Encode(), nil)
+	if err != nil {
+		return models.SyncChangeResult{}, err
+	}
+	req.Header.Set("X-Sync-Key", p.remote.RemoteKey)
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.service.client.Do(req)
+	if err != nil {
+		return models.SyncChangeResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, syncPullMaxBytes+1))
+	if err != nil {
+		return models.SyncChangeResult{}, err
+	}
+	if len(body) > syncPullMaxBytes {
+		return models.SyncChangeResult{}, fmt.Errorf("sync response is too large")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return models.SyncChangeResult{}, fmt.Errorf("remote responded with status %d", resp.StatusCode)
+	}
+	if !utils.VerifySyncBody(body, p.remote.RemoteKey, resp.Header.Get(models.SYNC_SIGNATURE_HEADER)) {
+		return models.SyncChangeResult{}, ErrSyncSignature
+	}
+	response := syncChangesResponse{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return models.SyncChangeResult{}, err
+	}
+	if !response.Success {
+		return models.SyncChangeResult{}, fmt.Errorf("remote error: %s", response.Error)
+	}
+	return response.Result, nil
+}
+
+func (p *syncPull) findMap(kind string, key string) (uint, string, bool) {
+	return p.service.repos.Import.FindImportMap(p.config.Uid, p.source.SourceHash, kind, utils.GetHashedString(key))
+}
+
+func (p *syncPull) saveMap(kind string, key string, targetUid uint, targetPath string) error {
+	return p.service.repos.Import.SaveImportMap(p.config.Uid, p.source.SourceHash, kind, utils.GetHashedString(key), targetUid, targetPath)
+}
+
+// 변경 하나 반영하기 (이 곳에서 관리자가 지운 글은 다시 만들지 않음)
+func (p *syncPull) apply(change models.SyncChangeItem) error {
+	key := fmt.Sprintf("%s/%d", change.Id, change.No)
+	postUid, _, found := p.findMap(models.IMPORT_KIND_POST, key)
+	found = found && postUid > 0
+
+	if change.Deleted || change.Post == nil {
+		if !found {
+			return nil
+		}
+		if local, err := p.service.repos.Sync.GetSyncPost(postUid); err == nil &&
+			local.BoardUid == p.config.Uid && local.Status != models.CONTENT_REMOVED {
+			if err := p.service.board.RemovePost(p.config.Uid, postUid, p.config.Admin.Board); err != nil {
+				return err
+			}
+			p.run.Removed++
+		}
+		return p.saveMap(models.IMPORT_KIND_POST, key, 0, "")
+	}
+
+	if !found {
+		return p.create(key, *change.Post)
+	}
+	local, err := p.service.repos.Sync.GetSyncPost(postUid)
+	if err != nil || local.BoardUid != p.config.Uid || local.Status == models.CONTENT_REMOVED {
+		return nil
+	}
+	if local.Modified == change.Post.Modified {
+		return nil
+	}
+	return p.update(postUid, *change.Post)
+}
+
+// 원격 글을 게시판 관리자 이름의 새 글로 저장하기
+func (p *syncPull) create(key string, post models.SyncPostItem) error {
+	categoryUid := uint(0)
+	if len(p.config.Category) > 0 {
+		categoryUid = p.config.Category[0].Uid
+	}
+	submitted := int64(post.Submitted)
+	if submitted < 1 {
+		submitted = p.service.now().UnixMilli()
+	}
+	postUid, err := p.service.repos.Import.InsertImportedPost(models.ImportPostParam{
+		BoardUid:    p.config.Uid,
+		UserUid:     p.config.Admin.Board,
+		CategoryUid: categoryUid,
+		Title:       p.title(post),
+		Content:     utils.Sanitize(p.importImages(post.Content)),
+		Status:      models.CONTENT_NORMAL,
+		Submitted:   submitted,
+		Modified:    int64(post.Modified),
+		SourceHash:  p.source.SourceHash,
+		SourceKey:   utils.GetHashedString(key),
+	})
+	if err != nil {
+		return err
+	}
+	p.run.Created++
+	if err := p.service.board.SaveTags(p.config.Uid, postUid, post.Tags); err != nil {
+		return err
+	}
+	p.importAttachments(postUid, post.Images)
+	return nil
+}
+
+// 원격에서 고친 제목, 본문, 태그를 반영하고 새로 추가된 첨부 이미지 가져오기
+func (p *syncPull) update(postUid uint, post models.SyncPostItem) error {
+	err := p.service.repos.Import.UpdateImportedPost(postUid, models.ImportPostParam{
+		Title:    p.title(post),
+		Content:  utils.Sanitize(p.importImages(post.Content)),
+		Modified: int64(post.Modified),
+	})
+	if err != nil {
+		return err
+	}
+	p.run.Modified++
+	p.service.repos.BoardView.RemovePostTags(postUid)
+	if err := p.service.board.SaveTags(p.config.Uid, postUid, post.Tags); err != nil {
+		return err
+	}
+	p.importAttachments(postUid, post.Images)
+	return nil
+}
+
+func (p *syncPull) title(post models.SyncPostItem) string {
+	title := utils.CutString(utils.Escape(post.Title), 299)
+	if title == "" {
+		title = fmt.Sprintf("%s #%d", post.Id, post.No)
+	}
+	return title
+}
+
+// 본문 이미지를 내려받아 삽입 이미지로 저장하고 본문 주소 바꾸기 (실패한 이미지는 원래 주소 유지)
+func (p *syncPull) importImages(content string) string {
+	replaced := make(map[string]string)
+	for _, source := range utils.ExtractImageSources(content, p.remote.RemoteURL) {
+		if _, publicPath, ok := p.findMap(models.IMPORT_KIND_IMAGE, source); ok {
+			replaced[source] = publicPath
+			continue
+		}
+		publicPath, err := saveRemoteInsertImage(p.service.repos, p.service.saveImage, p.config.Uid, p.config.Admin.Board, source)
+		if err != nil {
+			log.Printf("sync: failed to import image %s: %v", source, err)
+			continue
+		}
+		if err := p.saveMap(models.IMPORT_KIND_IMAGE, source, 0, publicPath); err != nil {
+			continue
+		}
+		p.run.Images++
+		replaced[source] = publicPath
+	}
+	if len(replaced) == 0 {
+		return content
+	}
+	content, _ = utils.RewriteHTMLLinks(content, p.remote.RemoteURL, func(link string) (string, bool) {
+		publicPath, ok := replaced[link]
+		return publicPath, ok
+	})
+	return content
+}
+
+// 아직 가져오지 않은 첨부 이미지를 첨부파일과 썸네일로 저장하기
+func (p *syncPull) importAttachments(postUid uint, images []models.SyncImageItem) {
+	base, err := url.Parse(p.remote.RemoteURL + "/")
+	if err != nil {
+		return
+	}
+	for _, image := range images {
+		key := fmt.Sprintf("%d", image.Uid)
+		if _, _, ok := p.findMap(models.IMPORT_KIND_ATTACH, key); ok {
+			continue
+		}
+		remotePath := image.Full
+		if remotePath == "" {
+			remotePath = image.File
+		}
+		ref, err := url.Parse(remotePath)
+		if remotePath == "" || err != nil {
+			continue
+		}
+		source := base.ResolveReference(ref).String()
+		if err := saveRemoteAttachment(p.service.repos, p.service.board, p.service.fetchImage, p.config.Uid, postUid, source); err != nil {
+			log.Printf("sync: failed to import attachment %s: %v", source, err)
+			continue
+		}
+		p.run.Images++
+		_ = p.saveMap(models.IMPORT_KIND_ATTACH, key, postUid, source)
+	}
+}
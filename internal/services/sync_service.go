@@ -1,22 +1,97 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
 	"github.com/sirini/goapi/internal/repositories"
 	"github.com/sirini/goapi/pkg/models"
 	"github.com/sirini/goapi/pkg/utils"
 )
 
 type SyncService interface {
+	GetChanges(param models.SyncChangeParam) (models.SyncChangeResult, error)
+	GetConsumerStatus(limit uint) (models.SyncConsumerStatus, error)
 	GetLatestPosts(bunch uint) []models.SyncPostItem
+	PullRemote() (models.SyncRunLog, error)
+	RunConsumerJob(ctx context.Context)
 }
 
 type NuboSyncService struct {
-	repos *repositories.Repository
+	repos      *repositories.Repository
+	board      BoardService
+	client     *http.Client
+	fetchImage func(imageURL string, outputPath string, width uint) error
+	saveImage  func(category models.UploadCategory, imageURL string, width uint) (models.SavedImage, error)
+	now        func() time.Time
+	pulling    atomic.Bool
 }
 
-// 리포지토리 묶음 주입받기
-func NewNuboSyncService(repos *repositories.Repository) *NuboSyncService {
-	return &NuboSyncService{repos: repos}
+// 리포지토리 묶음과 게시판 서비스 주입받기 (다른 NUBO의 글을 가져올 때 태그 저장과 글 삭제에 사용)
+func NewNuboSyncService(repos *repositories.Repository, board BoardService) *NuboSyncService {
+	return &NuboSyncService{
+		repos:      repos,
+		board:      board,
+		client:     utils.NewPublicHTTPClient(30 * time.Second),
+		fetchImage: utils.DownloadImage,
+		saveImage:  utils.SaveRemoteImage,
+		now:        time.Now,
+	}
+}
+
+// since 이후의 게시글 작성, 수정, 삭제 내역 가져오기 (삭제되었거나 더 이상 동기화 대상이 아닌 글은 tombstone으로)
+func (s *NuboSyncService) GetChanges(param models.SyncChangeParam) (models.SyncChangeResult, error) {
+	result := models.SyncChangeResult{Changes: make([]models.SyncChangeItem, 0), Next: param.Since}
+	boardUid := uint(0)
+	if len(param.BoardId) > 0 {
+		if boardUid = s.repos.Board.GetBoardUidById(param.BoardId); boardUid < 1 {
+			return result, fmt.Errorf("board not found")
+		}
+	}
+	if param.Limit < 1 || param.Limit > models.SYNC_CHANGES_MAX {
+		param.Limit = models.SYNC_CHANGES_MAX
+	}
+
+	before := s.now().UnixMilli() - models.SYNC_SETTLE_MILLIS
+	records, err := s.repos.Sync.GetChanges(param.Since, boardUid, before, param.Limit+1)
+	if err != nil {
+		return result, err
+	}
+	if uint(len(records)) > param.Limit {
+		records = records[:param.Limit]
+		result.HasMore = true
+	}
+
+	boards := make(map[uint]models.BoardConfig)
+	for _, record := range records {
+		config, ok := boards[record.BoardUid]
+		if !ok {
+			config = s.repos.Board.GetBoardConfig(record.BoardUid)
+			boards[record.BoardUid] = config
+		}
+		item := models.SyncChangeItem{
+			Seq:       record.Seq,
+			Action:    record.Action.String(),
+			Id:        config.Id,
+			No:        record.PostUid,
+			Timestamp: record.Timestamp,
+		}
+		if record.Action != models.SYNC_REMOVED {
+			post, err := s.repos.Sync.GetSyncPost(record.PostUid)
+			if err == nil && post.BoardUid == record.BoardUid {
+				if synced, ok := s.syncPostItem(post, config); ok {
+					item.Post = &synced
+				}
+			}
+		}
+		item.Deleted = item.Post == nil
+		result.Changes = append(result.Changes, item)
+		result.Next = record.Seq
+	}
+	return result, nil
 }
 
 // (허용된) 다른 곳에서 이 곳 게시글들을 동기화 할 수 있도록 최근 게시글들 가져오기
@@ -37,45 +112,60 @@ func (s *NuboSyncService) GetLatestPosts(bunch uint) []models.SyncPostItem {
 
 	for _, post := range posts {
 		config := s.repos.Board.GetBoardConfig(post.BoardUid)
-		writer := s.repos.Board.GetWriterInfo(post.UserUid)
-
-		hashtags := s.repos.BoardView.GetTags(post.Uid)
-		tags := make([]string, 0)
-		for _, tag := range hashtags {
-			tags = append(tags, tag.Name)
+		if item, ok := s.syncPostItem(post, config); ok {
+			items = append(items, item)
 		}
+	}
+	return items
+}
 
-		attachedImages, err := s.repos.BoardView.GetAttachedImages(post.Uid)
-		if err != nil {
-			return items
-		}
+// 게시글을 동기화 항목으로 바꾸기 (게시판 동기화 범위를 벗어나거나 공개 상태가 아니면 false)
+func (s *NuboSyncService) syncPostItem(post models.HomePostItem, config models.BoardConfig) (models.SyncPostItem, bool) {
+	if post.Status != models.CONTENT_NORMAL && post.Status != models.CONTENT_NOTICE {
+		return models.SyncPostItem{}, false
+	}
+	if config.SyncScope != models.SYNC_SCOPE_FULL && config.SyncScope != models.SYNC_SCOPE_SUMMARY {
+		return models.SyncPostItem{}, false
+	}
+	writer := s.repos.Board.GetWriterInfo(post.UserUid)
 
-		images := make([]models.SyncImageItem, 0)
-		for _, img := range attachedImages {
-			filename := s.repos.Sync.GetFileName(img.File.Uid)
-			image := models.SyncImageItem{
-				Uid:   img.File.Uid,
-				File:  img.File.Path,
-				Name:  filename,
-				Thumb: img.Thumbnail.Small,
-				Full:  img.Thumbnail.Large,
-				Desc:  img.Description,
-				Exif:  img.Exif,
-			}
-			images = append(images, image)
-		}
+	hashtags := s.repos.BoardView.GetTags(post.Uid)
+	tags := make([]string, 0)
+	for _, tag := range hashtags {
+		tags = append(tags, tag.Name)
+	}
 
-		item := models.SyncPostItem{
-			Id:        config.Id,
-			No:        post.Uid,
-			Title:     utils.Unescape(post.Title),
-			Content:   utils.Unescape(post.Content),
-			Submitted: post.Submitted,
-			Name:      writer.Name,
-			Tags:      tags,
-			Images:    images,
+	item := models.SyncPostItem{
+		Id:        config.Id,
+		No:        post.Uid,
+		Title:     utils.Unescape(post.Title),
+		Submitted: post.Submitted,
+		Modified:  post.Modified,
+		Name:      writer.Name,
+		Tags:      tags,
+		Images:    make([]models.SyncImageItem, 0),
+	}
+	if config.SyncScope == models.SYNC_SCOPE_SUMMARY {
+		return item, true
+	}
+	item.Content = utils.Unescape(post.Content)
+
+	attachedImages, err := s.repos.BoardView.GetAttachedImages(post.Uid)
+	if err != nil {
+		return item, true
+	}
+	for _, img := range attachedImages {
+		filename := s.repos.Sync.GetFileName(img.File.Uid)
+		image := models.SyncImageItem{
+			Uid:   img.File.Uid,
+			File:  img.File.Path,
+			Name:  filename,
+			Thumb: img.Thumbnail.Small,
+			Full:  img.Thumbnail.Large,
+			Desc:  img.Description,
+			Exif:  img.Exif,
 		}
-		items = append(items, item)
+		item.Images = append(item.Images, image)
 	}
-	return items
+	return item, true
 }
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+	"github.com/sirini/goapi/pkg/utils"
+)
+
+type syncRepoStub struct {
+	repositories.SyncRepository
+	records []models.SyncChangeRecord
+	posts   map[uint]models.HomePostItem
+	before  int64
+	limit   uint
+}
+
+func (r *syncRepoStub) GetChanges(since uint64, boardUid uint, before int64, limit uint) ([]models.SyncChangeRecord, error) {
+	r.before, r.limit = before, limit
+	items := make([]models.SyncChangeRecord, 0)
+	for _, record := range r.records {
+		if record.Seq > since && (boardUid < 1 || record.BoardUid == boardUid) && uint(len(items)) < limit {
+			items = append(items, record)
+		}
+	}
+	return items, nil
+}
+func (r *syncRepoStub) GetSyncPost(postUid uint) (models.HomePostItem, error) {
+	post, ok := r.posts[postUid]
+	if !ok {
+		return post, fmt.Errorf("not found")
+	}
+	return post, nil
+}
+func (*syncRepoStub) GetFileName(uint) string { return "a.jpg" }
+
+type syncBoardRepoStub struct{ repositories.BoardRepository }
+
+var syncBoards = map[uint]models.BoardConfig{
+	1: {Uid: 1, Id: "free"},
+	2: {Uid: 2, Id: "diary", SyncScope: models.SYNC_SCOPE_SUMMARY},
+}
+
+func (syncBoardRepoStub) GetBoardUidById(id string) uint {
+	for uid, config := range syncBoards {
+		if config.Id == id {
+			return uid
+		}
+	}
+	return 0
+}
+func (syncBoardRepoStub) GetBoardConfig(boardUid uint) models.BoardConfig {
+	return syncBoards[boardUid]
+}
+func (syncBoardRepoStub) GetWriterInfo(uint) models.BoardWriter {
+	return models.BoardWriter{UserBasicInfo: models.UserBasicInfo{Name: "Kim"}}
+}
+
+type syncBoardViewRepoStub struct {
+	repositories.BoardViewRepository
+}
+
+func (syncBoardViewRepoStub) GetTags(uint) []models.Pair { return []models.Pair{{Name: "travel"}} }
+func (syncBoardViewRepoStub) GetAttachedImages(uint) ([]models.BoardAttachedImage, error) {
+	return []models.BoardAttachedImage{{File: models.BoardFile{Uid: 3, Path: "/upload/a.jpg"}}}, nil
+}
+
+func TestSyncChangesIncludeTombstonesAndBoardScope(t *testing.T) {
+	repo := &syncRepoStub{
+		records: []models.SyncChangeRecord{
+			{Seq: 10, BoardUid: 1, PostUid: 7, Action: models.SYNC_CREATED},
+			{Seq: 11, BoardUid: 1, PostUid: 8, Action: models.SYNC_MODIFIED},
+			{Seq: 12, BoardUid: 1, PostUid: 9, Action: models.SYNC_REMOVED},
+			{Seq: 13, BoardUid: 2, PostUid: 5, Action: models.SYNC_MODIFIED},
+			{Seq: 14, BoardUid: 1, PostUid: 5, Action: models.SYNC_CREATED},
+		},
+		posts: map[uint]models.HomePostItem{
+			7: {BoardCommonPostItem: models.BoardCommonPostItem{Uid: 7, Title: "A &amp; B", Content: "<p>x</p>", Modified: 5, Status: models.CONTENT_NORMAL}, BoardUid: 1},
+			8: {BoardCommonPostItem: models.BoardCommonPostItem{Uid: 8, Status: models.CONTENT_SECRET}, BoardUid: 1},
+			5: {BoardCommonPostItem: models.BoardCommonPostItem{Uid: 5, Content: "<p>moved</p>", Status: models.CONTENT_NORMAL}, BoardUid: 1},
+		},
+	}
+	now := time.UnixMilli(1_700_000_000_000)
+	service := &NuboSyncService{
+		repos: &repositories.Repository{Sync: repo, Board: syncBoardRepoStub{}, BoardView: syncBoardViewRepoStub{}},
+		now:   func() time.Time { return now },
+	}
+
+	result, err := service.GetChanges(models.SyncChangeParam{Since: 9, Limit: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 4 || !result.HasMore || result.Next != 13 || repo.limit != 5 ||
+		repo.before != now.UnixMilli()-models.SYNC_SETTLE_MILLIS {
+		t.Fatalf("unexpected page %+v (limit %d, before %d)", result, repo.limit, repo.before)
+	}
+	created := result.Changes[0]
+	if created.Action != "created" || created.Deleted || created.Post == nil || created.Post.Title != "A & B" ||
+		created.Post.Content != "<p>x</p>" || len(created.Post.Images) != 1 || created.Post.Tags[0] != "travel" {
+		t.Fatalf("visible posts should carry the full post, got %+v %+v", created, created.Post)
+	}
+	if secret := result.Changes[1]; !secret.Deleted || secret.Post != nil {
+		t.Fatalf("posts that became secret should be tombstones, got %+v", secret)
+	}
+	if removed := result.Changes[2]; removed.Action != "removed" || !removed.Deleted || removed.No != 9 {
+		t.Fatalf("removed posts should be tombstones, got %+v", removed)
+	}
+	if moved := result.Changes[3]; !moved.Deleted || moved.Id != "diary" {
+		t.Fatalf("posts moved to another board should be tombstones for the old board, got %+v", moved)
+	}
+
+	result, err = service.GetChanges(models.SyncChangeParam{Since: result.Next, Limit: 10})
+	if err != nil || len(result.Changes) != 1 || result.HasMore || result.Next != 14 || result.Changes[0].Post == nil {
+		t.Fatalf("unexpected second page %+v %v", result, err)
+	}
+
+	syncBoards[1] = models.BoardConfig{Uid: 1, Id: "free", SyncScope: models.SYNC_SCOPE_SUMMARY}
+	t.Cleanup(func() { syncBoards[1] = models.BoardConfig{Uid: 1, Id: "free"} })
+	result, _ = service.GetChanges(models.SyncChangeParam{Since: 9, Limit: 1, BoardId: "free"})
+	if post := result.Changes[0].Post; post == nil || post.Content != "" || len(post.Images) != 0 || post.Title != "A & B" {
+		t.Fatalf("summary boards should omit content and images, got %+v", post)
+	}
+	if _, err := service.GetChanges(models.SyncChangeParam{BoardId: "missing"}); err == nil {
+		t.Fatal("unknown boards should be rejected")
+	}
+}
+
+type syncConsumerRepoStub struct {
+	repositories.SyncRepository
+	imported *importRepoStub
+	removed  map[uint]bool
+	source   models.SyncSource
+	runs     []models.SyncRunLog
+}
+
+func (r *syncConsumerRepoStub) GetSyncSource(sourceHash string, remote string, boardUid uint) (models.SyncSource, error) {
+	if r.source.Uid == 0 {
+		r.source = models.SyncSource{Uid: 1, SourceHash: sourceHash, Remote: remote, BoardUid: boardUid}
+	}
+	return r.source, nil
+}
+func (r *syncConsumerRepoStub) SaveSyncCursor(_ uint, cursor uint64) error {
+	r.source.Cursor = cursor
+	return nil
+}
+func (r *syncConsumerRepoStub) FinishSyncRun(run models.SyncRunLog) error {
+	r.runs = append(r.runs, run)
+	return nil
+}
+func (r *syncConsumerRepoStub) GetSyncPost(postUid uint) (models.HomePostItem, error) {
+	post, ok := r.imported.posts[postUid]
+	if !ok {
+		return models.HomePostItem{}, fmt.Errorf("not found")
+	}
+	status := post.Status
+	if r.removed[postUid] {
+		status = models.CONTENT_REMOVED
+	}
+	return models.HomePostItem{
+		BoardCommonPostItem: models.BoardCommonPostItem{Uid: postUid, Modified: uint64(post.Modified), Status: status},
+		BoardUid:            post.BoardUid,
+	}, nil
+}
+
+type syncConsumerBoardRepo struct{ importBoardRepo }
+
+func (syncConsumerBoardRepo) GetBoardUidById(id string) uint {
+	if id == "mirror" {
+		return 4
+	}
+	return 0
+}
+
+type syncConsumerViewRepo struct {
+	repositories.BoardViewRepository
+}
+
+func (syncConsumerViewRepo) RemovePostTags(uint) {}
+
+type syncConsumerBoardService struct {
+	importBoardService
+	repo *syncConsumerRepoStub
+	tags map[uint][]string
+}
+
+func (b *syncConsumerBoardService) SaveTags(_ uint, postUid uint, tags []string) error {
+	b.tags[postUid] = tags
+	return nil
+}
+func (b *syncConsumerBoardService) RemovePost(_ uint, postUid uint, userUid uint) error {
+	if userUid != 2 {
+		return fmt.Errorf("unexpected user %d", userUid)
+	}
+	b.repo.removed[postUid] = true
+	return nil
+}
+
+func TestSyncConsumerMirrorsRemoteChanges(t *testing.T) {
+	previous := configs.Env
+	configs.Env.UploadDir = t.TempDir()
+	t.Cleanup(func() { configs.Env = previous })
+
+	signKey := "remote-key"
+	changes := []models.SyncChangeItem{
+		{Seq: 1, Action: "created", Id: "free", No: 7, Post: &models.SyncPostItem{Id: "free", No: 7, Title: "Hello & bye",
+			Content: `<p><img src="/upload/images/a.webp"></p>`, Submitted: 1000, Tags: []string{"travel"},
+			Images: []models.SyncImageItem{{Uid: 3, File: "/upload/attachments/a.jpg", Full: "/upload/thumbnails/full/a.webp"}}}},
+		{Seq: 2, Action: "created", Id: "free", No: 8, Post: &models.SyncPostItem{Id: "free", No: 8, Title: "Second", Submitted: 2000}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/goapi/sync/changes" || r.Header.Get("X-Sync-Key") != "remote-key" || r.URL.Query().Get("board") != "free" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		since := uint64(0)
+		fmt.Sscan(r.URL.Query().Get("since"), &since)
+		result := models.SyncChangeResult{Changes: make([]models.SyncChangeItem, 0), Next: since}
+		for _, change := range changes {
+			if change.Seq > since {
+				result.Changes = append(result.Changes, change)
+				result.Next = change.Seq
+			}
+		}
+		body, _ := json.Marshal(models.ResponseCommon{Success: true, Result: result, Code: models.CODE_SUCCESS})
+		w.Header().Set(models.SYNC_SIGNATURE_HEADER, utils.SignSyncBody(body, signKey))
+		w.Write(body)
+	}))
+	defer server.Close()
+	configs.Env.SyncConsumer = configs.SyncConsumerEnv{
+		RemoteURL:   server.URL + "/goapi/",
+		RemoteKey:   "remote-key",
+		RemoteBoard: "free",
+		TargetBoard: "mirror",
+	}
+
+	imported := &importRepoStub{maps: make(map[string]uint), posts: make(map[uint]models.ImportPostParam), contents: make(map[uint]string)}
+	repo := &syncConsumerRepoStub{imported: imported, removed: make(map[uint]bool)}
+	board := &syncConsumerBoardService{repo: repo, tags: make(map[uint][]string)}
+	service := NewNuboSyncService(&repositories.Repository{
+		Board:        syncConsumerBoardRepo{},
+		BoardEdit:    &importEditRepo{},
+		BoardView:    syncConsumerViewRepo{},
+		ImageVariant: &imageVariantRepoStub{stored: map[string][]models.ImageVariant{}, inserted: map[string]uint{}},
+		Import:       imported,
+		Sync:         repo,
+	}, board)
+	service.client = server.Client()
+	fetched := make([]string, 0)
+	service.fetchImage = func(source string, _ string, _ uint) error {
+		fetched = append(fetched, source)
+		return nil
+	}
+	service.saveImage = stubRemoteImage(&fetched)
+
+	run, err := service.PullRemote()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Created != 2 || run.Images != 2 || run.ToSeq != 2 || repo.source.Cursor != 2 || len(imported.posts) != 2 {
+		t.Fatalf("unexpected first run %+v (cursor %d)", run, repo.source.Cursor)
+	}
+	first := imported.posts[1]
+	if first.BoardUid != 4 || first.UserUid != 2 || first.Title != "Hello &amp; bye" || first.Submitted != 1000 ||
+		!strings.Contains(first.Content, `src="/upload/images/`) || strings.Contains(first.Content, `"/upload/images/a.webp"`) {
+		t.Fatalf("remote posts should be stored for the board admin with re-hosted images, got %+v", first)
+	}
+	if len(board.tags[1]) != 1 || board.tags[1][0] != "travel" {
+		t.Fatalf("tags should be imported, got %v", board.tags)
+	}
+	if fetched[0] != server.URL+"/upload/images/a.webp" || fetched[1] != server.URL+"/upload/thumbnails/full/a.webp" {
+		t.Fatalf("images should be fetched from the remote origin, got %v", fetched)
+	}
+
+	repo.source.Cursor = 0
+	if run, err = service.PullRemote(); err != nil || run.Created != 0 || run.Modified != 0 || len(imported.posts) != 2 || len(fetched) != 2 {
+		t.Fatalf("replaying the feed should not duplicate posts or images, got %+v %v", run, err)
+	}
+
+	changes = append(changes,
+		models.SyncChangeItem{Seq: 3, Action: "modified", Id: "free", No: 7, Post: &models.SyncPostItem{Id: "free", No: 7,
+			Title: "Edited", Content: `<p><img src="/upload/images/a.webp"> more</p>`, Submitted: 1000, Modified: 5000, Tags: []string{"food"},
+			Images: []models.SyncImageItem{{Uid: 3, Full: "/upload/thumbnails/full/a.webp"}}}},
+		models.SyncChangeItem{Seq: 4, Action: "removed", Id: "free", No: 8, Deleted: true},
+	)
+	if run, err = service.PullRemote(); err != nil || run.FromSeq != 2 || run.Modified != 1 || run.Removed != 1 || run.Images != 0 {
+		t.Fatalf("edits and deletions should be applied, got %+v %v", run, err)
+	}
+	if edited := imported.posts[1]; edited.Title != "Edited" || edited.Modified != 5000 || !strings.Contains(edited.Content, "more") ||
+		board.tags[1][0] != "food" || !repo.removed[2] {
+		t.Fatalf("unexpected mirrored state %+v %v %v", edited, board.tags, repo.removed)
+	}
+
+	signKey = "other-key"
+	changes = append(changes, models.SyncChangeItem{Seq: 5, Action: "created", Id: "free", No: 9,
+		Post: &models.SyncPostItem{Id: "free", No: 9, Title: "Forged"}})
+	if _, err = service.PullRemote(); !errors.Is(err, ErrSyncSignature) {
+		t.Fatalf("responses with a bad signature should be rejected, got %v", err)
+	}
+	last := repo.runs[len(repo.runs)-1]
+	if last.Status != models.SYNC_RUN_FAILED || last.Error == "" || repo.source.Cursor != 4 || len(imported.posts) != 2 {
+		t.Fatalf("failed runs should be recorded without moving the cursor, got %+v (cursor %d)", last, repo.source.Cursor)
+	}
+}
@@ -21,8 +21,10 @@ type TradeService interface {
 }
 
 type NuboTradeService struct {
-	repos *repositories.Repository
-	board BoardService
+	repos  *repositories.Repository
+	board  BoardService
+	filter *contentFilter
+	spam   *spamGuard
 }
 
 func NewNuboTradeService(repos *repositories.Repository, board BoardService) *NuboTradeService {
@@ -141,7 +143,8 @@ func hasProductImage(content string, files []*multipart.FileHeader) bool {
 
 func (s *NuboTradeService) WriteTradePost(param models.TradeWriteParam) (models.TradeWriteResult, error) {
 	result := models.TradeWriteResult{}
-	if _, err := s.requireTradeBoard(param.BoardUid); err != nil {
+	config, err := s.requireTradeBoard(param.BoardUid)
+	if err != nil {
 		return result, err
 	}
 	if !hasProductImage(param.Content, param.Files) {
@@ -151,16 +154,24 @@ func (s *NuboTradeService) WriteTradePost(param models.TradeWriteParam) (models.
 		return result, fmt.Errorf("you have no permission to write a new trade post")
 	}
 	userLv, userPt := s.repos.User.GetUserLevelPoint(param.UserUid)
-	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, models.BOARD_ACTION_WRITE)
+	needLv, needPt := s.repos.BoardView.GetNeededLevelPoint(param.BoardUid, param.UserUid, models.BOARD_ACTION_WRITE)
 	if userLv < needLv {
 		return result, fmt.Errorf("level restriction")
 	}
 	if needPt < 0 && userPt < utils.Abs(needPt) {
 		return result, fmt.Errorf("not enough point")
 	}
-	if param.IsNotice && !s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid) {
+	isAdmin := s.repos.Auth.CheckPermissionByUid(param.UserUid, param.BoardUid)
+	if param.IsNotice && !isAdmin {
 		param.IsNotice = false
 	}
+	param.IsPending = config.RequireApproval && !isAdmin
+	if err := s.filter.FilterPost(&param.EditorWriteParam); err != nil {
+		return result, err
+	}
+	if err := s.spam.CheckPost(&param.EditorWriteParam); err != nil {
+		return result, err
+	}
 	postUid, err := s.repos.Trade.InsertTradePost(param, models.UpdatePointParam{
 		UserUid: param.UserUid, BoardUid: param.BoardUid, Action: models.POINT_ACTION_WRITE, Point: needPt,
 	})
@@ -204,9 +215,18 @@ func (s *NuboTradeService) ModifyTradePost(param models.TradeModifyParam) error
 	if param.IsNotice && !isAdmin {
 		param.IsNotice = false
 	}
+	wasPending := s.repos.Comment.GetPostStatus(param.PostUid) == models.CONTENT_PENDING
+	param.IsPending = wasPending
+	_, param.IsHidden = s.repos.Report.GetHiddenStatus(models.REPORT_TARGET_POST, param.PostUid)
+	if err := s.filter.FilterPost(&param.EditorWriteParam); err != nil {
+		return err
+	}
 	if err := s.repos.Trade.UpdateTradePost(param); err != nil {
 		return err
 	}
+	if err := saveEditedApproval(s.repos, param.PostUid, param.EditorWriteParam, wasPending); err != nil {
+		return err
+	}
 	s.repos.BoardView.RemovePostTags(param.PostUid)
 	if err := s.board.SaveTags(param.BoardUid, param.PostUid, param.Tags); err != nil {
 		return err
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type TrendingService interface {
+	GetTrendingPosts(boardUid uint, limit uint, userUid uint) ([]models.TrendingPostItem, error)
+	RefreshRanking() error
+	RunRankingJob(ctx context.Context)
+}
+
+type NuboTrendingService struct {
+	repos     *repositories.Repository
+	now       func() time.Time
+	mu        sync.RWMutex
+	refreshMu sync.Mutex
+	ranking   map[uint][]models.TrendingPostItem
+	updated   time.Time
+}
+
+// 리포지토리 묶음 주입받기
+func NewNuboTrendingService(repos *repositories.Repository) *NuboTrendingService {
+	return &NuboTrendingService{
+		repos:   repos,
+		now:     time.Now,
+		ranking: make(map[uint][]models.TrendingPostItem),
+	}
+}
+
+// 조회수, 좋아요, 댓글 수에 가중치를 곱한 뒤 경과 시간에 따라 감쇠시킨 점수 계산하기
+func trendingScore(config configs.TrendingConfig, hit, like, comment uint, ageHours float64) float64 {
+	if ageHours < 0 {
+		ageHours = 0
+	}
+	weighted := float64(hit)*config.HitWeight + float64(like)*config.LikeWeight + float64(comment)*config.CommentWeight
+	return weighted / math.Pow(ageHours+2, config.Gravity)
+}
+
+// 주기적으로 인기글 순위를 다시 계산하기 (ctx 종료 시 중단)
+func (s *NuboTrendingService) RunRankingJob(ctx context.Context) {
+	interval := time.Duration(configs.GetTrendingConfig().RefreshMinutes) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RefreshRanking(); err != nil {
+			log.Printf("trending: failed to refresh ranking: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 최근 공개 게시글들의 점수를 계산해서 사이트 전체 및 게시판별 상위 N개 캐시하기 (동시에 한 번만 계산)
+func (s *NuboTrendingService) RefreshRanking() error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	return s.refreshRanking()
+}
+
+// 인기글 순위 계산하기 (refreshMu를 잡은 상태에서 호출)
+func (s *NuboTrendingService) refreshRanking() error {
+	config := configs.GetTrendingConfig()
+	now := s.now()
+	since := now.Add(-time.Duration(config.WindowDays) * 24 * time.Hour).UnixMilli()
+
+	candidates, err := s.repos.Trending.GetTrendingCandidates(uint64(since), uint(config.MaxLevel))
+	if err != nil {
+		return err
+	}
+
+	scores := make(map[uint]float64, len(candidates))
+	for _, post := range candidates {
+		ageHours := float64(now.UnixMilli()-int64(post.Submitted)) / float64(time.Hour.Milliseconds())
+		scores[post.Uid] = trendingScore(config, post.Hit, post.Like, post.Comment, ageHours)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		left, right := scores[candidates[i].Uid], scores[candidates[j].Uid]
+		if left == right {
+			return candidates[i].Uid > candidates[j].Uid
+		}
+		return left > right
+	})
+
+	ranking := make(map[uint][]models.TrendingPostItem)
+	for _, post := range candidates {
+		siteFull := len(ranking[0]) >= config.Limit
+		boardFull := len(ranking[post.BoardUid]) >= config.Limit
+		if (siteFull && boardFull) || len(post.Board.Id) < 2 {
+			continue
+		}
+
+		item := models.TrendingPostItem{Score: scores[post.Uid]}
+		item.Uid = post.Uid
+		item.Title = post.Title
+		item.Content = post.Content
+		item.Submitted = post.Submitted
+		item.Modified = post.Modified
+		item.Hit = post.Hit
+		item.Status = post.Status
+		item.Id = post.Board.Id
+		item.Type = post.Board.Type
+		item.UseCategory = post.Board.UseCategory
+		item.Category = post.Category
+		item.Cover = post.Cover
+		item.Comment = post.Comment
+		item.Writer = post.Writer
+		item.Like = post.Like
+
+		if !siteFull {
+			ranking[0] = append(ranking[0], item)
+		}
+		if !boardFull {
+			ranking[post.BoardUid] = append(ranking[post.BoardUid], item)
+		}
+	}
+
+	s.mu.Lock()
+	s.ranking = ranking
+	s.updated = now
+	s.mu.Unlock()
+	return nil
+}
+
+// 캐시된 인기글 목록 가져오기 (boardUid가 0이면 사이트 전체, 만료된 순위는 새로 계산하는 동안 그대로 사용)
+func (s *NuboTrendingService) GetTrendingPosts(boardUid uint, limit uint, userUid uint) ([]models.TrendingPostItem, error) {
+	if err := s.loadRanking(); err != nil {
+		return nil, err
+	}
+	if s.isExpired() {
+		go s.refreshExpired()
+	}
+
+	s.mu.RLock()
+	cached := s.ranking[boardUid]
+	if limit > 0 && int(limit) < len(cached) {
+		cached = cached[:limit]
+	}
+	items := make([]models.TrendingPostItem, len(cached))
+	copy(items, cached)
+	s.mu.RUnlock()
+
+	if userUid > 0 && len(items) > 0 {
+		postUids := make([]uint, 0, len(items))
+		for _, item := range items {
+			postUids = append(postUids, item.Uid)
+		}
+		liked, err := s.repos.Trending.GetLikedPosts(postUids, userUid)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			items[i].Liked = liked[items[i].Uid]
+		}
+	}
+	return items, nil
+}
+
+// 아직 한 번도 계산하지 않았으면 순위 계산하기 (동시 요청은 첫 계산을 기다림)
+func (s *NuboTrendingService) loadRanking() error {
+	if !s.isEmpty() {
+		return nil
+	}
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if !s.isEmpty() {
+		return nil
+	}
+	return s.refreshRanking()
+}
+
+// 만료된 순위를 백그라운드에서 다시 계산하기 (이미 계산 중이면 건너뜀)
+func (s *NuboTrendingService) refreshExpired() {
+	if !s.refreshMu.TryLock() {
+		return
+	}
+	defer s.refreshMu.Unlock()
+	if !s.isExpired() {
+		return
+	}
+	if err := s.refreshRanking(); err != nil {
+		log.Printf("trending: failed to refresh expired ranking: %v", err)
+	}
+}
+
+// 순위를 한 번도 계산하지 않았는지 확인
+func (s *NuboTrendingService) isEmpty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.updated.IsZero()
+}
+
+// 캐시 만료 여부 확인 (갱신 주기의 두 배가 지나도록 작업이 돌지 않았으면 만료)
+func (s *NuboTrendingService) isExpired() bool {
+	interval := time.Duration(configs.GetTrendingConfig().RefreshMinutes) * time.Minute
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.updated.IsZero() || s.now().Sub(s.updated) > 2*interval
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/internal/repositories"
+	"github.com/sirini/goapi/pkg/models"
+)
+
+type trendingRepo struct {
+	repositories.TrendingRepository
+	mu         sync.Mutex
+	candidates []models.TrendingCandidate
+	queries    int
+}
+
+func (r *trendingRepo) GetTrendingCandidates(uint64, uint) ([]models.TrendingCandidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries++
+	return r.candidates, nil
+}
+
+func (r *trendingRepo) GetLikedPosts(postUids []uint, _ uint) (map[uint]bool, error) {
+	return map[uint]bool{2: true}, nil
+}
+
+func (r *trendingRepo) setCandidates(candidates []models.TrendingCandidate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.candidates = candidates
+}
+
+func (r *trendingRepo) queryCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.queries
+}
+
+// 인기글 점수 계산 대상 게시글 만들기
+func trendingPost(now time.Time, uid, boardUid, hit uint, ageHours uint64) models.TrendingCandidate {
+	item := models.TrendingCandidate{}
+	item.Uid = uid
+	item.BoardUid = boardUid
+	item.Hit = hit
+	item.Submitted = uint64(now.UnixMilli()) - ageHours*uint64(time.Hour.Milliseconds())
+	item.Board.Id = fmt.Sprintf("board%d", boardUid)
+	return item
+}
+
+func TestTrendingScoreDecaysWithAge(t *testing.T) {
+	config := configs.TrendingConfig{HitWeight: 1, LikeWeight: 5, CommentWeight: 3, Gravity: 1.8}
+	fresh := trendingScore(config, 100, 10, 5, 1)
+	stale := trendingScore(config, 100, 10, 5, 48)
+	if fresh <= stale {
+		t.Fatalf("fresh score %f should exceed stale score %f", fresh, stale)
+	}
+	if got := trendingScore(config, 0, 0, 0, 1); got != 0 {
+		t.Fatalf("empty post score = %f, want 0", got)
+	}
+}
+
+func TestRefreshRankingCachesSiteAndBoardTopN(t *testing.T) {
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.Trending = configs.TrendingEnv{Limit: "2"}
+
+	now := time.UnixMilli(1_800_000_000_000)
+	repos := &repositories.Repository{
+		Trending: &trendingRepo{candidates: []models.TrendingCandidate{
+			trendingPost(now, 1, 1, 10, 1),
+			trendingPost(now, 2, 1, 100, 1),
+			trendingPost(now, 3, 2, 100, 100),
+			trendingPost(now, 4, 1, 50, 1),
+		}},
+	}
+	service := NewNuboTrendingService(repos)
+	service.now = func() time.Time { return now }
+
+	site, err := service.GetTrendingPosts(0, 10, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(site) != 2 || site[0].Uid != 2 || site[1].Uid != 4 {
+		t.Fatalf("unexpected site ranking: %#v", site)
+	}
+	if !site[0].Liked || site[1].Liked {
+		t.Fatal("viewer like state was not applied")
+	}
+	board, _ := service.GetTrendingPosts(2, 10, 0)
+	if len(board) != 1 || board[0].Uid != 3 {
+		t.Fatalf("board top N should still include posts outside the site top N: %#v", board)
+	}
+}
+
+func TestTrendingServesStaleRankingWhileRefreshing(t *testing.T) {
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.Trending = configs.TrendingEnv{Limit: "2", RefreshMinutes: "10"}
+
+	now := time.UnixMilli(1_800_000_000_000)
+	repo := &trendingRepo{candidates: []models.TrendingCandidate{trendingPost(now, 1, 1, 10, 1)}}
+	service := NewNuboTrendingService(&repositories.Repository{Trending: repo})
+	var clock sync.Mutex
+	service.now = func() time.Time {
+		clock.Lock()
+		defer clock.Unlock()
+		return now
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if items, err := service.GetTrendingPosts(0, 10, 0); err != nil || len(items) != 1 {
+				t.Errorf("cold requests should wait for the first ranking, got %v %v", items, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := repo.queryCount(); got != 1 {
+		t.Fatalf("concurrent cold requests should compute the ranking once, got %d", got)
+	}
+
+	repo.setCandidates([]models.TrendingCandidate{trendingPost(now, 2, 1, 10, 1)})
+	clock.Lock()
+	now = now.Add(time.Hour)
+	clock.Unlock()
+	items, err := service.GetTrendingPosts(0, 10, 0)
+	if err != nil || len(items) != 1 || items[0].Uid != 1 {
+		t.Fatalf("expired ranking should be served as is, got %v %v", items, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for service.isExpired() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	items, _ = service.GetTrendingPosts(0, 10, 0)
+	if len(items) != 1 || items[0].Uid != 2 {
+		t.Fatalf("expired ranking should be refreshed in the background, got %v", items)
+	}
+}
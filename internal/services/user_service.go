@@ -61,7 +61,8 @@ func (s *NuboUserService) DeleteAccount(userUid uint, confirmation string) error
 }
 
 type NuboUserService struct {
-	repos *repositories.Repository
+	repos  *repositories.Repository
+	filter *contentFilter
 }
 
 // 리포지토리 묶음 주입받기
@@ -96,7 +97,15 @@ func (s *NuboUserService) ChangePassword(verifyUid uint, userCode string, newPas
 
 // 사용자 정보 변경하기
 func (s *NuboUserService) ChangeUserInfo(param models.UpdateUserInfoParam) error {
-	if err := s.repos.User.UpdateUserInfoString(param.UserUid, utils.Escape(param.Name), utils.Escape(param.Signature)); err != nil {
+	name, err := s.filter.Filter(models.FILTER_TARGET_USER_NAME, param.UserUid, param.Name)
+	if err != nil {
+		return err
+	}
+	signature, err := s.filter.Filter(models.FILTER_TARGET_USER_SIGNATURE, param.UserUid, param.Signature)
+	if err != nil {
+		return err
+	}
+	if err := s.repos.User.UpdateUserInfoString(param.UserUid, utils.Escape(name), utils.Escape(signature)); err != nil {
 		return err
 	}
 	if param.Profile != nil {
@@ -151,22 +160,24 @@ func (s *NuboUserService) ChangeUserProfile(userUid uint, profile *multipart.Fil
 			return err
 		}
 		defer os.Remove(tempPath)
-		profilePath, err := utils.SaveProfileImage(tempPath)
+		saved, err := utils.SaveProfileImage(tempPath)
 		if err != nil {
 			return err
 		}
 
-		publicProfilePath, err := utils.PublicUploadPath(profilePath)
+		publicProfile, err := utils.PublicSavedImage(saved)
 		if err != nil {
-			_ = os.Remove(profilePath)
+			utils.RemoveSavedImage(saved)
 			return err
 		}
-		if err := s.repos.User.UpdateUserProfile(userUid, publicProfilePath); err != nil {
-			_ = os.Remove(profilePath)
+		if err := s.repos.User.UpdateUserProfile(userUid, publicProfile.Path, publicProfile.Fallback); err != nil {
+			_ = utils.RemoveUploadFile(publicProfile.Path)
 			return err
 		}
+		_ = s.repos.ImageVariant.InsertImageVariants(0, publicProfile.Path, publicProfile.Variants)
 		if len(oldProfile) > 1 {
 			_ = utils.RemoveUploadFile(oldProfile)
+			_ = s.repos.ImageVariant.RemoveImageVariants(oldProfile)
 		}
 	}
 
@@ -76,6 +76,9 @@ func (p *GovipsProcessor) process(source *vips.ImageRef, variants []Variant) err
 	}
 
 	for _, variant := range variants {
+		if variant.SkipUpscale && variant.Width >= uint(source.Width()) {
+			continue
+		}
 		image, err := source.Copy()
 		if err != nil {
 			return fmt.Errorf("copy image for %q: %w", variant.Path, err)
@@ -113,6 +116,14 @@ func exportGovipsImage(image *vips.ImageRef, variant Variant) ([]byte, error) {
 		params.Quality = variant.Quality
 		output, _, err := image.ExportWebp(params)
 		return output, err
+	case FormatAVIF:
+		params := vips.NewAvifExportParams()
+		params.Quality = variant.Quality
+		if variant.Effort != nil {
+			params.Effort = *variant.Effort
+		}
+		output, _, err := image.ExportAvif(params)
+		return output, err
 	default:
 		return nil, unsupportedFormatError(variant.Format)
 	}
@@ -122,6 +133,10 @@ func validateVariants(variants []Variant) error {
 	for _, variant := range variants {
 		switch variant.Format {
 		case FormatJPEG, FormatWebP:
+		case FormatAVIF:
+			if variant.Effort != nil && (*variant.Effort < 0 || *variant.Effort > maxAvifEffort) {
+				return fmt.Errorf("avif effort out of range: %d", *variant.Effort)
+			}
 		default:
 			return unsupportedFormatError(variant.Format)
 		}
@@ -129,6 +144,9 @@ func validateVariants(variants []Variant) error {
 	return nil
 }
 
+// libvips accepts AVIF effort from 0 (fastest) to 9 (smallest output).
+const maxAvifEffort = 9
+
 func unsupportedFormatError(format Format) error {
 	return fmt.Errorf("unsupported image format: %d", format)
 }
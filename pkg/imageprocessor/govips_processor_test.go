@@ -27,19 +27,28 @@ func TestGovipsProcessorCreatesRequestedVariants(t *testing.T) {
 	dir := t.TempDir()
 	webpPath := filepath.Join(dir, "image.webp")
 	jpegPath := filepath.Join(dir, "image.jpg")
+	avifPath := filepath.Join(dir, "image.avif")
+	skippedPath := filepath.Join(dir, "image_w16.webp")
 	processor, err := NewGovipsProcessor()
 	if err != nil {
 		t.Fatal(err)
 	}
+	effort := 2
 	if err := processor.ProcessBuffer(input.Bytes(), []Variant{
 		{Path: webpPath, Width: 4, Quality: 90, Format: FormatWebP},
 		{Path: jpegPath, Width: 2, Quality: 60, Format: FormatJPEG},
+		{Path: avifPath, Width: 4, Quality: 50, Effort: &effort, Format: FormatAVIF},
+		{Path: skippedPath, Width: 16, Quality: 90, Format: FormatWebP, SkipUpscale: true},
 	}); err != nil {
 		t.Fatal(err)
 	}
 
 	assertVariant(t, webpPath, FormatWebP, 4, 2)
 	assertVariant(t, jpegPath, FormatJPEG, 2, 1)
+	assertVariant(t, avifPath, FormatAVIF, 4, 2)
+	if _, err := os.Stat(skippedPath); !os.IsNotExist(err) {
+		t.Fatal("variant wider than the source was written despite SkipUpscale")
+	}
 }
 
 func TestGovipsProcessorRejectsUnknownOutputFormatBeforeDecoding(t *testing.T) {
@@ -57,6 +66,44 @@ func TestGovipsProcessorRejectsUnknownOutputFormatBeforeDecoding(t *testing.T) {
 	}
 }
 
+func TestGovipsProcessorRejectsAvifEffortOutOfRange(t *testing.T) {
+	processor, err := NewGovipsProcessor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	effort := 10
+	err = processor.ProcessBuffer([]byte("not decoded before effort validation"), []Variant{{
+		Path:   filepath.Join(t.TempDir(), "image.avif"),
+		Width:  1,
+		Effort: &effort,
+		Format: FormatAVIF,
+	}})
+	if err == nil {
+		t.Fatal("out of range avif effort was accepted")
+	}
+}
+
+func TestValidateVariantsAcceptsFastestAvifEffort(t *testing.T) {
+	fastest := 0
+	if err := validateVariants([]Variant{{Format: FormatAVIF, Effort: &fastest}, {Format: FormatAVIF}}); err != nil {
+		t.Fatalf("effort 0 and the encoder default should be accepted: %v", err)
+	}
+}
+
+func TestParseFormatNamesAndExtensions(t *testing.T) {
+	for name, want := range map[string]Format{"JPG": FormatJPEG, "jpeg": FormatJPEG, " webp ": FormatWebP, "avif": FormatAVIF} {
+		if format, ok := ParseFormat(name); !ok || format != want {
+			t.Fatalf("%q parsed as %d", name, format)
+		}
+	}
+	if _, ok := ParseFormat("png"); ok {
+		t.Fatal("png is not an output format")
+	}
+	if FormatAVIF.Extension() != "avif" || FormatAVIF.MIMEType() != "image/avif" || FormatJPEG.Extension() != "jpg" {
+		t.Fatal("unexpected avif or jpeg naming")
+	}
+}
+
 func assertVariant(t *testing.T, path string, format Format, width, height int) {
 	t.Helper()
 	buffer, err := os.ReadFile(path)
@@ -72,6 +119,12 @@ func assertVariant(t *testing.T, path string, format Format, width, height int)
 		if len(buffer) < 2 || buffer[0] != 0xff || buffer[1] != 0xd8 {
 			t.Fatal("processor did not create a JPEG variant")
 		}
+	case FormatAVIF:
+		// The standard library has no AVIF decoder, so only the ISOBMFF brand is checked.
+		if len(buffer) < 12 || string(buffer[4:12]) != "ftypavif" {
+			t.Fatal("processor did not create an AVIF variant")
+		}
+		return
 	}
 	config, _, err := image.DecodeConfig(bytes.NewReader(buffer))
 	if err != nil {
@@ -2,6 +2,8 @@
 // Concrete engines stay behind Processor so callers do not depend on a binding API.
 package imageprocessor
 
+import "strings"
+
 // Format identifies an encoded output format.
 type Format uint8
 
@@ -9,14 +11,62 @@ const (
 	FormatUnknown Format = iota
 	FormatJPEG
 	FormatWebP
+	FormatAVIF
 )
 
+// ParseFormat maps a configuration name such as "webp" or "jpg" to a Format.
+func ParseFormat(name string) (Format, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "jpeg", "jpg":
+		return FormatJPEG, true
+	case "webp":
+		return FormatWebP, true
+	case "avif":
+		return FormatAVIF, true
+	default:
+		return FormatUnknown, false
+	}
+}
+
+// Extension returns the file extension, without a dot, used for the format.
+func (f Format) Extension() string {
+	switch f {
+	case FormatJPEG:
+		return "jpg"
+	case FormatWebP:
+		return "webp"
+	case FormatAVIF:
+		return "avif"
+	default:
+		return ""
+	}
+}
+
+// MIMEType returns the media type clients use to negotiate the format.
+func (f Format) MIMEType() string {
+	switch f {
+	case FormatJPEG:
+		return "image/jpeg"
+	case FormatWebP:
+		return "image/webp"
+	case FormatAVIF:
+		return "image/avif"
+	default:
+		return ""
+	}
+}
+
 // Variant describes one image derived from a shared input.
+// Effort only applies to AVIF, where nil keeps the encoder default.
+// SkipUpscale leaves the variant unwritten when the source is not wider than Width,
+// which keeps responsive width ladders from storing enlarged copies.
 type Variant struct {
-	Path    string
-	Width   uint
-	Quality int
-	Format  Format
+	Path        string
+	Width       uint
+	Quality     int
+	Effort      *int
+	Format      Format
+	SkipUpscale bool
 }
 
 // Processor creates one or more encoded variants from an image.
@@ -0,0 +1,212 @@
+package models
+
+import "encoding/json"
+
+// ActivityPub 문서 형식과 공용 주소
+const (
+	AP_CONTENT_TYPE  = "application/activity+json"
+	AP_ACCEPT_HEADER = `application/activity+json, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+	AP_JRD_TYPE      = "application/jrd+json"
+	AP_CONTEXT       = "https://www.w3.org/ns/activitystreams"
+	AP_SECURITY      = "https://w3id.org/security/v1"
+	AP_PUBLIC        = "https://www.w3.org/ns/activitystreams#Public"
+	AP_OUTBOX_ITEMS  = 20
+	AP_DELIVERY_BULK = 50
+)
+
+// ActivityPub 활동 종류
+const (
+	AP_ACCEPT   = "Accept"
+	AP_ANNOUNCE = "Announce"
+	AP_CREATE   = "Create"
+	AP_DELETE   = "Delete"
+	AP_FOLLOW   = "Follow"
+	AP_LIKE     = "Like"
+	AP_UNDO     = "Undo"
+	AP_UPDATE   = "Update"
+)
+
+// 발송 대기열 상태 정의
+type ApDeliveryStatus uint8
+
+const (
+	AP_DELIVERY_PENDING ApDeliveryStatus = iota
+	AP_DELIVERY_DONE
+	AP_DELIVERY_FAILED
+)
+
+// 게시판 액터의 서명용 RSA 키 정의 (PEM 형식)
+type ApKey struct {
+	PublicKey  string
+	PrivateKey string
+}
+
+// 원격 서버 액터 정보 정의 (서명 확인용 공개키와 받은편지함 주소 보관)
+type ApRemoteActor struct {
+	Uid         uint
+	ActorId     string
+	KeyId       string
+	PublicKey   string
+	Inbox       string
+	SharedInbox string
+	Name        string
+	Url         string
+	Fetched     int64
+}
+
+// 발송 대기열 항목 정의
+type ApDelivery struct {
+	Uid      uint
+	BoardUid uint
+	Inbox    string
+	Activity string
+	Attempts uint
+}
+
+// 원격 서버에서 받은 반응(좋아요, 공유, 답글) 기록 정의
+type ApReceivedActivity struct {
+	BoardUid   uint
+	PostUid    uint
+	CommentUid uint
+	ActorUid   uint
+	Type       string
+	ActivityId string
+}
+
+// 서명된 받은편지함 요청 정의 (헤더 이름은 소문자)
+type ApInboxRequest struct {
+	Method  string
+	Path    string
+	Headers map[string]string
+	Body    []byte
+}
+
+// 받은편지함으로 들어온 활동 정의 (object는 문자열 또는 객체)
+type ApIncoming struct {
+	Id     string          `json:"id"`
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+// 원격 게시물(답글) 정의
+type ApIncomingNote struct {
+	Id           string `json:"id"`
+	Type         string `json:"type"`
+	AttributedTo string `json:"attributedTo"`
+	InReplyTo    string `json:"inReplyTo"`
+	Content      string `json:"content"`
+	Url          string `json:"url"`
+	Published    string `json:"published"`
+}
+
+// 원격 액터 문서에서 필요한 항목 정의 (키 주소가 액터와 따로 있으면 Owner, PublicKeyPem만 채워짐)
+type ApActorDocument struct {
+	Id                string `json:"id"`
+	Owner             string `json:"owner"`
+	PublicKeyPem      string `json:"publicKeyPem"`
+	Type              string `json:"type"`
+	PreferredUsername string `json:"preferredUsername"`
+	Name              string `json:"name"`
+	Url               string `json:"url"`
+	Inbox             string `json:"inbox"`
+	Endpoints         struct {
+		SharedInbox string `json:"sharedInbox"`
+	} `json:"endpoints"`
+	PublicKey struct {
+		Id           string `json:"id"`
+		Owner        string `json:"owner"`
+		PublicKeyPem string `json:"publicKeyPem"`
+	} `json:"publicKey"`
+}
+
+// 게시판 액터 문서 정의
+type ApActor struct {
+	Context                   []string       `json:"@context"`
+	Id                        string         `json:"id"`
+	Type                      string         `json:"type"`
+	PreferredUsername         string         `json:"preferredUsername"`
+	Name                      string         `json:"name"`
+	Summary                   string         `json:"summary"`
+	Url                       string         `json:"url"`
+	Inbox                     string         `json:"inbox"`
+	Outbox                    string         `json:"outbox"`
+	Followers                 string         `json:"followers"`
+	ManuallyApprovesFollowers bool           `json:"manuallyApprovesFollowers"`
+	Discoverable              bool           `json:"discoverable"`
+	Published                 string         `json:"published,omitempty"`
+	PublicKey                 ApActorKeyInfo `json:"publicKey"`
+}
+
+// 액터 문서에 싣는 공개키 정의
+type ApActorKeyInfo struct {
+	Id           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// 게시글을 나타내는 Note 객체 정의
+type ApNote struct {
+	Context      any            `json:"@context,omitempty"`
+	Id           string         `json:"id"`
+	Type         string         `json:"type"`
+	AttributedTo string         `json:"attributedTo"`
+	Name         string         `json:"name,omitempty"`
+	Content      string         `json:"content"`
+	Url          string         `json:"url"`
+	Published    string         `json:"published"`
+	Updated      string         `json:"updated,omitempty"`
+	To           []string       `json:"to"`
+	Cc           []string       `json:"cc"`
+	Tag          []ApTag        `json:"tag,omitempty"`
+	Attachment   []ApAttachment `json:"attachment,omitempty"`
+}
+
+// Note에 붙는 해시태그 정의
+type ApTag struct {
+	Type string `json:"type"`
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+// Note에 붙는 첨부 이미지 정의
+type ApAttachment struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType"`
+	Url       string `json:"url"`
+}
+
+// 보낼 활동 정의
+type ApActivity struct {
+	Context   any      `json:"@context,omitempty"`
+	Id        string   `json:"id"`
+	Type      string   `json:"type"`
+	Actor     string   `json:"actor"`
+	Published string   `json:"published,omitempty"`
+	To        []string `json:"to,omitempty"`
+	Cc        []string `json:"cc,omitempty"`
+	Object    any      `json:"object"`
+}
+
+// 순서 있는 컬렉션(outbox, followers) 정의
+type ApCollection struct {
+	Context      string `json:"@context"`
+	Id           string `json:"id"`
+	Type         string `json:"type"`
+	TotalItems   uint   `json:"totalItems"`
+	OrderedItems []any  `json:"orderedItems,omitempty"`
+}
+
+// WebFinger 응답 정의
+type ApWebFinger struct {
+	Subject string            `json:"subject"`
+	Aliases []string          `json:"aliases"`
+	Links   []ApWebFingerLink `json:"links"`
+}
+
+// WebFinger 링크 정의
+type ApWebFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type"`
+	Href string `json:"href"`
+}
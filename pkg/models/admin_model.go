@@ -39,8 +39,10 @@ type AdminBoardLevelPolicy struct {
 
 // 게시판 설정 반환값 정의
 type AdminBoardResult struct {
-	Config BoardConfig `json:"config"`
-	Groups []Pair      `json:"groups"`
+	Config BoardConfig    `json:"config"`
+	Groups []Pair         `json:"groups"`
+	Roles  []Pair         `json:"roles"`
+	Acl    []BoardAclRule `json:"acl"`
 }
 
 // 게시판 포인트 정책 반환값 정의
@@ -51,26 +53,31 @@ type AdminBoardPointPolicy struct {
 
 // 게시판 생성에 필요한 파라미터 정의
 type AdminBoardCreateParam struct {
-	AdminUid      uint   `json:"adminUid"`
-	Categories    string `json:"categories,omitempty"`
-	GroupUid      uint   `json:"groupUid"`
-	Id            string `json:"id"`
-	Info          string `json:"info"`
-	LevelComment  uint   `json:"levelComment"`
-	LevelDownload uint   `json:"levelDownload"`
-	LevelList     uint   `json:"levelList"`
-	LevelView     uint   `json:"levelView"`
-	LevelWrite    uint   `json:"levelWrite"`
-	Name          string `json:"name"`
-	PointComment  int    `json:"pointComment"`
-	PointDownload int    `json:"pointDownload"`
-	PointView     int    `json:"pointView"`
-	PointWrite    int    `json:"pointWrite"`
-	RowCount      uint   `json:"rowCount"`
-	Type          Board  `json:"type"`
-	UseCategory   bool   `json:"useCategory"`
-	Width         uint   `json:"width"`
-	SkinKey       string `json:"skinKey"`
+	AdminUid             uint      `json:"adminUid"`
+	Categories           string    `json:"categories,omitempty"`
+	GroupUid             uint      `json:"groupUid"`
+	Id                   string    `json:"id"`
+	Info                 string    `json:"info"`
+	LevelComment         uint      `json:"levelComment"`
+	LevelDownload        uint      `json:"levelDownload"`
+	LevelList            uint      `json:"levelList"`
+	LevelView            uint      `json:"levelView"`
+	LevelWrite           uint      `json:"levelWrite"`
+	Name                 string    `json:"name"`
+	PointComment         int       `json:"pointComment"`
+	PointDownload        int       `json:"pointDownload"`
+	PointView            int       `json:"pointView"`
+	PointWrite           int       `json:"pointWrite"`
+	RowCount             uint      `json:"rowCount"`
+	Type                 Board     `json:"type"`
+	UseCategory          bool      `json:"useCategory"`
+	Width                uint      `json:"width"`
+	SkinKey              string    `json:"skinKey"`
+	RequireApproval      bool      `json:"requireApproval"`
+	CommentDepth         uint      `json:"commentDepth"`
+	PublicCommentHistory bool      `json:"publicCommentHistory"`
+	FeedSummary          bool      `json:"feedSummary"`
+	SyncScope            SyncScope `json:"syncScope"`
 }
 
 type SkinSettings map[string]string
@@ -83,7 +90,8 @@ type AdminSkinSettingParam struct {
 // 게시판 수정에 필요한 파라미터 정의
 type AdminBoardModifyParam struct {
 	AdminBoardCreateParam
-	BoardUid uint `json:"boardUid"`
+	BoardUid uint           `json:"boardUid"`
+	Acl      []BoardAclRule `json:"acl"` // nil이면 기존 역할 규칙 유지, 빈 배열이면 모두 삭제
 }
 
 // 대시보드에서 볼 업로드 사용량 캐시
@@ -306,3 +314,50 @@ type AdminUserInfo struct {
 	Level uint   `json:"level"`
 	Point uint   `json:"point"`
 }
+
+// 해시태그 관리 목록 검색 파라미터 정의
+type AdminHashtagSearchParam struct {
+	Page    uint   `query:"page" json:"page"`
+	Limit   uint   `query:"limit" json:"limit"`
+	Keyword string `query:"keyword" json:"keyword"`
+}
+
+// 해시태그 관리 목록 아이템 정의
+type AdminHashtagItem struct {
+	Uid       uint   `json:"uid"`
+	Name      string `json:"name"`
+	Used      uint   `json:"used"`
+	Posts     uint   `json:"posts"`
+	Banned    bool   `json:"banned"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// 해시태그 관리 목록 반환값 정의
+type AdminHashtagListResult struct {
+	Item  []AdminHashtagItem `json:"item"`
+	Total uint               `json:"total"`
+}
+
+// 해시태그 이름 변경 파라미터 정의
+type AdminHashtagRenameParam struct {
+	HashtagUid uint   `json:"hashtagUid"`
+	Name       string `json:"name"`
+}
+
+// 해시태그 병합 파라미터 정의 (sourceUids 태그들을 targetUid 태그로 합침)
+type AdminHashtagMergeParam struct {
+	TargetUid  uint   `json:"targetUid"`
+	SourceUids []uint `json:"sourceUids"`
+}
+
+// 해시태그 금지 설정 파라미터 정의
+type AdminHashtagBanParam struct {
+	HashtagUid uint `json:"hashtagUid"`
+	Banned     bool `json:"banned"`
+}
+
+// 해시태그 정리 결과 정의
+type AdminHashtagCleanupResult struct {
+	Recounted int64 `json:"recounted"`
+	Purged    int64 `json:"purged"`
+}
@@ -0,0 +1,86 @@
+package models
+
+// 게시글 유입 경로 타입 정의
+type TrafficSource uint8
+
+// 유입 경로 목록
+const (
+	TRAFFIC_DIRECT TrafficSource = iota
+	TRAFFIC_INTERNAL
+	TRAFFIC_SEARCH
+	TRAFFIC_SOCIAL
+	TRAFFIC_REFERRAL
+)
+
+// 유입 경로 문자로 변환
+func (ts TrafficSource) String() string {
+	switch ts {
+	case TRAFFIC_INTERNAL:
+		return "internal"
+	case TRAFFIC_SEARCH:
+		return "search"
+	case TRAFFIC_SOCIAL:
+		return "social"
+	case TRAFFIC_REFERRAL:
+		return "referral"
+	default:
+		return "direct"
+	}
+}
+
+// 게시글 열람 원본 이벤트 정의 (day는 YYYYMMDD 형식)
+type PostReadEvent struct {
+	PostUid      uint
+	ViewerHash   string
+	Source       TrafficSource
+	ReferrerHost string
+	Counted      bool
+	TokenHash    string
+	Day          uint
+	Timestamp    int64
+}
+
+// 열람 시간 비콘 파라미터 정의
+type PostReadBeaconParam struct {
+	Token   string `json:"token"`
+	Seconds uint   `json:"seconds"`
+}
+
+// 게시글 통계 요청 파라미터 정의
+type PostStatParam struct {
+	BoardUid uint
+	PostUid  uint `query:"postUid"`
+	UserUid  uint
+	Days     uint `query:"days"`
+}
+
+// 날짜별 게시글 통계 정의
+type PostStatDaily struct {
+	Day            uint `json:"day"`
+	Views          uint `json:"views"`
+	Readers        uint `json:"readers"`
+	AvgReadSeconds uint `json:"avgReadSeconds"`
+}
+
+// 유입 경로별 조회수 정의
+type PostStatSource struct {
+	Source string `json:"source"`
+	Views  uint   `json:"views"`
+}
+
+// 참조 도메인별 조회수 정의
+type PostStatReferrer struct {
+	Host  string `json:"host"`
+	Views uint   `json:"views"`
+}
+
+// 게시글 통계 반환 타입 정의
+type PostStatResult struct {
+	PostUid        uint               `json:"postUid"`
+	Views          uint               `json:"views"`
+	Readers        uint               `json:"readers"`
+	AvgReadSeconds uint               `json:"avgReadSeconds"`
+	Daily          []PostStatDaily    `json:"daily"`
+	Sources        []PostStatSource   `json:"sources"`
+	Referrers      []PostStatReferrer `json:"referrers"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// 승인 대기 게시글 목록 파라미터 정의 (관리자가 아니면 내 글만)
+type BoardApprovalParam struct {
+	BoardUid uint
+	UserUid  uint
+	Page     uint
+	Limit    uint
+}
+
+// 승인 대기 게시글 항목 정의
+type BoardApprovalItem struct {
+	PostUid   uint        `json:"postUid"`
+	BoardUid  uint        `json:"boardUid"`
+	Title     string      `json:"title"`
+	Writer    BoardWriter `json:"writer"`
+	Submitted uint64      `json:"submitted"`
+	IsSecret  bool        `json:"isSecret"`
+}
+
+type BoardApprovalResult struct {
+	Item  []BoardApprovalItem `json:"item"`
+	Total uint                `json:"total"`
+}
+
+// 게시글 승인/반려 파라미터 정의
+type BoardApprovalDecisionParam struct {
+	BoardUid uint   `json:"boardUid"`
+	PostUid  uint   `json:"postUid"`
+	Reason   string `json:"reason"`
+	UserUid  uint
+}
@@ -0,0 +1,106 @@
+package models
+
+// 게시판 보관 파일(zip) 형식 정의
+const (
+	BOARD_ARCHIVE_FORMAT   = "nubo-board-archive"
+	BOARD_ARCHIVE_VERSION  = 1
+	BOARD_ARCHIVE_DATA     = "board.json"
+	BOARD_ARCHIVE_FILE_DIR = "files/"
+)
+
+// 게시판 보관 파일 본문 정의 (모든 uid는 내보낸 서버 기준이며 가져올 때 새로 매겨짐)
+type BoardArchive struct {
+	Format     string                `json:"format"`
+	Version    uint                  `json:"version"`
+	Exported   int64                 `json:"exported"`
+	Source     string                `json:"source"`
+	GroupId    string                `json:"groupId"`
+	Board      AdminBoardCreateParam `json:"board"`
+	Categories []Pair                `json:"categories"`
+	Users      []ArchiveUser         `json:"users"`
+	Posts      []ArchivePost         `json:"posts"`
+	Comments   []ArchiveComment      `json:"comments"`
+	Images     []ArchiveImage        `json:"images"`
+}
+
+// 보관 파일에 담긴 회원 정보 (가져올 때 아이디(이메일)로 다시 연결)
+type ArchiveUser struct {
+	Uid  uint   `json:"uid"`
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// 보관 파일에 담긴 좋아요 정보
+type ArchiveLike struct {
+	UserUid   uint  `json:"userUid"`
+	Liked     bool  `json:"liked"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+// 보관 파일에 담긴 게시글 정의
+type ArchivePost struct {
+	Uid         uint          `json:"uid"`
+	UserUid     uint          `json:"userUid"`
+	CategoryUid uint          `json:"categoryUid"`
+	Title       string        `json:"title"`
+	Content     string        `json:"content"`
+	Submitted   int64         `json:"submitted"`
+	Modified    int64         `json:"modified"`
+	Hit         uint          `json:"hit"`
+	Status      Status        `json:"status"`
+	Tags        []string      `json:"tags"`
+	Likes       []ArchiveLike `json:"likes"`
+	Files       []ArchiveFile `json:"files"`
+}
+
+// 보관 파일에 담긴 첨부파일 정의 (썸네일, EXIF, 이미지 설명 포함)
+type ArchiveFile struct {
+	Uid         uint            `json:"uid"`
+	Name        string          `json:"name"`
+	Path        string          `json:"path"`
+	Thumbnail   *BoardThumbnail `json:"thumbnail,omitempty"`
+	Exif        *BoardExif      `json:"exif,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// 보관 파일에 담긴 댓글 정의
+type ArchiveComment struct {
+	Uid       uint          `json:"uid"`
+	ParentUid uint          `json:"parentUid"`
+	PostUid   uint          `json:"postUid"`
+	UserUid   uint          `json:"userUid"`
+	Content   string        `json:"content"`
+	Submitted int64         `json:"submitted"`
+	Modified  int64         `json:"modified"`
+	Status    Status        `json:"status"`
+	Likes     []ArchiveLike `json:"likes"`
+}
+
+// 보관 파일에 담긴 본문 삽입 이미지 정의
+type ArchiveImage struct {
+	UserUid   uint   `json:"userUid"`
+	Path      string `json:"path"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// 게시판 보관 파일 가져오기 파라미터 정의 (BoardId, GroupId가 비어 있으면 보관 파일 값 사용)
+type BoardArchiveImportParam struct {
+	ActorUid uint
+	BoardId  string
+	GroupId  string
+}
+
+// 게시판 보관 파일 가져오기 결과 정의
+type BoardArchiveReport struct {
+	BoardUid      uint     `json:"boardUid"`
+	BoardId       string   `json:"boardId"`
+	Categories    uint     `json:"categories"`
+	Posts         uint     `json:"posts"`
+	Comments      uint     `json:"comments"`
+	Likes         uint     `json:"likes"`
+	Files         uint     `json:"files"`
+	Images        uint     `json:"images"`
+	MatchedUsers  uint     `json:"matchedUsers"`
+	FallbackUsers uint     `json:"fallbackUsers"`
+	Warnings      []string `json:"warnings"`
+}
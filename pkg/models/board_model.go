@@ -46,6 +46,7 @@ const (
 	CONTENT_NORMAL
 	CONTENT_NOTICE
 	CONTENT_SECRET
+	CONTENT_PENDING // 승인이 필요한 게시판에서 관리자 승인을 기다리는 글
 )
 
 // 검색 옵션 정의
@@ -122,12 +123,13 @@ type BoardCommonPostItem struct {
 
 // 게시글 목록보기에 추가로 필요한 리턴 타입 정의
 type BoardCommonListItem struct {
-	Category Pair        `json:"category"`
-	Cover    string      `json:"cover"`
-	Comment  uint        `json:"comment"`
-	Like     uint        `json:"like"`
-	Liked    bool        `json:"liked"`
-	Writer   BoardWriter `json:"writer"`
+	Category      Pair           `json:"category"`
+	Cover         string         `json:"cover"`
+	CoverVariants []ImageVariant `json:"coverVariants,omitempty"`
+	Comment       uint           `json:"comment"`
+	Like          uint           `json:"like"`
+	Liked         bool           `json:"liked"`
+	Writer        BoardWriter    `json:"writer"`
 }
 
 // 게시글 목록보기용 리턴 타입 정의
@@ -176,20 +178,25 @@ type BoardAdminUid struct {
 
 // 게시판 설정 타입 정의
 type BoardConfig struct {
-	Uid         uint             `json:"uid"`
-	Id          string           `json:"id"`
-	GroupUid    uint             `json:"groupUid"`
-	Admin       BoardAdminUid    `json:"admin"`
-	Type        Board            `json:"type"`
-	Name        string           `json:"name"`
-	Info        string           `json:"info"`
-	RowCount    uint             `json:"rowCount"`
-	Width       uint             `json:"width"`
-	UseCategory bool             `json:"useCategory"`
-	Category    []Pair           `json:"category"`
-	Level       BoardActionLevel `json:"level"`
-	Point       BoardActionPoint `json:"point"`
-	SkinKey     string           `json:"skinKey"`
+	Uid                  uint             `json:"uid"`
+	Id                   string           `json:"id"`
+	GroupUid             uint             `json:"groupUid"`
+	Admin                BoardAdminUid    `json:"admin"`
+	Type                 Board            `json:"type"`
+	Name                 string           `json:"name"`
+	Info                 string           `json:"info"`
+	RowCount             uint             `json:"rowCount"`
+	Width                uint             `json:"width"`
+	UseCategory          bool             `json:"useCategory"`
+	Category             []Pair           `json:"category"`
+	Level                BoardActionLevel `json:"level"`
+	Point                BoardActionPoint `json:"point"`
+	SkinKey              string           `json:"skinKey"`
+	RequireApproval      bool             `json:"requireApproval"`
+	CommentDepth         uint             `json:"commentDepth"`
+	PublicCommentHistory bool             `json:"publicCommentHistory"` // 댓글 수정 이력을 관리자 외 모두에게 공개
+	FeedSummary          bool             `json:"feedSummary"`          // 피드에 본문 대신 요약만 싣기
+	SyncScope            SyncScope        `json:"syncScope"`            // 다른 서버로 동기화할 범위
 }
 
 // 게시글 가져오기 시 필요한 파라미터 정의
@@ -230,6 +237,7 @@ const (
 	BOARD_ACTION_COMMENT
 	BOARD_ACTION_WRITE
 	BOARD_ACTION_DOWNLOAD
+	BOARD_ACTION_MANAGE
 )
 
 // 게시판 액션들 문자로 변환
@@ -243,6 +251,8 @@ func (ba BoardAction) String() string {
 		return "write"
 	case BOARD_ACTION_DOWNLOAD:
 		return "download"
+	case BOARD_ACTION_MANAGE:
+		return "manage"
 	default:
 		return "list"
 	}
@@ -303,10 +313,13 @@ type BoardTag struct {
 	PostUid uint   `json:"postUid"`
 }
 
-// 썸네일 크기별 종류 정의
+// 썸네일 크기별 종류 정의 (AVIF로 저장했을 때는 대체 형식 경로도 함께, 없으면 빈 문자열)
 type BoardThumbnail struct {
-	Large string `json:"large"`
-	Small string `json:"small"`
+	Large         string         `json:"large"`
+	Small         string         `json:"small"`
+	LargeFallback string         `json:"largeFallback"`
+	SmallFallback string         `json:"smallFallback"`
+	Variants      []ImageVariant `json:"variants,omitempty"`
 }
 
 // 게시글 보기에서 공통으로 쓰이는 파라미터 정의
@@ -327,9 +340,13 @@ type BoardViewParam struct {
 	Id            string `query:"id"`
 	BoardUid      uint
 	UserUid       uint
-	PostUid       uint `query:"postUid"`
-	NeedUpdateHit bool `query:"needUpdateHit"`
-	LatestLimit   uint `query:"latestLimit"`
+	PostUid       uint   `query:"postUid"`
+	NeedUpdateHit bool   `query:"needUpdateHit"` // 중복 조회는 서버에서 거르므로 더 이상 사용하지 않음 (이전 클라이언트 호환용)
+	LatestLimit   uint   `query:"latestLimit"`
+	RelatedLimit  uint   `query:"relatedLimit"`
+	Referrer      string `query:"referrer"` // 클라이언트의 document.referrer (유입 경로 통계용)
+	IP            string `query:"-"`
+	Agent         string `query:"-"`
 }
 
 // 게시글 보기에 반환 타입 정의
@@ -343,7 +360,34 @@ type BoardViewResult struct {
 	NextPostUid    uint                       `json:"nextPostUid"`
 	WriterPosts    []BoardWriterLatestPost    `json:"writerPosts"`
 	WriterComments []BoardWriterLatestComment `json:"writerComments"`
+	Related        []BoardRelatedPost         `json:"related"`
+	Previews       []LinkPreview              `json:"previews"`
+	ContentImages  map[string][]ImageVariant  `json:"contentImages"`
 	IsAdmin        bool                       `json:"isAdmin"`
+	ReadToken      string                     `json:"readToken"`
+}
+
+// 관련 게시글 리턴 타입 정의
+type BoardRelatedPost struct {
+	Board     BoardBasicConfig `json:"board"`
+	PostUid   uint             `json:"postUid"`
+	Title     string           `json:"title"`
+	Cover     string           `json:"cover"`
+	Submitted uint64           `json:"submitted"`
+	Score     float64          `json:"score"`
+}
+
+// 관련 게시글 후보 정의 (태그 겹침, 같은 카테고리, 이미지 설명 텍스트)
+type BoardRelatedCandidate struct {
+	PostUid      uint
+	BoardUid     uint
+	UserUid      uint
+	Title        string
+	Submitted    uint64
+	SharedTags   uint
+	SameCategory bool
+	Description  string
+	Similarity   float64
 }
 
 // 게시글 좋아하기에 필요한 파라미터 정의
@@ -482,6 +526,8 @@ type EditorWriteParam struct {
 	Tags        []string
 	IsNotice    bool
 	IsSecret    bool
+	IsPending   bool
+	IsHidden    bool
 }
 
 // 갤러리 그리드형 반환타입 정의
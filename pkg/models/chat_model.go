@@ -10,10 +10,11 @@ type ChatItem struct {
 
 // 쪽지 내용 보기용 정보
 type ChatHistory struct {
-	Uid       uint   `json:"uid"`
-	UserUid   uint   `json:"userUid"`
-	Message   string `json:"message"`
-	Timestamp uint64 `json:"timestamp"`
+	Uid       uint          `json:"uid"`
+	UserUid   uint          `json:"userUid"`
+	Message   string        `json:"message"`
+	Timestamp uint64        `json:"timestamp"`
+	Previews  []LinkPreview `json:"previews"`
 }
 
 // 쪽지 보내기에 필요한 파라미터 정의
@@ -1,26 +1,52 @@
 package models
 
+// 댓글 정렬 방식 정의
+type CommentSort uint8
+
+// 댓글 정렬 방식 목록
+const (
+	COMMENT_SORT_OLDEST CommentSort = iota
+	COMMENT_SORT_NEWEST
+	COMMENT_SORT_LIKES
+)
+
+// 게시판별 답글 깊이 제한 (기본값은 기존과 같은 1단계)
+const (
+	COMMENT_DEPTH_DEFAULT = 1
+	COMMENT_DEPTH_MAX     = 10
+)
+
 // 댓글 목록 가져오기에 필요한 파라미터 정의
 type CommentListParam struct {
-	BoardUid uint `json:"boardUid"`
-	PostUid  uint `json:"postUid"`
-	UserUid  uint `json:"userUid"`
-	Page     uint `json:"page"`
-	Limit    uint `json:"limit"`
+	BoardUid   uint        `json:"boardUid"`
+	PostUid    uint        `json:"postUid"`
+	UserUid    uint        `json:"userUid"`
+	Page       uint        `json:"page"`
+	Limit      uint        `json:"limit"`
+	ShareToken string      `query:"shareToken" json:"shareToken"`
+	ParentUid  uint        `query:"parentUid" json:"parentUid"` // 0이면 최상위 댓글, 아니면 해당 댓글의 답글들
+	Sort       CommentSort `query:"sort" json:"sort"`
+	Expand     uint        `query:"expand" json:"expand"` // 함께 펼칠 답글 단계 수, 0이면 게시판 최대 깊이까지
 }
 
 // 댓글 내용 항목 정의
 type CommentItem struct {
-	Uid       uint        `json:"uid"`
-	ReplyUid  uint        `json:"replyUid"`
-	PostUid   uint        `json:"postUid"`
-	Writer    BoardWriter `json:"writer"`
-	Like      uint        `json:"like"`
-	Liked     bool        `json:"liked"`
-	Submitted uint64      `json:"submitted"`
-	Modified  uint64      `json:"modified"`
-	Status    Status      `json:"status"`
-	Content   string      `json:"content"`
+	Uid        uint        `json:"uid"`
+	ReplyUid   uint        `json:"replyUid"`
+	ParentUid  uint        `json:"parentUid"`
+	Depth      uint        `json:"depth"`
+	ReplyCount uint        `json:"replyCount"`
+	PostUid    uint        `json:"postUid"`
+	Writer     BoardWriter `json:"writer"`
+	Like       uint        `json:"like"`
+	Liked      bool        `json:"liked"`
+	Submitted  uint64      `json:"submitted"`
+	Modified   uint64      `json:"modified"`
+	Edited     bool        `json:"edited"`
+	EditCount  uint        `json:"editCount"`
+	Status     Status      `json:"status"`
+	Hidden     bool        `json:"hidden"` // 검토 중이라 본문을 비워서 내려보내는 댓글
+	Content    string      `json:"content"`
 }
 
 // 댓글 목록 가져오기 결과 정의
@@ -28,9 +54,21 @@ type CommentListResult struct {
 	BoardUid          uint          `json:"boardUid"`
 	SinceUid          uint          `json:"sinceUid"`
 	TotalCommentCount uint          `json:"totalCommentCount"`
+	ThreadCount       uint          `json:"threadCount"`
 	Comments          []CommentItem `json:"comments"`
 }
 
+// 댓글 스레드 위치 정보 정의 (path는 조상부터 자신까지의 고유 번호를 10자리로 이어 붙인 값)
+type CommentThread struct {
+	Uid       uint
+	PostUid   uint
+	ParentUid uint
+	RootUid   uint
+	Depth     uint
+	Path      string
+	Status    Status
+}
+
 // 댓글에 좋아요 처리에 필요한 파라미터 정의
 type CommentLikeParam struct {
 	BoardUid   uint `json:"boardUid"`
@@ -60,8 +98,31 @@ type CommentReplyParam struct {
 
 // 새 댓글 작성하기에 필요한 파라미터 정의
 type CommentWriteParam struct {
-	BoardUid uint   `json:"boardUid"`
-	PostUid  uint   `json:"postUid"`
-	UserUid  uint   `json:"userUid"`
-	Content  string `json:"content"`
+	BoardUid   uint   `json:"boardUid"`
+	PostUid    uint   `json:"postUid"`
+	UserUid    uint   `json:"userUid"`
+	Content    string `json:"content"`
+	ShareToken string `json:"shareToken"`
+}
+
+// 댓글 수정 이력 가져오기에 필요한 파라미터 정의
+type CommentHistoryParam struct {
+	BoardUid   uint `query:"boardUid" json:"boardUid"`
+	CommentUid uint `query:"commentUid" json:"commentUid"`
+	UserUid    uint `query:"userUid" json:"userUid"`
+}
+
+// 댓글 수정 이력 항목 정의 (content는 수정되기 전 내용)
+type CommentHistoryItem struct {
+	Uid       uint        `json:"uid"`
+	Editor    BoardWriter `json:"editor"`
+	Content   string      `json:"content"`
+	Written   uint64      `json:"written"`
+	Timestamp uint64      `json:"timestamp"`
+}
+
+// 댓글 수정 이력 반환값 정의
+type CommentHistoryResult struct {
+	CommentUid uint                 `json:"commentUid"`
+	History    []CommentHistoryItem `json:"history"`
 }
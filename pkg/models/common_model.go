@@ -13,36 +13,62 @@ type Table string
 
 // 게시판 테이블 이름들 정리
 const (
-	TABLE_BOARD         Table = "board"
-	TABLE_BOARD_CAT     Table = "board_category"
-	TABLE_CHAT          Table = "chat"
-	TABLE_COMMENT       Table = "comment"
-	TABLE_COMMENT_LIKE  Table = "comment_like"
-	TABLE_EXIF          Table = "exif"
-	TABLE_FILE          Table = "file"
-	TABLE_FILE_THUMB    Table = "file_thumbnail"
-	TABLE_GROUP         Table = "group"
-	TABLE_HASHTAG       Table = "hashtag"
-	TABLE_IMAGE         Table = "image"
-	TABLE_IMAGE_DESC    Table = "image_description"
-	TABLE_NOTI          Table = "notification"
-	TABLE_POINT_HISTORY Table = "point_history"
-	TABLE_POST          Table = "post"
-	TABLE_POST_HASHTAG  Table = "post_hashtag"
-	TABLE_POST_LIKE     Table = "post_like"
-	TABLE_PUSH_DEVICE   Table = "push_device"
-	TABLE_REPORT        Table = "report"
-	TABLE_SKIN_SETTING  Table = "skin_setting"
-	TABLE_TRADE         Table = "trade"
-	TABLE_USER          Table = "user"
-	TABLE_USER_ACCESS   Table = "user_access_log"
-	TABLE_USER_BLOCK    Table = "user_black_list"
-	TABLE_USER_PERM     Table = "user_permission"
-	TABLE_USER_TOKEN    Table = "user_token"
-	TABLE_USER_VERIFY   Table = "user_verification"
-	TABLE_MAIL_CAMPAIGN Table = "mail_campaign"
-	TABLE_MAIL_DELIVERY Table = "mail_delivery"
-	TABLE_SIGNUP_INVITE Table = "signup_invite"
+	TABLE_AP_ACTIVITY    Table = "activitypub_activity"
+	TABLE_AP_ACTOR       Table = "activitypub_actor"
+	TABLE_AP_DELIVERY    Table = "activitypub_delivery"
+	TABLE_AP_FOLLOWER    Table = "activitypub_follower"
+	TABLE_AP_KEY         Table = "activitypub_key"
+	TABLE_BOARD          Table = "board"
+	TABLE_BOARD_ACL      Table = "board_acl"
+	TABLE_BOARD_CAT      Table = "board_category"
+	TABLE_CHAT           Table = "chat"
+	TABLE_COMMENT        Table = "comment"
+	TABLE_COMMENT_HIST   Table = "comment_history"
+	TABLE_COMMENT_LIKE   Table = "comment_like"
+	TABLE_FILTER         Table = "content_filter"
+	TABLE_FILTER_LOG     Table = "content_filter_log"
+	TABLE_EXIF           Table = "exif"
+	TABLE_FILE           Table = "file"
+	TABLE_FILE_THUMB     Table = "file_thumbnail"
+	TABLE_GROUP          Table = "group"
+	TABLE_HASHTAG        Table = "hashtag"
+	TABLE_IMAGE          Table = "image"
+	TABLE_IMAGE_DESC     Table = "image_description"
+	TABLE_IMAGE_VARIANT  Table = "image_variant"
+	TABLE_IMPORT_JOB     Table = "import_job"
+	TABLE_IMPORT_MAP     Table = "import_map"
+	TABLE_LINK_PREVIEW   Table = "link_preview"
+	TABLE_NOTI           Table = "notification"
+	TABLE_POINT_HISTORY  Table = "point_history"
+	TABLE_POST           Table = "post"
+	TABLE_POST_APPROVAL  Table = "post_approval"
+	TABLE_POST_HASHTAG   Table = "post_hashtag"
+	TABLE_POST_LIKE      Table = "post_like"
+	TABLE_POST_READ      Table = "post_read_event"
+	TABLE_POST_SHARE     Table = "post_share"
+	TABLE_POST_SHARE_LOG Table = "post_share_log"
+	TABLE_POST_STAT      Table = "post_stat_daily"
+	TABLE_POST_STAT_REF  Table = "post_stat_referrer"
+	TABLE_POST_VIEW      Table = "post_view"
+	TABLE_PUSH_DEVICE    Table = "push_device"
+	TABLE_REPORT         Table = "report"
+	TABLE_ROLE           Table = "role"
+	TABLE_ROLE_MEMBER    Table = "role_member"
+	TABLE_SKIN_SETTING   Table = "skin_setting"
+	TABLE_SPAM_LOG       Table = "spam_log"
+	TABLE_SYNC_CHANGE    Table = "sync_change"
+	TABLE_SYNC_LOG       Table = "sync_log"
+	TABLE_SYNC_SOURCE    Table = "sync_source"
+	TABLE_TRADE          Table = "trade"
+	TABLE_USER           Table = "user"
+	TABLE_USER_ACCESS    Table = "user_access_log"
+	TABLE_USER_BLOCK     Table = "user_black_list"
+	TABLE_USER_PERM      Table = "user_permission"
+	TABLE_USER_TOKEN     Table = "user_token"
+	TABLE_USER_VERIFY    Table = "user_verification"
+	TABLE_MAIL_CAMPAIGN  Table = "mail_campaign"
+	TABLE_MAIL_DELIVERY  Table = "mail_delivery"
+	TABLE_SIGNUP_INVITE  Table = "signup_invite"
 )
 
 // 고유값과 이름 구조체 정의
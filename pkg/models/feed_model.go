@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// 피드 출력 형식 정의
+type FeedFormat string
+
+const (
+	FEED_RSS  FeedFormat = "rss"
+	FEED_ATOM FeedFormat = "atom"
+	FEED_JSON FeedFormat = "json"
+)
+
+// 피드 범위 정의
+type FeedScope uint8
+
+const (
+	FEED_SITE FeedScope = iota
+	FEED_BOARD
+	FEED_TAG
+	FEED_USER
+)
+
+// 요약 모드에서 본문 대신 싣는 글자 수
+const FEED_SUMMARY_LENGTH = 300
+
+// 피드 요청 파라미터 정의 (범위에 따라 BoardId, Hashtag, UserUid 중 하나만 사용)
+type FeedParam struct {
+	Scope   FeedScope
+	Format  FeedFormat
+	BoardId string
+	Hashtag string
+	UserUid uint
+}
+
+// 피드용 게시글 조회 파라미터 정의
+type FeedPostParam struct {
+	BoardUid uint
+	PostUid  uint
+	Hashtag  string
+	UserUid  uint
+	Limit    uint
+}
+
+// 피드용 게시글 레코드 정의 (게시판, 작성자 정보 포함)
+type FeedPost struct {
+	Uid         uint
+	BoardId     string
+	BoardType   Board
+	FeedSummary bool
+	UserName    string
+	Title       string
+	Content     string
+	Submitted   int64
+	Modified    int64
+}
+
+// 피드용 첨부파일 레코드 정의
+type FeedFile struct {
+	Name string
+	Path string
+}
+
+// 형식과 무관한 피드 정의 (RSS, Atom, JSON Feed 모두 여기서 만들어짐)
+type Feed struct {
+	Title       string
+	Link        string
+	FeedURL     string
+	Description string
+	Language    string
+	Location    *time.Location
+	Updated     int64
+	Generator   string
+	Items       []FeedItem
+}
+
+// 피드 항목 정의 (요약 모드에서는 Content가 비어 있음)
+type FeedItem struct {
+	PostUid    uint
+	Id         string
+	Link       string
+	Title      string
+	Content    string
+	Summary    string
+	Author     string
+	Published  int64
+	Updated    int64
+	Tags       []string
+	Enclosures []FeedEnclosure
+}
+
+// 피드 항목에 딸린 첨부 이미지 정의
+type FeedEnclosure struct {
+	URL    string
+	Type   string
+	Length uint
+}
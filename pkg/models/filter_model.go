@@ -0,0 +1,76 @@
+package models
+
+// 금지어 필터 매칭 방식 정의
+type FilterMatch uint8
+
+const (
+	FILTER_MATCH_EXACT FilterMatch = iota // 대소문자 구분 없이 포함 여부 검사
+	FILTER_MATCH_REGEX                    // 정규표현식 검사
+	FILTER_MATCH_JAMO                     // 한글 자모 분리, 띄어쓰기/특수문자 끼워넣기 무시하고 검사
+)
+
+// 금지어 필터에 걸렸을 때 처리 방식 정의
+type FilterAction uint8
+
+const (
+	FILTER_ACTION_NONE  FilterAction = iota // 걸린 항목 없음
+	FILTER_ACTION_MASK                      // 해당 부분을 * 로 가림
+	FILTER_ACTION_HOLD                      // 검토 대기로 보류 (게시글은 비밀글로 저장)
+	FILTER_ACTION_BLOCK                     // 작성 거부
+)
+
+// 금지어 필터가 적용되는 대상 정의
+type FilterTarget uint8
+
+const (
+	FILTER_TARGET_POST_TITLE FilterTarget = iota
+	FILTER_TARGET_POST_CONTENT
+	FILTER_TARGET_COMMENT
+	FILTER_TARGET_CHAT
+	FILTER_TARGET_USER_NAME
+	FILTER_TARGET_USER_SIGNATURE
+)
+
+// 금지어 필터 항목 정의
+type ContentFilterRule struct {
+	Uid     uint         `json:"uid"`
+	Pattern string       `json:"pattern"`
+	Match   FilterMatch  `json:"match"`
+	Action  FilterAction `json:"action"`
+	Enabled bool         `json:"enabled"`
+	Created uint64       `json:"created"`
+}
+
+// 금지어 필터 항목 추가/수정 파라미터 정의 (uid가 0이면 추가)
+type ContentFilterSaveParam struct {
+	Uid     uint         `json:"uid"`
+	Pattern string       `json:"pattern"`
+	Match   FilterMatch  `json:"match"`
+	Action  FilterAction `json:"action"`
+	Enabled bool         `json:"enabled"`
+}
+
+// 금지어 필터 적중 기록 정의
+type ContentFilterLog struct {
+	Uid       uint         `json:"uid"`
+	FilterUid uint         `json:"filterUid"`
+	Pattern   string       `json:"pattern"`
+	Target    FilterTarget `json:"target"`
+	Action    FilterAction `json:"action"`
+	UserUid   uint         `json:"userUid"`
+	Excerpt   string       `json:"excerpt"`
+	Timestamp uint64       `json:"timestamp"`
+}
+
+// 금지어 필터 적중 기록 검색 파라미터 정의
+type ContentFilterLogParam struct {
+	Page      uint `query:"page" json:"page"`
+	Limit     uint `query:"limit" json:"limit"`
+	FilterUid uint `query:"filterUid" json:"filterUid"`
+}
+
+// 금지어 필터 적중 기록 목록 반환값 정의
+type ContentFilterLogResult struct {
+	Item  []ContentFilterLog `json:"item"`
+	Total uint               `json:"total"`
+}
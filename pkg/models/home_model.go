@@ -55,3 +55,55 @@ type HomePostItem struct {
 	UserUid     uint `json:"userUid"`
 	CategoryUid uint `json:"categoryUid"`
 }
+
+// 인기글 점수 계산 대상 게시글 정의
+type TrendingCandidate struct {
+	HomePostItem
+	Like     uint                    `json:"like"`
+	Comment  uint                    `json:"comment"`
+	Board    BoardBasicSettingResult `json:"board"`
+	Category Pair                    `json:"category"`
+	Cover    string                  `json:"cover"`
+	Writer   BoardWriter             `json:"writer"`
+}
+
+// 인기글 리턴 타입 정의
+type TrendingPostItem struct {
+	BoardHomePostItem
+	Score float64 `json:"score"`
+}
+
+// 게시판 인기글 및 게시판 정보 리턴 타입 정의
+type TrendingPostResult struct {
+	Items  []TrendingPostItem `json:"items"`
+	Config BoardConfig        `json:"config"`
+}
+
+// 사이트맵 하나에 담을 수 있는 최대 주소 수
+const SITEMAP_URL_LIMIT = 50000
+
+// 사이트맵 주소 항목 정의 (lastmod는 W3C 날짜 형식)
+type SitemapURL struct {
+	Loc        string
+	LastMod    string
+	ChangeFreq string
+	Priority   string
+}
+
+// 사이트맵 인덱스에 나열할 게시판별 사이트맵 정의
+type SitemapIndexItem struct {
+	Loc     string
+	LastMod string
+}
+
+// 사이트맵에 들어갈 게시글 정의 (수정한 적 없으면 작성 시간)
+type SitemapPost struct {
+	Uid      uint
+	Modified int64
+}
+
+// 게시판의 공개 게시글 수와 마지막 수정 시간 정의
+type SitemapBoardStat struct {
+	Count    uint
+	Modified int64
+}
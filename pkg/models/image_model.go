@@ -0,0 +1,33 @@
+package models
+
+// 반응형 이미지(srcset)용 변형 정의 (Type은 image/avif 같은 MIME 형식)
+type ImageVariant struct {
+	Path  string `json:"path"`
+	Width uint   `json:"width"`
+	Type  string `json:"type"`
+}
+
+// 업로드 종류별 형식으로 저장한 이미지 정의 (Fallback은 AVIF로 저장했을 때의 대체 형식 경로, 아니면 빈 문자열)
+type SavedImage struct {
+	Path     string
+	Fallback string
+	Variants []ImageVariant
+}
+
+// 변형을 채워 넣을 기존 이미지 정의 (Original은 변형을 만들 원본, 썸네일이 아니면 Source와 같음)
+type ImageVariantTarget struct {
+	Uid      uint
+	FileUid  uint
+	Source   string
+	Large    string
+	Original string
+}
+
+// 기존 이미지 변형 채우기 결과 정의
+type ImageVariantBackfillReport struct {
+	Category UploadCategory `json:"category"`
+	Scanned  uint           `json:"scanned"`
+	Created  uint           `json:"created"`
+	Failed   uint           `json:"failed"`
+	Errors   []string       `json:"errors"`
+}
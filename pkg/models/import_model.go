@@ -0,0 +1,116 @@
+package models
+
+// 가져오기 작업 상태들
+const (
+	IMPORT_RUNNING = "running"
+	IMPORT_DONE    = "done"
+	IMPORT_FAILED  = "failed"
+)
+
+// 원본 항목과 가져온 레코드를 연결하는 종류들
+const (
+	IMPORT_KIND_POST    = "post"
+	IMPORT_KIND_COMMENT = "comment"
+	IMPORT_KIND_IMAGE   = "image"
+	IMPORT_KIND_ATTACH  = "attachment"
+)
+
+// 워드프레스 내보내기(WXR) 파일 전체 정의
+type WXRSite struct {
+	Title       string
+	Link        string
+	Posts       []WXRPost
+	Attachments map[uint]string
+}
+
+// 워드프레스 글 항목 정의 (시간은 밀리초 단위 유닉스 시간)
+type WXRPost struct {
+	Id          uint
+	Title       string
+	Link        string
+	Content     string
+	Type        string
+	Status      string
+	Submitted   int64
+	Modified    int64
+	Categories  []string
+	Tags        []string
+	ThumbnailId uint
+	Comments    []WXRComment
+}
+
+// 워드프레스 댓글 항목 정의
+type WXRComment struct {
+	Id          uint
+	ParentId    uint
+	Author      string
+	AuthorEmail string
+	Content     string
+	Type        string
+	Approved    bool
+	Submitted   int64
+}
+
+// 워드프레스 가져오기 요청 파라미터 정의
+type WordPressImportParam struct {
+	BoardUid uint
+	UserUid  uint
+	DryRun   bool
+	Site     WXRSite
+}
+
+// 가져온 글을 저장할 때 필요한 파라미터 정의
+type ImportPostParam struct {
+	BoardUid    uint
+	UserUid     uint
+	CategoryUid uint
+	Title       string
+	Content     string
+	Status      Status
+	Submitted   int64
+	Modified    int64
+	Hit         uint
+	SourceHash  string // 원본 사이트 해시 (SourceKey와 함께 있으면 글 저장과 같은 트랜잭션으로 연결 정보 남김)
+	SourceKey   string // 원본 글 키 해시
+}
+
+// 가져온 댓글을 저장할 때 필요한 파라미터 정의
+type ImportCommentParam struct {
+	BoardUid  uint
+	PostUid   uint
+	ParentUid uint
+	UserUid   uint
+	Content   string
+	Submitted int64
+	Modified  int64
+	Status    Status
+}
+
+// 가져오기 결과 보고서 정의 (dryRun이면 실제로 저장하지 않고 예상 결과만 집계)
+type ImportReport struct {
+	DryRun          bool     `json:"dryRun"`
+	Posts           uint     `json:"posts"`
+	SkippedPosts    uint     `json:"skippedPosts"`
+	IgnoredItems    uint     `json:"ignoredItems"`
+	Comments        uint     `json:"comments"`
+	SkippedComments uint     `json:"skippedComments"`
+	Images          uint     `json:"images"`
+	FailedImages    uint     `json:"failedImages"`
+	RewrittenLinks  uint     `json:"rewrittenLinks"`
+	Categories      []string `json:"categories"`
+	Tags            []string `json:"tags"`
+	Warnings        []string `json:"warnings"`
+}
+
+// 가져오기 작업 정의
+type ImportJob struct {
+	Uid       uint         `json:"uid"`
+	BoardUid  uint         `json:"boardUid"`
+	UserUid   uint         `json:"userUid"`
+	Source    string       `json:"source"`
+	Status    string       `json:"status"`
+	Report    ImportReport `json:"report"`
+	LastError string       `json:"lastError"`
+	Created   int64        `json:"created"`
+	Updated   int64        `json:"updated"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// 링크 미리보기 항목 정의 (image는 서버에 저장된 축소 이미지 경로)
+type LinkPreview struct {
+	Url         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	SiteName    string `json:"siteName"`
+	Image       string `json:"image"`
+	Type        string `json:"type"`
+}
+
+// 링크 미리보기 캐시 레코드 정의
+type LinkPreviewRecord struct {
+	LinkPreview
+	UrlHash string
+	Failed  bool
+	Fetched int64
+}
@@ -0,0 +1,76 @@
+package models
+
+import "encoding/xml"
+
+// 게시글 메타데이터 설명 최대 길이, oEmbed 버전과 기본 크기, 캐시 시간 정의
+const (
+	META_DESCRIPTION_LENGTH = 200
+	OEMBED_VERSION          = "1.0"
+	OEMBED_RICH_WIDTH       = 600
+	OEMBED_RICH_HEIGHT      = 200
+	OEMBED_CACHE_AGE        = 3600
+)
+
+// oEmbed 응답 형식 정의
+type OEmbedFormat string
+
+const (
+	OEMBED_JSON OEmbedFormat = "json"
+	OEMBED_XML  OEmbedFormat = "xml"
+)
+
+// oEmbed 요청 파라미터 정의 (MaxWidth, MaxHeight가 0이면 제한 없음)
+type OEmbedParam struct {
+	URL       string `query:"url"`
+	Format    string `query:"format"`
+	MaxWidth  uint   `query:"maxwidth"`
+	MaxHeight uint   `query:"maxheight"`
+}
+
+// 게시글 메타데이터의 대표 이미지 정의 (크기를 알 수 없으면 0)
+type BoardMetaImage struct {
+	URL    string `json:"url"`
+	Width  uint   `json:"width"`
+	Height uint   `json:"height"`
+	Alt    string `json:"alt"`
+}
+
+// 게시글 메타데이터의 작성자 정의
+type BoardMetaAuthor struct {
+	Name    string `json:"name"`
+	Profile string `json:"profile"`
+}
+
+// 게시글 OpenGraph 메타데이터 반환 타입 정의 (주소는 모두 절대 주소, 시간은 RFC3339)
+type BoardMetaResult struct {
+	SiteName      string           `json:"siteName"`
+	Board         BoardBasicConfig `json:"board"`
+	PostUid       uint             `json:"postUid"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	Canonical     string           `json:"canonical"`
+	Cover         BoardMetaImage   `json:"cover"`
+	Author        BoardMetaAuthor  `json:"author"`
+	PublishedTime string           `json:"publishedTime"`
+	ModifiedTime  string           `json:"modifiedTime"`
+	Tags          []string         `json:"tags"`
+}
+
+// oEmbed 응답 정의 (사진은 URL, 나머지는 HTML을 채움)
+type OEmbedResult struct {
+	XMLName         xml.Name `json:"-" xml:"oembed"`
+	Type            string   `json:"type" xml:"type"`
+	Version         string   `json:"version" xml:"version"`
+	Title           string   `json:"title" xml:"title"`
+	AuthorName      string   `json:"author_name,omitempty" xml:"author_name,omitempty"`
+	ProviderName    string   `json:"provider_name" xml:"provider_name"`
+	ProviderURL     string   `json:"provider_url" xml:"provider_url"`
+	CacheAge        uint     `json:"cache_age" xml:"cache_age"`
+	ThumbnailURL    string   `json:"thumbnail_url,omitempty" xml:"thumbnail_url,omitempty"`
+	ThumbnailWidth  uint     `json:"thumbnail_width,omitempty" xml:"thumbnail_width,omitempty"`
+	ThumbnailHeight uint     `json:"thumbnail_height,omitempty" xml:"thumbnail_height,omitempty"`
+	URL             string   `json:"url,omitempty" xml:"url,omitempty"`
+	HTML            string   `json:"html,omitempty" xml:"html,omitempty"`
+	Width           uint     `json:"width" xml:"width"`
+	Height          uint     `json:"height" xml:"height"`
+}
@@ -31,4 +31,6 @@ const (
 	NOTI_LEAVE_COMMENT
 	NOTI_REPLY_COMMENT
 	NOTI_CHAT_MESSAGE
+	NOTI_POST_APPROVED
+	NOTI_POST_REJECTED
 )
@@ -0,0 +1,101 @@
+package models
+
+// 신고 대상 종류 정의
+type ReportTarget uint8
+
+const (
+	REPORT_TARGET_USER ReportTarget = iota
+	REPORT_TARGET_POST
+	REPORT_TARGET_COMMENT
+)
+
+// 신고 사유 분류 정의
+type ReportReason uint8
+
+const (
+	REPORT_REASON_OTHER ReportReason = iota
+	REPORT_REASON_SPAM
+	REPORT_REASON_ABUSE
+	REPORT_REASON_OBSCENE
+	REPORT_REASON_ILLEGAL
+	REPORT_REASON_PRIVACY
+	REPORT_REASON_COPYRIGHT
+	REPORT_REASON_FILTER // 금지어 필터가 검토를 위해 보류한 내용 (회원이 고를 수 없음)
+)
+
+// 신고 처리 방식 정의
+type ReportResolveAction uint8
+
+const (
+	REPORT_RESOLVE_DISMISS  ReportResolveAction = iota // 신고 기각 (자동으로 숨겨진 내용은 다시 공개)
+	REPORT_RESOLVE_REMOVE                              // 신고된 글/댓글 삭제
+	REPORT_RESOLVE_SANCTION                            // 삭제 후 작성자의 글, 댓글, 쪽지 작성 권한 회수
+)
+
+// 게시글/댓글 신고하기 파라미터 정의
+type ContentReportParam struct {
+	UserUid   uint
+	Target    ReportTarget `json:"target"`
+	TargetUid uint         `json:"targetUid"`
+	Reason    ReportReason `json:"reason"`
+	Content   string       `json:"content"`
+}
+
+// 신고된 게시글/댓글의 위치와 작성자 정보 정의
+type ReportTargetInfo struct {
+	BoardUid  uint
+	PostUid   uint
+	WriterUid uint
+	Status    Status
+}
+
+// 게시글/댓글 신고 목록 검색 파라미터 정의 (Target이 0이면 게시글, 댓글 모두)
+type AdminContentReportParam struct {
+	Page     uint         `query:"page" json:"page"`
+	Limit    uint         `query:"limit" json:"limit"`
+	IsSolved bool         `query:"isSolved" json:"isSolved"`
+	Target   ReportTarget `query:"target" json:"target"`
+}
+
+// 신고 사유별 건수 정의
+type AdminContentReportReason struct {
+	Reason ReportReason `json:"reason"`
+	Count  uint         `json:"count"`
+}
+
+// 신고자가 남긴 신고 내용 정의
+type AdminContentReportRequest struct {
+	From    BoardWriter  `json:"from"`
+	Reason  ReportReason `json:"reason"`
+	Request string       `json:"request"`
+	Date    uint64       `json:"date"`
+}
+
+// 대상별로 묶은 게시글/댓글 신고 항목 정의
+type AdminContentReportItem struct {
+	Target       ReportTarget                `json:"target"`
+	TargetUid    uint                        `json:"targetUid"`
+	BoardUid     uint                        `json:"boardUid"`
+	PostUid      uint                        `json:"postUid"`
+	Writer       BoardWriter                 `json:"writer"`
+	Excerpt      string                      `json:"excerpt"`
+	Status       Status                      `json:"status"`
+	Hidden       bool                        `json:"hidden"`
+	Count        uint                        `json:"count"`
+	LastReported uint64                      `json:"lastReported"`
+	Reasons      []AdminContentReportReason  `json:"reasons"`
+	Requests     []AdminContentReportRequest `json:"requests"`
+}
+
+type AdminContentReportResult struct {
+	Item  []AdminContentReportItem `json:"item"`
+	Total uint                     `json:"total"`
+}
+
+// 게시글/댓글 신고 처리 파라미터 정의
+type AdminContentReportResolveParam struct {
+	Target    ReportTarget        `json:"target"`
+	TargetUid uint                `json:"targetUid"`
+	Action    ReportResolveAction `json:"action"`
+	Response  string              `json:"response"`
+}
@@ -0,0 +1,43 @@
+package models
+
+// 게시판 역할 규칙 판정 결과 정의
+type BoardAclDecision uint8
+
+// 게시판 역할 규칙 판정 결과 목록
+const (
+	BOARD_ACL_NONE  BoardAclDecision = iota // 규칙 없음, 레벨 제한만 적용
+	BOARD_ACL_ALLOW                         // 허용된 역할 소속, 레벨 제한 면제
+	BOARD_ACL_DENY                          // 거부된 역할 소속이거나 허용 역할에 속하지 않음
+)
+
+// 역할 규칙으로 거부되었을 때 돌려줄 필요 레벨 (회원 레벨 최대값보다 큼)
+const BOARD_LEVEL_DENIED = 256
+
+// 게시판 활동별 역할 허용/거부 규칙 정의
+type BoardAclRule struct {
+	RoleUid uint        `json:"roleUid"`
+	Action  BoardAction `json:"action"`
+	Allow   bool        `json:"allow"`
+}
+
+// 회원 역할 항목 정의
+type UserRole struct {
+	Uid         uint   `json:"uid"`
+	Name        string `json:"name"`
+	Info        string `json:"info"`
+	MemberCount uint   `json:"memberCount"`
+	Timestamp   uint64 `json:"timestamp"`
+}
+
+// 회원 역할 생성/수정 파라미터 정의
+type AdminRoleSaveParam struct {
+	Uid  uint   `json:"uid"`
+	Name string `json:"name"`
+	Info string `json:"info"`
+}
+
+// 역할 구성원 추가/삭제 파라미터 정의
+type AdminRoleMemberParam struct {
+	RoleUid uint `query:"roleUid" json:"roleUid"`
+	UserUid uint `query:"userUid" json:"userUid"`
+}
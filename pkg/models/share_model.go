@@ -0,0 +1,72 @@
+package models
+
+// 비밀글 공유 링크 생성 파라미터 정의
+type PostShareCreateParam struct {
+	BoardUid      uint `json:"boardUid"`
+	PostUid       uint `json:"postUid"`
+	Hours         uint `json:"hours"`
+	MaxViews      uint `json:"maxViews"`
+	AllowComment  bool `json:"allowComment"`
+	AllowDownload bool `json:"allowDownload"`
+	UserUid       uint
+}
+
+// 비밀글 공유 링크 항목 정의
+type PostShareItem struct {
+	Uid           uint  `json:"uid"`
+	BoardUid      uint  `json:"boardUid"`
+	PostUid       uint  `json:"postUid"`
+	CreatedBy     uint  `json:"createdBy"`
+	Created       int64 `json:"created"`
+	Expires       int64 `json:"expires"`
+	MaxViews      uint  `json:"maxViews"`
+	Views         uint  `json:"views"`
+	AllowComment  bool  `json:"allowComment"`
+	AllowDownload bool  `json:"allowDownload"`
+	Revoked       bool  `json:"revoked"`
+}
+
+// 공유 링크 생성 결과 정의 (토큰 원문은 생성 시에만 반환)
+type PostShareCreated struct {
+	PostShareItem
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// 공유 링크 목록/관리 파라미터 정의
+type PostShareManageParam struct {
+	BoardUid uint `query:"boardUid" json:"boardUid"`
+	PostUid  uint `query:"postUid" json:"postUid"`
+	ShareUid uint `query:"shareUid" json:"shareUid"`
+	UserUid  uint
+}
+
+// 공유 링크로 글 보기 파라미터 정의
+type PostShareViewParam struct {
+	Token   string `query:"token"`
+	UserUid uint
+	IP      string
+	Agent   string
+}
+
+// 공유 링크로 본 게시글 반환값 정의
+type PostShareViewResult struct {
+	Board         BoardBasicConfig     `json:"board"`
+	Post          BoardListItem        `json:"post"`
+	Images        []BoardAttachedImage `json:"images"`
+	Files         []BoardAttachment    `json:"files"`
+	Tags          []Pair               `json:"tags"`
+	Expires       int64                `json:"expires"`
+	AllowComment  bool                 `json:"allowComment"`
+	AllowDownload bool                 `json:"allowDownload"`
+}
+
+// 공유 링크 열람 기록 항목 정의
+type PostShareLog struct {
+	Uid       uint   `json:"uid"`
+	ShareUid  uint   `json:"shareUid"`
+	UserUid   uint   `json:"userUid"`
+	IPHash    string `json:"ipHash"`
+	Agent     string `json:"agent"`
+	Timestamp int64  `json:"timestamp"`
+}
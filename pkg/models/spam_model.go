@@ -0,0 +1,38 @@
+package models
+
+// 스팸 점수를 매기는 작성 대상 정의
+type SpamTarget uint8
+
+const (
+	SPAM_TARGET_POST SpamTarget = iota
+	SPAM_TARGET_COMMENT
+	SPAM_TARGET_CHAT
+)
+
+// 스팸 점수에 따른 처리 결과 정의
+type SpamVerdict uint8
+
+const (
+	SPAM_VERDICT_ALLOW  SpamVerdict = iota // 그대로 작성
+	SPAM_VERDICT_HOLD                      // 검토 대기로 보류 (게시글은 비밀글로 저장)
+	SPAM_VERDICT_REJECT                    // 작성 거부
+)
+
+// 스팸 점수 계산에 쓰이는 작성자/내용 신호 정의
+type SpamSignal struct {
+	AccountHours float64
+	Level        int
+	Links        int
+	Duplicates   int
+	RecentWrites int
+}
+
+// 스팸 점수 기록 정의
+type SpamLog struct {
+	UserUid   uint
+	Target    SpamTarget
+	Hash      string
+	Score     int
+	Verdict   SpamVerdict
+	Timestamp uint64
+}
@@ -18,7 +18,128 @@ type SyncPostItem struct {
 	Title     string          `json:"title"`
 	Content   string          `json:"content"`
 	Submitted uint64          `json:"submitted"`
+	Modified  uint64          `json:"modified"`
 	Name      string          `json:"name"`
 	Tags      []string        `json:"tags"`
 	Images    []SyncImageItem `json:"images"`
 }
+
+// 게시판별 동기화 범위 정의 (설정하지 않은 게시판은 기존처럼 모두 보냄)
+type SyncScope uint8
+
+const (
+	SYNC_SCOPE_FULL    SyncScope = iota // 본문과 이미지까지 모두 보냄
+	SYNC_SCOPE_SUMMARY                  // 제목, 태그 등만 보내고 본문과 이미지는 보내지 않음
+	SYNC_SCOPE_NONE                     // 동기화하지 않음
+)
+
+// 게시글 변경 종류 정의
+type SyncAction uint8
+
+const (
+	SYNC_CREATED SyncAction = iota
+	SYNC_MODIFIED
+	SYNC_REMOVED
+)
+
+// 변경 종류를 문자열로 반환
+func (a SyncAction) String() string {
+	switch a {
+	case SYNC_MODIFIED:
+		return "modified"
+	case SYNC_REMOVED:
+		return "removed"
+	default:
+		return "created"
+	}
+}
+
+// 변경 피드 최대 개수, 커밋이 늦은 변경을 건너뛰지 않도록 기다리는 시간, 응답 서명 헤더 이름
+const (
+	SYNC_CHANGES_MAX      = 500
+	SYNC_SETTLE_MILLIS    = 3000
+	SYNC_SIGNATURE_HEADER = "X-Sync-Signature"
+)
+
+// 게시글 변경 기록 레코드 정의
+type SyncChangeRecord struct {
+	Seq       uint64
+	BoardUid  uint
+	PostUid   uint
+	Action    SyncAction
+	Timestamp int64
+}
+
+// 변경 피드 요청 파라미터 정의 (BoardId가 비어 있으면 모든 게시판)
+type SyncChangeParam struct {
+	Since   uint64
+	Limit   uint
+	BoardId string
+}
+
+// 변경 피드 항목 정의 (삭제되었거나 더 이상 동기화 대상이 아니면 Deleted가 true이고 Post는 비어 있음)
+type SyncChangeItem struct {
+	Seq       uint64        `json:"seq"`
+	Action    string        `json:"action"`
+	Id        string        `json:"id"`
+	No        uint          `json:"no"`
+	Timestamp int64         `json:"timestamp"`
+	Deleted   bool          `json:"deleted"`
+	Post      *SyncPostItem `json:"post,omitempty"`
+}
+
+// 변경 피드 결과 정의 (다음 요청에는 Next를 since로 보냄)
+type SyncChangeResult struct {
+	Changes []SyncChangeItem `json:"changes"`
+	Next    uint64           `json:"next"`
+	HasMore bool             `json:"hasMore"`
+}
+
+// 다른 NUBO에서 글을 가져오는 실행 결과 상태 정의
+const (
+	SYNC_RUN_SUCCESS = "success"
+	SYNC_RUN_FAILED  = "failed"
+)
+
+// 다른 NUBO의 동기화 원본 상태 정의 (Cursor는 마지막으로 반영한 변경 번호, 글 수는 누적값)
+type SyncSource struct {
+	Uid         uint   `json:"uid"`
+	SourceHash  string `json:"-"`
+	Remote      string `json:"remote"`
+	BoardUid    uint   `json:"boardUid"`
+	Cursor      uint64 `json:"cursor"`
+	Created     uint   `json:"created"`
+	Modified    uint   `json:"modified"`
+	Removed     uint   `json:"removed"`
+	LastRun     int64  `json:"lastRun"`
+	LastSuccess int64  `json:"lastSuccess"`
+	LastError   string `json:"lastError"`
+}
+
+// 동기화 가져오기 한 번의 실행 기록 정의
+type SyncRunLog struct {
+	Uid       uint   `json:"uid"`
+	SourceUid uint   `json:"sourceUid"`
+	Started   int64  `json:"started"`
+	Finished  int64  `json:"finished"`
+	FromSeq   uint64 `json:"fromSeq"`
+	ToSeq     uint64 `json:"toSeq"`
+	Created   uint   `json:"created"`
+	Modified  uint   `json:"modified"`
+	Removed   uint   `json:"removed"`
+	Images    uint   `json:"images"`
+	Status    string `json:"status"`
+	Error     string `json:"error"`
+}
+
+// 관리화면에 보여줄 동기화 가져오기 설정과 상태 정의
+type SyncConsumerStatus struct {
+	Enabled         bool         `json:"enabled"`
+	Running         bool         `json:"running"`
+	Remote          string       `json:"remote"`
+	RemoteBoard     string       `json:"remoteBoard"`
+	TargetBoard     string       `json:"targetBoard"`
+	IntervalMinutes int          `json:"intervalMinutes"`
+	Sources         []SyncSource `json:"sources"`
+	Logs            []SyncRunLog `json:"logs"`
+}
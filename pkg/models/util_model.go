@@ -7,6 +7,7 @@ type UploadCategory string
 const (
 	UPLOAD_ATTACH  UploadCategory = "attachments"
 	UPLOAD_IMAGE   UploadCategory = "images"
+	UPLOAD_PREVIEW UploadCategory = "previews"
 	UPLOAD_PROFILE UploadCategory = "profile"
 	UPLOAD_TEMP    UploadCategory = "temp"
 	UPLOAD_THUMB   UploadCategory = "thumbnails"
@@ -0,0 +1,302 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/sirini/goapi/pkg/models"
+)
+
+// 피드 형식별 Content-Type
+var FeedContentType = map[models.FeedFormat]string{
+	models.FEED_RSS:  "application/rss+xml; charset=UTF-8",
+	models.FEED_ATOM: "application/atom+xml; charset=UTF-8",
+	models.FEED_JSON: "application/feed+json; charset=UTF-8",
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Self          rssSelf   `xml:"atom:link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssSelf struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssGuid struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length uint   `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Guid        rssGuid       `xml:"guid"`
+	Description rssCDATA      `xml:"description"`
+	Content     *rssCDATA     `xml:"content:encoded"`
+	Creator     string        `xml:"dc:creator,omitempty"`
+	Categories  []string      `xml:"category"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang      string      `xml:"xml:lang,attr,omitempty"`
+	Id        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Generator string      `xml:"generator"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length uint   `xml:"length,attr,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Id         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomAuthor     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    atomText       `xml:"summary"`
+	Content    *atomText      `xml:"content"`
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Language    string         `json:"language,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+	Size     uint   `json:"size_in_bytes,omitempty"`
+}
+
+type jsonFeedItem struct {
+	Id            string               `json:"id"`
+	URL           string               `json:"url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html,omitempty"`
+	ContentText   string               `json:"content_text,omitempty"`
+	Summary       string               `json:"summary,omitempty"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+// 밀리초 시간을 피드 시간대의 주어진 형식 날짜로 바꾸기 (시간대가 없으면 UTC)
+func feedDate(timestamp int64, layout string, location *time.Location) string {
+	if location == nil {
+		location = time.UTC
+	}
+	return time.UnixMilli(timestamp).In(location).Format(layout)
+}
+
+// 피드를 요청한 형식(RSS 2.0, Atom 1.0, JSON Feed 1.1)으로 만들기
+func RenderFeed(feed models.Feed, format models.FeedFormat) ([]byte, error) {
+	switch format {
+	case models.FEED_RSS:
+		return renderRss(feed)
+	case models.FEED_ATOM:
+		return renderAtom(feed)
+	case models.FEED_JSON:
+		return renderJsonFeed(feed)
+	}
+	return nil, fmt.Errorf("unsupported feed format %q", format)
+}
+
+// RSS 2.0 만들기 (본문은 CDATA로 감싸고, 날짜는 RFC 822 형식이며, 항목당 첨부는 첫 번째 이미지만 싣기)
+func renderRss(feed models.Feed) ([]byte, error) {
+	channel := rssChannel{
+		Title:         feed.Title,
+		Link:          feed.Link,
+		Self:          rssSelf{Href: feed.FeedURL, Rel: "self", Type: "application/rss+xml"},
+		Description:   feed.Description,
+		Language:      feed.Language,
+		LastBuildDate: feedDate(feed.Updated, time.RFC1123Z, feed.Location),
+		Generator:     feed.Generator,
+		Items:         make([]rssItem, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Guid:        rssGuid{IsPermaLink: "true", Value: item.Id},
+			Description: rssCDATA{Value: item.Summary},
+			Creator:     item.Author,
+			Categories:  item.Tags,
+			PubDate:     feedDate(item.Published, time.RFC1123Z, feed.Location),
+		}
+		if len(item.Content) > 0 {
+			entry.Content = &rssCDATA{Value: item.Content}
+		}
+		if len(item.Enclosures) > 0 {
+			first := item.Enclosures[0]
+			entry.Enclosure = &rssEnclosure{URL: first.URL, Length: first.Length, Type: first.Type}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+	return marshalFeedXML(rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: channel,
+	})
+}
+
+// Atom 1.0 만들기
+func renderAtom(feed models.Feed) ([]byte, error) {
+	doc := atomFeed{
+		Lang:      feed.Language,
+		Id:        feed.FeedURL,
+		Title:     feed.Title,
+		Subtitle:  feed.Description,
+		Updated:   feedDate(feed.Updated, time.RFC3339, feed.Location),
+		Generator: feed.Generator,
+		Links: []atomLink{
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+			{Href: feed.FeedURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: make([]atomEntry, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			Id:        item.Id,
+			Title:     item.Title,
+			Links:     []atomLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Published: feedDate(item.Published, time.RFC3339, feed.Location),
+			Updated:   feedDate(item.Updated, time.RFC3339, feed.Location),
+			Author:    atomAuthor{Name: feed.Title},
+			Summary:   atomText{Type: "text", Value: item.Summary},
+		}
+		if len(item.Author) > 0 {
+			entry.Author.Name = item.Author
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		for _, enclosure := range item.Enclosures {
+			entry.Links = append(entry.Links, atomLink{Href: enclosure.URL, Rel: "enclosure", Type: enclosure.Type, Length: enclosure.Length})
+		}
+		if len(item.Content) > 0 {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshalFeedXML(doc)
+}
+
+// JSON Feed 1.1 만들기
+func renderJsonFeed(feed models.Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedURL,
+		Description: feed.Description,
+		Language:    feed.Language,
+		Items:       make([]jsonFeedItem, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		entry := jsonFeedItem{
+			Id:            item.Id,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.Content,
+			Summary:       item.Summary,
+			DatePublished: feedDate(item.Published, time.RFC3339, feed.Location),
+			DateModified:  feedDate(item.Updated, time.RFC3339, feed.Location),
+			Tags:          item.Tags,
+		}
+		if len(entry.ContentHTML) < 1 {
+			entry.ContentText = item.Summary
+		}
+		if len(item.Author) > 0 {
+			entry.Authors = []jsonFeedAuthor{{Name: item.Author}}
+		}
+		for _, enclosure := range item.Enclosures {
+			entry.Attachments = append(entry.Attachments, jsonFeedAttachment{URL: enclosure.URL, MimeType: enclosure.Type, Size: enclosure.Length})
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(doc)
+	return body.Bytes(), err
+}
+
+// XML 선언을 붙여 피드 문서 직렬화하기
+func marshalFeedXML(doc any) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
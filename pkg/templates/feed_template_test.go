@@ -0,0 +1,102 @@
+package templates
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirini/goapi/pkg/models"
+)
+
+func TestRenderFeedFormats(t *testing.T) {
+	feed := models.Feed{Title: "Tom & Jerry", Link: "https://example.com/blog/diary",
+		FeedURL: "https://example.com/goapi/feed/board/diary/rss", Language: "ko-KR", Updated: 1_700_000_000_000,
+		Items: []models.FeedItem{
+			{Id: "https://example.com/blog/diary/7", Link: "https://example.com/blog/diary/7", Title: "<First>",
+				Content: `<p>Hi</p>`, Summary: "Hi", Author: "Kim", Published: 1_600_000_000_000, Updated: 1_700_000_000_000,
+				Tags: []string{"travel"}, Enclosures: []models.FeedEnclosure{
+					{URL: "https://example.com/upload/a.jpg", Type: "image/jpeg", Length: 10},
+					{URL: "https://example.com/upload/b.png", Type: "image/png", Length: 20}}},
+			{Id: "https://example.com/blog/diary/5", Link: "https://example.com/blog/diary/5", Title: "Second", Summary: "Only summary"},
+		}}
+
+	rss, err := RenderFeed(feed, models.FEED_RSS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(rss)
+	if !strings.HasPrefix(body, xml.Header) || !strings.Contains(body, "<title>Tom &amp; Jerry</title>") ||
+		!strings.Contains(body, "<language>ko-KR</language>") || strings.Count(body, "<enclosure ") != 1 ||
+		!strings.Contains(body, "<content:encoded><![CDATA[<p>Hi</p>]]></content:encoded>") ||
+		strings.Count(body, "<content:encoded>") != 1 || !strings.Contains(body, `rel="self"`) {
+		t.Fatalf("unexpected rss %s", body)
+	}
+	if err := xml.Unmarshal(rss, new(struct{})); err != nil {
+		t.Fatalf("rss should be well-formed: %v", err)
+	}
+
+	atom, err := RenderFeed(feed, models.FEED_ATOM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body = string(atom)
+	if !strings.Contains(body, `<feed xmlns="http://www.w3.org/2005/Atom"`) || !strings.Contains(body, "<updated>2023-11-14T22:13:20Z</updated>") ||
+		strings.Count(body, `rel="enclosure"`) != 2 || strings.Count(body, `<content type="html">`) != 1 ||
+		!strings.Contains(body, "<name>Tom &amp; Jerry</name>") {
+		t.Fatalf("unexpected atom %s", body)
+	}
+
+	data, err := RenderFeed(feed, models.FEED_JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Version string `json:"version"`
+		Items   []struct {
+			ContentHTML string `json:"content_html"`
+			ContentText string `json:"content_text"`
+			Attachments []struct {
+				URL string `json:"url"`
+			} `json:"attachments"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Version != "https://jsonfeed.org/version/1.1" || parsed.Items[0].ContentHTML != "<p>Hi</p>" ||
+		len(parsed.Items[0].Attachments) != 2 || parsed.Items[1].ContentText != "Only summary" {
+		t.Fatalf("unexpected json feed %s", data)
+	}
+
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	feed.Location = seoul
+	feed.Items = []models.FeedItem{{Title: "A & B < C", Content: "<p>x]]>y & z</p>", Summary: "x]]>y", Published: 1_700_000_000_000}}
+	if rss, err = RenderFeed(feed, models.FEED_RSS); err != nil {
+		t.Fatal(err)
+	}
+	body = string(rss)
+	if !strings.Contains(body, "<pubDate>Wed, 15 Nov 2023 07:13:20 +0900</pubDate>") || !strings.Contains(body, "<title>A &amp; B &lt; C</title>") {
+		t.Fatalf("dates should follow the feed time zone and titles should be escaped, got %s", body)
+	}
+	var parsedRss struct {
+		Items []struct {
+			Description string `xml:"description"`
+			Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		} `xml:"channel>item"`
+	}
+	if err := xml.Unmarshal(rss, &parsedRss); err != nil {
+		t.Fatalf("rss should stay well-formed even when content contains ]]>: %v", err)
+	}
+	if parsedRss.Items[0].Content != "<p>x]]>y & z</p>" || parsedRss.Items[0].Description != "x]]>y" {
+		t.Fatalf("CDATA content should round-trip, got %+v", parsedRss.Items)
+	}
+
+	if _, err := RenderFeed(feed, "xml"); err == nil {
+		t.Fatal("unknown formats should be rejected")
+	}
+}
@@ -1,5 +1,12 @@
 package templates
 
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/sirini/goapi/pkg/models"
+)
+
 const SitemapBody = `<?xml version="1.0" encoding="UTF-8"?>
 <urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
 {{- range . }}
@@ -11,3 +18,46 @@ const SitemapBody = `<?xml version="1.0" encoding="UTF-8"?>
   </url>
 {{- end }}
 </urlset>`
+
+const SitemapIndexBody = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+{{- range . }}
+  <sitemap>
+    <loc>{{ .Loc }}</loc>
+    <lastmod>{{ .LastMod }}</lastmod>
+  </sitemap>
+{{- end }}
+</sitemapindex>`
+
+var sitemapTemplate = template.Must(template.New("sitemap").Parse(SitemapBody))
+var sitemapIndexTemplate = template.Must(template.New("sitemap-index").Parse(SitemapIndexBody))
+
+// 게시글 주소 목록으로 사이트맵 XML 만들기
+func RenderSitemap(items []models.SitemapURL) (string, error) {
+	escaped := make([]models.SitemapURL, 0, len(items))
+	for _, item := range items {
+		escaped = append(escaped, models.SitemapURL{
+			Loc:        template.HTMLEscapeString(item.Loc),
+			LastMod:    template.HTMLEscapeString(item.LastMod),
+			ChangeFreq: template.HTMLEscapeString(item.ChangeFreq),
+			Priority:   template.HTMLEscapeString(item.Priority),
+		})
+	}
+	var body bytes.Buffer
+	err := sitemapTemplate.Execute(&body, escaped)
+	return body.String(), err
+}
+
+// 게시판별 사이트맵 목록으로 사이트맵 인덱스 XML 만들기
+func RenderSitemapIndex(items []models.SitemapIndexItem) (string, error) {
+	escaped := make([]models.SitemapIndexItem, 0, len(items))
+	for _, item := range items {
+		escaped = append(escaped, models.SitemapIndexItem{
+			Loc:     template.HTMLEscapeString(item.Loc),
+			LastMod: template.HTMLEscapeString(item.LastMod),
+		})
+	}
+	var body bytes.Buffer
+	err := sitemapIndexTemplate.Execute(&body, escaped)
+	return body.String(), err
+}
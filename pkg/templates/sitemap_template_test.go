@@ -0,0 +1,26 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirini/goapi/pkg/models"
+)
+
+func TestRenderSitemapEscapesLocations(t *testing.T) {
+	body, err := RenderSitemap([]models.SitemapURL{{Loc: "https://example.com/board/a&b/1", LastMod: "2020-09-13T12:26:40Z"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(body, `<?xml version="1.0" encoding="UTF-8"?>`) || !strings.Contains(body, "<loc>https://example.com/board/a&amp;b/1</loc>") {
+		t.Fatalf("unexpected sitemap %s", body)
+	}
+
+	index, err := RenderSitemapIndex([]models.SitemapIndexItem{{Loc: "https://example.com/goapi/sitemap/free/1.xml"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(index, "<sitemapindex") || !strings.Contains(index, "<loc>https://example.com/goapi/sitemap/free/1.xml</loc>") {
+		t.Fatalf("unexpected sitemap index %s", index)
+	}
+}
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 서명 시간과 서버 시간이 이보다 많이 차이 나면 서명을 받아들이지 않음
+const activityPubClockSkew = 12 * time.Hour
+
+// 게시판 액터용 RSA 키 쌍을 PEM 형식으로 만들기
+func GenerateActivityPubKey() (string, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", "", err
+	}
+	private, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: private})), nil
+}
+
+// PEM 형식의 RSA 개인키 읽기
+func parseActivityPubPrivateKey(privatePem string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privatePem))
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+	return key, nil
+}
+
+// PEM 형식의 RSA 공개키 읽기
+func parseActivityPubPublicKey(publicPem string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicPem))
+	if block == nil {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not RSA")
+	}
+	return key, nil
+}
+
+// 본문의 Digest 헤더 값 만들기
+func ActivityPubDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// 서명할 헤더들로 서명 문자열 만들기 (headers의 이름은 소문자)
+func activityPubSigningString(method string, path string, names []string, headers map[string]string) (string, error) {
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "(request-target)" {
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(method), path))
+			continue
+		}
+		value, ok := headers[name]
+		if !ok {
+			return "", fmt.Errorf("signed header %q is missing", name)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", name, strings.TrimSpace(value)))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// 나가는 요청에 Date, Digest, Signature 헤더 붙이기 (draft-cavage HTTP 서명, rsa-sha256)
+func SignActivityPubRequest(req *http.Request, keyId string, privatePem string, body []byte, now time.Time) error {
+	key, err := parseActivityPubPrivateKey(privatePem)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Date", now.UTC().Format(http.TimeFormat))
+	headers := map[string]string{"host": req.URL.Host, "date": req.Header.Get("Date")}
+	names := []string{"(request-target)", "host", "date"}
+	if body != nil {
+		req.Header.Set("Digest", ActivityPubDigest(body))
+		headers["digest"] = req.Header.Get("Digest")
+		names = append(names, "digest")
+	}
+	signing, err := activityPubSigningString(req.Method, req.URL.RequestURI(), names, headers)
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(signing))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+	req.Host = req.URL.Host
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyId, strings.Join(names, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// Signature 헤더를 항목별로 나누기
+func parseActivityPubSignature(value string) map[string]string {
+	params := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		name, raw, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		params[strings.ToLower(name)] = strings.Trim(raw, `"`)
+	}
+	return params
+}
+
+// 요청의 Signature 헤더에서 서명한 키 주소 꺼내기
+func ActivityPubSignatureKeyId(headers map[string]string) (string, error) {
+	keyId := parseActivityPubSignature(headers["signature"])["keyid"]
+	if len(keyId) < 1 {
+		return "", fmt.Errorf("signature is missing")
+	}
+	return keyId, nil
+}
+
+// 들어온 요청의 HTTP 서명과 Digest, Date 확인하기 (headers의 이름은 소문자)
+func VerifyActivityPubRequest(method string, path string, headers map[string]string, body []byte, publicPem string, now time.Time) error {
+	params := parseActivityPubSignature(headers["signature"])
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil || len(signature) < 1 {
+		return fmt.Errorf("invalid signature")
+	}
+	names := strings.Fields(strings.ToLower(params["headers"]))
+	if len(names) < 1 {
+		names = []string{"date"}
+	}
+	required := []string{"(request-target)", "host", "date"}
+	if body != nil {
+		required = append(required, "digest")
+	}
+	for _, name := range required {
+		found := false
+		for _, signed := range names {
+			found = found || signed == name
+		}
+		if !found {
+			return fmt.Errorf("header %q must be signed", name)
+		}
+	}
+
+	date, err := http.ParseTime(headers["date"])
+	if err != nil {
+		return fmt.Errorf("invalid date header")
+	}
+	if diff := now.Sub(date); diff > activityPubClockSkew || diff < -activityPubClockSkew {
+		return fmt.Errorf("signature date is out of range")
+	}
+	if body != nil {
+		algorithm, value, _ := strings.Cut(headers["digest"], "=")
+		if !strings.EqualFold(algorithm, "SHA-256") || "SHA-256="+value != ActivityPubDigest(body) {
+			return fmt.Errorf("digest does not match the body")
+		}
+	}
+
+	key, err := parseActivityPubPublicKey(publicPem)
+	if err != nil {
+		return err
+	}
+	signing, err := activityPubSigningString(method, path, names, headers)
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(signing))
+	return rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature)
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActivityPubSignatureRoundTrip(t *testing.T) {
+	public, private, err := GenerateActivityPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"type":"Follow"}`)
+	req, _ := http.NewRequest(http.MethodPost, "https://remote.example/users/alice/inbox", strings.NewReader(string(body)))
+	if err := SignActivityPubRequest(req, "https://example.com/ap/board/diary#main-key", private, body, now); err != nil {
+		t.Fatal(err)
+	}
+	headers := map[string]string{"host": req.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = req.Header.Get(name)
+	}
+	if keyId, err := ActivityPubSignatureKeyId(headers); err != nil || keyId != "https://example.com/ap/board/diary#main-key" {
+		t.Fatalf("unexpected key id %q %v", keyId, err)
+	}
+	if err := VerifyActivityPubRequest("POST", "/users/alice/inbox", headers, body, public, now); err != nil {
+		t.Fatalf("signature should verify: %v", err)
+	}
+	if err := VerifyActivityPubRequest("POST", "/users/alice/inbox", headers, []byte(`{"type":"Undo"}`), public, now); err == nil {
+		t.Fatal("a changed body should not match the digest")
+	}
+	if err := VerifyActivityPubRequest("POST", "/users/bob/inbox", headers, body, public, now); err == nil {
+		t.Fatal("a different request target should not verify")
+	}
+	if err := VerifyActivityPubRequest("POST", "/users/alice/inbox", headers, body, public, now.Add(13*time.Hour)); err == nil {
+		t.Fatal("an old signature should be rejected")
+	}
+}
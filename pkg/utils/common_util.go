@@ -47,3 +47,24 @@ func ConvTimestamp(timestamp uint64) string {
 	t := time.UnixMilli(int64(timestamp))
 	return t.Format("2006:01:02 15:04:05")
 }
+
+// 검색 엔진, 미리보기 수집기, 스크립트 등 사람이 아닌 요청으로 보이는 User-Agent 조각들
+var botAgentMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "facebookexternalhit", "embedly", "preview",
+	"curl/", "wget/", "python-requests", "python-urllib", "go-http-client", "okhttp",
+	"headless", "lighthouse", "phantomjs", "scrapy", "httpclient", "java/",
+}
+
+// User-Agent가 비어 있거나 알려진 봇/수집기 형태인지 확인
+func IsBotAgent(agent string) bool {
+	agent = strings.ToLower(strings.TrimSpace(agent))
+	if agent == "" {
+		return true
+	}
+	for _, marker := range botAgentMarkers {
+		if strings.Contains(agent, marker) {
+			return true
+		}
+	}
+	return false
+}
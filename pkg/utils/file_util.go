@@ -58,14 +58,97 @@ func PublicUploadPath(filePath string) (string, error) {
 	return publicUploadRoot + "/" + filepath.ToSlash(relativePath), nil
 }
 
+// 업로드 파일 지우기 (AVIF 대체 형식 파일과 <이름>_w<너비> 반응형 변형 파일도 함께 지움)
 func RemoveUploadFile(publicPath string) error {
 	filePath, err := UploadFilePath(publicPath)
 	if err != nil {
 		return err
 	}
+	if fallback := ImageFallbackPath(filePath); fallback != "" {
+		_ = os.Remove(fallback)
+	}
+	if IsImage(filePath) {
+		ladder, _ := filepath.Glob(strings.TrimSuffix(filePath, filepath.Ext(filePath)) + "_w[0-9]*")
+		for _, path := range ladder {
+			_ = os.Remove(path)
+		}
+	}
 	return os.Remove(filePath)
 }
 
+// 반응형 변형들의 저장 경로를 공개 경로로 바꾸기
+func PublicImageVariants(variants []models.ImageVariant) ([]models.ImageVariant, error) {
+	result := make([]models.ImageVariant, 0, len(variants))
+	for _, variant := range variants {
+		public, err := PublicUploadPath(variant.Path)
+		if err != nil {
+			return nil, err
+		}
+		variant.Path = public
+		result = append(result, variant)
+	}
+	return result, nil
+}
+
+// 저장한 이미지의 경로들을 공개 경로로 바꾸기 (비어 있는 대체 형식 경로는 그대로 둠)
+func PublicSavedImage(saved models.SavedImage) (models.SavedImage, error) {
+	path, err := PublicUploadPath(saved.Path)
+	if err != nil {
+		return models.SavedImage{}, err
+	}
+	fallback := ""
+	if saved.Fallback != "" {
+		if fallback, err = PublicUploadPath(saved.Fallback); err != nil {
+			return models.SavedImage{}, err
+		}
+	}
+	variants, err := PublicImageVariants(saved.Variants)
+	if err != nil {
+		return models.SavedImage{}, err
+	}
+	return models.SavedImage{Path: path, Fallback: fallback, Variants: variants}, nil
+}
+
+// 공개 경로로 바꾸기 전에 저장한 이미지 파일들 지우기 (대체 형식 파일과 반응형 변형 포함)
+func RemoveSavedImage(saved models.SavedImage) {
+	_ = os.Remove(saved.Path)
+	if saved.Fallback != "" {
+		_ = os.Remove(saved.Fallback)
+	}
+	for _, variant := range saved.Variants {
+		_ = os.Remove(variant.Path)
+	}
+}
+
+// 썸네일의 저장 경로들을 공개 경로로 바꾸기 (비어 있는 대체 형식 경로는 그대로 둠)
+func PublicThumbnail(thumb models.BoardThumbnail) (models.BoardThumbnail, error) {
+	result := models.BoardThumbnail{}
+	for _, pair := range []struct {
+		from string
+		to   *string
+	}{
+		{thumb.Large, &result.Large},
+		{thumb.Small, &result.Small},
+		{thumb.LargeFallback, &result.LargeFallback},
+		{thumb.SmallFallback, &result.SmallFallback},
+	} {
+		if pair.from == "" {
+			continue
+		}
+		public, err := PublicUploadPath(pair.from)
+		if err != nil {
+			return models.BoardThumbnail{}, err
+		}
+		*pair.to = public
+	}
+	variants, err := PublicImageVariants(thumb.Variants)
+	if err != nil {
+		return models.BoardThumbnail{}, err
+	}
+	result.Variants = variants
+	return result, nil
+}
+
 // 대상 경로에 파일 복사하기
 func CopyFile(destPath string, file multipart.File) error {
 	dest, err := os.Create(destPath)
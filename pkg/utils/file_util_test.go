@@ -72,3 +72,51 @@ func TestUploadDirectoryKeepsLegacyDefault(t *testing.T) {
 		t.Fatalf("upload directory = %q, want upload", directory)
 	}
 }
+
+func TestRemoveUploadFileRemovesFallbackAndWidthVariants(t *testing.T) {
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.UploadDir = t.TempDir()
+
+	dir := filepath.Join(configs.Env.UploadDir, "images")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"a.avif", "a.webp", "a_w320.avif", "a_w320.webp", "ab.webp", "a_wide.webp"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("image"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RemoveUploadFile("/upload/images/a.avif"); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range names {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if removed := os.IsNotExist(err); removed != (i < 4) {
+			t.Fatalf("%s removed = %v", name, removed)
+		}
+	}
+}
+
+func TestPublicSavedImageKeepsEmptyFallback(t *testing.T) {
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.UploadDir = t.TempDir()
+
+	base := filepath.Join(configs.Env.UploadDir, "profile", "a")
+	saved, err := PublicSavedImage(models.SavedImage{Path: base + ".avif", Fallback: base + ".webp",
+		Variants: []models.ImageVariant{{Path: base + "_w64.avif", Width: 64, Type: "image/avif"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Path != "/upload/profile/a.avif" || saved.Fallback != "/upload/profile/a.webp" || saved.Variants[0].Path != "/upload/profile/a_w64.avif" {
+		t.Fatalf("unexpected public image %+v", saved)
+	}
+	if saved, err = PublicSavedImage(models.SavedImage{Path: base + ".webp"}); err != nil || saved.Fallback != "" {
+		t.Fatalf("images without a fallback should keep it empty, got %+v %v", saved, err)
+	}
+	if _, err := PublicSavedImage(models.SavedImage{Path: "/etc/passwd"}); err == nil {
+		t.Fatal("paths outside the upload directory should be rejected")
+	}
+}
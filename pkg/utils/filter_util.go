@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirini/goapi/pkg/models"
+)
+
+// 한글 음절을 자모로 분리할 때 쓰는 호환 자모 목록
+var (
+	jamoChoseong  = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+	jamoJungseong = []rune("ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ")
+	jamoJongseong = []rune("ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ")
+)
+
+const (
+	hangulSyllableFirst = 0xAC00
+	hangulSyllableLast  = 0xD7A3
+)
+
+// 금지어 필터에 걸린 구간 (rune 위치 기준, [Start, End))
+type FilterSpan struct {
+	Start int
+	End   int
+}
+
+// 금지어 필터 항목 하나를 검사하는 매처
+type ContentMatcher struct {
+	Rule    models.ContentFilterRule
+	pattern []rune
+	regex   *regexp.Regexp
+}
+
+// 금지어 필터 항목으로 매처 만들기 (정규표현식 오류나 빈 패턴은 에러 반환)
+func NewContentMatcher(rule models.ContentFilterRule) (*ContentMatcher, error) {
+	pattern := strings.TrimSpace(rule.Pattern)
+	if len(pattern) < 1 {
+		return nil, fmt.Errorf("empty filter pattern")
+	}
+	matcher := &ContentMatcher{Rule: rule}
+
+	switch rule.Match {
+	case models.FILTER_MATCH_REGEX:
+		regex, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter regex: %w", err)
+		}
+		matcher.regex = regex
+	case models.FILTER_MATCH_JAMO:
+		matcher.pattern, _ = normalizeJamo(pattern)
+		if len(matcher.pattern) < 1 {
+			return nil, fmt.Errorf("filter pattern has no letters")
+		}
+	default:
+		matcher.pattern = []rune(strings.ToLower(pattern))
+	}
+	return matcher, nil
+}
+
+// 텍스트에서 금지어에 걸린 구간들 찾기
+func (m *ContentMatcher) Find(text string) []FilterSpan {
+	switch m.Rule.Match {
+	case models.FILTER_MATCH_REGEX:
+		spans := make([]FilterSpan, 0)
+		for _, index := range m.regex.FindAllStringIndex(text, -1) {
+			if index[0] == index[1] {
+				continue
+			}
+			start := utf8.RuneCountInString(text[:index[0]])
+			spans = append(spans, FilterSpan{Start: start, End: start + utf8.RuneCountInString(text[index[0]:index[1]])})
+		}
+		return spans
+	case models.FILTER_MATCH_JAMO:
+		normalized, origins := normalizeJamo(text)
+		spans := make([]FilterSpan, 0)
+		for _, found := range findRunes(normalized, m.pattern) {
+			spans = append(spans, FilterSpan{Start: origins[found], End: origins[found+len(m.pattern)-1] + 1})
+		}
+		return spans
+	default:
+		lowered := []rune(text)
+		for i, r := range lowered {
+			lowered[i] = unicode.ToLower(r)
+		}
+		spans := make([]FilterSpan, 0)
+		for _, found := range findRunes(lowered, m.pattern) {
+			spans = append(spans, FilterSpan{Start: found, End: found + len(m.pattern)})
+		}
+		return spans
+	}
+}
+
+// 금지어에 걸린 구간들을 * 로 가리기
+func MaskFilterSpans(text string, spans []FilterSpan) string {
+	if len(spans) == 0 {
+		return text
+	}
+	runes := []rune(text)
+	for _, span := range spans {
+		for i := span.Start; i < span.End && i < len(runes); i++ {
+			if !unicode.IsSpace(runes[i]) {
+				runes[i] = '*'
+			}
+		}
+	}
+	return string(runes)
+}
+
+// 겹치지 않게 rune 슬라이스에서 패턴 위치들 찾기
+func findRunes(text []rune, pattern []rune) []int {
+	found := make([]int, 0)
+	if len(pattern) == 0 {
+		return found
+	}
+	for i := 0; i+len(pattern) <= len(text); {
+		matched := true
+		for j := range pattern {
+			if text[i+j] != pattern[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			found = append(found, i)
+			i += len(pattern)
+			continue
+		}
+		i++
+	}
+	return found
+}
+
+// 한글 음절은 자모로 분리하고 글자/숫자가 아닌 문자는 제거해서 비교용 rune 배열과 원래 위치 만들기
+func normalizeJamo(text string) ([]rune, []int) {
+	normalized := make([]rune, 0, len(text))
+	origins := make([]int, 0, len(text))
+	index := 0
+	for _, r := range text {
+		switch {
+		case r >= hangulSyllableFirst && r <= hangulSyllableLast:
+			offset := int(r - hangulSyllableFirst)
+			normalized = append(normalized, jamoChoseong[offset/(21*28)], jamoJungseong[(offset%(21*28))/28])
+			origins = append(origins, index, index)
+			if jong := offset % 28; jong > 0 {
+				normalized = append(normalized, jamoJongseong[jong-1])
+				origins = append(origins, index)
+			}
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			normalized = append(normalized, unicode.ToLower(r))
+			origins = append(origins, index)
+		}
+		index++
+	}
+	return normalized, origins
+}
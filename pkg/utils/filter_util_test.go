@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/sirini/goapi/pkg/models"
+)
+
+func TestContentMatcherFindsExactRegexAndJamoHits(t *testing.T) {
+	cases := []struct {
+		rule models.ContentFilterRule
+		text string
+		want string
+	}{
+		{models.ContentFilterRule{Pattern: "SPAM", Match: models.FILTER_MATCH_EXACT}, "buy spam now", "buy **** now"},
+		{models.ContentFilterRule{Pattern: `https?://bad\.example\S*`, Match: models.FILTER_MATCH_REGEX}, "see http://bad.example/x ok", "see ******************** ok"},
+		{models.ContentFilterRule{Pattern: "바보", Match: models.FILTER_MATCH_JAMO}, "너 바.보 야", "너 *** 야"},
+		{models.ContentFilterRule{Pattern: "바보", Match: models.FILTER_MATCH_JAMO}, "ㅂㅏㅂㅗ 같다", "**** 같다"},
+	}
+	for _, tc := range cases {
+		matcher, err := NewContentMatcher(tc.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := MaskFilterSpans(tc.text, matcher.Find(tc.text)); got != tc.want {
+			t.Fatalf("mask(%q) with %q = %q, want %q", tc.text, tc.rule.Pattern, got, tc.want)
+		}
+	}
+}
+
+func TestContentMatcherRejectsInvalidRules(t *testing.T) {
+	if _, err := NewContentMatcher(models.ContentFilterRule{Pattern: "(", Match: models.FILTER_MATCH_REGEX}); err == nil {
+		t.Fatal("invalid regex was accepted")
+	}
+	if _, err := NewContentMatcher(models.ContentFilterRule{Pattern: " ", Match: models.FILTER_MATCH_EXACT}); err == nil {
+		t.Fatal("empty pattern was accepted")
+	}
+	if _, err := NewContentMatcher(models.ContentFilterRule{Pattern: "바보", Match: models.FILTER_MATCH_JAMO}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -86,29 +87,65 @@ func AskImageDescription(ctx context.Context, path, model string) (ImageDescript
 
 // URL로부터 이미지 경로를 받아서 지정된 크기로 줄이고 .webp 형식으로 저장
 func DownloadImage(imageUrl string, outputPath string, width uint) error {
-	client := &http.Client{Timeout: 15 * time.Second}
-	return downloadImage(client, imageUrl, outputPath, width)
+	if _, err := ValidatePublicURL(imageUrl); err != nil {
+		return err
+	}
+	return downloadImage(NewPublicHTTPClient(15*time.Second), imageUrl, outputPath, width)
 }
 
 func downloadImage(client *http.Client, imageUrl string, outputPath string, width uint) error {
-	resp, err := client.Get(imageUrl)
+	buffer, err := fetchImageBuffer(client, imageUrl)
 	if err != nil {
 		return err
 	}
+	return SaveImage(buffer, outputPath, width)
+}
+
+// URL의 이미지를 내려받아 업로드 종류별 형식으로 주 이미지와 반응형 변형들 저장하기 (본문 삽입, 프로필 이미지)
+func SaveRemoteImage(category models.UploadCategory, imageUrl string, width uint) (models.SavedImage, error) {
+	if _, err := ValidatePublicURL(imageUrl); err != nil {
+		return models.SavedImage{}, err
+	}
+	return saveRemoteImage(NewPublicHTTPClient(15*time.Second), category, imageUrl, width)
+}
+
+func saveRemoteImage(client *http.Client, category models.UploadCategory, imageUrl string, width uint) (models.SavedImage, error) {
+	buffer, err := fetchImageBuffer(client, imageUrl)
+	if err != nil {
+		return models.SavedImage{}, err
+	}
+	tempDir := filepath.Join(UploadDirectory(), string(models.UPLOAD_TEMP))
+	if err := os.MkdirAll(tempDir, os.ModePerm); err != nil {
+		return models.SavedImage{}, err
+	}
+	tempPath := fmt.Sprintf("%s/%s", tempDir, uuid.New().String())
+	if err := os.WriteFile(tempPath, buffer, 0o600); err != nil {
+		return models.SavedImage{}, err
+	}
+	defer os.Remove(tempPath)
+	return saveCategoryImage(category, tempPath, width)
+}
+
+// 원격 이미지를 크기 제한 안에서 내려받기
+func fetchImageBuffer(client *http.Client, imageUrl string) ([]byte, error) {
+	resp, err := client.Get(imageUrl)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return fmt.Errorf("image download failed with status %d", resp.StatusCode)
+		return nil, fmt.Errorf("image download failed with status %d", resp.StatusCode)
 	}
 
 	const maxRemoteImageSize = 10 << 20
 	buffer, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteImageSize+1))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(buffer) > maxRemoteImageSize {
-		return fmt.Errorf("remote image exceeds %d bytes", maxRemoteImageSize)
+		return nil, fmt.Errorf("remote image exceeds %d bytes", maxRemoteImageSize)
 	}
-	return SaveImage(buffer, outputPath, width)
+	return buffer, nil
 }
 
 // 주어진 파일 경로가 이미지 파일인지 아닌지 확인하기
@@ -252,39 +289,131 @@ func SaveImage(inputBuffer []byte, outputPath string, width uint) error {
 	}})
 }
 
-// 본문 삽입용 이미지 저장하고 경로 반환
-func SaveInsertImage(inputPath string) (string, error) {
-	result := ""
-	savePath, err := MakeSavePath(models.UPLOAD_IMAGE)
-	if err != nil {
-		return result, err
+// 업로드 종류별 저장 형식과 품질로 변형 목록 만들기 (AVIF이면 같은 이름의 대체 형식 변형을 뒤에 덧붙임)
+func categoryVariants(category models.UploadCategory, basePath string, width uint) []imageprocessor.Variant {
+	config := configs.GetImageFormatConfig()
+	name := config.Insert
+	switch category {
+	case models.UPLOAD_THUMB:
+		name = config.Thumbnail
+	case models.UPLOAD_PROFILE:
+		name = config.Profile
+	}
+	format, _ := imageprocessor.ParseFormat(name)
+	primary := imageprocessor.Variant{
+		Path:    basePath + "." + format.Extension(),
+		Width:   width,
+		Quality: 90,
+		Format:  format,
+	}
+	if format != imageprocessor.FormatAVIF {
+		return []imageprocessor.Variant{primary}
 	}
 
-	result = fmt.Sprintf("%s/%s.webp", savePath, uuid.New().String()[:8])
-	err = ResizeImage(inputPath, result, configs.SIZE_CONTENT_INSERT.Number())
-	if err != nil {
-		return result, err
+	primary.Quality = config.AvifQuality
+	primary.Effort = &config.AvifEffort
+	fallback, ok := imageprocessor.ParseFormat(config.Fallback)
+	if !ok {
+		return []imageprocessor.Variant{primary}
 	}
-	return result, nil
+	return []imageprocessor.Variant{primary, {
+		Path:    basePath + "." + fallback.Extension(),
+		Width:   width,
+		Quality: 90,
+		Format:  fallback,
+	}}
 }
 
-// 프로필 이미지 저장하고 경로 반환
-func SaveProfileImage(inputPath string) (string, error) {
-	result := ""
-	savePath, err := MakeSavePath(models.UPLOAD_PROFILE)
-	if err != nil {
-		return result, err
+// 업로드 종류별 반응형(srcset) 너비 목록 가져오기
+func categoryWidths(category models.UploadCategory) []uint {
+	config := configs.GetImageWidthsConfig()
+	switch category {
+	case models.UPLOAD_THUMB:
+		return config.Thumbnail
+	case models.UPLOAD_PROFILE:
+		return config.Profile
+	default:
+		return config.Insert
 	}
+}
+
+// 반응형 너비마다 <기본 경로>_w<너비> 변형 만들기 (주 이미지와 같은 너비는 빼고, 원본보다 넓은 너비는 만들지 않음)
+func ladderVariants(category models.UploadCategory, basePath string, exclude ...uint) []imageprocessor.Variant {
+	variants := make([]imageprocessor.Variant, 0)
+	for _, width := range categoryWidths(category) {
+		if slices.Contains(exclude, width) {
+			continue
+		}
+		for _, variant := range categoryVariants(category, fmt.Sprintf("%s_w%d", basePath, width), width) {
+			variant.SkipUpscale = true
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
 
-	result = fmt.Sprintf("%s/%s.webp", savePath, uuid.New().String()[:8])
-	err = ResizeImage(inputPath, result, configs.SIZE_PROFILE.Number())
+// 변형 목록대로 이미지를 저장하고, 하나라도 실패하면 만든 파일들을 모두 지우기
+func processVariants(inputPath string, variants []imageprocessor.Variant) error {
+	if err := defaultImageProcessor.ProcessFile(inputPath, variants); err != nil {
+		for _, variant := range variants {
+			_ = os.Remove(variant.Path)
+		}
+		return err
+	}
+	return nil
+}
+
+// 실제로 만들어진 변형 파일만 srcset 항목으로 바꾸기 (원본보다 넓어서 건너뛴 변형은 제외)
+func savedImageVariants(variants []imageprocessor.Variant) []models.ImageVariant {
+	result := make([]models.ImageVariant, 0, len(variants))
+	for _, variant := range variants {
+		if _, err := os.Stat(variant.Path); err != nil {
+			continue
+		}
+		result = append(result, models.ImageVariant{Path: variant.Path, Width: variant.Width, Type: variant.Format.MIMEType()})
+	}
+	return result
+}
+
+// 변형 목록에서 대체 형식 파일 경로 꺼내기 (없으면 빈 문자열)
+func fallbackPath(variants []imageprocessor.Variant) string {
+	if len(variants) < 2 {
+		return ""
+	}
+	return variants[1].Path
+}
+
+// 본문 삽입용 이미지와 반응형 변형들 저장하고 경로 반환 (AVIF이면 같은 이름의 대체 형식 파일도 함께 저장)
+func SaveInsertImage(inputPath string) (models.SavedImage, error) {
+	return saveCategoryImage(models.UPLOAD_IMAGE, inputPath, configs.SIZE_CONTENT_INSERT.Number())
+}
+
+// 프로필 이미지와 반응형 변형들 저장하고 경로 반환 (AVIF이면 같은 이름의 대체 형식 파일도 함께 저장)
+func SaveProfileImage(inputPath string) (models.SavedImage, error) {
+	return saveCategoryImage(models.UPLOAD_PROFILE, inputPath, configs.SIZE_PROFILE.Number())
+}
+
+// 주 이미지와 반응형 변형들을 한 번에 만들어 저장하기
+func saveCategoryImage(category models.UploadCategory, inputPath string, width uint) (models.SavedImage, error) {
+	savePath, err := MakeSavePath(category)
 	if err != nil {
-		return result, err
+		return models.SavedImage{}, err
 	}
-	return result, nil
+
+	basePath := fmt.Sprintf("%s/%s", savePath, uuid.New().String()[:8])
+	primary := categoryVariants(category, basePath, width)
+	variants := append(primary, ladderVariants(category, basePath, width)...)
+	if err := processVariants(inputPath, variants); err != nil {
+		return models.SavedImage{}, err
+	}
+	return models.SavedImage{
+		Path:     primary[0].Path,
+		Fallback: fallbackPath(primary),
+		Variants: savedImageVariants(variants),
+	}, nil
 }
 
-// 썸네일 이미지 저장하고 경로 반환
+// 썸네일 이미지와 반응형 변형들 저장하고 경로 반환 (AVIF이면 대체 형식 경로도 함께 반환)
 func SaveThumbnailImage(inputPath string) (models.BoardThumbnail, error) {
 	result := models.BoardThumbnail{}
 	savePath, err := MakeSavePath(models.UPLOAD_THUMB)
@@ -293,27 +422,65 @@ func SaveThumbnailImage(inputPath string) (models.BoardThumbnail, error) {
 	}
 
 	randName := uuid.New().String()[:8]
-	result.Small = fmt.Sprintf("%s/t%s.webp", savePath, randName)
-	result.Large = fmt.Sprintf("%s/f%s.webp", savePath, randName)
-
-	err = defaultImageProcessor.ProcessFile(inputPath, []imageprocessor.Variant{
-		{
-			Path:    result.Small,
-			Width:   configs.SIZE_THUMBNAIL.Number(),
-			Quality: 90,
-			Format:  imageprocessor.FormatWebP,
-		},
-		{
-			Path:    result.Large,
-			Width:   configs.SIZE_FULL.Number(),
-			Quality: 90,
-			Format:  imageprocessor.FormatWebP,
-		},
-	})
-	if err != nil {
-		_ = os.Remove(result.Small)
-		_ = os.Remove(result.Large)
+	largeBase := fmt.Sprintf("%s/f%s", savePath, randName)
+	small := categoryVariants(models.UPLOAD_THUMB, fmt.Sprintf("%s/t%s", savePath, randName), configs.SIZE_THUMBNAIL.Number())
+	large := categoryVariants(models.UPLOAD_THUMB, largeBase, configs.SIZE_FULL.Number())
+	variants := append(append(small, large...), ladderVariants(models.UPLOAD_THUMB, largeBase, configs.SIZE_THUMBNAIL.Number(), configs.SIZE_FULL.Number())...)
+	if err := processVariants(inputPath, variants); err != nil {
 		return result, err
 	}
+
+	result.Small = small[0].Path
+	result.Large = large[0].Path
+	result.SmallFallback = fallbackPath(small)
+	result.LargeFallback = fallbackPath(large)
+	result.Variants = savedImageVariants(variants)
 	return result, nil
 }
+
+// 아직 기록하지 않은 너비의 기존 이미지들(대체 형식 포함)을 srcset 항목으로 모으고, 빠진 반응형 너비 변형을 마지막 이미지 이름 뒤에 _w<너비>로 만들어 덧붙이기 (기존 이미지 변형 채우기용)
+func BackfillImageVariants(category models.UploadCategory, inputPath string, primaries []models.ImageVariant, recorded []uint) ([]models.ImageVariant, error) {
+	result := make([]models.ImageVariant, 0)
+	exclude := slices.Clone(recorded)
+	for _, primary := range primaries {
+		if slices.Contains(recorded, primary.Width) {
+			continue
+		}
+		exclude = append(exclude, primary.Width)
+		for _, path := range []string{primary.Path, ImageFallbackPath(primary.Path)} {
+			format, ok := imageprocessor.ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+			if _, err := os.Stat(path); !ok || err != nil {
+				continue
+			}
+			result = append(result, models.ImageVariant{Path: path, Width: primary.Width, Type: format.MIMEType()})
+		}
+	}
+	if len(primaries) < 1 {
+		return result, nil
+	}
+
+	last := primaries[len(primaries)-1].Path
+	ladder := ladderVariants(category, strings.TrimSuffix(last, filepath.Ext(last)), exclude...)
+	if len(ladder) < 1 {
+		return result, nil
+	}
+	if err := processVariants(inputPath, ladder); err != nil {
+		return nil, err
+	}
+	return append(result, savedImageVariants(ladder)...), nil
+}
+
+// AVIF 이미지와 같은 이름으로 저장한 대체 형식 파일 경로 찾기 (없으면 빈 문자열)
+func ImageFallbackPath(path string) string {
+	ext := filepath.Ext(path)
+	if !strings.EqualFold(ext, ".avif") {
+		return ""
+	}
+	for _, format := range []imageprocessor.Format{imageprocessor.FormatWebP, imageprocessor.FormatJPEG} {
+		candidate := strings.TrimSuffix(path, ext) + "." + format.Extension()
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -7,8 +7,13 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/sirini/goapi/internal/configs"
+	"github.com/sirini/goapi/pkg/imageprocessor"
+	"github.com/sirini/goapi/pkg/models"
 )
 
 type roundTripFunc func(*http.Request) (*http.Response, error)
@@ -41,6 +46,23 @@ func TestDownloadImagePropagatesInvalidImageError(t *testing.T) {
 	}
 }
 
+func TestSaveRemoteImageRejectsHTTPErrorWithoutLeavingFiles(t *testing.T) {
+	original := configs.Env
+	t.Cleanup(func() { configs.Env = original })
+	configs.Env.UploadDir = t.TempDir()
+
+	if _, err := saveRemoteImage(imageTestClient(http.StatusNotFound, "not found"), models.UPLOAD_PROFILE, "https://example.test/image", 64); err == nil {
+		t.Fatal("HTTP error response was accepted as an image")
+	}
+	if _, err := saveRemoteImage(imageTestClient(http.StatusOK, "not an image"), models.UPLOAD_IMAGE, "https://example.test/image", 64); err == nil {
+		t.Fatal("image conversion error was ignored")
+	}
+	temp, _ := os.ReadDir(filepath.Join(configs.Env.UploadDir, string(models.UPLOAD_TEMP)))
+	if len(temp) != 0 {
+		t.Fatalf("downloaded temp files should be removed, got %d", len(temp))
+	}
+}
+
 func TestEncodeImageReturnsWebPDataURL(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "thumbnail.webp")
 	payload := []byte("RIFF\x00\x00\x00\x00WEBP")
@@ -74,3 +96,61 @@ func TestEncodeImageRejectsUnsupportedFormat(t *testing.T) {
 		t.Fatal("unsupported OpenAI image format was accepted")
 	}
 }
+
+func TestCategoryVariantsAddFallbackOnlyForAvif(t *testing.T) {
+	previous := configs.Env
+	t.Cleanup(func() { configs.Env = previous })
+	configs.Env.ImageFormat = configs.ImageFormatEnv{Insert: "avif", Thumbnail: "avif", Profile: "jpeg", Fallback: "jpeg", AvifQuality: "55", AvifEffort: "3"}
+
+	variants := categoryVariants(models.UPLOAD_IMAGE, "/upload/images/a", 640)
+	if len(variants) != 2 || variants[0].Path != "/upload/images/a.avif" || variants[0].Format != imageprocessor.FormatAVIF ||
+		variants[0].Quality != 55 || variants[0].Effort == nil || *variants[0].Effort != 3 || variants[1].Path != "/upload/images/a.jpg" ||
+		variants[1].Format != imageprocessor.FormatJPEG || variants[1].Width != 640 {
+		t.Fatalf("unexpected avif variants %+v", variants)
+	}
+	if profile := categoryVariants(models.UPLOAD_PROFILE, "/upload/profile/p", 256); len(profile) != 1 || profile[0].Path != "/upload/profile/p.jpg" {
+		t.Fatalf("non-avif formats should not get a fallback, got %+v", profile)
+	}
+	configs.Env.ImageFormat.Fallback = "none"
+	if thumb := categoryVariants(models.UPLOAD_THUMB, "/upload/thumbnails/t", 512); len(thumb) != 1 || fallbackPath(thumb) != "" {
+		t.Fatalf("fallback should be disabled, got %+v", thumb)
+	}
+}
+
+func TestLadderVariantsSkipPrimaryWidthsAndUpscaling(t *testing.T) {
+	previous := configs.Env
+	t.Cleanup(func() { configs.Env = previous })
+	configs.Env.ImageFormat = configs.ImageFormatEnv{Thumbnail: "avif", Fallback: "webp"}
+	configs.Env.ImageWidths = configs.ImageWidthsEnv{Thumbnail: "256,512,1024"}
+
+	variants := ladderVariants(models.UPLOAD_THUMB, "/upload/thumbnails/fab", 512, 2400)
+	paths := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		if !variant.SkipUpscale {
+			t.Fatalf("ladder variant %q may be enlarged", variant.Path)
+		}
+		paths = append(paths, variant.Path)
+	}
+	want := []string{"/upload/thumbnails/fab_w256.avif", "/upload/thumbnails/fab_w256.webp", "/upload/thumbnails/fab_w1024.avif", "/upload/thumbnails/fab_w1024.webp"}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("ladder paths = %v", paths)
+	}
+}
+
+func TestImageFallbackPathFindsSiblingFile(t *testing.T) {
+	dir := t.TempDir()
+	avif := filepath.Join(dir, "a.avif")
+	if ImageFallbackPath(avif) != "" {
+		t.Fatal("missing fallback was reported")
+	}
+	webp := filepath.Join(dir, "a.webp")
+	if err := os.WriteFile(webp, []byte("RIFF"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := ImageFallbackPath(avif); got != webp {
+		t.Fatalf("fallback = %q, want %q", got, webp)
+	}
+	if ImageFallbackPath(webp) != "" {
+		t.Fatal("only avif images have fallbacks")
+	}
+}
@@ -0,0 +1,299 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirini/goapi/pkg/models"
+	"golang.org/x/net/html"
+)
+
+var ErrPrivateAddress = errors.New("destination address is not allowed")
+
+const (
+	linkPageSizeLimit   = 1 << 20
+	linkOEmbedSizeLimit = 256 << 10
+	linkMaxRedirects    = 3
+)
+
+var linkPattern = regexp.MustCompile(`(?i)https?://[^\s"'<>()\[\]{}]+`)
+
+// 공인 주소로 취급하지 않는 대역들 (사설, 루프백, 링크 로컬 외 특수 용도 대역)
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// 외부로 나가도 되는 공인 IP 주소인지 확인
+func IsPublicIP(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// 사용자가 입력한 주소가 외부 요청을 보내도 되는 형태인지 확인 (http/https, 기본 포트만 허용)
+func ValidatePublicURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported url scheme")
+	}
+	if parsed.Hostname() == "" || parsed.User != nil {
+		return nil, fmt.Errorf("invalid url host")
+	}
+	if port := parsed.Port(); port != "" && port != "80" && port != "443" {
+		return nil, ErrPrivateAddress
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") || strings.HasSuffix(host, ".internal") {
+		return nil, ErrPrivateAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return nil, ErrPrivateAddress
+	}
+	return parsed, nil
+}
+
+// 연결 직전 실제로 해석된 IP를 검사해서 DNS 재바인딩으로 내부망에 닿는 것을 막기
+func rejectPrivateAddress(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// 내부망 주소로는 연결하지 않는 외부 요청용 HTTP 클라이언트 만들기
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: rejectPrivateAddress}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                  nil,
+			DialContext:            dialer.DialContext,
+			TLSHandshakeTimeout:    timeout,
+			ResponseHeaderTimeout:  timeout,
+			MaxResponseHeaderBytes: 64 << 10,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= linkMaxRedirects {
+				return fmt.Errorf("too many redirects")
+			}
+			_, err := ValidatePublicURL(req.URL.String())
+			return err
+		},
+	}
+}
+
+// 본문에서 http(s) 주소들을 중복 없이 최대 limit개까지 추출
+func ExtractLinks(text string, limit int) []string {
+	links := make([]string, 0)
+	if limit < 1 {
+		return links
+	}
+	seen := make(map[string]bool)
+	for _, match := range linkPattern.FindAllString(Unescape(text), -1) {
+		link := strings.TrimRight(match, ".,;:!?")
+		if seen[link] {
+			continue
+		}
+		if _, err := ValidatePublicURL(link); err != nil {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+		if len(links) >= limit {
+			break
+		}
+	}
+	return links
+}
+
+// 페이지의 OpenGraph, 트위터 카드, oEmbed 정보를 읽어 미리보기와 대표 이미지 주소 반환
+func FetchLinkPreview(client *http.Client, pageURL string) (models.LinkPreview, string, error) {
+	preview := models.LinkPreview{Url: pageURL}
+	if _, err := ValidatePublicURL(pageURL); err != nil {
+		return preview, "", err
+	}
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return preview, "", err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "NUBO-LinkPreview/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return preview, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return preview, "", fmt.Errorf("link preview failed with status %d", resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return preview, "", fmt.Errorf("unsupported content type %q", mediaType)
+	}
+
+	meta := parseLinkMeta(io.LimitReader(resp.Body, linkPageSizeLimit))
+	base := req.URL
+	if resp.Request != nil {
+		base = resp.Request.URL
+	}
+	if meta.oembed != "" {
+		if oembedURL, err := base.Parse(meta.oembed); err == nil {
+			meta.fillOEmbed(client, oembedURL.String())
+		}
+	}
+
+	preview.Title = Escape(CutString(meta.first("og:title", "twitter:title", "oembed:title", "title"), 200))
+	preview.Description = Escape(CutString(meta.first("og:description", "twitter:description", "description"), 500))
+	preview.SiteName = Escape(CutString(meta.first("og:site_name", "oembed:provider_name", "twitter:site"), 100))
+	preview.Type = CutString(meta.first("og:type", "oembed:type"), 30)
+	if preview.SiteName == "" {
+		preview.SiteName = Escape(base.Hostname())
+	}
+	if preview.Title == "" && preview.Description == "" {
+		return preview, "", fmt.Errorf("no preview metadata found")
+	}
+
+	imageURL := ""
+	if image := meta.first("og:image:secure_url", "og:image", "twitter:image", "twitter:image:src", "oembed:thumbnail_url"); image != "" {
+		if resolved, err := base.Parse(image); err == nil {
+			if _, err := ValidatePublicURL(resolved.String()); err == nil {
+				imageURL = resolved.String()
+			}
+		}
+	}
+	return preview, imageURL, nil
+}
+
+// 페이지 head에서 읽어낸 메타 정보
+type linkMeta struct {
+	values map[string]string
+	oembed string
+}
+
+// 앞에 있는 키부터 값이 있는 첫 번째 항목 반환
+func (m linkMeta) first(keys ...string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(m.values[key]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// oEmbed JSON 응답에서 비어 있는 항목만 채우기 (html 필드는 사용하지 않음)
+func (m *linkMeta) fillOEmbed(client *http.Client, oembedURL string) {
+	if _, err := ValidatePublicURL(oembedURL); err != nil {
+		return
+	}
+	resp, err := client.Get(oembedURL)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	var data struct {
+		Type         string `json:"type"`
+		Title        string `json:"title"`
+		ProviderName string `json:"provider_name"`
+		ThumbnailUrl string `json:"thumbnail_url"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, linkOEmbedSizeLimit)).Decode(&data); err != nil {
+		return
+	}
+	m.values["oembed:type"] = data.Type
+	m.values["oembed:title"] = data.Title
+	m.values["oembed:provider_name"] = data.ProviderName
+	m.values["oembed:thumbnail_url"] = data.ThumbnailUrl
+}
+
+// HTML을 토큰 단위로 읽으며 title, meta, oEmbed 링크만 모으기 (body가 시작되면 중단)
+func parseLinkMeta(body io.Reader) linkMeta {
+	meta := linkMeta{values: make(map[string]string)}
+	tokenizer := html.NewTokenizer(body)
+	inTitle := false
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return meta
+		case html.TextToken:
+			if inTitle && meta.values["title"] == "" {
+				meta.values["title"] = strings.Join(strings.Fields(string(tokenizer.Text())), " ")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				return meta
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			attrs := make(map[string]string)
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = tokenizer.TagAttr()
+				attrs[strings.ToLower(string(key))] = string(value)
+			}
+			switch string(name) {
+			case "body":
+				return meta
+			case "title":
+				inTitle = true
+			case "meta":
+				key := strings.ToLower(attrs["property"])
+				if key == "" {
+					key = strings.ToLower(attrs["name"])
+				}
+				if key != "" && meta.values[key] == "" {
+					meta.values[key] = attrs["content"]
+				}
+			case "link":
+				if strings.EqualFold(attrs["rel"], "alternate") && strings.EqualFold(attrs["type"], "application/json+oembed") && meta.oembed == "" {
+					meta.oembed = attrs["href"]
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// 동기화 응답 본문의 HMAC-SHA256 서명 만들기 ("sha256=<hex>" 형식)
+func SignSyncBody(body []byte, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// 동기화 응답 본문과 서명이 맞는지 확인하기
+func VerifySyncBody(body []byte, key string, signature string) bool {
+	if len(key) < 1 || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	return hmac.Equal([]byte(SignSyncBody(body, key)), []byte(strings.TrimSpace(signature)))
+}
@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirini/goapi/pkg/models"
+	"golang.org/x/net/html"
+)
+
+const wxrDateLayout = "2006-01-02 15:04:05"
+
+var (
+	wxrCaptionPattern = regexp.MustCompile(`(?s)\[caption[^\]]*\](.*?)\[/caption\]`)
+	wxrBlockPattern   = regexp.MustCompile(`(?i)<(p|div|figure|table|ul|ol|h[1-6]|blockquote|pre)[\s>]`)
+	wxrParagraphSplit = regexp.MustCompile(`\n\s*\n`)
+)
+
+// WXR 파일의 XML 구조 (wp 네임스페이스는 버전마다 달라 로컬 이름으로만 매칭)
+type wxrDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Link  string    `xml:"link"`
+		Items []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title      string `xml:"title"`
+	Link       string `xml:"link"`
+	PubDate    string `xml:"pubDate"`
+	Content    string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PostId     uint   `xml:"post_id"`
+	PostDate   string `xml:"post_date"`
+	PostGmt    string `xml:"post_date_gmt"`
+	Modified   string `xml:"post_modified_gmt"`
+	Status     string `xml:"status"`
+	PostType   string `xml:"post_type"`
+	Attachment string `xml:"attachment_url"`
+	Categories []struct {
+		Domain   string `xml:"domain,attr"`
+		Nicename string `xml:"nicename,attr"`
+		Name     string `xml:",chardata"`
+	} `xml:"category"`
+	Meta []struct {
+		Key   string `xml:"meta_key"`
+		Value string `xml:"meta_value"`
+	} `xml:"postmeta"`
+	Comments []struct {
+		Id       uint   `xml:"comment_id"`
+		Parent   uint   `xml:"comment_parent"`
+		Author   string `xml:"comment_author"`
+		Email    string `xml:"comment_author_email"`
+		Date     string `xml:"comment_date"`
+		DateGmt  string `xml:"comment_date_gmt"`
+		Content  string `xml:"comment_content"`
+		Approved string `xml:"comment_approved"`
+		Type     string `xml:"comment_type"`
+	} `xml:"comment"`
+}
+
+// 워드프레스 내보내기(WXR) 파일을 읽어 글, 댓글, 첨부 이미지 주소로 정리하기
+func ParseWXR(reader io.Reader) (models.WXRSite, error) {
+	site := models.WXRSite{Posts: make([]models.WXRPost, 0), Attachments: make(map[uint]string)}
+	doc := wxrDocument{}
+	decoder := xml.NewDecoder(reader)
+	decoder.Strict = false
+	if err := decoder.Decode(&doc); err != nil {
+		return site, fmt.Errorf("invalid WXR file: %w", err)
+	}
+	site.Title = strings.TrimSpace(doc.Channel.Title)
+	site.Link = strings.TrimRight(strings.TrimSpace(doc.Channel.Link), "/")
+	if site.Link == "" {
+		return site, fmt.Errorf("invalid WXR file: missing site link")
+	}
+
+	for _, item := range doc.Channel.Items {
+		if item.PostType == "attachment" {
+			if item.PostId > 0 && item.Attachment != "" {
+				site.Attachments[item.PostId] = strings.TrimSpace(item.Attachment)
+			}
+			continue
+		}
+		post := models.WXRPost{
+			Id:         item.PostId,
+			Title:      strings.TrimSpace(item.Title),
+			Link:       strings.TrimSpace(item.Link),
+			Content:    item.Content,
+			Type:       item.PostType,
+			Status:     item.Status,
+			Submitted:  wxrTime(item.PostGmt, item.PostDate, item.PubDate),
+			Categories: make([]string, 0),
+			Tags:       make([]string, 0),
+			Comments:   make([]models.WXRComment, 0),
+		}
+		post.Modified = wxrTime(item.Modified, "", "")
+		for _, category := range item.Categories {
+			name := strings.TrimSpace(category.Name)
+			if name == "" {
+				continue
+			}
+			switch category.Domain {
+			case "category":
+				post.Categories = append(post.Categories, name)
+			case "post_tag":
+				post.Tags = append(post.Tags, name)
+			}
+		}
+		for _, meta := range item.Meta {
+			if meta.Key == "_thumbnail_id" {
+				if id, err := strconv.ParseUint(strings.TrimSpace(meta.Value), 10, 32); err == nil {
+					post.ThumbnailId = uint(id)
+				}
+			}
+		}
+		for _, comment := range item.Comments {
+			post.Comments = append(post.Comments, models.WXRComment{
+				Id:          comment.Id,
+				ParentId:    comment.Parent,
+				Author:      strings.TrimSpace(comment.Author),
+				AuthorEmail: strings.ToLower(strings.TrimSpace(comment.Email)),
+				Content:     comment.Content,
+				Type:        comment.Type,
+				Approved:    comment.Approved == "1",
+				Submitted:   wxrTime(comment.DateGmt, comment.Date, ""),
+			})
+		}
+		site.Posts = append(site.Posts, post)
+	}
+	return site, nil
+}
+
+// GMT 시간, 사이트 현지 시간, RSS pubDate 순으로 읽을 수 있는 첫 번째 시간 반환 (없으면 0)
+func wxrTime(gmt string, local string, pubDate string) int64 {
+	if parsed, err := time.ParseInLocation(wxrDateLayout, strings.TrimSpace(gmt), time.UTC); err == nil && parsed.Year() > 1 {
+		return parsed.UnixMilli()
+	}
+	if parsed, err := time.ParseInLocation(wxrDateLayout, strings.TrimSpace(local), time.Local); err == nil && parsed.Year() > 1 {
+		return parsed.UnixMilli()
+	}
+	if parsed, err := time.Parse(time.RFC1123Z, strings.TrimSpace(pubDate)); err == nil {
+		return parsed.UnixMilli()
+	}
+	return 0
+}
+
+// 워드프레스 본문을 HTML로 정리하기 (캡션 단축코드 제거, 문단 태그가 없으면 빈 줄 기준으로 문단 나누기)
+func WordPressContentToHTML(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = wxrCaptionPattern.ReplaceAllString(content, "$1")
+	if wxrBlockPattern.MatchString(content) {
+		return strings.TrimSpace(content)
+	}
+	paragraphs := make([]string, 0)
+	for _, paragraph := range wxrParagraphSplit.Split(content, -1) {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		paragraphs = append(paragraphs, "<p>"+strings.ReplaceAll(paragraph, "\n", "<br>")+"</p>")
+	}
+	return strings.Join(paragraphs, "\n")
+}
+
+// 본문에 삽입된 이미지 주소들을 기준 주소로 풀어서 중복 없이 반환
+func ExtractImageSources(content string, base string) []string {
+	sources := make([]string, 0)
+	baseURL, _ := url.Parse(base)
+	seen := make(map[string]bool)
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return sources
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+		token := tokenizer.Token()
+		if token.Data != "img" {
+			continue
+		}
+		for _, attr := range token.Attr {
+			if attr.Key != "src" {
+				continue
+			}
+			source := resolveWXRLink(baseURL, attr.Val)
+			if source != "" && !seen[source] {
+				seen[source] = true
+				sources = append(sources, source)
+			}
+		}
+	}
+}
+
+// 상대 주소를 기준 주소로 풀고 http(s) 주소만 반환
+func resolveWXRLink(base *url.URL, link string) string {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		parsed = base.ResolveReference(parsed)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return ""
+	}
+	return parsed.String()
+}
+
+// 본문의 href, src 속성을 rewrite 결과로 바꾸기 (바뀐 이미지의 srcset, sizes는 원래 사이트를 가리키므로 제거)
+func RewriteHTMLLinks(content string, base string, rewrite func(string) (string, bool)) (string, uint) {
+	baseURL, _ := url.Parse(base)
+	var builder strings.Builder
+	var count uint
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return builder.String(), count
+		}
+		raw := string(tokenizer.Raw())
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			builder.WriteString(raw)
+			continue
+		}
+		token := tokenizer.Token()
+		changed := false
+		attrs := make([]html.Attribute, 0, len(token.Attr))
+		for _, attr := range token.Attr {
+			if attr.Key == "href" || attr.Key == "src" {
+				if replaced, ok := rewrite(resolveWXRLink(baseURL, attr.Val)); ok {
+					attr.Val = replaced
+					changed = true
+					count++
+				}
+			}
+			attrs = append(attrs, attr)
+		}
+		if !changed {
+			builder.WriteString(raw)
+			continue
+		}
+		token.Attr = attrs[:0]
+		for _, attr := range attrs {
+			if token.Data == "img" && (attr.Key == "srcset" || attr.Key == "sizes") {
+				continue
+			}
+			token.Attr = append(token.Attr, attr)
+		}
+		builder.WriteString(token.String())
+	}
+}
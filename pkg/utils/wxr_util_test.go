@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleWXR = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"
+  xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/" xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+  <title>Old blog</title>
+  <link>https://old.example.com/</link>
+  <item>
+    <title>Cover</title>
+    <wp:post_id>9</wp:post_id>
+    <wp:post_type>attachment</wp:post_type>
+    <wp:attachment_url>https://old.example.com/wp-content/uploads/cover.jpg</wp:attachment_url>
+  </item>
+  <item>
+    <title>Hello &amp; welcome</title>
+    <link>https://old.example.com/2019/03/hello/</link>
+    <content:encoded><![CDATA[First line
+second line
+
+[caption id="a"]<img src="/wp-content/uploads/a.jpg" srcset="a-300.jpg 300w" />[/caption]]]></content:encoded>
+    <excerpt:encoded><![CDATA[excerpt]]></excerpt:encoded>
+    <wp:post_id>12</wp:post_id>
+    <wp:post_date>2019-03-01 21:00:00</wp:post_date>
+    <wp:post_date_gmt>2019-03-01 12:00:00</wp:post_date_gmt>
+    <wp:status>publish</wp:status>
+    <wp:post_type>post</wp:post_type>
+    <category domain="category" nicename="diary"><![CDATA[Diary]]></category>
+    <category domain="post_tag" nicename="go"><![CDATA[go]]></category>
+    <wp:postmeta><wp:meta_key>_thumbnail_id</wp:meta_key><wp:meta_value>9</wp:meta_value></wp:postmeta>
+    <wp:comment>
+      <wp:comment_id>3</wp:comment_id>
+      <wp:comment_author><![CDATA[Kim]]></wp:comment_author>
+      <wp:comment_author_email>Kim@Example.com</wp:comment_author_email>
+      <wp:comment_date_gmt>2019-03-02 00:00:00</wp:comment_date_gmt>
+      <wp:comment_content><![CDATA[Nice]]></wp:comment_content>
+      <wp:comment_approved>1</wp:comment_approved>
+      <wp:comment_parent>0</wp:comment_parent>
+    </wp:comment>
+  </item>
+</channel>
+</rss>`
+
+func TestParseWXR(t *testing.T) {
+	site, err := ParseWXR(strings.NewReader(sampleWXR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if site.Link != "https://old.example.com" || len(site.Posts) != 1 || site.Attachments[9] == "" {
+		t.Fatalf("unexpected site %+v", site)
+	}
+	post := site.Posts[0]
+	if post.Id != 12 || post.Title != "Hello & welcome" || post.Submitted != 1551441600000 || post.ThumbnailId != 9 {
+		t.Fatalf("unexpected post %+v", post)
+	}
+	if len(post.Categories) != 1 || post.Categories[0] != "Diary" || len(post.Tags) != 1 || post.Tags[0] != "go" {
+		t.Fatalf("categories and tags should be split by domain, got %v %v", post.Categories, post.Tags)
+	}
+	if len(post.Comments) != 1 || !post.Comments[0].Approved || post.Comments[0].AuthorEmail != "kim@example.com" {
+		t.Fatalf("unexpected comments %+v", post.Comments)
+	}
+
+	content := WordPressContentToHTML(post.Content)
+	if !strings.HasPrefix(content, "<p>First line<br>second line</p>") || strings.Contains(content, "[caption") {
+		t.Fatalf("content should be converted into paragraphs, got %q", content)
+	}
+	sources := ExtractImageSources(content, site.Link)
+	if len(sources) != 1 || sources[0] != "https://old.example.com/wp-content/uploads/a.jpg" {
+		t.Fatalf("unexpected image sources %v", sources)
+	}
+	rewritten, count := RewriteHTMLLinks(content, site.Link, func(link string) (string, bool) {
+		return "/upload/images/a.webp", link == sources[0]
+	})
+	if count != 1 || !strings.Contains(rewritten, `src="/upload/images/a.webp"`) || strings.Contains(rewritten, "srcset") {
+		t.Fatalf("image should point to the re-hosted copy without srcset, got %q", rewritten)
+	}
+}